@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"dancer/pkg/client"
+)
+
+// runDomain domain 子命令
+func runDomain(args []string) error {
+	return runSubcommand("domain", args, map[string]func([]string) error{
+		"list":   domainList,
+		"get":    domainGet,
+		"create": domainCreate,
+		"update": domainUpdate,
+		"delete": domainDelete,
+	})
+}
+
+// newDomainFlags domain 子命令参数，均需指定 Zone
+func newDomainFlags(name string) (*commandFlags, *string) {
+	f := newFlags("domain " + name)
+	zone := f.String("zone", "", "所属 Zone（必填）")
+	return f, zone
+}
+
+// requireZone 检查是否指定了 Zone
+func requireZone(zone string) error {
+	if zone == "" {
+		return usageError("-zone is required")
+	}
+	return nil
+}
+
+func domainList(args []string) error {
+	f, zone := newDomainFlags("list")
+	prefix := f.String("prefix", "", "子域名前缀")
+	contains := f.String("contains", "", "子域名包含")
+	ip := f.String("ip", "", "包含该 IP 或属于该网段")
+	if _, err := f.parse(args); err != nil {
+		return err
+	}
+	if err := requireZone(*zone); err != nil {
+		return err
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	list, err := c.ListDomains(ctx, &client.ListDomainsRequest{
+		Zone:         *zone,
+		NamePrefix:   *prefix,
+		NameContains: *contains,
+		IP:           *ip,
+	})
+	if err != nil {
+		return err
+	}
+	return printResult(f.output, list, func(w io.Writer) {
+		printDomains(w, list.Domains...)
+	})
+}
+
+func domainGet(args []string) error {
+	f, zone := newDomainFlags("get")
+	rest, err := f.parse(args, "<domain>")
+	if err != nil {
+		return err
+	}
+	if err := requireZone(*zone); err != nil {
+		return err
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	domain, err := c.GetDomain(ctx, &client.GetDomainRequest{Zone: *zone, Domain: rest[0]})
+	if err != nil {
+		return err
+	}
+	return printDomain(f.output, domain)
+}
+
+func domainCreate(args []string) error {
+	f, zone := newDomainFlags("create")
+	var ips stringList
+	f.Var(&ips, "ip", "IP，可重复指定或以逗号分隔（必填）")
+	ttl := f.Int("ttl", 300, "TTL（秒）")
+	leaseTTL := f.Int64("lease-ttl", 0, "租约时长（秒），设置后为临时 Domain")
+	rest, err := f.parse(args, "<domain>")
+	if err != nil {
+		return err
+	}
+	if err := requireZone(*zone); err != nil {
+		return err
+	}
+	if len(ips) == 0 {
+		return usageError("-ip is required")
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	domain, err := c.CreateDomain(ctx, &client.CreateDomainRequest{
+		Zone:     *zone,
+		Domain:   rest[0],
+		IPs:      ips,
+		TTL:      *ttl,
+		LeaseTTL: *leaseTTL,
+	})
+	if err != nil {
+		return err
+	}
+	return printDomain(f.output, domain)
+}
+
+func domainUpdate(args []string) error {
+	f, zone := newDomainFlags("update")
+	var ips stringList
+	f.Var(&ips, "ip", "新的 IP 列表，可重复指定或以逗号分隔（必填）")
+	ttl := f.Int("ttl", 0, "TTL（秒），不指定时保持原值")
+	rest, err := f.parse(args, "<domain>")
+	if err != nil {
+		return err
+	}
+	if err := requireZone(*zone); err != nil {
+		return err
+	}
+	if len(ips) == 0 {
+		return usageError("-ip is required")
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	domain, err := c.UpdateDomain(ctx, &client.UpdateDomainRequest{Zone: *zone, Domain: rest[0], IPs: ips, TTL: *ttl})
+	if err != nil {
+		return err
+	}
+	return printDomain(f.output, domain)
+}
+
+func domainDelete(args []string) error {
+	f, zone := newDomainFlags("delete")
+	rest, err := f.parse(args, "<domain>")
+	if err != nil {
+		return err
+	}
+	if err := requireZone(*zone); err != nil {
+		return err
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	if err := c.DeleteDomain(ctx, &client.DeleteDomainRequest{Zone: *zone, Domain: rest[0]}); err != nil {
+		return err
+	}
+	fmt.Printf("Domain %s.%s moved to trash\n", rest[0], *zone)
+	return nil
+}
+
+// printDomain 输出单个 Domain
+func printDomain(format string, domain *client.DomainDTO) error {
+	return printResult(format, domain, func(w io.Writer) {
+		printDomains(w, domain)
+	})
+}
+
+// printDomains 以表格输出 Domain，动态实例 IP 标注 (dynamic)
+func printDomains(w io.Writer, domains ...*client.DomainDTO) {
+	row(w, "NAME", "IPS", "TTL", "EXPIRES", "UPDATED")
+	for _, d := range domains {
+		ips := strings.Join(d.IPs, ",")
+		if len(d.DynamicIPs) > 0 {
+			ips += " " + strings.Join(d.DynamicIPs, ",") + " (dynamic)"
+		}
+		row(w, d.Name, ips, d.TTL, formatTime(d.ExpiresAt), formatTime(d.UpdatedAt))
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"dancer/pkg/client"
+)
+
+// commandFlags 各命令共用的参数
+type commandFlags struct {
+	*flag.FlagSet
+	server string
+	token  string
+	output string
+}
+
+// newFlags 创建命令参数，包含服务地址、Token 与输出格式
+func newFlags(name string) *commandFlags {
+	f := &commandFlags{FlagSet: flag.NewFlagSet("dancerctl "+name, flag.ContinueOnError)}
+	f.StringVar(&f.server, "server", "", "服务地址，如 http://localhost:8080")
+	f.StringVar(&f.token, "token", "", "JWT，覆盖 login 缓存的 Token")
+	f.StringVar(&f.output, "o", "table", "输出格式：table / json / yaml")
+	return f
+}
+
+// parse 解析参数，参数与位置参数可以交替出现，返回位置参数
+func (f *commandFlags) parse(args []string, positional ...string) ([]string, error) {
+	f.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] %s\n", f.Name(), strings.Join(positional, " "))
+		f.PrintDefaults()
+	}
+
+	var rest []string
+	for {
+		if err := f.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return nil, usageError("")
+			}
+			return nil, usageError(err.Error())
+		}
+		if f.NArg() == 0 {
+			break
+		}
+		rest = append(rest, f.Arg(0))
+		args = f.Args()[1:]
+	}
+
+	switch f.output {
+	case "table", "json", "yaml":
+	default:
+		return nil, usageError(fmt.Sprintf("invalid output format %q, expected table, json or yaml", f.output))
+	}
+	if len(rest) != len(positional) {
+		f.Usage()
+		return nil, usageError(fmt.Sprintf("%s expects %d argument(s), got %d", f.Name(), len(positional), len(rest)))
+	}
+	return rest, nil
+}
+
+// isSet 参数是否在命令行中指定
+func (f *commandFlags) isSet(name string) bool {
+	set := false
+	f.Visit(func(fl *flag.Flag) {
+		if fl.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// client 按参数、环境变量与 login 缓存创建客户端
+func (f *commandFlags) client() (*client.Client, error) {
+	creds, err := loadCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	server := firstNonEmpty(f.server, os.Getenv("DANCER_SERVER"), creds.Server)
+	if server == "" {
+		return nil, usageError("no server configured, run 'dancerctl login -server URL' or set DANCER_SERVER")
+	}
+	c := client.New(server)
+	c.Token = firstNonEmpty(f.token, os.Getenv("DANCER_TOKEN"))
+	// 缓存的 Token 只用于登录时的服务
+	if c.Token == "" && server == creds.Server {
+		c.Token = creds.Token
+	}
+	return c, nil
+}
+
+// stringList 可重复指定的字符串参数，也接受逗号分隔
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
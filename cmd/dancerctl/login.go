@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"dancer/pkg/client"
+	"golang.org/x/term"
+)
+
+// defaultServer 未指定服务地址时登录的服务
+const defaultServer = "http://localhost:8080"
+
+// credentials login 缓存的服务地址与 Token
+type credentials struct {
+	Server   string `json:"server"`
+	Username string `json:"username"`
+	Token    string `json:"token"`
+}
+
+// credentialsPath 缓存文件路径，默认 ~/.config/dancerctl/credentials.json，可通过 DANCERCTL_CONFIG 指定
+func credentialsPath() (string, error) {
+	if path := os.Getenv("DANCERCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "dancerctl", "credentials.json"), nil
+}
+
+// loadCredentials 读取缓存，未登录时返回空值
+func loadCredentials() (*credentials, error) {
+	path, err := credentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &credentials{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var creds credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("invalid credentials file %s: %w", path, err)
+	}
+	return &creds, nil
+}
+
+// saveCredentials 写入缓存，文件仅当前用户可读
+func saveCredentials(creds *credentials) error {
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// runLogin 登录并缓存 Token
+func runLogin(args []string) error {
+	f := newFlags("login")
+	username := f.String("u", "", "用户名，未指定时提示输入")
+	tenantID := f.String("tenant", "", "登录的租户，默认为默认租户")
+	passwordStdin := f.Bool("password-stdin", false, "从标准输入读取密码")
+	if _, err := f.parse(args); err != nil {
+		return err
+	}
+
+	creds, err := loadCredentials()
+	if err != nil {
+		return err
+	}
+	server := firstNonEmpty(f.server, os.Getenv("DANCER_SERVER"), creds.Server, defaultServer)
+
+	stdin := bufio.NewReader(os.Stdin)
+	if *username == "" {
+		fmt.Fprint(os.Stderr, "Username: ")
+		line, err := stdin.ReadString('\n')
+		if err != nil && line == "" {
+			return err
+		}
+		*username = strings.TrimSpace(line)
+	}
+	password, err := readPassword(stdin, "Password: ", *passwordStdin)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+
+	c := client.New(server)
+	resp, err := c.Login(ctx, &client.LoginRequest{Tenant: *tenantID, Username: *username, Password: password})
+	if err != nil {
+		return err
+	}
+
+	if err := saveCredentials(&credentials{Server: server, Username: *username, Token: resp.Token}); err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+	fmt.Printf("Logged in to %s as %s\n", server, *username)
+	return nil
+}
+
+// runLogout 删除缓存的 Token
+func runLogout(args []string) error {
+	f := newFlags("logout")
+	if _, err := f.parse(args); err != nil {
+		return err
+	}
+
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	fmt.Println("Logged out")
+	return nil
+}
+
+// readPassword 读取密码：fromStdin 或标准输入不是终端时读取一行，否则关闭回显提示输入
+func readPassword(stdin *bufio.Reader, prompt string, fromStdin bool) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if fromStdin || !term.IsTerminal(fd) {
+		line, err := stdin.ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return string(password), nil
+}
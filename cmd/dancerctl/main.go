@@ -0,0 +1,106 @@
+// dancerctl Dancer API 命令行客户端
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"dancer/pkg/client"
+)
+
+const usage = `Usage: dancerctl <command> [flags] [args]
+
+Commands:
+  login      登录并缓存 Token
+  logout     删除缓存的 Token
+  zone       list | get | create | update | delete
+  domain     list | get | create | update | delete
+  user       list | get | create | update | delete
+  export     导出 Zone 与 Domain 到期望状态文件
+  import     从期望状态文件或目录导入（默认只显示计划）
+
+每个命令支持 -o table|json|yaml 选择输出格式，-h 查看参数。
+服务地址与 Token 依次取自 -server / -token 参数、环境变量 DANCER_SERVER / DANCER_TOKEN、login 缓存。
+`
+
+// requestTimeout 单个命令的超时
+const requestTimeout = 5 * time.Minute
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "help" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	commands := map[string]func([]string) error{
+		"login":  runLogin,
+		"logout": runLogout,
+		"zone":   runZone,
+		"domain": runDomain,
+		"user":   runUser,
+		"export": runExport,
+		"import": runImport,
+	}
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err := run(os.Args[2:]); err != nil {
+		var usageErr usageError
+		switch {
+		case errors.As(err, &usageErr):
+			if usageErr != "" {
+				fmt.Fprintln(os.Stderr, usageErr)
+			}
+			os.Exit(2)
+		case client.IsUnauthorized(err):
+			fmt.Fprintf(os.Stderr, "Error: %v\nRun 'dancerctl login' to sign in again.\n", err)
+		default:
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// usageError 参数错误，退出码为 2
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// runSubcommand 按第一个参数分派子命令
+func runSubcommand(name string, args []string, subcommands map[string]func([]string) error) error {
+	if len(args) == 0 {
+		return usageError(fmt.Sprintf("Usage: dancerctl %s <%s> [flags] [args]", name, subcommandNames(subcommands)))
+	}
+	run, ok := subcommands[args[0]]
+	if !ok {
+		return usageError(fmt.Sprintf("unknown %s subcommand %q, expected one of: %s", name, args[0], subcommandNames(subcommands)))
+	}
+	return run(args[1:])
+}
+
+// subcommandNames 子命令名称，按固定顺序列出
+func subcommandNames(subcommands map[string]func([]string) error) string {
+	names := ""
+	for _, name := range []string{"list", "get", "create", "update", "delete"} {
+		if _, ok := subcommands[name]; ok {
+			if names != "" {
+				names += "|"
+			}
+			names += name
+		}
+	}
+	return names
+}
+
+// newContext 命令使用的上下文
+func newContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), requestTimeout)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"dancer/internal/services"
+	"dancer/pkg/client"
+)
+
+// runExport 导出 Zone 与 Domain 到期望状态文件，文件可直接用于 import 或 dancer sync
+// 临时 Domain 与动态实例不导出
+func runExport(args []string) error {
+	f := newFlags("export")
+	file := f.String("f", "-", "输出文件，.json 结尾时为 JSON，否则为 YAML；- 为标准输出")
+	var zones stringList
+	f.Var(&zones, "zone", "只导出指定 Zone，可重复指定，默认全部")
+	if _, err := f.parse(args); err != nil {
+		return err
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+
+	if len(zones) == 0 {
+		list, err := c.ListZones(ctx, &client.ListZonesRequest{})
+		if err != nil {
+			return err
+		}
+		for _, z := range list.Zones {
+			zones = append(zones, z.Zone)
+		}
+	}
+
+	desired := make([]*client.DesiredZone, 0, len(zones))
+	for _, name := range zones {
+		zone, err := c.GetZone(ctx, &client.GetZoneRequest{Zone: name})
+		if err != nil {
+			return err
+		}
+		domains, err := c.ListDomains(ctx, &client.ListDomainsRequest{Zone: name})
+		if err != nil {
+			return err
+		}
+
+		dz := &client.DesiredZone{
+			Zone:             zone.Zone,
+			ApprovalRequired: zone.ApprovalRequired,
+			Domains:          []*client.DesiredDomain{},
+		}
+		for _, d := range domains.Domains {
+			if d.LeaseTTL > 0 {
+				continue
+			}
+			dz.Domains = append(dz.Domains, &client.DesiredDomain{Domain: d.Domain, IPs: d.IPs, TTL: d.TTL})
+		}
+		desired = append(desired, dz)
+	}
+
+	data, err := services.MarshalDesiredState(*file, desired)
+	if err != nil {
+		return err
+	}
+	if *file == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*file, data, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d zones to %s\n", len(desired), *file)
+	return nil
+}
+
+// runImport 从期望状态文件或目录导入：默认只输出同步计划，-apply 时应用
+func runImport(args []string) error {
+	f := newFlags("import")
+	path := f.String("f", "", "期望状态文件或目录（必填）")
+	prune := f.Bool("prune", false, "删除期望状态中没有的 Zone 与 Domain")
+	apply := f.Bool("apply", false, "应用同步计划，默认只显示计划")
+	if _, err := f.parse(args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return usageError("-f is required")
+	}
+
+	zones, err := services.LoadDesiredState(*path)
+	if err != nil {
+		return err
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+
+	req := &client.SyncRequest{Prune: *prune, Zones: zones}
+	var plan *client.SyncPlan
+	if *apply {
+		plan, err = c.SyncApply(ctx, req)
+	} else {
+		plan, err = c.SyncPlan(ctx, req)
+	}
+	if err != nil {
+		return err
+	}
+
+	if f.output == "table" {
+		services.WriteSyncPlan(os.Stdout, plan)
+	} else if err := printResult(f.output, plan, nil); err != nil {
+		return err
+	}
+
+	if len(plan.Problems) > 0 {
+		return fmt.Errorf("desired state is invalid")
+	}
+	for _, zp := range plan.Zones {
+		if zp.Status == client.SyncStatusFailed {
+			return fmt.Errorf("zone %s failed to apply", zp.Zone)
+		}
+	}
+	return nil
+}
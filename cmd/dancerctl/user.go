@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"dancer/pkg/client"
+)
+
+// runUser user 子命令（Admin）
+func runUser(args []string) error {
+	return runSubcommand("user", args, map[string]func([]string) error{
+		"list":   userList,
+		"get":    userGet,
+		"create": userCreate,
+		"update": userUpdate,
+		"delete": userDelete,
+	})
+}
+
+func userList(args []string) error {
+	f := newFlags("user list")
+	prefix := f.String("prefix", "", "用户名前缀")
+	userType := f.String("type", "", "用户类型：super_admin / admin / normal")
+	if _, err := f.parse(args); err != nil {
+		return err
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	list, err := c.ListUsers(ctx, &client.ListUsersRequest{NamePrefix: *prefix, UserType: client.UserType(*userType)})
+	if err != nil {
+		return err
+	}
+	return printResult(f.output, list, func(w io.Writer) {
+		printUsers(w, list.Users...)
+	})
+}
+
+func userGet(args []string) error {
+	f := newFlags("user get")
+	rest, err := f.parse(args, "<username|id>")
+	if err != nil {
+		return err
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	user, err := findUser(ctx, c, rest[0])
+	if err != nil {
+		return err
+	}
+	return printUser(f.output, user)
+}
+
+func userCreate(args []string) error {
+	f := newFlags("user create")
+	userType := f.String("type", string(client.UserTypeNormal), "用户类型：super_admin / admin / normal")
+	passwordStdin := f.Bool("password-stdin", false, "从标准输入读取密码")
+	rest, err := f.parse(args, "<username>")
+	if err != nil {
+		return err
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+	password, err := readPassword(bufio.NewReader(os.Stdin), "New password: ", *passwordStdin)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	user, err := c.CreateUser(ctx, &client.CreateUserRequest{
+		Username: rest[0],
+		Password: password,
+		UserType: client.UserType(*userType),
+	})
+	if err != nil {
+		return err
+	}
+	return printUser(f.output, user)
+}
+
+func userUpdate(args []string) error {
+	f := newFlags("user update")
+	username := f.String("username", "", "新用户名")
+	userType := f.String("type", "", "新用户类型：super_admin / admin / normal")
+	setPassword := f.Bool("password", false, "重置密码，提示输入新密码")
+	passwordStdin := f.Bool("password-stdin", false, "重置密码，从标准输入读取新密码")
+	rest, err := f.parse(args, "<username|id>")
+	if err != nil {
+		return err
+	}
+	if *username == "" && *userType == "" && !*setPassword && !*passwordStdin {
+		return usageError("nothing to update, specify -username, -type or -password")
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+
+	req := &client.UpdateUserRequest{Username: *username, UserType: client.UserType(*userType)}
+	if *setPassword || *passwordStdin {
+		if req.Password, err = readPassword(bufio.NewReader(os.Stdin), "New password: ", *passwordStdin); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	user, err := findUser(ctx, c, rest[0])
+	if err != nil {
+		return err
+	}
+	req.ID = user.ID
+	if err := c.UpdateUser(ctx, req); err != nil {
+		return err
+	}
+	fmt.Printf("User %s updated\n", user.Username)
+	return nil
+}
+
+func userDelete(args []string) error {
+	f := newFlags("user delete")
+	rest, err := f.parse(args, "<username|id>")
+	if err != nil {
+		return err
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	user, err := findUser(ctx, c, rest[0])
+	if err != nil {
+		return err
+	}
+	if err := c.DeleteUser(ctx, &client.DeleteUserRequest{ID: user.ID}); err != nil {
+		return err
+	}
+	fmt.Printf("User %s deleted\n", user.Username)
+	return nil
+}
+
+// findUser 按用户名或 ID 查找用户
+func findUser(ctx context.Context, c *client.Client, name string) (*client.UserDTO, error) {
+	list, err := c.ListUsers(ctx, &client.ListUsersRequest{})
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range list.Users {
+		if u.Username == name || u.ID == name {
+			return u, nil
+		}
+	}
+	return nil, fmt.Errorf("user %s not found", name)
+}
+
+// printUser 输出单个用户
+func printUser(format string, user *client.UserDTO) error {
+	return printResult(format, user, func(w io.Writer) {
+		printUsers(w, user)
+	})
+}
+
+// printUsers 以表格输出用户
+func printUsers(w io.Writer, users ...*client.UserDTO) {
+	row(w, "ID", "USERNAME", "TYPE", "CREATED")
+	for _, u := range users {
+		row(w, u.ID, u.Username, u.UserType, formatTime(u.CreatedAt))
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"dancer/pkg/client"
+)
+
+// runZone zone 子命令（Admin）
+func runZone(args []string) error {
+	return runSubcommand("zone", args, map[string]func([]string) error{
+		"list":   zoneList,
+		"get":    zoneGet,
+		"create": zoneCreate,
+		"update": zoneUpdate,
+		"delete": zoneDelete,
+	})
+}
+
+func zoneList(args []string) error {
+	f := newFlags("zone list")
+	prefix := f.String("prefix", "", "Zone 名称前缀")
+	contains := f.String("contains", "", "Zone 名称包含")
+	if _, err := f.parse(args); err != nil {
+		return err
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	list, err := c.ListZones(ctx, &client.ListZonesRequest{NamePrefix: *prefix, NameContains: *contains})
+	if err != nil {
+		return err
+	}
+	return printResult(f.output, list, func(w io.Writer) {
+		printZones(w, list.Zones...)
+	})
+}
+
+func zoneGet(args []string) error {
+	f := newFlags("zone get")
+	rest, err := f.parse(args, "<zone>")
+	if err != nil {
+		return err
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	zone, err := c.GetZone(ctx, &client.GetZoneRequest{Zone: rest[0]})
+	if err != nil {
+		return err
+	}
+	return printZone(f.output, zone)
+}
+
+func zoneCreate(args []string) error {
+	f := newFlags("zone create")
+	approval := f.Bool("approval", false, "Domain 变更必须经过审批")
+	rest, err := f.parse(args, "<zone>")
+	if err != nil {
+		return err
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	zone, err := c.CreateZone(ctx, &client.CreateZoneRequest{Zone: rest[0], ApprovalRequired: *approval})
+	if err != nil {
+		return err
+	}
+	return printZone(f.output, zone)
+}
+
+func zoneUpdate(args []string) error {
+	f := newFlags("zone update")
+	approval := f.Bool("approval", false, "Domain 变更是否必须经过审批，如 -approval=false")
+	rest, err := f.parse(args, "<zone>")
+	if err != nil {
+		return err
+	}
+	if !f.isSet("approval") {
+		return usageError("nothing to update, specify -approval=true|false")
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	zone, err := c.UpdateZone(ctx, &client.UpdateZoneRequest{Zone: rest[0], ApprovalRequired: approval})
+	if err != nil {
+		return err
+	}
+	return printZone(f.output, zone)
+}
+
+func zoneDelete(args []string) error {
+	f := newFlags("zone delete")
+	force := f.Bool("force", false, "Zone 下仍有 Domain 时一并删除")
+	rest, err := f.parse(args, "<zone>")
+	if err != nil {
+		return err
+	}
+	c, err := f.client()
+	if err != nil {
+		return err
+	}
+
+	req := &client.DeleteZoneRequest{Zone: rest[0]}
+	if *force {
+		req.Confirm = rest[0]
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	entry, err := c.DeleteZone(ctx, req)
+	if err != nil {
+		return err
+	}
+	return printResult(f.output, entry, func(w io.Writer) {
+		fmt.Fprintf(w, "Zone %s moved to trash with %d domains (trash id %s, expires %s)\n",
+			entry.Zone, entry.DomainCount, entry.ID, formatTime(entry.ExpiresAt))
+	})
+}
+
+// printZone 输出单个 Zone
+func printZone(format string, zone *client.ZoneDTO) error {
+	return printResult(format, zone, func(w io.Writer) {
+		printZones(w, zone)
+	})
+}
+
+// printZones 以表格输出 Zone
+func printZones(w io.Writer, zones ...*client.ZoneDTO) {
+	row(w, "ZONE", "RECORDS", "APPROVAL", "UPDATED")
+	for _, z := range zones {
+		row(w, z.Zone, z.RecordCount, z.ApprovalRequired, formatTime(z.UpdatedAt))
+	}
+}
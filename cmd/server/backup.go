@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"dancer/internal/models"
+	"dancer/internal/services"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tenant"
+)
+
+// backupPassphraseEnv 未指定口令文件时读取口令的环境变量
+const backupPassphraseEnv = "DANCER_BACKUP_PASSPHRASE"
+
+// runBackup 执行 backup 子命令：导出全量备份到文件
+func runBackup(args []string) int {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	configPath := fs.String("config", "config.toml", "配置文件路径")
+	output := fs.String("o", "", "备份文件路径，默认 dancer-backup-{时间}.json.gz[.enc]")
+	excludeHashes := fs.Bool("exclude-password-hashes", false, "不导出用户密码哈希")
+	passphraseFile := fs.String("passphrase-file", "", "加密口令文件，未指定时读取环境变量 "+backupPassphraseEnv+"，均为空则不加密")
+	tenantID := fs.String("tenant", tenant.Default, "导出的租户")
+	fs.Parse(args)
+
+	passphrase, err := readBackupPassphrase(*passphraseFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read passphrase: %v\n", err)
+		return 1
+	}
+
+	backupService, closeFn, err := newBackupService(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	defer closeFn()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	ctx = tenant.WithTenant(ctx, *tenantID)
+
+	data, err := backupService.Export(ctx, &models.ExportBackupRequest{
+		ExcludePasswordHashes: *excludeHashes,
+		Passphrase:            passphrase,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to export backup: %v\n", err)
+		return 1
+	}
+
+	path := *output
+	if path == "" {
+		path = fmt.Sprintf("dancer-backup-%s.json.gz", time.Now().Format("20060102-150405"))
+		if passphrase != "" {
+			path += ".enc"
+		}
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write backup: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Backup written to %s (%d bytes)\n", path, len(data))
+	return 0
+}
+
+// runRestore 执行 restore 子命令：从备份文件恢复，结果以 JSON 输出
+func runRestore(args []string) int {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	configPath := fs.String("config", "config.toml", "配置文件路径")
+	input := fs.String("i", "", "备份文件路径（必填）")
+	mode := fs.String("mode", string(models.RestoreModeMerge), "恢复模式：replace 或 merge")
+	dryRun := fs.Bool("dry-run", false, "只校验并输出将要进行的修改")
+	passphraseFile := fs.String("passphrase-file", "", "解密口令文件，未指定时读取环境变量 "+backupPassphraseEnv)
+	tenantID := fs.String("tenant", tenant.Default, "恢复到的租户")
+	fs.Parse(args)
+
+	restoreMode := models.RestoreMode(*mode)
+	if *input == "" || (restoreMode != models.RestoreModeReplace && restoreMode != models.RestoreModeMerge) {
+		fs.Usage()
+		return 2
+	}
+
+	passphrase, err := readBackupPassphrase(*passphraseFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read passphrase: %v\n", err)
+		return 1
+	}
+	data, err := os.ReadFile(*input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read backup: %v\n", err)
+		return 1
+	}
+
+	backupService, closeFn, err := newBackupService(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	defer closeFn()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	ctx = tenant.WithTenant(ctx, *tenantID)
+
+	// 命令行以超级管理员身份恢复
+	caller := &models.CurrentUser{UserType: models.UserTypeSuperAdmin, Tenant: tenant.Of(*tenantID)}
+	result, err := backupService.Restore(ctx, caller, data, &models.RestoreBackupRequest{
+		Mode:       restoreMode,
+		DryRun:     *dryRun,
+		Passphrase: passphrase,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to restore backup: %v\n", err)
+		return 1
+	}
+
+	out, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(out))
+	if len(result.Problems) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// newBackupService 连接 etcd，返回备份服务及释放连接的函数
+func newBackupService(configPath string) (*services.BackupService, func(), error) {
+	etcdClient, cfg, err := connectEtcd(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	backupService := services.NewBackupService(
+		etcd.NewBackupStorage(etcdClient, cfg),
+		etcd.NewUserStorage(etcdClient),
+		etcd.NewZoneStorage(etcdClient),
+		etcd.NewDomainStorage(etcdClient, cfg),
+	)
+	return backupService, func() { etcdClient.Close() }, nil
+}
+
+// readBackupPassphrase 读取备份口令，优先使用口令文件，其次使用环境变量
+func readBackupPassphrase(file string) (string, error) {
+	if file == "" {
+		return os.Getenv(backupPassphraseEnv), nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
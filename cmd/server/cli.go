@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"dancer/internal/config"
+	"dancer/internal/logger"
+	"dancer/internal/storage/etcd"
+)
+
+// connectEtcd 为子命令加载配置、初始化日志并连接 etcd
+// 标准输出留给命令结果，控制台日志输出到标准错误
+func connectEtcd(configPath string) (*etcd.Client, *config.Config, error) {
+	if err := config.Load(configPath); err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	cfg := config.GetConfig()
+
+	// 初始化日志期间将标准输出指向标准错误，控制台日志随之输出到标准错误
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	err := logger.Init(
+		cfg.Logger.Level,
+		cfg.Logger.FilePath,
+		cfg.Logger.MaxSize,
+		cfg.Logger.MaxBackup,
+		cfg.Logger.MaxAge,
+	)
+	os.Stdout = stdout
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	etcdClient, err := etcd.NewClient(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize etcd client: %w", err)
+	}
+
+	// 子命令可能先于服务启动执行，读写数据前先完成租户 key 布局的迁移
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := etcd.NewTenantStorage(etcdClient, cfg).MigrateLayout(ctx); err != nil {
+		etcdClient.Close()
+		return nil, nil, fmt.Errorf("failed to migrate tenant layout: %w", err)
+	}
+	return etcdClient, cfg, nil
+}
@@ -10,15 +10,31 @@ import (
 	"time"
 
 	"dancer/internal/config"
+	"dancer/internal/dnsserver"
 	"dancer/internal/handlers"
 	"dancer/internal/logger"
+	"dancer/internal/publisher"
 	"dancer/internal/router"
 	"dancer/internal/services"
+	"dancer/internal/storage"
 	"dancer/internal/storage/etcd"
+	"dancer/internal/tracing"
 )
 
 func main() {
-	// 打印青色 ASCII Logo（最先显示）
+	// 子命令：备份 / 恢复 / 声明式同步，直接读写 etcd，不启动服务
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "backup":
+			os.Exit(runBackup(os.Args[2:]))
+		case "restore":
+			os.Exit(runRestore(os.Args[2:]))
+		case "sync":
+			os.Exit(runSync(os.Args[2:]))
+		}
+	}
+
+	// 打印青色 ASCII Logo
 	fmt.Println("\033[36m")
 	fmt.Println("    ██████╗  █████╗ ███╗   ██╗ ██████╗███████╗██████╗ ")
 	fmt.Println("    ██╔══██╗██╔══██╗████╗  ██║██╔════╝██╔════╝██╔══██╗")
@@ -55,6 +71,12 @@ func main() {
 
 	logger.Log.Info("Starting Dancer DNS Management Tool")
 
+	// 初始化链路追踪
+	shutdownTracing, err := tracing.Init(cfg)
+	if err != nil {
+		logger.Log.WithError(err).Fatal("Failed to initialize tracing")
+	}
+
 	// 初始化 etcd 客户端（允许启动时无连接）
 	etcdClient, err := etcd.NewClient(cfg)
 	if err != nil {
@@ -66,34 +88,135 @@ func main() {
 	userStorage := etcd.NewUserStorage(etcdClient)
 	zoneStorage := etcd.NewZoneStorage(etcdClient)
 	domainStorage := etcd.NewDomainStorage(etcdClient, cfg)
+	tokenStorage := etcd.NewAPITokenStorage(etcdClient)
+	scheduleStorage := etcd.NewScheduleStorage(etcdClient)
+	changeStorage := etcd.NewChangeRequestStorage(etcdClient)
+	eventStorage := etcd.NewEventStorage(etcdClient)
+	webhookStorage := etcd.NewWebhookStorage(etcdClient, cfg)
+	historyStorage := etcd.NewHistoryStorage(etcdClient, cfg)
+	trashStorage := etcd.NewTrashStorage(etcdClient, cfg)
+	backupStorage := etcd.NewBackupStorage(etcdClient, cfg)
+	verificationStorage := etcd.NewVerificationStorage(etcdClient, cfg)
+	publishedStorage := etcd.NewPublishedStorage(etcdClient)
+	tenantStorage := etcd.NewTenantStorage(etcdClient, cfg)
+
+	// 初始化发布目标
+	publishers, err := publisher.NewRegistry(cfg, func(name string) publisher.Publisher { return domainStorage.View(name) })
+	if err != nil {
+		logger.Log.WithError(err).Fatal("Failed to initialize publishers")
+	}
 
 	// 初始化服务层
 	userService := services.NewUserService(userStorage)
-	zoneService := services.NewZoneService(zoneStorage, domainStorage)
+	tenantService := services.NewTenantService(tenantStorage, zoneStorage, userService)
+	zoneService := services.NewZoneService(zoneStorage, domainStorage, trashStorage, publishers)
 	domainService := services.NewDomainService(zoneStorage, domainStorage)
+	tokenService := services.NewAPITokenService(tokenStorage, zoneStorage)
+	registryService := services.NewRegistryService(zoneStorage, domainStorage)
+	scheduleService := services.NewScheduleService(scheduleStorage, zoneStorage, domainService, etcdClient, cfg)
+	changeService := services.NewChangeRequestService(changeStorage, zoneStorage, domainStorage)
+	searchService := services.NewSearchService(etcdClient)
+	eventService := services.NewEventService(eventStorage)
+	webhookService := services.NewWebhookService(webhookStorage, eventStorage, etcdClient, cfg)
+	historyService := services.NewHistoryService(historyStorage, eventStorage, zoneStorage, domainStorage, etcdClient)
+	trashService := services.NewTrashService(trashStorage, zoneStorage, etcdClient)
+	backupService := services.NewBackupService(backupStorage, userStorage, zoneStorage, domainStorage)
+	syncService := services.NewSyncService(zoneService, domainService, changeService, zoneStorage, domainStorage)
+	verificationService := services.NewVerificationService(zoneStorage, domainStorage, verificationStorage, cfg)
+	metricsService := services.NewMetricsService(etcdClient, zoneStorage, searchService)
+	healthService := services.NewHealthService(etcdClient, userService, searchService)
+	publishService := services.NewPublishService(publishedStorage, zoneStorage, etcdClient, publishers)
+	recordCountService := services.NewRecordCountService(zoneStorage, etcdClient)
+
+	// 注册 /metrics 中按需统计的指标
+	metricsService.Register()
+
+	// 后台任务的生命周期与进程一致，退出时统一取消
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	// 启用读缓存（每个副本各自维护）
+	if cfg.Etcd.ReadCache {
+		etcdClient.StartCache(workerCtx, storage.ZoneKeyPrefix, storage.DomainKeyPrefix, storage.InstanceKeyPrefix)
+	}
 
-	// 初始化默认管理员（在后台 goroutine 中执行，避免阻塞启动）
+	// 迁移租户 key 布局、补写 API Token 摘要索引并初始化默认管理员（在后台 goroutine 中执行，避免阻塞启动）
+	// 失败时重试直到成功，完成前 /readyz 返回 503
 	go func() {
-		// 等待 etcd 连接就绪
-		if err := etcdClient.WaitForConnection(30 * time.Second); err != nil {
-			logger.Log.WithError(err).Error("Failed to wait for etcd connection, skipping default admin initialization")
-			return
+		for workerCtx.Err() == nil {
+			// 等待 etcd 连接就绪
+			err := etcdClient.WaitForConnection(30 * time.Second)
+			if err == nil {
+				if err = tenantService.Init(workerCtx); err == nil {
+					if err = tokenService.Init(workerCtx); err == nil {
+						if err = userService.InitDefaultAdmin(workerCtx); err == nil {
+							return
+						}
+					}
+				}
+			}
+			logger.Log.WithError(err).Error("Failed to initialize default admin, retrying")
+
+			select {
+			case <-workerCtx.Done():
+			case <-time.After(5 * time.Second):
+			}
 		}
+	}()
 
-		ctx := context.Background()
-		if err := userService.InitDefaultAdmin(ctx); err != nil {
-			logger.Log.WithError(err).Error("Failed to initialize default admin")
+	// 启动定时变更执行器（多副本部署时仅 leader 执行）
+	go scheduleService.Run(workerCtx)
+
+	// 启动搜索索引同步（每个副本各自维护）
+	go searchService.Run(workerCtx)
+
+	// 启动 Webhook 投递器（多副本部署时仅 leader 投递）
+	go webhookService.Run(workerCtx)
+
+	// 启动 Domain 历史记录器（多副本部署时仅 leader 记录）
+	go historyService.Run(workerCtx)
+
+	// 启动回收站清理（多副本部署时仅 leader 执行）
+	go trashService.Run(workerCtx)
+
+	// 启动外部发布目标同步（多副本部署时仅 leader 执行）
+	go publishService.Run(workerCtx)
+
+	// 启动 Zone 记录数量校正（多副本部署时仅 leader 执行）
+	go recordCountService.Run(workerCtx)
+
+	// 启动内置 DNS 服务器（每个副本各自应答）
+	if cfg.DNS.Enabled {
+		if err := dnsserver.New(cfg, etcdClient).Start(workerCtx); err != nil {
+			logger.Log.WithError(err).Fatal("Failed to start DNS server")
 		}
-	}()
+		logger.Log.Infof("DNS server listening on %s", cfg.DNS.Listen)
+	}
 
 	// 初始化处理器
 	userHandler := handlers.NewUserHandler(userService)
 	zoneHandler := handlers.NewZoneHandler(zoneService)
 	domainHandler := handlers.NewDomainHandler(domainService)
-	healthHandler := handlers.NewHealthHandler(etcdClient)
+	healthHandler := handlers.NewHealthHandler(healthService)
+	tokenHandler := handlers.NewAPITokenHandler(tokenService)
+	registryHandler := handlers.NewRegistryHandler(registryService)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleService)
+	changeHandler := handlers.NewChangeRequestHandler(changeService)
+	searchHandler := handlers.NewSearchHandler(searchService)
+	eventHandler := handlers.NewEventHandler(eventService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	historyHandler := handlers.NewHistoryHandler(historyService)
+	trashHandler := handlers.NewTrashHandler(trashService)
+	backupHandler := handlers.NewBackupHandler(backupService)
+	syncHandler := handlers.NewSyncHandler(syncService)
+	verificationHandler := handlers.NewVerificationHandler(verificationService)
+	publisherHandler := handlers.NewPublisherHandler(publishService)
+	tenantHandler := handlers.NewTenantHandler(tenantService)
+	v2Handler := handlers.NewV2Handler(zoneService, domainService, userService)
 
 	// 初始化路由
-	e := router.New(userHandler, zoneHandler, domainHandler, healthHandler)
+	e := router.New(userHandler, zoneHandler, domainHandler, healthHandler, tokenHandler, registryHandler, scheduleHandler, changeHandler, searchHandler, eventHandler, webhookHandler, historyHandler, trashHandler, backupHandler, syncHandler, verificationHandler, publisherHandler, tenantHandler, v2Handler)
+	e.Server.RegisterOnShutdown(eventHandler.Close)
 
 	// 启动服务器
 	go func() {
@@ -110,9 +233,13 @@ func main() {
 	<-quit
 
 	logger.Log.Info("Shutting down server...")
+	stopWorkers()
 	shutdownCtx := context.Background()
 	if err := e.Shutdown(shutdownCtx); err != nil {
 		logger.Log.WithError(err).Error("Server forced to shutdown")
 	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Log.WithError(err).Error("Failed to flush traces")
+	}
 	logger.Log.Info("Server exited")
 }
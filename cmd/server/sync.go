@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"dancer/internal/models"
+	"dancer/internal/publisher"
+	"dancer/internal/services"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tenant"
+)
+
+// syncProposer 命令行同步提交变更申请时的申请人
+const syncProposer = "sync"
+
+// runSync 执行 sync 子命令：sync plan 输出同步计划，sync apply 应用同步计划
+// 计划有问题或有 Zone 应用失败时返回 1；plan 指定 -detailed-exitcode 时有变更返回 2
+func runSync(args []string) int {
+	if len(args) == 0 || (args[0] != "plan" && args[0] != "apply") {
+		fmt.Fprintln(os.Stderr, "Usage: dancer sync plan|apply -f <file|dir> [-prune] [-json] [-tenant default] [-config config.toml]")
+		return 2
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("sync "+action, flag.ExitOnError)
+	configPath := fs.String("config", "config.toml", "配置文件路径")
+	path := fs.String("f", "", "期望状态文件或目录（必填）")
+	prune := fs.Bool("prune", false, "删除期望状态中没有的 Zone 与 Domain")
+	asJSON := fs.Bool("json", false, "以 JSON 输出计划")
+	detailed := fs.Bool("detailed-exitcode", false, "plan 有变更时返回 2")
+	tenantID := fs.String("tenant", tenant.Default, "同步的租户")
+	fs.Parse(args[1:])
+
+	if *path == "" {
+		fs.Usage()
+		return 2
+	}
+
+	zones, err := services.LoadDesiredState(*path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load desired state: %v\n", err)
+		return 1
+	}
+
+	syncService, closeFn, err := newSyncService(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	defer closeFn()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	ctx = tenant.WithTenant(ctx, *tenantID)
+
+	req := &models.SyncRequest{Prune: *prune, Zones: zones}
+	var plan *models.SyncPlan
+	if action == "apply" {
+		plan, err = syncService.Apply(ctx, syncProposer, req)
+	} else {
+		plan, err = syncService.Plan(ctx, req)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to %s sync: %v\n", action, err)
+		return 1
+	}
+
+	if *asJSON {
+		out, _ := json.MarshalIndent(plan, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		services.WriteSyncPlan(os.Stdout, plan)
+	}
+
+	if len(plan.Problems) > 0 {
+		return 1
+	}
+	for _, zp := range plan.Zones {
+		if zp.Status == models.SyncStatusFailed {
+			return 1
+		}
+	}
+	if *detailed && action == "plan" && !plan.Empty() {
+		return 2
+	}
+	return 0
+}
+
+// newSyncService 连接 etcd，返回同步服务及释放连接的函数
+func newSyncService(configPath string) (*services.SyncService, func(), error) {
+	etcdClient, cfg, err := connectEtcd(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	zoneStorage := etcd.NewZoneStorage(etcdClient)
+	domainStorage := etcd.NewDomainStorage(etcdClient, cfg)
+	publishers, err := publisher.NewRegistry(cfg, func(name string) publisher.Publisher { return domainStorage.View(name) })
+	if err != nil {
+		etcdClient.Close()
+		return nil, nil, err
+	}
+	syncService := services.NewSyncService(
+		services.NewZoneService(zoneStorage, domainStorage, etcd.NewTrashStorage(etcdClient, cfg), publishers),
+		services.NewDomainService(zoneStorage, domainStorage),
+		services.NewChangeRequestService(etcd.NewChangeRequestStorage(etcdClient), zoneStorage, domainStorage),
+		zoneStorage,
+		domainStorage,
+	)
+	return syncService, func() { etcdClient.Close() }, nil
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// apiTokenPrefix API Token 明文前缀，便于识别
+const apiTokenPrefix = "dnc_"
+
+// GenerateAPIToken 生成 API Token，返回明文及其摘要
+func GenerateAPIToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := apiTokenPrefix + hex.EncodeToString(buf)
+	return token, HashAPIToken(token), nil
+}
+
+// HashAPIToken 计算 API Token 摘要，etcd 中只保存摘要
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -12,16 +12,18 @@ type Claims struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	UserType string `json:"user_type"`
+	TenantID string `json:"tenant_id"` // 升级前签发的 JWT 没有该字段，属于默认租户
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID, username, userType string) (string, error) {
+func GenerateToken(userID, username, userType, tenantID string) (string, error) {
 	cfg := config.GetConfig()
 
 	claims := Claims{
 		UserID:   userID,
 		Username: username,
 		UserType: userType,
+		TenantID: tenantID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(cfg.JWT.Expiry) * time.Second)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
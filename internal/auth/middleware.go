@@ -1,11 +1,13 @@
 package auth
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
 	"dancer/internal/errors"
 	"dancer/internal/models"
+	"dancer/internal/tenant"
 	"github.com/labstack/echo/v4"
 )
 
@@ -35,8 +37,67 @@ func JWTMiddleware() echo.MiddlewareFunc {
 			c.Set("user_id", claims.UserID)
 			c.Set("username", claims.Username)
 			c.Set("user_type", claims.UserType)
+			c.Set("tenant_id", tenant.Of(claims.TenantID))
 			c.Set("claims", claims)
 
+			// 存储层按请求 ctx 中的租户读写数据
+			req := c.Request()
+			c.SetRequest(req.WithContext(tenant.WithTenant(req.Context(), claims.TenantID)))
+
+			return next(c)
+		}
+	}
+}
+
+// QueryTokenMiddleware 允许通过查询参数 token 传递 JWT，供无法设置请求头的客户端（如浏览器 EventSource）使用
+// 需放在 JWTMiddleware 之前；请求已带 Authorization 头时不做处理
+// token 会从请求 URL 中移除，避免写入访问日志
+func QueryTokenMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			query := req.URL.Query()
+			if token := query.Get("token"); token != "" {
+				if req.Header.Get("Authorization") == "" {
+					req.Header.Set("Authorization", "Bearer "+token)
+				}
+				query.Del("token")
+				req.URL.RawQuery = query.Encode()
+				req.RequestURI = req.URL.RequestURI()
+			}
+			return next(c)
+		}
+	}
+}
+
+// APITokenMiddleware API Token 认证中间件（服务注册使用）
+// authenticate 根据 Token 明文返回对应的 API Token
+func APITokenMiddleware(authenticate func(ctx context.Context, token string) (*models.APIToken, error)) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			authHeader := c.Request().Header.Get("Authorization")
+			if authHeader == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, errors.ErrUnauthorized)
+			}
+
+			parts := strings.SplitN(authHeader, " ", 2)
+			if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+				return echo.NewHTTPError(http.StatusUnauthorized, errors.ErrInvalidToken)
+			}
+
+			token, err := authenticate(c.Request().Context(), parts[1])
+			if err != nil {
+				if err == errors.ErrEtcdUnavailable {
+					return err
+				}
+				return echo.NewHTTPError(http.StatusUnauthorized, errors.ErrInvalidToken)
+			}
+
+			// 将 Token 信息存入上下文，请求以 Token 所属租户的身份访问数据
+			c.Set("api_token", token)
+			req := c.Request()
+			c.SetRequest(req.WithContext(tenant.WithTenant(req.Context(), token.Tenant)))
+
 			return next(c)
 		}
 	}
@@ -46,8 +107,21 @@ func JWTMiddleware() echo.MiddlewareFunc {
 func RequireAdmin() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			userType := c.Get("user_type")
-			if userType == nil || userType.(string) != string(models.UserTypeAdmin) {
+			userType, _ := c.Get("user_type").(string)
+			if !models.UserType(userType).IsAdmin() {
+				return echo.NewHTTPError(http.StatusForbidden, errors.ErrForbidden)
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireSuperAdmin 超级管理员权限检查中间件
+func RequireSuperAdmin() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userType, _ := c.Get("user_type").(string)
+			if models.UserType(userType) != models.UserTypeSuperAdmin {
 				return echo.NewHTTPError(http.StatusForbidden, errors.ErrForbidden)
 			}
 			return next(c)
@@ -61,5 +135,12 @@ func GetCurrentUser(c echo.Context) *models.CurrentUser {
 		ID:       c.Get("user_id").(string),
 		Username: c.Get("username").(string),
 		UserType: models.UserType(c.Get("user_type").(string)),
+		Tenant:   c.Get("tenant_id").(string),
 	}
 }
+
+// GetAPIToken 从上下文获取当前请求使用的 API Token
+func GetAPIToken(c echo.Context) *models.APIToken {
+	token, _ := c.Get("api_token").(*models.APIToken)
+	return token
+}
@@ -0,0 +1,59 @@
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// 构建时通过 -ldflags 注入，如
+// go build -ldflags "-X dancer/internal/buildinfo.Version=v1.2.0 -X dancer/internal/buildinfo.Commit=$(git rev-parse HEAD)"
+// 未注入时从 Go 工具链记录的构建信息中读取
+var (
+	Version string
+	Commit  string
+)
+
+// startTime 进程启动时间
+var startTime = time.Now()
+
+// Info 构建信息
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Modified  bool   `json:"modified,omitempty"` // 构建时工作区有未提交的修改
+	GoVersion string `json:"go_version"`
+	StartTime int64  `json:"start_time"` // 进程启动时间戳
+	Uptime    int64  `json:"uptime"`     // 运行时长 (秒)
+}
+
+// Get 返回构建信息
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		GoVersion: runtime.Version(),
+		StartTime: startTime.Unix(),
+		Uptime:    int64(time.Since(startTime).Seconds()),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" {
+			info.Version = bi.Main.Version
+		}
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = "unknown"
+	}
+	return info
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/pelletier/go-toml/v2"
 )
@@ -52,7 +53,114 @@ func Load(path string) error {
 	if cfg.Etcd.CorednsPrefix == "" {
 		cfg.Etcd.CorednsPrefix = "/skydns"
 	}
+	if cfg.Etcd.MaxTxnOps == 0 {
+		cfg.Etcd.MaxTxnOps = 128
+	}
+	if cfg.Scheduler.PollInterval == 0 {
+		cfg.Scheduler.PollInterval = 5
+	}
+	if cfg.Webhook.Timeout == 0 {
+		cfg.Webhook.Timeout = 10
+	}
+	if cfg.Webhook.MaxAttempts == 0 {
+		cfg.Webhook.MaxAttempts = 8
+	}
+	if cfg.Webhook.InitialBackoff == 0 {
+		cfg.Webhook.InitialBackoff = 5
+	}
+	if cfg.Webhook.MaxBackoff == 0 {
+		cfg.Webhook.MaxBackoff = 600
+	}
+	if cfg.Webhook.HistorySize == 0 {
+		cfg.Webhook.HistorySize = 100
+	}
+	if cfg.History.MaxVersions == 0 {
+		cfg.History.MaxVersions = 50
+	}
+	if cfg.Trash.Retention == 0 {
+		cfg.Trash.Retention = 7 * 24 * 3600
+	}
+	if cfg.Verify.Protocol == "" {
+		cfg.Verify.Protocol = "udp"
+	}
+	if cfg.Verify.Timeout == 0 {
+		cfg.Verify.Timeout = 3
+	}
+	if cfg.Verify.Concurrency == 0 {
+		cfg.Verify.Concurrency = 8
+	}
+	if cfg.DNS.Listen == "" {
+		cfg.DNS.Listen = ":53"
+	}
+	if cfg.DNS.TTL == 0 {
+		cfg.DNS.TTL = 300
+	}
+	if cfg.DNS.NegativeTTL == 0 {
+		cfg.DNS.NegativeTTL = 30
+	}
+	if cfg.DNS.ForwardTimeout == 0 {
+		cfg.DNS.ForwardTimeout = 3
+	}
+	if cfg.DNS.View == "" {
+		cfg.DNS.View = DefaultView
+	}
+	if err := checkViews(&cfg); err != nil {
+		return err
+	}
+	for i := range cfg.Publishers {
+		p := &cfg.Publishers[i]
+		if p.Protocol == "" {
+			p.Protocol = "tcp"
+		}
+		if p.Timeout == 0 {
+			p.Timeout = 5
+		}
+		if p.TSIGAlgorithm == "" {
+			p.TSIGAlgorithm = "hmac-sha256"
+		}
+		if p.TTL == 0 {
+			p.TTL = 300
+		}
+		if p.View == "" {
+			p.View = DefaultView
+		}
+	}
+	if cfg.Tracing.Exporter == "" {
+		cfg.Tracing.Exporter = "none"
+	}
+	if cfg.Tracing.SampleRatio == nil {
+		ratio := 1.0
+		cfg.Tracing.SampleRatio = &ratio
+	} else if r := *cfg.Tracing.SampleRatio; r < 0 || r > 1 {
+		return fmt.Errorf("tracing sample_ratio must be between 0 and 1, got %v", r)
+	}
+	if cfg.Tracing.ServiceName == "" {
+		cfg.Tracing.ServiceName = "dancer"
+	}
 
 	GlobalConfig = &cfg
 	return nil
 }
+
+// checkViews 检查 CoreDNS 视图配置：名称必填且不重复，前缀必填且互不重叠（包括默认视图的前缀）
+func checkViews(cfg *Config) error {
+	names := map[string]bool{DefaultView: true}
+	prefixes := map[string]string{DefaultView: cfg.Etcd.CorednsPrefix}
+	for _, v := range cfg.Views {
+		if v.Name == "" || v.Prefix == "" {
+			return fmt.Errorf("view name and prefix are required")
+		}
+		if names[v.Name] {
+			return fmt.Errorf("duplicate view name %q", v.Name)
+		}
+		for name, prefix := range prefixes {
+			a, b := strings.TrimSuffix(v.Prefix, "/")+"/", strings.TrimSuffix(prefix, "/")+"/"
+			if strings.HasPrefix(a, b) || strings.HasPrefix(b, a) {
+				return fmt.Errorf("view %q prefix %q overlaps view %q prefix %q", v.Name, v.Prefix, name, prefix)
+			}
+		}
+		names[v.Name] = true
+		prefixes[v.Name] = v.Prefix
+	}
+	return nil
+}
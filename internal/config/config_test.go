@@ -0,0 +1,53 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// loadConfig 写入临时配置文件并加载
+func loadConfig(t *testing.T, content string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Load(path); err != nil {
+		return nil, err
+	}
+	return GlobalConfig, nil
+}
+
+// TestTracingSampleRatio 未配置采样率时默认为 1，显式配置的 0 保持不变，超出范围时报错
+func TestTracingSampleRatio(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    float64
+		wantErr bool
+	}{
+		{"unset", "", 1, false},
+		{"zero", "[tracing]\nsample_ratio = 0\n", 0, false},
+		{"half", "[tracing]\nsample_ratio = 0.5\n", 0.5, false},
+		{"negative", "[tracing]\nsample_ratio = -0.1\n", 0, true},
+		{"above one", "[tracing]\nsample_ratio = 2\n", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadConfig(t, tt.content)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Load succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.Tracing.SampleRatio == nil || *cfg.Tracing.SampleRatio != tt.want {
+				t.Errorf("SampleRatio = %v, want %v", cfg.Tracing.SampleRatio, tt.want)
+			}
+		})
+	}
+}
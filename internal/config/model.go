@@ -16,6 +16,8 @@ type Config struct {
 		HealthCheckInterval  int      `toml:"health_check_interval"`  // 健康检查间隔(秒)
 		DialTimeout          int      `toml:"dial_timeout"`           // 连接超时(秒)
 		CorednsPrefix        string   `toml:"coredns_prefix"`         // CoreDNS etcd key 前缀, 默认 /skydns
+		MaxTxnOps            int      `toml:"max_txn_ops"`            // 单个事务最大操作数, 需与 etcd --max-txn-ops 一致, 默认 128
+		ReadCache            bool     `toml:"read_cache"`             // 是否启用 Zone / Domain 读缓存
 	} `toml:"etcd"`
 
 	JWT struct {
@@ -23,6 +25,56 @@ type Config struct {
 		Expiry int64  `toml:"expiry"`
 	} `toml:"jwt"`
 
+	Scheduler struct {
+		PollInterval int `toml:"poll_interval"` // 定时变更检查间隔(秒)
+	} `toml:"scheduler"`
+
+	Webhook struct {
+		Timeout        int `toml:"timeout"`         // 单次投递超时(秒)
+		MaxAttempts    int `toml:"max_attempts"`    // 最大尝试次数, 用尽后进入死信列表
+		InitialBackoff int `toml:"initial_backoff"` // 首次重试间隔(秒), 之后每次翻倍
+		MaxBackoff     int `toml:"max_backoff"`     // 最大重试间隔(秒)
+		HistorySize    int `toml:"history_size"`    // 每个订阅保留的成功投递记录数
+	} `toml:"webhook"`
+
+	History struct {
+		MaxVersions int `toml:"max_versions"` // 每个 Domain 保留的历史版本数
+	} `toml:"history"`
+
+	Trash struct {
+		Retention int64 `toml:"retention"` // 回收站保留时长(秒), 超过后永久删除
+	} `toml:"trash"`
+
+	Verify struct {
+		Server      string `toml:"server"`      // 校验查询的 DNS 服务器, 如 127.0.0.1:53, 未写端口时为 53, 为空时不启用校验
+		Protocol    string `toml:"protocol"`    // 查询协议: udp / tcp, 默认 udp
+		Timeout     int    `toml:"timeout"`     // 单次查询超时(秒), 默认 3
+		Concurrency int    `toml:"concurrency"` // Zone 校验时同时查询的 Domain 数, 默认 8
+	} `toml:"verify"`
+
+	DNS struct {
+		Enabled        bool     `toml:"enabled"`         // 是否启动内置 DNS 服务器, 小规模部署时可替代 CoreDNS
+		Listen         string   `toml:"listen"`          // 监听地址, 同时监听 UDP 与 TCP, 默认 :53
+		TTL            int      `toml:"ttl"`             // 记录未设置 TTL 时使用的 TTL(秒), 默认 300
+		NegativeTTL    int      `toml:"negative_ttl"`    // NXDOMAIN / NODATA 应答的缓存时间, 即 SOA minimum(秒), 默认 30
+		Nameservers    []string `toml:"nameservers"`     // Zone apex 的 NS 记录, 默认 ns.dns.{zone}
+		Hostmaster     string   `toml:"hostmaster"`      // SOA 中的管理员邮箱, 默认 hostmaster.{zone}
+		Forward        []string `toml:"forward"`         // 非托管域名的上游 DNS 服务器, 为空时返回 REFUSED
+		ForwardTimeout int      `toml:"forward_timeout"` // 转发超时(秒), 默认 3
+		View           string   `toml:"view"`            // 应答的 CoreDNS 视图, 默认 coredns
+	} `toml:"dns"`
+
+	Views      []ViewConfig      `toml:"views"`      // 除默认 coredns 视图外的 CoreDNS 视图, 各自写入独立的 etcd 前缀, Zone 通过 publishers 选择
+	Publishers []PublisherConfig `toml:"publishers"` // 除 CoreDNS 视图外的发布目标, Zone 通过名称选择
+
+	Tracing struct {
+		Exporter    string   `toml:"exporter"`     // 导出方式: none / otlp / stdout, 默认 none
+		Endpoint    string   `toml:"endpoint"`     // OTLP HTTP 地址, 如 localhost:4318, 为空时读取 OTEL_EXPORTER_OTLP_ENDPOINT
+		Insecure    bool     `toml:"insecure"`     // OTLP 使用 HTTP 而非 HTTPS
+		SampleRatio *float64 `toml:"sample_ratio"` // 采样率 [0, 1], 默认 1; 0 时只记录上游已采样的请求
+		ServiceName string   `toml:"service_name"` // 上报的服务名, 默认 dancer
+	} `toml:"tracing"`
+
 	Logger struct {
 		Level     string `toml:"level"`
 		FilePath  string `toml:"file_path"`
@@ -32,6 +84,37 @@ type Config struct {
 	} `toml:"logger"`
 }
 
+// DefaultView 默认 CoreDNS 视图的名称，即内置的 coredns 发布目标
+const DefaultView = "coredns"
+
+// ViewConfig CoreDNS 视图配置, 用于内外网分别解析 (split-horizon)
+// 默认视图 coredns 使用 etcd.coredns_prefix; 每个视图相当于一个 coredns 类型的发布目标
+type ViewConfig struct {
+	Name   string `toml:"name"`   // 名称, Zone 的 publishers 与 Domain 的 view_ips 中引用, 不能为 coredns
+	Prefix string `toml:"prefix"` // CoreDNS etcd key 前缀, 如 /skydns-internal, 不能与其他视图的前缀重叠
+}
+
+// PublisherConfig 发布目标配置
+type PublisherConfig struct {
+	Name string `toml:"name"` // 名称, Zone 的 publishers 中引用, 不能与 CoreDNS 视图重名
+	Type string `toml:"type"` // 类型: rfc2136 / bindfile
+	View string `toml:"view"` // 发布哪个 CoreDNS 视图的记录, 默认 coredns
+
+	// rfc2136: 向主服务器发送 DNS UPDATE
+	Server        string `toml:"server"`         // 接受动态更新的服务器, 如 127.0.0.1:53, 未写端口时为 53
+	Protocol      string `toml:"protocol"`       // 更新请求的协议: udp / tcp, 默认 tcp; 列出记录使用 AXFR, 总是 TCP
+	Timeout       int    `toml:"timeout"`        // 单次请求超时(秒), 默认 5
+	TSIGName      string `toml:"tsig_name"`      // TSIG 密钥名, 为空时不签名
+	TSIGSecret    string `toml:"tsig_secret"`    // TSIG 密钥, base64
+	TSIGAlgorithm string `toml:"tsig_algorithm"` // TSIG 算法, 默认 hmac-sha256
+
+	// bindfile: 在目录中为每个 Zone 生成 {zone}.zone 区域文件
+	Directory   string   `toml:"directory"`   // 区域文件目录
+	Nameservers []string `toml:"nameservers"` // 区域文件中的 NS 记录, 默认 ns.dns.{zone}
+	Hostmaster  string   `toml:"hostmaster"`  // SOA 中的管理员邮箱, 默认 hostmaster.{zone}
+	TTL         int      `toml:"ttl"`         // SOA / NS 记录的 TTL(秒), 默认 300
+}
+
 var GlobalConfig *Config
 
 func GetConfig() *Config {
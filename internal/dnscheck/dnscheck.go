@@ -0,0 +1,167 @@
+package dnscheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// 查询协议
+const (
+	NetworkUDP = "udp"
+	NetworkTCP = "tcp"
+)
+
+// Resolver 向指定的 DNS 服务器查询域名的地址记录
+// 服务器地址可以是 CoreDNS，也可以是测试中用 dns.Server 在本地启动的服务器
+type Resolver struct {
+	server  string
+	network string
+	timeout time.Duration
+}
+
+// NewResolver 创建 Resolver，server 未写端口时使用 53
+func NewResolver(server, network string, timeout time.Duration) *Resolver {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	return &Resolver{server: server, network: network, timeout: timeout}
+}
+
+// Server 查询的 DNS 服务器地址
+func (r *Resolver) Server() string {
+	return r.server
+}
+
+// Network 查询协议
+func (r *Resolver) Network() string {
+	return r.network
+}
+
+// Answer 应答中的一条地址记录
+type Answer struct {
+	Type string // A / AAAA
+	IP   string
+	TTL  int
+}
+
+// Result 一次查询的结果
+type Result struct {
+	Answers       []Answer      // A 与 AAAA 应答，按 IP 排序
+	Authoritative bool          // 两次查询的应答是否都带 AA 标志
+	Rcode         string        // 应答码，NXDOMAIN 时没有地址记录
+	Latency       time.Duration // 两次查询的总耗时
+}
+
+// Lookup 查询 name 的 A 与 AAAA 记录
+// 查询失败或应答码为 NOERROR 与 NXDOMAIN 以外的值（如 SERVFAIL、REFUSED）时返回错误，此时 Result 中只有应答码与耗时
+func (r *Resolver) Lookup(ctx context.Context, name string) (*Result, error) {
+	result := &Result{}
+	start := time.Now()
+	defer func() { result.Latency = time.Since(start) }()
+
+	for i, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		resp, err := r.exchange(ctx, name, qtype)
+		if err != nil {
+			result.Authoritative = false
+			return result, fmt.Errorf("%s query failed: %w", dns.TypeToString[qtype], err)
+		}
+		if result.Rcode == "" || resp.Rcode != dns.RcodeSuccess {
+			result.Rcode = dns.RcodeToString[resp.Rcode]
+		}
+		if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+			return result, fmt.Errorf("%s query returned %s", dns.TypeToString[qtype], result.Rcode)
+		}
+		result.Authoritative = resp.Authoritative && (i == 0 || result.Authoritative)
+
+		// 应答可能先给出 CNAME，只取地址记录
+		for _, rr := range resp.Answer {
+			switch rr := rr.(type) {
+			case *dns.A:
+				result.Answers = append(result.Answers, Answer{Type: "A", IP: rr.A.String(), TTL: int(rr.Hdr.Ttl)})
+			case *dns.AAAA:
+				result.Answers = append(result.Answers, Answer{Type: "AAAA", IP: rr.AAAA.String(), TTL: int(rr.Hdr.Ttl)})
+			}
+		}
+	}
+
+	sort.Slice(result.Answers, func(i, j int) bool {
+		return result.Answers[i].IP < result.Answers[j].IP
+	})
+	return result, nil
+}
+
+// exchange 发送一次查询，UDP 应答被截断时改用 TCP 重新查询
+func (r *Resolver) exchange(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+
+	client := &dns.Client{Net: r.network, Timeout: r.timeout}
+	resp, _, err := client.ExchangeContext(ctx, msg, r.server)
+	if err == nil && resp.Truncated && r.network == NetworkUDP {
+		client.Net = NetworkTCP
+		resp, _, err = client.ExchangeContext(ctx, msg, r.server)
+	}
+	return resp, err
+}
+
+// Diff 应答与期望记录的差异
+type Diff struct {
+	Missing     []string // 期望中有、应答中没有的 IP
+	Unexpected  []string // 应答中有、期望中没有的 IP
+	TTLMismatch []string // TTL 不符合要求的 IP
+}
+
+// Empty 应答与期望是否一致
+func (d *Diff) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Unexpected) == 0 && len(d.TTLMismatch) == 0
+}
+
+// Compare 对比应答与期望的 IP 和 TTL
+// 权威应答的 TTL 必须等于期望值；经过缓存的非权威应答 TTL 会递减，只要求不超过期望值
+func Compare(expectedIPs []string, expectedTTL int, result *Result) *Diff {
+	expected := make(map[string]bool, len(expectedIPs))
+	for _, ip := range expectedIPs {
+		expected[normalizeIP(ip)] = true
+	}
+
+	diff := &Diff{}
+	seen := make(map[string]bool, len(result.Answers))
+	for _, a := range result.Answers {
+		ip := normalizeIP(a.IP)
+		if seen[ip] {
+			continue
+		}
+		seen[ip] = true
+
+		if !expected[ip] {
+			diff.Unexpected = append(diff.Unexpected, ip)
+			continue
+		}
+		if a.TTL > expectedTTL || (result.Authoritative && a.TTL != expectedTTL) {
+			diff.TTLMismatch = append(diff.TTLMismatch, ip)
+		}
+	}
+	for ip := range expected {
+		if !seen[ip] {
+			diff.Missing = append(diff.Missing, ip)
+		}
+	}
+
+	sort.Strings(diff.Missing)
+	sort.Strings(diff.Unexpected)
+	sort.Strings(diff.TTLMismatch)
+	return diff
+}
+
+// normalizeIP 统一 IP 的写法，如 IPv6 的缩写形式；无法解析时原样返回
+func normalizeIP(ip string) string {
+	if parsed := net.ParseIP(ip); parsed != nil {
+		return parsed.String()
+	}
+	return ip
+}
@@ -0,0 +1,145 @@
+package dnscheck
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// startServer 在 127.0.0.1 上启动 UDP DNS 服务器，按 records 权威应答，其他名称返回 NXDOMAIN，
+// servfail 中的名称返回 SERVFAIL；返回服务器地址
+func startServer(t *testing.T, records map[string][]string, servfail string) string {
+	t.Helper()
+
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		resp.Authoritative = true
+
+		q := req.Question[0]
+		rrs, ok := records[q.Name]
+		switch {
+		case q.Name == servfail:
+			resp.Rcode = dns.RcodeServerFailure
+		case !ok:
+			resp.Rcode = dns.RcodeNameError
+		}
+		for _, s := range rrs {
+			rr, err := dns.NewRR(s)
+			if err != nil {
+				t.Errorf("invalid record %q: %v", s, err)
+				continue
+			}
+			if rr.Header().Rrtype == q.Qtype {
+				resp.Answer = append(resp.Answer, rr)
+			}
+		}
+		_ = w.WriteMsg(resp)
+	})
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	started := make(chan struct{})
+	server := &dns.Server{PacketConn: conn, Handler: handler, NotifyStartedFunc: func() { close(started) }}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+	<-started
+
+	return conn.LocalAddr().String()
+}
+
+// TestLookupMatch 应答与期望的 IP 和 TTL 一致
+func TestLookupMatch(t *testing.T) {
+	addr := startServer(t, map[string][]string{
+		"www.example.com.": {
+			"www.example.com. 300 IN A 192.0.2.2",
+			"www.example.com. 300 IN A 192.0.2.1",
+			"www.example.com. 300 IN AAAA 2001:db8::1",
+		},
+	}, "")
+
+	result, err := NewResolver(addr, NetworkUDP, time.Second).Lookup(context.Background(), "www.example.com")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if !result.Authoritative || result.Rcode != "NOERROR" {
+		t.Errorf("authoritative = %v, rcode = %s, want authoritative NOERROR", result.Authoritative, result.Rcode)
+	}
+	want := []Answer{
+		{Type: "A", IP: "192.0.2.1", TTL: 300},
+		{Type: "A", IP: "192.0.2.2", TTL: 300},
+		{Type: "AAAA", IP: "2001:db8::1", TTL: 300},
+	}
+	if !reflect.DeepEqual(result.Answers, want) {
+		t.Errorf("answers = %+v, want %+v", result.Answers, want)
+	}
+
+	// 期望中的 IPv6 使用完整写法
+	diff := Compare([]string{"192.0.2.1", "192.0.2.2", "2001:0db8:0000:0000:0000:0000:0000:0001"}, 300, result)
+	if !diff.Empty() {
+		t.Errorf("diff = %+v, want empty", diff)
+	}
+}
+
+// TestLookupMismatch 应答缺少、多出 IP 或 TTL 不同时报告差异
+func TestLookupMismatch(t *testing.T) {
+	addr := startServer(t, map[string][]string{
+		"www.example.com.": {
+			"www.example.com. 300 IN A 192.0.2.1",
+			"www.example.com. 60 IN A 192.0.2.3",
+			"www.example.com. 300 IN A 198.51.100.1",
+		},
+	}, "")
+
+	result, err := NewResolver(addr, NetworkUDP, time.Second).Lookup(context.Background(), "www.example.com.")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+
+	diff := Compare([]string{"192.0.2.1", "192.0.2.2", "192.0.2.3"}, 300, result)
+	want := &Diff{
+		Missing:     []string{"192.0.2.2"},
+		Unexpected:  []string{"198.51.100.1"},
+		TTLMismatch: []string{"192.0.2.3"},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("diff = %+v, want %+v", diff, want)
+	}
+}
+
+// TestLookupNXDOMAIN 名称不存在时没有地址记录，期望的 IP 全部缺失
+func TestLookupNXDOMAIN(t *testing.T) {
+	addr := startServer(t, nil, "")
+
+	result, err := NewResolver(addr, NetworkUDP, time.Second).Lookup(context.Background(), "missing.example.com")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if result.Rcode != "NXDOMAIN" || len(result.Answers) != 0 {
+		t.Errorf("rcode = %s with %d answers, want NXDOMAIN with none", result.Rcode, len(result.Answers))
+	}
+
+	diff := Compare([]string{"192.0.2.1"}, 300, result)
+	if !reflect.DeepEqual(diff.Missing, []string{"192.0.2.1"}) {
+		t.Errorf("missing = %v, want [192.0.2.1]", diff.Missing)
+	}
+}
+
+// TestLookupServfail SERVFAIL 应答返回错误
+func TestLookupServfail(t *testing.T) {
+	addr := startServer(t, nil, "broken.example.com.")
+
+	result, err := NewResolver(addr, NetworkUDP, time.Second).Lookup(context.Background(), "broken.example.com")
+	if err == nil {
+		t.Fatal("Lookup succeeded, want error")
+	}
+	if result.Rcode != "SERVFAIL" {
+		t.Errorf("rcode = %s, want SERVFAIL", result.Rcode)
+	}
+}
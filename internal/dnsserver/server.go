@@ -0,0 +1,363 @@
+package dnsserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"dancer/internal/config"
+	"dancer/internal/logger"
+	"dancer/internal/metrics"
+	"dancer/internal/storage"
+	"dancer/internal/storage/etcd"
+	"github.com/miekg/dns"
+)
+
+// maxCNAMEChain 在托管 Zone 内追踪 CNAME 的最大次数
+const maxCNAMEChain = 8
+
+// SOA 的刷新参数，没有从服务器时只作展示
+const (
+	soaRefresh = 7200
+	soaRetry   = 1800
+	soaExpire  = 86400
+)
+
+// maxUDPSize 支持 EDNS0 的客户端最多接收的 UDP 应答大小
+const maxUDPSize = 4096
+
+// Server 内置权威 DNS 服务器
+// 从 etcd watch 维护的内存视图应答托管 Zone 的 A / AAAA / CNAME / TXT / SRV / MX 查询，并合成 Zone apex 的 SOA 与 NS；
+// 记录读取自 dns.view 指定的 CoreDNS 视图，与读取同一前缀的 CoreDNS etcd 插件看到的数据一致。其他域名按配置转发或返回 REFUSED
+type Server struct {
+	config     *config.Config
+	etcdClient *etcd.Client
+	view       *view
+	forward    []string // 上游服务器，已补全端口
+
+	servers []*dns.Server
+}
+
+func New(cfg *config.Config, etcdClient *etcd.Client) *Server {
+	prefix, _ := etcdClient.CoreDNSPrefix(cfg.DNS.View)
+	s := &Server{
+		config:     cfg,
+		etcdClient: etcdClient,
+		view:       newView(cfg.DNS.View, prefix),
+	}
+	for _, upstream := range cfg.DNS.Forward {
+		if _, _, err := net.SplitHostPort(upstream); err != nil {
+			upstream = net.JoinHostPort(upstream, "53")
+		}
+		s.forward = append(s.forward, upstream)
+	}
+	return s
+}
+
+// Start 开始同步视图，并在 UDP 与 TCP 上监听；dns.view 未配置或监听失败时返回错误
+// 服务在 ctx 取消时停止；视图完成首次加载前，托管 Zone 的查询返回 SERVFAIL
+func (s *Server) Start(ctx context.Context) error {
+	if s.view.prefix == "" {
+		return fmt.Errorf("unknown view %q", s.view.name)
+	}
+
+	pc, err := net.ListenPacket("udp", s.config.DNS.Listen)
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", s.config.DNS.Listen)
+	if err != nil {
+		pc.Close()
+		return err
+	}
+
+	go s.etcdClient.WatchPrefix(ctx, storage.ZoneKeyPrefix, &zoneWatcher{s.view})
+	go s.etcdClient.WatchPrefix(ctx, s.view.prefix, &recordWatcher{s.view})
+
+	s.servers = []*dns.Server{
+		{PacketConn: pc, Handler: s},
+		{Listener: ln, Handler: s},
+	}
+	for _, srv := range s.servers {
+		go func() {
+			if err := srv.ActivateAndServe(); err != nil {
+				logger.Log.WithError(err).Error("DNS server stopped")
+			}
+		}()
+	}
+
+	go func() {
+		<-ctx.Done()
+		for _, srv := range s.servers {
+			if err := srv.Shutdown(); err != nil {
+				logger.Log.WithError(err).Warn("Failed to shut down DNS server")
+			}
+		}
+	}()
+	return nil
+}
+
+// ServeDNS 实现 dns.Handler
+func (s *Server) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	if len(r.Question) != 1 {
+		s.reply(w, r, new(dns.Msg).SetRcode(r, dns.RcodeFormatError))
+		return
+	}
+
+	q := r.Question[0]
+	zone := s.view.findZone(dns.CanonicalName(q.Name))
+	switch {
+	case zone == "":
+		s.forwardQuery(w, r)
+	case q.Qclass != dns.ClassINET:
+		s.reply(w, r, new(dns.Msg).SetRcode(r, dns.RcodeRefused))
+	case !s.view.ready():
+		s.reply(w, r, new(dns.Msg).SetRcode(r, dns.RcodeServerFailure))
+	default:
+		s.reply(w, r, s.answer(r, zone))
+	}
+}
+
+// answer 应答托管 Zone 内的查询
+// 域名存在但没有所查类型的记录时返回 NODATA（NOERROR 且没有应答），域名不存在时返回 NXDOMAIN，两者都在 authority 中附带 SOA
+func (s *Server) answer(r *dns.Msg, zone string) *dns.Msg {
+	q := r.Question[0]
+	name := dns.CanonicalName(q.Name)
+
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Authoritative = true
+
+	if name == zone {
+		switch q.Qtype {
+		case dns.TypeSOA:
+			m.Answer = []dns.RR{s.soa(zone, uint32(s.config.DNS.TTL))}
+			return m
+		case dns.TypeNS:
+			m.Answer = s.ns(zone)
+			return m
+		}
+	}
+
+	recs, exists := s.view.lookup(name)
+	m.Answer, m.Extra = s.records(q.Name, q.Qtype, recs, 0)
+	if len(m.Answer) == 0 {
+		if !exists && name != zone {
+			m.Rcode = dns.RcodeNameError
+		}
+		m.Ns = []dns.RR{s.soa(zone, uint32(s.config.DNS.NegativeTTL))}
+	}
+	return m
+}
+
+// records 将 owner 下的记录转换为所查类型的应答，返回应答与附加记录
+// 没有地址记录但有 CNAME 时返回 CNAME，目标在托管 Zone 内时继续追踪
+func (s *Server) records(owner string, qtype uint16, recs []*record, depth int) (answer, extra []dns.RR) {
+	switch qtype {
+	case dns.TypeA, dns.TypeAAAA:
+		var cname *record
+		for _, r := range recs {
+			ip := net.ParseIP(r.Host)
+			switch {
+			case ip == nil:
+				if cname == nil && r.Host != "" && !r.Mail && r.Text == "" {
+					cname = r
+				}
+			case qtype == dns.TypeA && ip.To4() != nil:
+				answer = append(answer, &dns.A{Hdr: s.header(owner, dns.TypeA, r), A: ip.To4()})
+			case qtype == dns.TypeAAAA && ip.To4() == nil:
+				answer = append(answer, &dns.AAAA{Hdr: s.header(owner, dns.TypeAAAA, r), AAAA: ip})
+			}
+		}
+		if len(answer) == 0 && cname != nil {
+			answer = s.chaseCNAME(owner, qtype, cname, depth)
+		}
+
+	case dns.TypeCNAME:
+		for _, r := range recs {
+			if r.Host != "" && net.ParseIP(r.Host) == nil && !r.Mail && r.Text == "" {
+				answer = append(answer, &dns.CNAME{Hdr: s.header(owner, dns.TypeCNAME, r), Target: dns.Fqdn(r.Host)})
+				break
+			}
+		}
+
+	case dns.TypeTXT:
+		for _, r := range recs {
+			if r.Text != "" {
+				answer = append(answer, &dns.TXT{Hdr: s.header(owner, dns.TypeTXT, r), Txt: splitText(r.Text)})
+			}
+		}
+
+	case dns.TypeMX:
+		for _, r := range recs {
+			if r.Mail && r.Host != "" {
+				target, glue := s.target(owner, r)
+				answer = append(answer, &dns.MX{Hdr: s.header(owner, dns.TypeMX, r), Preference: uint16(r.Priority), Mx: target})
+				extra = append(extra, glue...)
+			}
+		}
+
+	case dns.TypeSRV:
+		for _, r := range recs {
+			if r.Port > 0 && r.Host != "" {
+				target, glue := s.target(owner, r)
+				answer = append(answer, &dns.SRV{
+					Hdr:      s.header(owner, dns.TypeSRV, r),
+					Priority: uint16(r.Priority),
+					Weight:   uint16(r.Weight),
+					Port:     uint16(r.Port),
+					Target:   target,
+				})
+				extra = append(extra, glue...)
+			}
+		}
+	}
+	return answer, extra
+}
+
+// chaseCNAME 返回 CNAME 及其在托管 Zone 内解析到的记录；目标不在托管 Zone 内时只返回 CNAME，由客户端继续解析
+// depth 为已追踪的次数，超过 maxCNAMEChain 时停止，避免 CNAME 循环
+func (s *Server) chaseCNAME(owner string, qtype uint16, cname *record, depth int) []dns.RR {
+	target := dns.Fqdn(cname.Host)
+	answer := []dns.RR{&dns.CNAME{Hdr: s.header(owner, dns.TypeCNAME, cname), Target: target}}
+	if depth >= maxCNAMEChain || s.view.findZone(dns.CanonicalName(target)) == "" {
+		return answer
+	}
+
+	recs, _ := s.view.lookup(dns.CanonicalName(target))
+	rrs, _ := s.records(target, qtype, recs, depth+1)
+	return append(answer, rrs...)
+}
+
+// target 返回 MX / SRV 记录的目标域名；host 为 IP 时以记录 ID 合成目标域名（如 x1.www.example.com.），并返回对应的地址记录作为附加记录
+func (s *Server) target(owner string, r *record) (string, []dns.RR) {
+	ip := net.ParseIP(r.Host)
+	if ip == nil {
+		return dns.Fqdn(r.Host), nil
+	}
+
+	target := r.id + "." + dns.Fqdn(owner)
+	if ip.To4() != nil {
+		return target, []dns.RR{&dns.A{Hdr: s.header(target, dns.TypeA, r), A: ip.To4()}}
+	}
+	return target, []dns.RR{&dns.AAAA{Hdr: s.header(target, dns.TypeAAAA, r), AAAA: ip}}
+}
+
+// header 生成记录头，记录未设置 TTL 时使用配置的默认 TTL
+func (s *Server) header(owner string, rrtype uint16, r *record) dns.RR_Header {
+	ttl := r.TTL
+	if ttl == 0 {
+		ttl = uint32(s.config.DNS.TTL)
+	}
+	return dns.RR_Header{Name: owner, Rrtype: rrtype, Class: dns.ClassINET, Ttl: ttl}
+}
+
+// soa 合成 Zone 的 SOA 记录，序列号取视图已同步到的 etcd revision
+func (s *Server) soa(zone string, ttl uint32) dns.RR {
+	return &dns.SOA{
+		Hdr:     dns.RR_Header{Name: zone, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: ttl},
+		Ns:      s.nameservers(zone)[0],
+		Mbox:    s.hostmaster(zone),
+		Serial:  s.view.serial(),
+		Refresh: soaRefresh,
+		Retry:   soaRetry,
+		Expire:  soaExpire,
+		Minttl:  uint32(s.config.DNS.NegativeTTL),
+	}
+}
+
+// ns 合成 Zone 的 NS 记录
+func (s *Server) ns(zone string) []dns.RR {
+	nameservers := s.nameservers(zone)
+	rrs := make([]dns.RR, len(nameservers))
+	for i, ns := range nameservers {
+		rrs[i] = &dns.NS{Hdr: dns.RR_Header{Name: zone, Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: uint32(s.config.DNS.TTL)}, Ns: ns}
+	}
+	return rrs
+}
+
+// nameservers 返回 Zone 的名称服务器，未配置时为 ns.dns.{zone}
+func (s *Server) nameservers(zone string) []string {
+	if len(s.config.DNS.Nameservers) == 0 {
+		return []string{"ns.dns." + zone}
+	}
+	nameservers := make([]string, len(s.config.DNS.Nameservers))
+	for i, ns := range s.config.DNS.Nameservers {
+		nameservers[i] = dns.Fqdn(ns)
+	}
+	return nameservers
+}
+
+// hostmaster 返回 SOA 的管理员邮箱（@ 替换为 .），未配置时为 hostmaster.{zone}
+func (s *Server) hostmaster(zone string) string {
+	if s.config.DNS.Hostmaster == "" {
+		return "hostmaster." + zone
+	}
+	return dns.Fqdn(strings.Replace(s.config.DNS.Hostmaster, "@", ".", 1))
+}
+
+// forwardQuery 将非托管域名的查询依次转发到上游服务器，使用与客户端相同的协议
+// 未配置上游时返回 REFUSED，所有上游都失败时返回 SERVFAIL
+func (s *Server) forwardQuery(w dns.ResponseWriter, r *dns.Msg) {
+	if len(s.forward) == 0 {
+		s.reply(w, r, new(dns.Msg).SetRcode(r, dns.RcodeRefused))
+		return
+	}
+
+	client := &dns.Client{Net: w.LocalAddr().Network(), Timeout: time.Duration(s.config.DNS.ForwardTimeout) * time.Second}
+	var lastErr error
+	for _, upstream := range s.forward {
+		resp, _, err := client.Exchange(r, upstream)
+		if err == nil {
+			observe(r, resp, true)
+			if err := w.WriteMsg(resp); err != nil {
+				logger.Log.WithError(err).Debug("Failed to write DNS response")
+			}
+			return
+		}
+		lastErr = err
+	}
+
+	logger.Log.WithError(lastErr).WithField("name", r.Question[0].Name).Warn("Failed to forward DNS query")
+	s.reply(w, r, new(dns.Msg).SetRcode(r, dns.RcodeServerFailure))
+}
+
+// reply 写入本服务器生成的应答，请求带 EDNS0 时附带 OPT；UDP 应答超过客户端可接收的大小时截断
+func (s *Server) reply(w dns.ResponseWriter, r *dns.Msg, m *dns.Msg) {
+	size := dns.MinMsgSize
+	if opt := r.IsEdns0(); opt != nil {
+		size = min(max(int(opt.UDPSize()), dns.MinMsgSize), maxUDPSize)
+		m.SetEdns0(maxUDPSize, false)
+	}
+	if w.LocalAddr().Network() == "udp" {
+		m.Truncate(size)
+	}
+
+	observe(r, m, false)
+
+	if err := w.WriteMsg(m); err != nil && !errors.Is(err, net.ErrClosed) {
+		logger.Log.WithError(err).Debug("Failed to write DNS response")
+	}
+}
+
+// observe 记录查询指标
+func observe(r, m *dns.Msg, forwarded bool) {
+	qtype := "other"
+	if len(r.Question) > 0 {
+		qtype = dns.TypeToString[r.Question[0].Qtype]
+	}
+	metrics.ObserveDNSQuery(qtype, dns.RcodeToString[m.Rcode], forwarded)
+}
+
+// splitText 将 TXT 内容按 255 字节拆分为多个字符串
+func splitText(text string) []string {
+	var parts []string
+	for len(text) > 255 {
+		parts = append(parts, text[:255])
+		text = text[255:]
+	}
+	return append(parts, text)
+}
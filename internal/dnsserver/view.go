@@ -0,0 +1,252 @@
+package dnsserver
+
+import (
+	"encoding/json"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"github.com/miekg/dns"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// record CoreDNS etcd 插件格式的记录，字段与 CoreDNS 的 msg.Service 一致
+// host 为 IP 时是 A / AAAA 记录，为域名时是 CNAME 记录；mail 为 true 时是 MX 记录；port 不为 0 时可作为 SRV 记录；text 不为空时是 TXT 记录
+type record struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`
+	Priority int    `json:"priority,omitempty"`
+	Weight   int    `json:"weight,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Mail     bool   `json:"mail,omitempty"`
+	TTL      uint32 `json:"ttl,omitempty"`
+
+	key  string // etcd key
+	name string // 所属域名，小写 FQDN
+	id   string // key 的最后一段，如 x1，用于合成 SRV / MX 的目标域名
+}
+
+// view 托管 Zone 与 CoreDNS 记录的内存视图，通过 etcd watch 与存储保持一致，查询不访问 etcd
+type view struct {
+	name   string // CoreDNS 视图名称
+	prefix string // CoreDNS key 前缀
+
+	mu        sync.RWMutex
+	zones     map[string]bool               // 托管 Zone，小写 FQDN
+	records   map[string]*record            // etcd key -> 记录
+	names     map[string]map[string]*record // 域名 -> etcd key -> 记录
+	nodes     map[string]int                // 域名及其各级上级域名 -> 下属记录数，用于区分 NXDOMAIN 与 NODATA
+	revision  int64
+	zonesOK   bool // Zone 已完成首次加载
+	recordsOK bool // 记录已完成首次加载
+}
+
+func newView(name, prefix string) *view {
+	return &view{
+		name:    name,
+		prefix:  prefix,
+		zones:   make(map[string]bool),
+		records: make(map[string]*record),
+		names:   make(map[string]map[string]*record),
+		nodes:   make(map[string]int),
+	}
+}
+
+// ready 是否已完成首次加载
+func (v *view) ready() bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.zonesOK && v.recordsOK
+}
+
+// findZone 返回 name 所属的托管 Zone（最长匹配），不属于任何托管 Zone 时返回空串
+func (v *view) findZone(name string) string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	for off, end := 0, false; !end; off, end = dns.NextLabel(name, off) {
+		if v.zones[name[off:]] {
+			return name[off:]
+		}
+	}
+	return ""
+}
+
+// lookup 返回 name 下的记录（按 key 排序），以及 name 是否存在（有记录，或者是有记录的域名的上级）
+// name 没有记录时，按 SRV / MX 合成的目标域名（记录 ID + 上级域名，如 x1.www.example.com.）查找对应的地址记录
+func (v *view) lookup(name string) ([]*record, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if len(v.names[name]) == 0 {
+		if off, end := dns.NextLabel(name, 0); !end {
+			for _, r := range v.names[name[off:]] {
+				if strings.EqualFold(r.id, name[:off-1]) && net.ParseIP(r.Host) != nil {
+					return []*record{r}, true
+				}
+			}
+		}
+	}
+
+	recs := make([]*record, 0, len(v.names[name]))
+	for _, r := range v.names[name] {
+		recs = append(recs, r)
+	}
+	sort.Slice(recs, func(i, j int) bool {
+		return recs[i].key < recs[j].key
+	})
+	return recs, v.nodes[name] > 0
+}
+
+// serial SOA 序列号，取视图已同步到的 etcd revision
+func (v *view) serial() uint32 {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return uint32(v.revision)
+}
+
+// putRecord 写入记录，调用方需持有写锁
+func (v *view) putRecord(r *record) {
+	v.deleteRecord(r.key)
+	v.records[r.key] = r
+	if v.names[r.name] == nil {
+		v.names[r.name] = make(map[string]*record)
+	}
+	v.names[r.name][r.key] = r
+	for off, end := 0, false; !end; off, end = dns.NextLabel(r.name, off) {
+		v.nodes[r.name[off:]]++
+	}
+}
+
+// deleteRecord 删除记录，调用方需持有写锁
+func (v *view) deleteRecord(key string) {
+	old, ok := v.records[key]
+	if !ok {
+		return
+	}
+	delete(v.records, key)
+	delete(v.names[old.name], key)
+	if len(v.names[old.name]) == 0 {
+		delete(v.names, old.name)
+	}
+	for off, end := 0, false; !end; off, end = dns.NextLabel(old.name, off) {
+		name := old.name[off:]
+		if v.nodes[name]--; v.nodes[name] <= 0 {
+			delete(v.nodes, name)
+		}
+	}
+}
+
+// decodeRecord 解码 CoreDNS 记录
+// key 形如 {prefix}/com/example/www/x1：最后一段为记录 ID，其余各段反转后为域名 www.example.com
+func (v *view) decodeRecord(kv *mvccpb.KeyValue) (*record, bool) {
+	parts := strings.Split(strings.TrimPrefix(string(kv.Key), v.prefix), "/")
+	if len(parts) < 2 {
+		return nil, false
+	}
+
+	var r record
+	if err := json.Unmarshal(kv.Value, &r); err != nil {
+		return nil, false
+	}
+	r.key = string(kv.Key)
+	r.id = parts[len(parts)-1]
+
+	labels := parts[:len(parts)-1]
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+	r.name = dns.CanonicalName(strings.Join(labels, "."))
+	return &r, true
+}
+
+// zoneWatcher 将 /dancer/zones/ 的变化同步到视图
+type zoneWatcher struct {
+	v *view
+}
+
+func (w *zoneWatcher) Reset(kvs []*mvccpb.KeyValue, revision int64) {
+	w.v.mu.Lock()
+	defer w.v.mu.Unlock()
+
+	w.v.zones = make(map[string]bool, len(kvs))
+	for _, kv := range kvs {
+		if servesZone(kv.Value, w.v.name) {
+			w.v.zones[zoneName(kv.Key)] = true
+		}
+	}
+	w.v.revision = max(w.v.revision, revision)
+	w.v.zonesOK = true
+}
+
+func (w *zoneWatcher) Apply(events []*clientv3.Event, revision int64) {
+	w.v.mu.Lock()
+	defer w.v.mu.Unlock()
+
+	for _, ev := range events {
+		if ev.Type == clientv3.EventTypeDelete || !servesZone(ev.Kv.Value, w.v.name) {
+			delete(w.v.zones, zoneName(ev.Kv.Key))
+		} else {
+			w.v.zones[zoneName(ev.Kv.Key)] = true
+		}
+	}
+	w.v.revision = max(w.v.revision, revision)
+}
+
+// servesZone Zone 是否发布到视图；只有写入视图记录的 Zone 由内置 DNS 服务器应答，其余按非托管域名处理
+func servesZone(value []byte, name string) bool {
+	var zone models.Zone
+	if err := json.Unmarshal(value, &zone); err != nil {
+		return false
+	}
+	return zone.PublishesTo(name)
+}
+
+// zoneName 由 Zone key 得到小写 FQDN
+func zoneName(key []byte) string {
+	_, zone, _ := storage.SplitZoneKey(string(key))
+	return dns.CanonicalName(zone)
+}
+
+// recordWatcher 将 CoreDNS 记录的变化同步到视图
+type recordWatcher struct {
+	v *view
+}
+
+func (w *recordWatcher) Reset(kvs []*mvccpb.KeyValue, revision int64) {
+	w.v.mu.Lock()
+	defer w.v.mu.Unlock()
+
+	w.v.records = make(map[string]*record, len(kvs))
+	w.v.names = make(map[string]map[string]*record)
+	w.v.nodes = make(map[string]int)
+	for _, kv := range kvs {
+		if r, ok := w.v.decodeRecord(kv); ok {
+			w.v.putRecord(r)
+		}
+	}
+	w.v.revision = max(w.v.revision, revision)
+	w.v.recordsOK = true
+}
+
+func (w *recordWatcher) Apply(events []*clientv3.Event, revision int64) {
+	w.v.mu.Lock()
+	defer w.v.mu.Unlock()
+
+	for _, ev := range events {
+		if ev.Type == clientv3.EventTypeDelete {
+			w.v.deleteRecord(string(ev.Kv.Key))
+			continue
+		}
+		if r, ok := w.v.decodeRecord(ev.Kv); ok {
+			w.v.putRecord(r)
+		} else {
+			w.v.deleteRecord(string(ev.Kv.Key))
+		}
+	}
+	w.v.revision = max(w.v.revision, revision)
+}
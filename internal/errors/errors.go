@@ -19,11 +19,66 @@ var (
 	// Zone 相关错误
 	ErrZoneNotFound = errors.New("zone not found")
 	ErrZoneExists   = errors.New("zone already exists")
+	ErrZoneNotEmpty = errors.New("zone still has domains, set confirm to the zone name to delete it")
 
 	// Domain 相关错误
 	ErrDomainNotFound = errors.New("domain not found")
 	ErrDomainExists   = errors.New("domain already exists")
 
+	// 租约相关错误
+	ErrDomainNotEphemeral = errors.New("domain is not bound to a lease")
+
+	// 服务注册相关错误
+	ErrTokenNotFound    = errors.New("api token not found")
+	ErrInstanceNotFound = errors.New("instance not found")
+
+	// 定时变更相关错误
+	ErrScheduleNotFound   = errors.New("scheduled change not found")
+	ErrScheduleNotPending = errors.New("scheduled change is no longer pending")
+	ErrScheduleConflict   = errors.New("scheduled change was modified concurrently")
+
+	// 变更审批相关错误
+	ErrApprovalRequired        = errors.New("zone requires changes to go through an approved change request")
+	ErrChangeRequestNotFound   = errors.New("change request not found")
+	ErrChangeRequestNotPending = errors.New("change request is no longer pending")
+	ErrSelfApproval            = errors.New("change request cannot be approved by its proposer")
+	ErrChangeConflict          = errors.New("domains changed concurrently, change was not applied")
+
+	// 搜索相关错误
+	ErrSearchIndexNotReady = errors.New("search index is still loading")
+
+	// 事件流相关错误
+	ErrRevisionCompacted = errors.New("requested revision has been compacted, re-list and resubscribe")
+
+	// Webhook 相关错误
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrDeliveryConflict = errors.New("webhook delivery was modified concurrently")
+
+	// 版本历史相关错误
+	ErrVersionNotFound = errors.New("domain version not found")
+
+	// 回收站相关错误
+	ErrTrashEntryNotFound = errors.New("trash entry not found")
+
+	// 备份恢复相关错误
+	ErrInvalidBackup            = errors.New("invalid or unsupported backup archive")
+	ErrBackupPassphraseRequired = errors.New("backup archive is encrypted, passphrase required")
+	ErrBackupDecryptFailed      = errors.New("failed to decrypt backup archive, wrong passphrase or corrupted data")
+
+	// DNS 校验相关错误
+	ErrVerificationNotConfigured = errors.New("DNS verification is not configured, set verify.server")
+	ErrVerificationNotFound      = errors.New("domain has not been verified yet")
+
+	// 发布目标相关错误
+	ErrUnknownPublisher = errors.New("publisher is not configured")
+	ErrUnknownView      = errors.New("view is not configured")
+
+	// 租户相关错误
+	ErrTenantNotFound = errors.New("tenant not found")
+	ErrTenantExists   = errors.New("tenant already exists")
+	ErrTenantNotEmpty = errors.New("tenant still has zones, delete them first")
+
 	// 其他业务错误
 	ErrCannotDeleteDefaultAdmin = errors.New("cannot delete default admin user")
 
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"dancer/internal/auth"
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// maxBackupSize 允许上传的备份文件大小上限
+const maxBackupSize = 256 << 20
+
+// BackupHandler 备份与恢复 HTTP 处理器
+type BackupHandler struct {
+	backupService *services.BackupService
+	validate      *validator.Validate
+}
+
+func NewBackupHandler(backupService *services.BackupService) *BackupHandler {
+	return &BackupHandler{
+		backupService: backupService,
+		validate:      validator.New(),
+	}
+}
+
+// ExportBackup 导出全量备份，以文件下载的形式返回（Admin）
+func (h *BackupHandler) ExportBackup(c echo.Context) error {
+	var req models.ExportBackupRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	data, err := h.backupService.Export(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to export backup")
+		return err
+	}
+
+	filename := fmt.Sprintf("dancer-backup-%s.json.gz", time.Now().Format("20060102-150405"))
+	if req.Passphrase != "" {
+		filename += ".enc"
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return c.Blob(200, echo.MIMEOctetStream, data)
+}
+
+// RestoreBackup 从上传的备份文件恢复（Admin）
+func (h *BackupHandler) RestoreBackup(c echo.Context) error {
+	var req models.RestoreBackupRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	file, err := c.FormFile("archive")
+	if err != nil || file.Size > maxBackupSize {
+		return apperrors.ErrInvalidInput
+	}
+	src, err := file.Open()
+	if err != nil {
+		return apperrors.ErrInvalidInput
+	}
+	defer src.Close()
+	data, err := io.ReadAll(io.LimitReader(src, maxBackupSize))
+	if err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	result, err := h.backupService.Restore(c.Request().Context(), auth.GetCurrentUser(c), data, &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to restore backup")
+		return err
+	}
+
+	return c.JSON(200, result)
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"dancer/internal/auth"
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// ChangeRequestHandler 变更申请 HTTP 处理器
+type ChangeRequestHandler struct {
+	changeService *services.ChangeRequestService
+	validate      *validator.Validate
+}
+
+func NewChangeRequestHandler(changeService *services.ChangeRequestService) *ChangeRequestHandler {
+	return &ChangeRequestHandler{
+		changeService: changeService,
+		validate:      validator.New(),
+	}
+}
+
+// toChangeRequestDTO 将 ChangeRequest 转换为 ChangeRequestDTO
+func toChangeRequestDTO(cr *models.ChangeRequest) *models.ChangeRequestDTO {
+	return &models.ChangeRequestDTO{
+		ID:            cr.ID,
+		Zone:          cr.Zone,
+		Title:         cr.Title,
+		Changes:       cr.Changes,
+		Status:        cr.Status,
+		ProposedBy:    cr.ProposedBy,
+		ReviewedBy:    cr.ReviewedBy,
+		ReviewComment: cr.ReviewComment,
+		Error:         cr.Error,
+		CreatedAt:     cr.CreatedAt,
+		UpdatedAt:     cr.UpdatedAt,
+		ReviewedAt:    cr.ReviewedAt,
+		AppliedAt:     cr.AppliedAt,
+	}
+}
+
+// ListChangeRequests 列出变更申请
+func (h *ChangeRequestHandler) ListChangeRequests(c echo.Context) error {
+	var req models.ListChangeRequestsRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	changes, err := h.changeService.ListChangeRequests(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to list change requests")
+		return err
+	}
+
+	dtos := make([]*models.ChangeRequestDTO, len(changes))
+	for i, cr := range changes {
+		dtos[i] = toChangeRequestDTO(cr)
+	}
+
+	return c.JSON(200, &models.ChangeRequestListDTO{ChangeRequests: dtos})
+}
+
+// GetChangeRequest 获取变更申请详情，待审批的申请附带与当前状态的差异
+func (h *ChangeRequestHandler) GetChangeRequest(c echo.Context) error {
+	var req models.GetChangeRequestRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	ctx := c.Request().Context()
+	cr, err := h.changeService.GetChangeRequest(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	dto := toChangeRequestDTO(cr)
+	if cr.Status == models.ChangeRequestPending {
+		diff, err := h.changeService.DiffChangeRequest(ctx, cr)
+		if err != nil {
+			logger.Log.WithError(err).Error("Failed to diff change request")
+			return err
+		}
+		dto.Diff = diff
+	}
+
+	return c.JSON(200, dto)
+}
+
+// ProposeChangeRequest 提交变更申请
+func (h *ChangeRequestHandler) ProposeChangeRequest(c echo.Context) error {
+	var req models.ProposeChangeRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	currentUser := auth.GetCurrentUser(c)
+
+	cr, err := h.changeService.ProposeChangeRequest(c.Request().Context(), currentUser.ID, &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to propose change request")
+		return err
+	}
+
+	return c.JSON(200, toChangeRequestDTO(cr))
+}
+
+// ApproveChangeRequest 批准并应用变更申请
+func (h *ChangeRequestHandler) ApproveChangeRequest(c echo.Context) error {
+	var req models.ReviewChangeRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	currentUser := auth.GetCurrentUser(c)
+
+	cr, err := h.changeService.ApproveChangeRequest(c.Request().Context(), currentUser.ID, &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to approve change request")
+		return err
+	}
+
+	return c.JSON(200, toChangeRequestDTO(cr))
+}
+
+// RejectChangeRequest 驳回变更申请
+func (h *ChangeRequestHandler) RejectChangeRequest(c echo.Context) error {
+	var req models.ReviewChangeRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	currentUser := auth.GetCurrentUser(c)
+
+	cr, err := h.changeService.RejectChangeRequest(c.Request().Context(), currentUser.ID, &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to reject change request")
+		return err
+	}
+
+	return c.JSON(200, toChangeRequestDTO(cr))
+}
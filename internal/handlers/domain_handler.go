@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	apperrors "dancer/internal/errors"
 	"dancer/internal/logger"
 	"dancer/internal/models"
 	"dancer/internal/services"
@@ -28,37 +29,41 @@ func toDomainDTO(domain *models.Domain) *models.DomainDTO {
 		Domain:      domain.Domain,
 		Name:        domain.Name,
 		IPs:         domain.IPs,
+		ViewIPs:     domain.ViewIPs,
+		DynamicIPs:  domain.DynamicIPs,
 		TTL:         domain.TTL,
 		RecordCount: domain.RecordCount,
+		LeaseTTL:    domain.LeaseTTL,
+		ExpiresAt:   domain.ExpiresAt,
 		CreatedAt:   domain.CreatedAt,
 		UpdatedAt:   domain.UpdatedAt,
 	}
 }
 
-// ListDomains 列出 Zone 下所有 Domain
+// ListDomains 列出 Zone 下的 Domain
 func (h *DomainHandler) ListDomains(c echo.Context) error {
 	var req models.ListDomainsRequest
 	if err := c.Bind(&req); err != nil {
-		return err
+		return apperrors.ErrInvalidInput
 	}
 
 	if err := h.validate.Struct(req); err != nil {
-		return err
+		return apperrors.ErrInvalidInput
 	}
 
-	domains, err := h.domainService.ListDomains(c.Request().Context(), &req)
+	page, err := h.domainService.ListDomains(c.Request().Context(), &req)
 	if err != nil {
 		logger.Log.WithError(err).Error("Failed to list domains")
 		return err
 	}
 
 	// 转换为 DTO
-	dtos := make([]*models.DomainDTO, len(domains))
-	for i, domain := range domains {
+	dtos := make([]*models.DomainDTO, len(page.Items))
+	for i, domain := range page.Items {
 		dtos[i] = toDomainDTO(domain)
 	}
 
-	return c.JSON(200, &models.DomainListDTO{Domains: dtos})
+	return c.JSON(200, &models.DomainListDTO{Domains: dtos, Total: page.Total, NextCursor: page.NextCursor})
 }
 
 // GetDomain 获取 Domain 详情
@@ -142,3 +147,82 @@ func (h *DomainHandler) DeleteDomain(c echo.Context) error {
 		Message: "Domain deleted successfully",
 	})
 }
+
+// RenewDomain 续约临时 Domain
+func (h *DomainHandler) RenewDomain(c echo.Context) error {
+	var req models.RenewDomainRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return err
+	}
+
+	domain, err := h.domainService.RenewDomain(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to renew domain")
+		return err
+	}
+
+	return c.JSON(200, toDomainDTO(domain))
+}
+
+// BatchDomains 批量创建 / 更新 / 删除 Domain
+func (h *DomainHandler) BatchDomains(c echo.Context) error {
+	var req models.BatchDomainsRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+	if req.Mode == "" {
+		req.Mode = models.BatchModeAtomic
+	}
+
+	// 逐项校验格式，失败项在结果中单独报告
+	invalid := make([]error, len(req.Items))
+	for i, item := range req.Items {
+		if item != nil {
+			invalid[i] = h.validate.Struct(item)
+		}
+	}
+
+	results, err := h.domainService.BatchDomains(c.Request().Context(), &req, invalid)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to apply domain batch")
+		return err
+	}
+
+	resp := &models.BatchDomainsResultDTO{
+		Zone:    req.Zone,
+		Mode:    req.Mode,
+		Results: make([]*models.BatchItemResultDTO, len(results)),
+	}
+	for i, r := range results {
+		dto := &models.BatchItemResultDTO{
+			Index:  r.Index,
+			Op:     r.Op,
+			Domain: r.Domain,
+			Status: r.Status,
+			Error:  r.Error,
+		}
+		if r.Result != nil {
+			dto.Result = toDomainDTO(r.Result)
+		}
+		resp.Results[i] = dto
+
+		switch r.Status {
+		case models.BatchItemSucceeded:
+			resp.Succeeded++
+		case models.BatchItemFailed:
+			resp.Failed++
+		case models.BatchItemSkipped:
+			resp.Skipped++
+		}
+	}
+
+	return c.JSON(200, resp)
+}
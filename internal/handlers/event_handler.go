@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"dancer/internal/auth"
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// eventHeartbeatInterval 事件流心跳间隔，避免空闲连接被代理断开
+const eventHeartbeatInterval = 15 * time.Second
+
+// EventHandler 变更事件流 HTTP 处理器
+type EventHandler struct {
+	eventService *services.EventService
+	validate     *validator.Validate
+
+	// 服务关闭时结束所有事件流，否则长连接会阻塞优雅退出
+	closed context.Context
+	close  context.CancelFunc
+}
+
+func NewEventHandler(eventService *services.EventService) *EventHandler {
+	closed, closeStreams := context.WithCancel(context.Background())
+	return &EventHandler{
+		eventService: eventService,
+		validate:     validator.New(),
+		closed:       closed,
+		close:        closeStreams,
+	}
+}
+
+// Close 结束所有事件流
+func (h *EventHandler) Close() {
+	h.close()
+}
+
+func toChangeEventDTO(event *models.ChangeEvent) *models.ChangeEventDTO {
+	dto := &models.ChangeEventDTO{
+		Revision: event.Revision,
+		Type:     event.Type,
+		Op:       event.Op,
+		Zone:     event.ZoneName,
+		Name:     event.Name,
+	}
+	switch {
+	case event.Zone != nil:
+		dto.Data = toZoneDTO(event.Zone)
+	case event.Domain != nil:
+		dto.Data = toDomainDTO(event.Domain)
+	case event.User != nil:
+		dto.Data = toUserDTO(event.User)
+	}
+	return dto
+}
+
+// StreamEvents 以 Server-Sent Events 推送 Zone / Domain / 用户变更
+// 连接建立后先推送 ready 事件（携带起始 revision），之后每个变更一条消息，消息 id 为 revision
+// 断线重连时通过 revision 参数或 Last-Event-ID 头从上次收到的 revision 之后继续
+// 所需历史已被压缩时推送 reset 事件并关闭连接，客户端需重新全量读取后不带 revision 重新订阅
+func (h *EventHandler) StreamEvents(c echo.Context) error {
+	var req models.StreamEventsRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if lastID := c.Request().Header.Get("Last-Event-ID"); req.Revision == 0 && lastID != "" {
+		revision, err := strconv.ParseInt(lastID, 10, 64)
+		if err != nil || revision < 0 {
+			return apperrors.ErrInvalidInput
+		}
+		req.Revision = revision
+	}
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+	defer context.AfterFunc(h.closed, cancel)()
+
+	revision, err := h.eventService.StartRevision(ctx, req.Revision)
+	if err != nil {
+		return err
+	}
+
+	resp := c.Response()
+	resp.Header().Set(echo.HeaderContentType, "text/event-stream")
+	resp.Header().Set(echo.HeaderCacheControl, "no-cache")
+	resp.Header().Set(echo.HeaderConnection, "keep-alive")
+	resp.Header().Set("X-Accel-Buffering", "no")
+	resp.WriteHeader(http.StatusOK)
+
+	stream := &eventStream{resp: resp}
+	if err := stream.send("ready", revision, map[string]int64{"revision": revision}); err != nil {
+		return nil
+	}
+
+	// 心跳
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(eventHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = stream.heartbeat()
+			}
+		}
+	}()
+
+	err = h.eventService.Stream(ctx, &req, auth.GetCurrentUser(c), revision, func(event *models.ChangeEvent) error {
+		return stream.send("", event.Revision, toChangeEventDTO(event))
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err == apperrors.ErrRevisionCompacted {
+		_ = stream.send("reset", 0, &Response{Code: "revision_compacted", Message: err.Error()})
+		return nil
+	}
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to stream events")
+	}
+	return nil
+}
+
+// eventStream SSE 输出，事件与心跳可能在不同 goroutine 中写入
+type eventStream struct {
+	mu   sync.Mutex
+	resp *echo.Response
+}
+
+// send 写入一条事件，name 为空时使用默认的 message 事件，id 为 0 时不设置事件 id
+func (s *eventStream) send(name string, id int64, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id > 0 {
+		if _, err := fmt.Fprintf(s.resp, "id: %d\n", id); err != nil {
+			return err
+		}
+	}
+	if name != "" {
+		if _, err := fmt.Fprintf(s.resp, "event: %s\n", name); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(s.resp, "data: %s\n\n", payload); err != nil {
+		return err
+	}
+	s.resp.Flush()
+	return nil
+}
+
+// heartbeat 写入 SSE 注释行作为心跳
+func (s *eventStream) heartbeat() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := fmt.Fprint(s.resp, ": ping\n\n"); err != nil {
+		return err
+	}
+	s.resp.Flush()
+	return nil
+}
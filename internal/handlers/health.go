@@ -3,44 +3,48 @@ package handlers
 import (
 	"net/http"
 
-	"dancer/internal/storage/etcd"
+	"dancer/internal/services"
 	"github.com/labstack/echo/v4"
 )
 
 // HealthHandler 健康检查处理器
 type HealthHandler struct {
-	etcdClient *etcd.Client
+	healthService *services.HealthService
 }
 
 // NewHealthHandler 创建健康检查处理器
-func NewHealthHandler(etcdClient *etcd.Client) *HealthHandler {
+func NewHealthHandler(healthService *services.HealthService) *HealthHandler {
 	return &HealthHandler{
-		etcdClient: etcdClient,
+		healthService: healthService,
 	}
 }
 
 // Check 健康检查
 // 所有 components 为 up 时返回 200，任一 component 为 down 时返回 503
 func (h *HealthHandler) Check(c echo.Context) error {
-	// 检查 etcd 状态
-	etcdStatus := "up"
-	if !h.etcdClient.IsConnected() {
-		etcdStatus = "down"
-	}
+	return respondHealth(c, h.healthService.EtcdHealth())
+}
 
-	// 计算总状态
-	overallStatus := "up"
-	httpStatus := http.StatusOK
+// Livez 存活检查，进程能处理请求即返回 200
+func (h *HealthHandler) Livez(c echo.Context) error {
+	return respondHealth(c, h.healthService.Liveness())
+}
+
+// Readyz 就绪检查，etcd 未连接、默认管理员未初始化或搜索索引未加载时返回 503
+func (h *HealthHandler) Readyz(c echo.Context) error {
+	return respondHealth(c, h.healthService.Readiness())
+}
+
+// Details 详细健康状态（管理员），始终返回 200，整体状态见 status
+func (h *HealthHandler) Details(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.healthService.Details(c.Request().Context()))
+}
 
-	if etcdStatus == "down" {
-		overallStatus = "down"
+// respondHealth 按整体状态返回 200 或 503
+func respondHealth(c echo.Context, status *services.HealthStatus) error {
+	httpStatus := http.StatusOK
+	if status.Status != services.HealthUp {
 		httpStatus = http.StatusServiceUnavailable
 	}
-
-	return c.JSON(httpStatus, map[string]interface{}{
-		"status": overallStatus,
-		"components": map[string]string{
-			"etcd": etcdStatus,
-		},
-	})
+	return c.JSON(httpStatus, status)
 }
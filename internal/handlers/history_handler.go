@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// HistoryHandler Domain 版本历史 HTTP 处理器
+type HistoryHandler struct {
+	historyService *services.HistoryService
+	validate       *validator.Validate
+}
+
+func NewHistoryHandler(historyService *services.HistoryService) *HistoryHandler {
+	return &HistoryHandler{
+		historyService: historyService,
+		validate:       validator.New(),
+	}
+}
+
+// toDomainVersionDTO 将 DomainVersion 转换为 DomainVersionDTO
+func toDomainVersionDTO(v *models.DomainVersion) *models.DomainVersionDTO {
+	dto := &models.DomainVersionDTO{
+		Revision:  v.Revision,
+		Op:        v.Op,
+		Timestamp: v.Timestamp,
+	}
+	if v.Exists() {
+		dto.Domain = toDomainDTO(v.Domain)
+	}
+	return dto
+}
+
+// DomainHistory 列出 Domain 的历史版本
+func (h *HistoryHandler) DomainHistory(c echo.Context) error {
+	var req models.DomainHistoryRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	versions, err := h.historyService.ListVersions(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to list domain history")
+		return err
+	}
+
+	dtos := make([]*models.DomainVersionDTO, len(versions))
+	for i, v := range versions {
+		dtos[i] = toDomainVersionDTO(v)
+	}
+
+	return c.JSON(200, &models.DomainHistoryDTO{
+		Zone:     req.Zone,
+		Domain:   req.Domain,
+		Versions: dtos,
+	})
+}
+
+// DiffDomainVersions 对比 Domain 的两个版本
+func (h *HistoryHandler) DiffDomainVersions(c echo.Context) error {
+	var req models.DiffDomainVersionsRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	diff, err := h.historyService.DiffVersions(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to diff domain versions")
+		return err
+	}
+
+	return c.JSON(200, diff)
+}
+
+// RollbackDomain 将 Domain 恢复到历史版本
+func (h *HistoryHandler) RollbackDomain(c echo.Context) error {
+	var req models.RollbackDomainRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	domain, err := h.historyService.RollbackDomain(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to roll back domain")
+		return err
+	}
+
+	// 目标为删除版本时 Domain 已被删除
+	if domain == nil {
+		return c.JSON(200, &models.Response{
+			Code:    "success",
+			Message: "Domain rolled back to deleted version",
+		})
+	}
+
+	return c.JSON(200, toDomainDTO(domain))
+}
+
+// RollbackZone 将 Zone 下所有 Domain 恢复到指定时间点（Admin）
+func (h *HistoryHandler) RollbackZone(c echo.Context) error {
+	var req models.RollbackZoneRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	result, err := h.historyService.RollbackZone(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to roll back zone")
+		return err
+	}
+
+	return c.JSON(200, &models.ZoneRollbackDTO{
+		Zone:      req.Zone,
+		Timestamp: req.Timestamp,
+		DryRun:    req.DryRun,
+		Changes:   result.Changes,
+		Skipped:   result.Skipped,
+	})
+}
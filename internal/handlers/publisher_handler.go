@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// PublisherHandler 发布目标 HTTP 处理器
+type PublisherHandler struct {
+	publishService *services.PublishService
+	validate       *validator.Validate
+}
+
+func NewPublisherHandler(publishService *services.PublishService) *PublisherHandler {
+	return &PublisherHandler{
+		publishService: publishService,
+		validate:       validator.New(),
+	}
+}
+
+// ListPublishers 列出已配置的发布目标（Admin）
+func (h *PublisherHandler) ListPublishers(c echo.Context) error {
+	return c.JSON(200, &models.PublisherListDTO{Publishers: h.publishService.ListPublishers()})
+}
+
+// ListPublished 读取发布目标上 Zone 当前的地址记录（Admin）
+func (h *PublisherHandler) ListPublished(c echo.Context) error {
+	var req models.ListPublishedRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	records, err := h.publishService.ListPublished(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to list published records")
+		return err
+	}
+
+	return c.JSON(200, records)
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"dancer/internal/auth"
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// RegistryHandler 服务注册 HTTP 处理器
+type RegistryHandler struct {
+	registryService *services.RegistryService
+	validate        *validator.Validate
+}
+
+func NewRegistryHandler(registryService *services.RegistryService) *RegistryHandler {
+	return &RegistryHandler{
+		registryService: registryService,
+		validate:        validator.New(),
+	}
+}
+
+// toInstanceDTO 将 Instance 转换为 InstanceDTO
+func toInstanceDTO(inst *models.Instance) *models.InstanceDTO {
+	return &models.InstanceDTO{
+		Zone:          inst.Zone,
+		Domain:        inst.Domain,
+		InstanceID:    inst.InstanceID,
+		IP:            inst.IP,
+		LeaseTTL:      inst.LeaseTTL,
+		RegisteredAt:  inst.RegisteredAt,
+		LastHeartbeat: inst.LastHeartbeat,
+	}
+}
+
+// Register 注册动态实例（API Token）
+func (h *RegistryHandler) Register(c echo.Context) error {
+	var req models.RegisterInstanceRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	inst, err := h.registryService.Register(c.Request().Context(), auth.GetAPIToken(c), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to register instance")
+		return err
+	}
+
+	return c.JSON(200, toInstanceDTO(inst))
+}
+
+// Heartbeat 实例心跳（API Token）
+func (h *RegistryHandler) Heartbeat(c echo.Context) error {
+	var req models.HeartbeatInstanceRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	inst, err := h.registryService.Heartbeat(c.Request().Context(), auth.GetAPIToken(c), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, toInstanceDTO(inst))
+}
+
+// Deregister 注销动态实例（API Token）
+func (h *RegistryHandler) Deregister(c echo.Context) error {
+	var req models.DeregisterInstanceRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.registryService.Deregister(c.Request().Context(), auth.GetAPIToken(c), &req); err != nil {
+		logger.Log.WithError(err).Error("Failed to deregister instance")
+		return err
+	}
+
+	return c.JSON(200, &models.Response{
+		Code:    "success",
+		Message: "instance deregistered successfully",
+	})
+}
+
+// ListInstances 列出 Domain 下所有动态实例（JWT）
+func (h *RegistryHandler) ListInstances(c echo.Context) error {
+	var req models.ListInstancesRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	instances, err := h.registryService.ListInstances(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to list instances")
+		return err
+	}
+
+	dtos := make([]*models.InstanceDTO, len(instances))
+	for i, inst := range instances {
+		dtos[i] = toInstanceDTO(inst)
+	}
+
+	return c.JSON(200, &models.InstanceListDTO{Instances: dtos})
+}
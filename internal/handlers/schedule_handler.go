@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"dancer/internal/auth"
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// ScheduleHandler 定时变更 HTTP 处理器
+type ScheduleHandler struct {
+	scheduleService *services.ScheduleService
+	validate        *validator.Validate
+}
+
+func NewScheduleHandler(scheduleService *services.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+		validate:        validator.New(),
+	}
+}
+
+// toScheduleDTO 将 ScheduledChange 转换为 ScheduleDTO
+func toScheduleDTO(sc *models.ScheduledChange) *models.ScheduleDTO {
+	return &models.ScheduleDTO{
+		ID:           sc.ID,
+		Zone:         sc.Zone,
+		Domain:       sc.Domain,
+		IPs:          sc.IPs,
+		TTL:          sc.TTL,
+		ExecuteAt:    sc.ExecuteAt,
+		LowerTTL:     sc.LowerTTL,
+		LowerTTLLead: sc.LowerTTLLead,
+		TTLLoweredAt: sc.TTLLoweredAt,
+		Status:       sc.Status,
+		Error:        sc.Error,
+		CreatedBy:    sc.CreatedBy,
+		CreatedAt:    sc.CreatedAt,
+		UpdatedAt:    sc.UpdatedAt,
+		ExecutedAt:   sc.ExecutedAt,
+	}
+}
+
+// ListSchedules 列出定时变更
+func (h *ScheduleHandler) ListSchedules(c echo.Context) error {
+	var req models.ListSchedulesRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	schedules, err := h.scheduleService.ListSchedules(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to list scheduled changes")
+		return err
+	}
+
+	dtos := make([]*models.ScheduleDTO, len(schedules))
+	for i, sc := range schedules {
+		dtos[i] = toScheduleDTO(sc)
+	}
+
+	return c.JSON(200, &models.ScheduleListDTO{Schedules: dtos})
+}
+
+// GetSchedule 获取定时变更详情
+func (h *ScheduleHandler) GetSchedule(c echo.Context) error {
+	var req models.GetScheduleRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	sc, err := h.scheduleService.GetSchedule(c.Request().Context(), req.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, toScheduleDTO(sc))
+}
+
+// CreateSchedule 创建定时变更
+func (h *ScheduleHandler) CreateSchedule(c echo.Context) error {
+	var req models.CreateScheduleRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	currentUser := auth.GetCurrentUser(c)
+
+	sc, err := h.scheduleService.CreateSchedule(c.Request().Context(), currentUser.ID, &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to create scheduled change")
+		return err
+	}
+
+	return c.JSON(200, toScheduleDTO(sc))
+}
+
+// CancelSchedule 取消定时变更
+func (h *ScheduleHandler) CancelSchedule(c echo.Context) error {
+	var req models.CancelScheduleRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	sc, err := h.scheduleService.CancelSchedule(c.Request().Context(), req.ID)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to cancel scheduled change")
+		return err
+	}
+
+	return c.JSON(200, toScheduleDTO(sc))
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// SearchHandler 搜索 HTTP 处理器
+type SearchHandler struct {
+	searchService *services.SearchService
+	validate      *validator.Validate
+}
+
+func NewSearchHandler(searchService *services.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+		validate:      validator.New(),
+	}
+}
+
+// SearchDomains 跨 Zone 搜索 Domain
+func (h *SearchHandler) SearchDomains(c echo.Context) error {
+	var req models.SearchDomainsRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	result, err := h.searchService.Search(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to search domains")
+		return err
+	}
+
+	dtos := make([]*models.DomainDTO, len(result.Domains))
+	for i, domain := range result.Domains {
+		dtos[i] = toDomainDTO(domain)
+	}
+
+	return c.JSON(200, &models.SearchDomainsDTO{Domains: dtos, Total: result.Total, Revision: result.Revision})
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"dancer/internal/auth"
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/services"
+	"github.com/labstack/echo/v4"
+)
+
+// SyncHandler 声明式同步 HTTP 处理器
+// 期望状态在服务层逐项校验，问题在计划中报告
+type SyncHandler struct {
+	syncService *services.SyncService
+}
+
+func NewSyncHandler(syncService *services.SyncService) *SyncHandler {
+	return &SyncHandler{syncService: syncService}
+}
+
+// Plan 生成同步计划，不做修改（Admin）
+func (h *SyncHandler) Plan(c echo.Context) error {
+	var req models.SyncRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	plan, err := h.syncService.Plan(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to plan sync")
+		return err
+	}
+
+	return c.JSON(200, plan)
+}
+
+// Apply 生成并应用同步计划（Admin）
+func (h *SyncHandler) Apply(c echo.Context) error {
+	var req models.SyncRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	currentUser := auth.GetCurrentUser(c)
+
+	plan, err := h.syncService.Apply(c.Request().Context(), currentUser.ID, &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to apply sync")
+		return err
+	}
+
+	return c.JSON(200, plan)
+}
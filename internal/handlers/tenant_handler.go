@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"dancer/internal/auth"
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// TenantHandler 租户 HTTP 处理器
+type TenantHandler struct {
+	tenantService *services.TenantService
+	validate      *validator.Validate
+}
+
+func NewTenantHandler(tenantService *services.TenantService) *TenantHandler {
+	return &TenantHandler{
+		tenantService: tenantService,
+		validate:      validator.New(),
+	}
+}
+
+// ListTenants 列出所有租户（Super Admin）
+func (h *TenantHandler) ListTenants(c echo.Context) error {
+	tenants, err := h.tenantService.ListTenants(c.Request().Context())
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to list tenants")
+		return err
+	}
+
+	return c.JSON(200, &models.TenantListDTO{Tenants: tenants})
+}
+
+// GetTenant 获取租户（Super Admin）
+func (h *TenantHandler) GetTenant(c echo.Context) error {
+	var req models.TenantRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	t, err := h.tenantService.GetTenant(c.Request().Context(), req.ID)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to get tenant")
+		return err
+	}
+
+	return c.JSON(200, t)
+}
+
+// CreateTenant 创建租户及其第一个管理员（Super Admin）
+func (h *TenantHandler) CreateTenant(c echo.Context) error {
+	var req models.CreateTenantRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	t, err := h.tenantService.CreateTenant(c.Request().Context(), auth.GetCurrentUser(c), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to create tenant")
+		return err
+	}
+
+	return c.JSON(200, t)
+}
+
+// DeleteTenant 删除没有 Zone 的租户及其用户（Super Admin）
+func (h *TenantHandler) DeleteTenant(c echo.Context) error {
+	var req models.TenantRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.tenantService.DeleteTenant(c.Request().Context(), req.ID); err != nil {
+		logger.Log.WithError(err).Error("Failed to delete tenant")
+		return err
+	}
+
+	return c.JSON(200, &models.Response{
+		Code:    "success",
+		Message: "tenant deleted successfully",
+	})
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+
+	"dancer/internal/auth"
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// APITokenHandler API Token HTTP 处理器
+type APITokenHandler struct {
+	tokenService *services.APITokenService
+	validate     *validator.Validate
+}
+
+func NewAPITokenHandler(tokenService *services.APITokenService) *APITokenHandler {
+	return &APITokenHandler{
+		tokenService: tokenService,
+		validate:     validator.New(),
+	}
+}
+
+// toAPITokenDTO 将 APIToken 转换为 APITokenDTO（排除 Token 摘要）
+func toAPITokenDTO(token *models.APIToken) *models.APITokenDTO {
+	return &models.APITokenDTO{
+		ID:        token.ID,
+		Name:      token.Name,
+		Zone:      token.Zone,
+		Domains:   token.Domains,
+		CreatedBy: token.CreatedBy,
+		CreatedAt: token.CreatedAt,
+	}
+}
+
+// ListTokens 列出所有 API Token（Admin）
+func (h *APITokenHandler) ListTokens(c echo.Context) error {
+	tokens, err := h.tokenService.ListTokens(c.Request().Context())
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to list api tokens")
+		return err
+	}
+
+	dtos := make([]*models.APITokenDTO, len(tokens))
+	for i, token := range tokens {
+		dtos[i] = toAPITokenDTO(token)
+	}
+
+	return c.JSON(200, &models.APITokenListDTO{Tokens: dtos})
+}
+
+// CreateToken 创建 API Token（Admin）
+func (h *APITokenHandler) CreateToken(c echo.Context) error {
+	var req models.CreateAPITokenRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	currentUser := auth.GetCurrentUser(c)
+
+	token, plain, err := h.tokenService.CreateToken(c.Request().Context(), currentUser.ID, &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to create api token")
+		return err
+	}
+
+	return c.JSON(200, &models.APITokenCreatedDTO{
+		APITokenDTO: toAPITokenDTO(token),
+		Token:       plain,
+	})
+}
+
+// DeleteToken 删除 API Token（Admin）
+func (h *APITokenHandler) DeleteToken(c echo.Context) error {
+	var req models.DeleteAPITokenRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.tokenService.DeleteToken(c.Request().Context(), req.ID); err != nil {
+		logger.Log.WithError(err).Error("Failed to delete api token")
+		return err
+	}
+
+	return c.JSON(200, &models.Response{
+		Code:    "success",
+		Message: "api token deleted successfully",
+	})
+}
+
+// Authenticate 校验 API Token，供服务注册认证中间件使用
+func (h *APITokenHandler) Authenticate(ctx context.Context, token string) (*models.APIToken, error) {
+	return h.tokenService.Authenticate(ctx, token)
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// TrashHandler 回收站 HTTP 处理器
+type TrashHandler struct {
+	trashService *services.TrashService
+	validate     *validator.Validate
+}
+
+func NewTrashHandler(trashService *services.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+		validate:     validator.New(),
+	}
+}
+
+// toTrashEntryDTO 将 TrashEntry 转换为 TrashEntryDTO
+func toTrashEntryDTO(entry *models.TrashEntry) *models.TrashEntryDTO {
+	dto := &models.TrashEntryDTO{
+		ID:          entry.ID,
+		Type:        entry.Type,
+		Zone:        entry.Zone,
+		Domain:      entry.Domain,
+		DomainCount: entry.DomainCount,
+		DeletedAt:   entry.DeletedAt,
+		ExpiresAt:   entry.ExpiresAt,
+	}
+	if entry.Record != nil {
+		dto.Record = toDomainDTO(entry.Record)
+	}
+	return dto
+}
+
+// ListDomainTrash 列出回收站中的 Domain
+func (h *TrashHandler) ListDomainTrash(c echo.Context) error {
+	return h.list(c, models.TrashTypeDomain)
+}
+
+// ListZoneTrash 列出回收站中的 Zone（Admin）
+func (h *TrashHandler) ListZoneTrash(c echo.Context) error {
+	return h.list(c, models.TrashTypeZone)
+}
+
+// RestoreDomain 从回收站恢复 Domain
+func (h *TrashHandler) RestoreDomain(c echo.Context) error {
+	var req models.TrashEntryRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	domain, err := h.trashService.RestoreDomain(c.Request().Context(), req.ID)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to restore domain")
+		return err
+	}
+
+	return c.JSON(200, toDomainDTO(domain))
+}
+
+// RestoreZone 从回收站恢复 Zone 及其下所有 Domain（Admin）
+func (h *TrashHandler) RestoreZone(c echo.Context) error {
+	var req models.TrashEntryRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	zone, err := h.trashService.RestoreZone(c.Request().Context(), req.ID)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to restore zone")
+		return err
+	}
+
+	return c.JSON(200, toZoneDTO(zone))
+}
+
+// PurgeDomain 永久删除回收站中的 Domain（Admin）
+func (h *TrashHandler) PurgeDomain(c echo.Context) error {
+	return h.purge(c, models.TrashTypeDomain)
+}
+
+// PurgeZone 永久删除回收站中的 Zone（Admin）
+func (h *TrashHandler) PurgeZone(c echo.Context) error {
+	return h.purge(c, models.TrashTypeZone)
+}
+
+// list 列出指定类型的回收站条目
+func (h *TrashHandler) list(c echo.Context, typ models.TrashType) error {
+	var req models.ListTrashRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	entries, err := h.trashService.ListEntries(c.Request().Context(), typ, req.Zone)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to list trash entries")
+		return err
+	}
+
+	dtos := make([]*models.TrashEntryDTO, len(entries))
+	for i, entry := range entries {
+		dtos[i] = toTrashEntryDTO(entry)
+	}
+
+	return c.JSON(200, &models.TrashListDTO{Entries: dtos})
+}
+
+// purge 永久删除指定类型的回收站条目
+func (h *TrashHandler) purge(c echo.Context, typ models.TrashType) error {
+	var req models.TrashEntryRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.trashService.Purge(c.Request().Context(), typ, req.ID); err != nil {
+		logger.Log.WithError(err).Error("Failed to purge trash entry")
+		return err
+	}
+
+	return c.JSON(200, &models.Response{
+		Code:    "success",
+		Message: "Trash entry purged successfully",
+	})
+}
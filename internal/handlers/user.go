@@ -4,6 +4,7 @@ import (
 	"dancer/internal/auth"
 	apperrors "dancer/internal/errors"
 	"dancer/internal/logger"
+	"dancer/internal/metrics"
 	"dancer/internal/models"
 	"dancer/internal/services"
 	"github.com/go-playground/validator/v10"
@@ -44,7 +45,8 @@ func (h *UserHandler) Login(c echo.Context) error {
 		return apperrors.ErrInvalidInput
 	}
 
-	token, _, err := h.userService.Login(c.Request().Context(), req.Username, req.Password)
+	token, _, err := h.userService.Login(c.Request().Context(), req.Tenant, req.Username, req.Password)
+	metrics.ObserveLogin(err)
 	if err != nil {
 		return err
 	}
@@ -58,7 +60,7 @@ func (h *UserHandler) Login(c echo.Context) error {
 func (h *UserHandler) RefreshToken(c echo.Context) error {
 	currentUser := auth.GetCurrentUser(c)
 
-	token, err := auth.GenerateToken(currentUser.ID, currentUser.Username, string(currentUser.UserType))
+	token, err := auth.GenerateToken(currentUser.ID, currentUser.Username, string(currentUser.UserType), currentUser.Tenant)
 	if err != nil {
 		logger.Log.WithError(err).Error("Failed to refresh token")
 		return err
@@ -108,18 +110,27 @@ func (h *UserHandler) ChangePassword(c echo.Context) error {
 
 // ListUsers 列出所有用户（Admin）
 func (h *UserHandler) ListUsers(c echo.Context) error {
-	users, err := h.userService.ListUsers(c.Request().Context())
+	var req models.ListUsersRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	page, err := h.userService.ListUsers(c.Request().Context(), &req)
 	if err != nil {
 		return err
 	}
 
 	// 转换用户列表，排除 password 字段
-	responses := make([]*models.UserDTO, len(users))
-	for i, user := range users {
+	responses := make([]*models.UserDTO, len(page.Items))
+	for i, user := range page.Items {
 		responses[i] = toUserDTO(user)
 	}
 
-	return c.JSON(200, &models.UserListDTO{Users: responses})
+	return c.JSON(200, &models.UserListDTO{Users: responses, Total: page.Total, NextCursor: page.NextCursor})
 }
 
 // CreateUser 创建用户（Admin）
@@ -133,7 +144,7 @@ func (h *UserHandler) CreateUser(c echo.Context) error {
 		return apperrors.ErrInvalidInput
 	}
 
-	user, err := h.userService.CreateUser(c.Request().Context(), &req)
+	user, err := h.userService.CreateUser(c.Request().Context(), auth.GetCurrentUser(c), &req)
 	if err != nil {
 		return err
 	}
@@ -152,7 +163,7 @@ func (h *UserHandler) UpdateUser(c echo.Context) error {
 		return apperrors.ErrInvalidInput
 	}
 
-	if err := h.userService.UpdateUser(c.Request().Context(), &req); err != nil {
+	if err := h.userService.UpdateUser(c.Request().Context(), auth.GetCurrentUser(c), &req); err != nil {
 		return err
 	}
 
@@ -173,7 +184,7 @@ func (h *UserHandler) DeleteUser(c echo.Context) error {
 		return apperrors.ErrInvalidInput
 	}
 
-	if err := h.userService.DeleteUser(c.Request().Context(), req.ID); err != nil {
+	if err := h.userService.DeleteUser(c.Request().Context(), auth.GetCurrentUser(c), req.ID); err != nil {
 		return err
 	}
 
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// toDomainResource 将 Domain 实体转换为 v2 资源
+func toDomainResource(domain *models.Domain) *models.DomainResource {
+	viewIPs := domain.ViewIPs
+	if viewIPs == nil {
+		viewIPs = map[string][]string{}
+	}
+	return &models.DomainResource{
+		ID:          domain.Zone + "/" + domain.Domain,
+		Zone:        domain.Zone,
+		Name:        domain.Domain,
+		FQDN:        domain.Name,
+		IPs:         nonNil(domain.IPs),
+		ViewIPs:     viewIPs,
+		DynamicIPs:  nonNil(domain.DynamicIPs),
+		TTL:         domain.TTL,
+		RecordCount: domain.RecordCount,
+		LeaseTTL:    domain.LeaseTTL,
+		ExpiresAt:   domain.ExpiresAt,
+		CreatedAt:   domain.CreatedAt,
+		UpdatedAt:   domain.UpdatedAt,
+	}
+}
+
+// domainLocation Domain 资源地址
+func domainLocation(domain *models.Domain) string {
+	return "/api/v2/zones/" + domain.Zone + "/domains/" + domain.Domain
+}
+
+// domainParams 读取并校验路径中的 Zone 与 Domain 名称
+func (h *V2Handler) domainParams(c echo.Context) (string, string, error) {
+	zone, err := h.zoneParam(c)
+	if err != nil {
+		return "", "", err
+	}
+	name := c.Param("name")
+	if name == "" {
+		return "", "", apperrors.ErrInvalidInput
+	}
+	return zone, name, nil
+}
+
+// ListDomains GET /zones/{zone}/domains，过滤与分页参数同 v1，通过查询参数传递
+func (h *V2Handler) ListDomains(c echo.Context) error {
+	zone, err := h.zoneParam(c)
+	if err != nil {
+		return err
+	}
+	req := models.ListDomainsRequest{Zone: zone}
+	if err := h.bind(c, &req); err != nil {
+		return err
+	}
+
+	page, err := h.domainService.ListDomains(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to list domains")
+		return err
+	}
+
+	domains := make([]*models.DomainResource, len(page.Items))
+	for i, domain := range page.Items {
+		domains[i] = toDomainResource(domain)
+	}
+	return v2List(c, domains, page.Total, page.NextCursor)
+}
+
+// CreateDomain POST /zones/{zone}/domains
+func (h *V2Handler) CreateDomain(c echo.Context) error {
+	zone, err := h.zoneParam(c)
+	if err != nil {
+		return err
+	}
+	var req models.CreateDomainResource
+	if err := h.bind(c, &req); err != nil {
+		return err
+	}
+
+	domain, err := h.domainService.CreateDomain(c.Request().Context(), &models.CreateDomainRequest{
+		Zone:     zone,
+		Domain:   req.Name,
+		IPs:      req.IPs,
+		ViewIPs:  req.ViewIPs,
+		TTL:      req.TTL,
+		LeaseTTL: req.LeaseTTL,
+	})
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to create domain")
+		return err
+	}
+	return v2Created(c, domainLocation(domain), toDomainResource(domain))
+}
+
+// GetDomain GET /zones/{zone}/domains/{name}
+func (h *V2Handler) GetDomain(c echo.Context) error {
+	zone, name, err := h.domainParams(c)
+	if err != nil {
+		return err
+	}
+
+	domain, err := h.domainService.GetDomain(c.Request().Context(), &models.GetDomainRequest{Zone: zone, Domain: name})
+	if err != nil {
+		return err
+	}
+	return v2Data(c, http.StatusOK, toDomainResource(domain))
+}
+
+// PutDomain PUT /zones/{zone}/domains/{name}，不存在时创建（201），存在时替换 IP、视图 IP 与 TTL（200），内容相同时不做修改
+func (h *V2Handler) PutDomain(c echo.Context) error {
+	zone, name, err := h.domainParams(c)
+	if err != nil {
+		return err
+	}
+	var req models.PutDomainResource
+	if err := h.bind(c, &req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	domain, err := h.domainService.GetDomain(ctx, &models.GetDomainRequest{Zone: zone, Domain: name})
+	if errors.Is(err, apperrors.ErrDomainNotFound) {
+		domain, err = h.domainService.CreateDomain(ctx, &models.CreateDomainRequest{Zone: zone, Domain: name, IPs: req.IPs, ViewIPs: req.ViewIPs, TTL: req.TTL})
+		if err != nil {
+			logger.Log.WithError(err).Error("Failed to create domain")
+			return err
+		}
+		return v2Created(c, domainLocation(domain), toDomainResource(domain))
+	}
+	if err != nil {
+		return err
+	}
+
+	// 不填 view_ips 时清除所有视图覆盖
+	if req.ViewIPs == nil {
+		req.ViewIPs = map[string][]string{}
+	}
+	if !sameIPs(domain.IPs, req.IPs) || !sameViewIPs(domain.ViewIPs, req.ViewIPs) || domain.TTL != req.TTL {
+		domain, err = h.domainService.UpdateDomain(ctx, &models.UpdateDomainRequest{Zone: zone, Domain: name, IPs: req.IPs, ViewIPs: req.ViewIPs, TTL: req.TTL})
+		if err != nil {
+			logger.Log.WithError(err).Error("Failed to update domain")
+			return err
+		}
+	}
+	return v2Data(c, http.StatusOK, toDomainResource(domain))
+}
+
+// PatchDomain PATCH /zones/{zone}/domains/{name}
+func (h *V2Handler) PatchDomain(c echo.Context) error {
+	zone, name, err := h.domainParams(c)
+	if err != nil {
+		return err
+	}
+	var req models.PatchDomainResource
+	if err := h.bind(c, &req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	domain, err := h.domainService.GetDomain(ctx, &models.GetDomainRequest{Zone: zone, Domain: name})
+	if err != nil {
+		return err
+	}
+
+	update := &models.UpdateDomainRequest{Zone: zone, Domain: name, IPs: domain.IPs, TTL: domain.TTL}
+	if req.IPs != nil {
+		update.IPs = req.IPs
+	}
+	update.ViewIPs = req.ViewIPs
+	if req.TTL != nil {
+		update.TTL = *req.TTL
+	}
+
+	domain, err = h.domainService.UpdateDomain(ctx, update)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to update domain")
+		return err
+	}
+	return v2Data(c, http.StatusOK, toDomainResource(domain))
+}
+
+// DeleteDomain DELETE /zones/{zone}/domains/{name}，Domain 移入回收站
+func (h *V2Handler) DeleteDomain(c echo.Context) error {
+	zone, name, err := h.domainParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.domainService.DeleteDomain(c.Request().Context(), &models.DeleteDomainRequest{Zone: zone, Domain: name}); err != nil {
+		logger.Log.WithError(err).Error("Failed to delete domain")
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+
+	apperrors "dancer/internal/errors"
+	"dancer/internal/models"
+	"dancer/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// V2Handler /api/v2 资源接口处理器
+// 路由按资源组织，成功响应统一包装为 {"data": ...}；创建返回 201 与 Location，删除返回 204，PUT 幂等
+type V2Handler struct {
+	zoneService   *services.ZoneService
+	domainService *services.DomainService
+	userService   *services.UserService
+	validate      *validator.Validate
+}
+
+func NewV2Handler(zoneService *services.ZoneService, domainService *services.DomainService, userService *services.UserService) *V2Handler {
+	return &V2Handler{
+		zoneService:   zoneService,
+		domainService: domainService,
+		userService:   userService,
+		validate:      validator.New(),
+	}
+}
+
+// bind 绑定并校验请求
+func (h *V2Handler) bind(c echo.Context, req interface{}) error {
+	if err := c.Bind(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+	return nil
+}
+
+// zoneParam 读取并校验路径中的 Zone 名称
+func (h *V2Handler) zoneParam(c echo.Context) (string, error) {
+	zone := c.Param("zone")
+	if err := h.validate.Var(zone, "required,fqdn"); err != nil {
+		return "", apperrors.ErrInvalidInput
+	}
+	return zone, nil
+}
+
+// v2Data 返回单个资源
+func v2Data(c echo.Context, status int, v interface{}) error {
+	return c.JSON(status, &models.ResourceEnvelope{Data: v})
+}
+
+// v2Created 返回新建的资源及其地址
+func v2Created(c echo.Context, location string, v interface{}) error {
+	c.Response().Header().Set(echo.HeaderLocation, location)
+	return v2Data(c, http.StatusCreated, v)
+}
+
+// v2List 返回资源列表
+func v2List(c echo.Context, v interface{}, total int, nextCursor string) error {
+	return c.JSON(http.StatusOK, &models.ResourceEnvelope{
+		Data: v,
+		Meta: &models.ListMeta{Total: total, NextCursor: nextCursor},
+	})
+}
+
+// sameIPs 两个 IP 列表是否完全相同（含顺序）
+func sameIPs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sameViewIPs 两组按视图覆盖的 IP 是否完全相同，nil 与空 map 视为相同
+func sameViewIPs(a, b map[string][]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for view, ips := range a {
+		other, ok := b[view]
+		if !ok || !sameIPs(ips, other) {
+			return false
+		}
+	}
+	return true
+}
+
+// nonNil 将 nil 切片转换为空切片，保证 JSON 中为 []
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+
+	"dancer/internal/auth"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// toUserResource 将用户实体转换为 v2 资源（不含密码）
+func toUserResource(user *models.User) *models.UserResource {
+	return &models.UserResource{
+		ID:        user.ID,
+		Username:  user.Username,
+		UserType:  user.UserType,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+// ListUsers GET /users，过滤与分页参数同 v1，通过查询参数传递（Admin）
+func (h *V2Handler) ListUsers(c echo.Context) error {
+	var req models.ListUsersRequest
+	if err := h.bind(c, &req); err != nil {
+		return err
+	}
+
+	page, err := h.userService.ListUsers(c.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	users := make([]*models.UserResource, len(page.Items))
+	for i, user := range page.Items {
+		users[i] = toUserResource(user)
+	}
+	return v2List(c, users, page.Total, page.NextCursor)
+}
+
+// CreateUser POST /users（Admin）
+func (h *V2Handler) CreateUser(c echo.Context) error {
+	var req models.CreateUserResource
+	if err := h.bind(c, &req); err != nil {
+		return err
+	}
+
+	user, err := h.userService.CreateUser(c.Request().Context(), auth.GetCurrentUser(c), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to create user")
+		return err
+	}
+	return v2Created(c, "/api/v2/users/"+user.ID, toUserResource(user))
+}
+
+// GetUser GET /users/{id}（Admin）
+func (h *V2Handler) GetUser(c echo.Context) error {
+	user, err := h.userService.GetCurrentUser(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return v2Data(c, http.StatusOK, toUserResource(user))
+}
+
+// PatchUser PATCH /users/{id}（Admin）
+func (h *V2Handler) PatchUser(c echo.Context) error {
+	var req models.PatchUserResource
+	if err := h.bind(c, &req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	err := h.userService.UpdateUser(ctx, auth.GetCurrentUser(c), &models.UpdateUserRequest{
+		ID:       c.Param("id"),
+		Username: req.Username,
+		Password: req.Password,
+		UserType: req.UserType,
+	})
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to update user")
+		return err
+	}
+
+	user, err := h.userService.GetCurrentUser(ctx, c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return v2Data(c, http.StatusOK, toUserResource(user))
+}
+
+// DeleteUser DELETE /users/{id}（Admin）
+func (h *V2Handler) DeleteUser(c echo.Context) error {
+	if err := h.userService.DeleteUser(c.Request().Context(), auth.GetCurrentUser(c), c.Param("id")); err != nil {
+		logger.Log.WithError(err).Error("Failed to delete user")
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// toZoneResource 将 Zone 实体转换为 v2 资源
+func toZoneResource(zone *models.Zone) *models.ZoneResource {
+	return &models.ZoneResource{
+		ID:               zone.Zone,
+		Zone:             zone.Zone,
+		ApprovalRequired: zone.ApprovalRequired,
+		Publishers:       zone.PublisherNames(),
+		RecordCount:      zone.RecordCount,
+		CreatedAt:        zone.CreatedAt,
+		UpdatedAt:        zone.UpdatedAt,
+	}
+}
+
+// ListZones GET /zones，过滤与分页参数同 v1，通过查询参数传递（Admin）
+func (h *V2Handler) ListZones(c echo.Context) error {
+	var req models.ListZonesRequest
+	if err := h.bind(c, &req); err != nil {
+		return err
+	}
+
+	page, err := h.zoneService.ListZones(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to list zones")
+		return err
+	}
+
+	zones := make([]*models.ZoneResource, len(page.Items))
+	for i, zone := range page.Items {
+		zones[i] = toZoneResource(zone)
+	}
+	return v2List(c, zones, page.Total, page.NextCursor)
+}
+
+// CreateZone POST /zones（Admin）
+func (h *V2Handler) CreateZone(c echo.Context) error {
+	var req models.CreateZoneResource
+	if err := h.bind(c, &req); err != nil {
+		return err
+	}
+
+	zone, err := h.zoneService.CreateZone(c.Request().Context(), &models.CreateZoneRequest{
+		Zone:             req.Zone,
+		ApprovalRequired: req.ApprovalRequired,
+		Publishers:       req.Publishers,
+	})
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to create zone")
+		return err
+	}
+	return v2Created(c, "/api/v2/zones/"+zone.Zone, toZoneResource(zone))
+}
+
+// GetZone GET /zones/{zone}（Admin）
+func (h *V2Handler) GetZone(c echo.Context) error {
+	name, err := h.zoneParam(c)
+	if err != nil {
+		return err
+	}
+
+	zone, err := h.zoneService.GetZone(c.Request().Context(), name)
+	if err != nil {
+		return err
+	}
+	return v2Data(c, http.StatusOK, toZoneResource(zone))
+}
+
+// PutZone PUT /zones/{zone}，不存在时创建（201），存在时替换（200），内容相同时不做修改（Admin）
+func (h *V2Handler) PutZone(c echo.Context) error {
+	name, err := h.zoneParam(c)
+	if err != nil {
+		return err
+	}
+	var req models.PutZoneResource
+	if err := h.bind(c, &req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	zone, err := h.zoneService.GetZone(ctx, name)
+	if errors.Is(err, apperrors.ErrZoneNotFound) {
+		zone, err = h.zoneService.CreateZone(ctx, &models.CreateZoneRequest{Zone: name, ApprovalRequired: req.ApprovalRequired, Publishers: req.Publishers})
+		if err != nil {
+			logger.Log.WithError(err).Error("Failed to create zone")
+			return err
+		}
+		return v2Created(c, "/api/v2/zones/"+zone.Zone, toZoneResource(zone))
+	}
+	if err != nil {
+		return err
+	}
+
+	// 替换语义下未填写 publishers 表示恢复默认，以非 nil 的空切片传给 UpdateZone
+	publishers := req.Publishers
+	if publishers == nil {
+		publishers = []string{}
+	}
+	desired := &models.Zone{Publishers: publishers}
+	if zone.ApprovalRequired != req.ApprovalRequired || !slices.Equal(zone.PublisherNames(), desired.PublisherNames()) {
+		zone, err = h.zoneService.UpdateZone(ctx, &models.UpdateZoneRequest{Zone: name, ApprovalRequired: &req.ApprovalRequired, Publishers: publishers})
+		if err != nil {
+			logger.Log.WithError(err).Error("Failed to update zone")
+			return err
+		}
+	}
+	return v2Data(c, http.StatusOK, toZoneResource(zone))
+}
+
+// PatchZone PATCH /zones/{zone}（Admin）
+func (h *V2Handler) PatchZone(c echo.Context) error {
+	name, err := h.zoneParam(c)
+	if err != nil {
+		return err
+	}
+	var req models.PatchZoneResource
+	if err := h.bind(c, &req); err != nil {
+		return err
+	}
+
+	zone, err := h.zoneService.UpdateZone(c.Request().Context(), &models.UpdateZoneRequest{
+		Zone:             name,
+		ApprovalRequired: req.ApprovalRequired,
+		Publishers:       req.Publishers,
+	})
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to update zone")
+		return err
+	}
+	return v2Data(c, http.StatusOK, toZoneResource(zone))
+}
+
+// DeleteZone DELETE /zones/{zone}，Zone 及其 Domain 移入回收站
+// Zone 下仍有 Domain 时需要通过查询参数 confirm 填写 Zone 名称（Admin）
+func (h *V2Handler) DeleteZone(c echo.Context) error {
+	name, err := h.zoneParam(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.zoneService.DeleteZone(c.Request().Context(), &models.DeleteZoneRequest{Zone: name, Confirm: c.QueryParam("confirm")})
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to delete zone")
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
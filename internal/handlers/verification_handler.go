@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// VerificationHandler DNS 校验 HTTP 处理器
+type VerificationHandler struct {
+	verificationService *services.VerificationService
+	validate            *validator.Validate
+}
+
+func NewVerificationHandler(verificationService *services.VerificationService) *VerificationHandler {
+	return &VerificationHandler{
+		verificationService: verificationService,
+		validate:            validator.New(),
+	}
+}
+
+// VerifyDomain 向 DNS 服务器查询 Domain 并与记录对比
+func (h *VerificationHandler) VerifyDomain(c echo.Context) error {
+	var req models.VerifyDomainRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	v, err := h.verificationService.VerifyDomain(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to verify domain")
+		return err
+	}
+
+	return c.JSON(200, v)
+}
+
+// GetDomainVerification 获取 Domain 最近一次校验结果
+func (h *VerificationHandler) GetDomainVerification(c echo.Context) error {
+	var req models.VerifyDomainRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	v, err := h.verificationService.GetDomainVerification(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to get domain verification")
+		return err
+	}
+
+	return c.JSON(200, v)
+}
+
+// VerifyZone 校验 Zone 下所有 Domain（Admin）
+func (h *VerificationHandler) VerifyZone(c echo.Context) error {
+	var req models.VerifyZoneRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	summary, err := h.verificationService.VerifyZone(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to verify zone")
+		return err
+	}
+
+	return c.JSON(200, summary)
+}
+
+// ListZoneVerifications 获取 Zone 下各 Domain 最近一次校验结果（Admin）
+func (h *VerificationHandler) ListZoneVerifications(c echo.Context) error {
+	var req models.VerifyZoneRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	summary, err := h.verificationService.ListZoneVerifications(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to list zone verifications")
+		return err
+	}
+
+	return c.JSON(200, summary)
+}
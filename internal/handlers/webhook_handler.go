@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"dancer/internal/auth"
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// WebhookHandler Webhook HTTP 处理器
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+	validate       *validator.Validate
+}
+
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		validate:       validator.New(),
+	}
+}
+
+// toWebhookDTO 将 Webhook 转换为 WebhookDTO（排除签名密钥）
+func toWebhookDTO(wh *models.Webhook) *models.WebhookDTO {
+	return &models.WebhookDTO{
+		ID:        wh.ID,
+		Name:      wh.Name,
+		URL:       wh.URL,
+		Zones:     wh.Zones,
+		Types:     wh.Types,
+		Ops:       wh.Ops,
+		Enabled:   wh.Enabled,
+		CreatedBy: wh.CreatedBy,
+		CreatedAt: wh.CreatedAt,
+		UpdatedAt: wh.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryDTO(d *models.WebhookDelivery) *models.WebhookDeliveryDTO {
+	dto := &models.WebhookDeliveryDTO{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		EventType:      d.EventType,
+		EventOp:        d.EventOp,
+		Zone:           d.Zone,
+		Name:           d.Name,
+		Revision:       d.Revision,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastAttemptAt:  d.LastAttemptAt,
+		ResponseStatus: d.ResponseStatus,
+		Error:          d.Error,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+		Payload:        d.Payload,
+	}
+	if d.Status == models.DeliveryStatusPending {
+		dto.NextAttemptAt = d.NextAttemptAt
+	}
+	return dto
+}
+
+// ListWebhooks 列出所有 Webhook 订阅（Admin）
+func (h *WebhookHandler) ListWebhooks(c echo.Context) error {
+	webhooks, err := h.webhookService.ListWebhooks(c.Request().Context())
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to list webhooks")
+		return err
+	}
+
+	dtos := make([]*models.WebhookDTO, len(webhooks))
+	for i, wh := range webhooks {
+		dtos[i] = toWebhookDTO(wh)
+	}
+
+	return c.JSON(200, &models.WebhookListDTO{Webhooks: dtos})
+}
+
+// GetWebhook 获取 Webhook 订阅（Admin）
+func (h *WebhookHandler) GetWebhook(c echo.Context) error {
+	var req models.GetWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	wh, err := h.webhookService.GetWebhook(c.Request().Context(), req.ID)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to get webhook")
+		return err
+	}
+
+	return c.JSON(200, toWebhookDTO(wh))
+}
+
+// CreateWebhook 创建 Webhook 订阅（Admin），签名密钥仅在响应中返回这一次
+func (h *WebhookHandler) CreateWebhook(c echo.Context) error {
+	var req models.CreateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	currentUser := auth.GetCurrentUser(c)
+
+	wh, err := h.webhookService.CreateWebhook(c.Request().Context(), currentUser.ID, &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to create webhook")
+		return err
+	}
+
+	return c.JSON(200, &models.WebhookSecretDTO{
+		WebhookDTO: toWebhookDTO(wh),
+		Secret:     wh.Secret,
+	})
+}
+
+// UpdateWebhook 更新 Webhook 订阅（Admin），轮换密钥时在响应中返回新的签名密钥
+func (h *WebhookHandler) UpdateWebhook(c echo.Context) error {
+	var req models.UpdateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	wh, secret, err := h.webhookService.UpdateWebhook(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to update webhook")
+		return err
+	}
+
+	return c.JSON(200, &models.WebhookSecretDTO{
+		WebhookDTO: toWebhookDTO(wh),
+		Secret:     secret,
+	})
+}
+
+// DeleteWebhook 删除 Webhook 订阅及其投递记录（Admin）
+func (h *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	var req models.GetWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request().Context(), req.ID); err != nil {
+		logger.Log.WithError(err).Error("Failed to delete webhook")
+		return err
+	}
+
+	return c.JSON(200, &models.Response{
+		Code:    "success",
+		Message: "webhook deleted successfully",
+	})
+}
+
+// ListDeliveries 列出投递记录（Admin），status 为 dead 时即死信列表
+func (h *WebhookHandler) ListDeliveries(c echo.Context) error {
+	var req models.ListWebhookDeliveriesRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	deliveries, total, err := h.webhookService.ListDeliveries(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to list webhook deliveries")
+		return err
+	}
+
+	dtos := make([]*models.WebhookDeliveryDTO, len(deliveries))
+	for i, d := range deliveries {
+		dtos[i] = toWebhookDeliveryDTO(d)
+	}
+
+	return c.JSON(200, &models.WebhookDeliveryListDTO{Deliveries: dtos, Total: total})
+}
+
+// Redeliver 重新投递（Admin）
+func (h *WebhookHandler) Redeliver(c echo.Context) error {
+	var req models.RedeliverWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	d, err := h.webhookService.Redeliver(c.Request().Context(), &req)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to redeliver webhook")
+		return err
+	}
+
+	return c.JSON(200, toWebhookDeliveryDTO(d))
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	apperrors "dancer/internal/errors"
 	"dancer/internal/logger"
 	"dancer/internal/models"
 	"dancer/internal/services"
@@ -24,28 +25,39 @@ func NewZoneHandler(zoneService *services.ZoneService) *ZoneHandler {
 // toZoneDTO 将 Zone 实体转换为 ZoneDTO
 func toZoneDTO(zone *models.Zone) *models.ZoneDTO {
 	return &models.ZoneDTO{
-		Zone:        zone.Zone,
-		RecordCount: zone.RecordCount,
-		CreatedAt:   zone.CreatedAt,
-		UpdatedAt:   zone.UpdatedAt,
+		Zone:             zone.Zone,
+		RecordCount:      zone.RecordCount,
+		ApprovalRequired: zone.ApprovalRequired,
+		Publishers:       zone.PublisherNames(),
+		CreatedAt:        zone.CreatedAt,
+		UpdatedAt:        zone.UpdatedAt,
 	}
 }
 
-// ListZones 列出所有 Zone
+// ListZones 列出 Zone
 func (h *ZoneHandler) ListZones(c echo.Context) error {
-	zones, err := h.zoneService.ListZones(c.Request().Context())
+	var req models.ListZonesRequest
+	if err := c.Bind(&req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return apperrors.ErrInvalidInput
+	}
+
+	page, err := h.zoneService.ListZones(c.Request().Context(), &req)
 	if err != nil {
 		logger.Log.WithError(err).Error("Failed to list zones")
 		return err
 	}
 
 	// 转换为 DTO
-	dtos := make([]*models.ZoneDTO, len(zones))
-	for i, zone := range zones {
+	dtos := make([]*models.ZoneDTO, len(page.Items))
+	for i, zone := range page.Items {
 		dtos[i] = toZoneDTO(zone)
 	}
 
-	return c.JSON(200, &models.ZoneListDTO{Zones: dtos})
+	return c.JSON(200, &models.ZoneListDTO{Zones: dtos, Total: page.Total, NextCursor: page.NextCursor})
 }
 
 // GetZone 获取 Zone 详情
@@ -108,7 +120,7 @@ func (h *ZoneHandler) UpdateZone(c echo.Context) error {
 	return c.JSON(200, toZoneDTO(zone))
 }
 
-// DeleteZone 删除 Zone
+// DeleteZone 删除 Zone，返回回收站条目
 func (h *ZoneHandler) DeleteZone(c echo.Context) error {
 	var req models.DeleteZoneRequest
 	if err := c.Bind(&req); err != nil {
@@ -119,7 +131,8 @@ func (h *ZoneHandler) DeleteZone(c echo.Context) error {
 		return err
 	}
 
-	if err := h.zoneService.DeleteZone(c.Request().Context(), &req); err != nil {
+	entry, err := h.zoneService.DeleteZone(c.Request().Context(), &req)
+	if err != nil {
 		logger.Log.WithError(err).Error("Failed to delete zone")
 		return err
 	}
@@ -127,5 +140,6 @@ func (h *ZoneHandler) DeleteZone(c echo.Context) error {
 	return c.JSON(200, &models.Response{
 		Code:    "success",
 		Message: "Zone deleted successfully",
+		Data:    toTrashEntryDTO(entry),
 	})
 }
@@ -0,0 +1,216 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace 指标名前缀
+const namespace = "dancer"
+
+// inventoryTimeout 抓取时统计数据量的最长时间
+const inventoryTimeout = 5 * time.Second
+
+// Registry 所有指标注册在该 Registry 中，由 Handler 输出
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// HTTP 请求
+var (
+	HTTPRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// etcd 操作与连接
+var (
+	EtcdOperationDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "etcd_operation_duration_seconds",
+		Help:      "etcd operation latency by storage method and operation.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 14),
+	}, []string{"method", "op"})
+
+	EtcdOperationErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "etcd_operation_errors_total",
+		Help:      "Failed etcd operations by storage method and operation.",
+	}, []string{"method", "op"})
+
+	EtcdReconnects = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "etcd_reconnects_total",
+		Help:      "Successful etcd connections after the first one.",
+	})
+
+	EtcdConnectFailures = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "etcd_connect_failures_total",
+		Help:      "Failed etcd connection attempts.",
+	})
+)
+
+// 登录
+var Logins = factory.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "logins_total",
+	Help:      "Login attempts by result: success, failure (invalid credentials) or error.",
+}, []string{"result"})
+
+// CoreDNS 记录同步
+var (
+	CoreDNSSyncs = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "coredns_sync_total",
+		Help:      "etcd writes carrying CoreDNS record changes by result: success, failure or conflict.",
+	}, []string{"result"})
+
+	CoreDNSRecordWrites = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "coredns_record_writes_total",
+		Help:      "CoreDNS record keys written by operation: put or delete.",
+	}, []string{"op"})
+)
+
+// DNS 校验
+var DNSVerifications = factory.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "dns_verifications_total",
+	Help:      "Domains verified against the configured DNS server by status: match, mismatch or error.",
+}, []string{"status"})
+
+// 内置 DNS 服务器
+var DNSQueries = factory.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "dns_queries_total",
+	Help:      "Queries answered by the embedded DNS server by query type, response code and source: local or forward.",
+}, []string{"type", "rcode", "source"})
+
+// 外部发布目标同步
+var Publishes = factory.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "publish_total",
+	Help:      "Sync operations against external publishers by publisher and result: success or failure.",
+}, []string{"publisher", "result"})
+
+// Handler 返回 Prometheus 抓取端点
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveLogin 按登录结果计数
+func ObserveLogin(err error) {
+	switch {
+	case err == nil:
+		Logins.WithLabelValues("success").Inc()
+	case errors.Is(err, apperrors.ErrInvalidCredentials):
+		Logins.WithLabelValues("failure").Inc()
+	default:
+		Logins.WithLabelValues("error").Inc()
+	}
+}
+
+// ObserveDNSQuery 按查询类型、应答码与来源计数；qtype 为支持的类型以外的值时记为 other，避免标签数量无限增长
+func ObserveDNSQuery(qtype, rcode string, forwarded bool) {
+	switch qtype {
+	case "A", "AAAA", "CNAME", "TXT", "SRV", "MX", "SOA", "NS":
+	default:
+		qtype = "other"
+	}
+	source := "local"
+	if forwarded {
+		source = "forward"
+	}
+	DNSQueries.WithLabelValues(qtype, rcode, source).Inc()
+}
+
+// ObservePublish 按发布目标与结果计数一次同步操作
+func ObservePublish(publisher string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	Publishes.WithLabelValues(publisher, result).Inc()
+}
+
+// RegisterEtcdState 注册 etcd 连接状态，state 返回 etcd.Client.GetState 的值
+func RegisterEtcdState(state func() int) {
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "etcd_connection_state",
+		Help:      "etcd connection state: 0 disconnected, 1 connecting, 2 connected.",
+	}, func() float64 { return float64(state()) })
+}
+
+// Inventory Zone、Domain 与记录数量
+type Inventory struct {
+	Zones     int
+	Domains   int
+	Records   int // 地址记录数量，包括静态 IP 与动态实例
+	Instances int // 动态实例数量
+}
+
+// RegisterInventory 注册数据量统计，每次抓取时调用 fn；fn 返回错误时不输出这些指标
+func RegisterInventory(fn func(ctx context.Context) (*Inventory, error)) {
+	Registry.MustRegister(&inventoryCollector{
+		fn:        fn,
+		zones:     prometheus.NewDesc(namespace+"_zones", "Number of zones.", nil, nil),
+		domains:   prometheus.NewDesc(namespace+"_domains", "Number of domains.", nil, nil),
+		records:   prometheus.NewDesc(namespace+"_records", "Number of address records, static IPs plus dynamic instances.", nil, nil),
+		instances: prometheus.NewDesc(namespace+"_instances", "Number of registered dynamic instances.", nil, nil),
+	})
+}
+
+// inventoryCollector 抓取时统计数据量
+type inventoryCollector struct {
+	fn                                 func(ctx context.Context) (*Inventory, error)
+	zones, domains, records, instances *prometheus.Desc
+}
+
+func (c *inventoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.zones
+	ch <- c.domains
+	ch <- c.records
+	ch <- c.instances
+}
+
+func (c *inventoryCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), inventoryTimeout)
+	defer cancel()
+
+	inv, err := c.fn(ctx)
+	if err != nil {
+		logger.Log.WithError(err).Debug("Failed to collect inventory metrics")
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.zones, prometheus.GaugeValue, float64(inv.Zones))
+	ch <- prometheus.MustNewConstMetric(c.domains, prometheus.GaugeValue, float64(inv.Domains))
+	ch <- prometheus.MustNewConstMetric(c.records, prometheus.GaugeValue, float64(inv.Records))
+	ch <- prometheus.MustNewConstMetric(c.instances, prometheus.GaugeValue, float64(inv.Instances))
+}
@@ -0,0 +1,72 @@
+package models
+
+// BackupVersion 当前备份格式版本，恢复时拒绝更高版本的备份
+const BackupVersion = 1
+
+// Backup Dancer 全量备份内容
+// 只包含用户、Zone、Domain 及其 CoreDNS 记录；临时 Domain 与动态实例随租约存在，不备份
+type Backup struct {
+	Version           int               `json:"version"`
+	CreatedAt         int64             `json:"created_at"`
+	Revision          int64             `json:"revision"`           // 导出时的 etcd revision，所有数据取自该 revision
+	CoreDNSPrefix     string            `json:"coredns_prefix"`     // 导出时的 CoreDNS etcd 前缀
+	PasswordsIncluded bool              `json:"passwords_included"` // 是否包含用户密码哈希
+	Users             []*User           `json:"users"`
+	Zones             []*Zone           `json:"zones"`
+	Domains           []*Domain         `json:"domains"`
+	CoreDNSRecords    []*BackupKeyValue `json:"coredns_records"` // Domain 的 CoreDNS 静态记录，恢复时由 Domain 重新生成
+}
+
+// BackupKeyValue 备份中的原始 etcd 键值
+type BackupKeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// RestoreMode 恢复模式
+type RestoreMode string
+
+const (
+	RestoreModeReplace RestoreMode = "replace" // 以备份为准，删除备份中没有的用户、Zone 与 Domain
+	RestoreModeMerge   RestoreMode = "merge"   // 写入备份中的数据，保留备份中没有的数据
+)
+
+// RestoreStats 某类数据的恢复统计
+type RestoreStats struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Deleted   int `json:"deleted"`
+	Unchanged int `json:"unchanged"`
+}
+
+// RestoreSkip 恢复时跳过的数据及原因
+type RestoreSkip struct {
+	Type   string `json:"type"` // user / domain
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// RestorePlan 恢复需要执行的写入与删除
+// 删除的 Zone / Domain 移入回收站
+type RestorePlan struct {
+	PutUsers      []*User
+	DeleteUsers   []*User
+	PutZones      []*Zone
+	DeleteZones   []*Zone
+	PutDomains    []*Domain // 新建或有变化的 Domain，同时同步 CoreDNS 记录
+	SyncDomains   []*Domain // 未变化的 Domain，只同步 CoreDNS 记录
+	DeleteDomains []*Domain
+}
+
+// RestoreResult 恢复结果
+// Problems 不为空时备份未通过校验，不做任何修改
+type RestoreResult struct {
+	Mode     RestoreMode    `json:"mode"`
+	DryRun   bool           `json:"dry_run"`
+	Applied  bool           `json:"applied"`
+	Problems []string       `json:"problems"`
+	Users    RestoreStats   `json:"users"`
+	Zones    RestoreStats   `json:"zones"`
+	Domains  RestoreStats   `json:"domains"`
+	Skipped  []*RestoreSkip `json:"skipped"`
+}
@@ -0,0 +1,92 @@
+package models
+
+// ChangeOp Domain 变更操作类型
+type ChangeOp string
+
+const (
+	ChangeOpCreate ChangeOp = "create"
+	ChangeOpUpdate ChangeOp = "update"
+	ChangeOpDelete ChangeOp = "delete"
+)
+
+// DomainChange 单个 Domain 变更
+type DomainChange struct {
+	Op     ChangeOp `json:"op" validate:"required,oneof=create update delete"`
+	Domain string   `json:"domain" validate:"required"`
+	IPs    []string `json:"ips,omitempty" validate:"required_unless=Op delete,omitempty,dive,ip"`
+	TTL    int      `json:"ttl,omitempty" validate:"omitempty,min=1"` // create 必填，update 不填则保持原值
+}
+
+// ChangeRequestStatus 变更申请状态
+type ChangeRequestStatus string
+
+const (
+	ChangeRequestPending  ChangeRequestStatus = "pending"  // 等待审批
+	ChangeRequestApproved ChangeRequestStatus = "approved" // 已批准，正在应用
+	ChangeRequestApplied  ChangeRequestStatus = "applied"  // 已批准并成功应用
+	ChangeRequestRejected ChangeRequestStatus = "rejected" // 已驳回
+	ChangeRequestFailed   ChangeRequestStatus = "failed"   // 已批准但应用失败
+)
+
+// ChangeRequest 变更申请：针对一个 Zone 的一批 Domain 变更，经审批后原子应用
+type ChangeRequest struct {
+	ID            string              `json:"id"`
+	Tenant        string              `json:"tenant,omitempty"` // 所属租户，为空时属于默认租户
+	Zone          string              `json:"zone"`             // 目标 zone
+	Title         string              `json:"title"`            // 变更说明
+	Changes       []*DomainChange     `json:"changes"`          // 变更列表
+	Status        ChangeRequestStatus `json:"status"`           // 状态
+	ProposedBy    string              `json:"proposed_by"`      // 申请人用户 ID
+	ReviewedBy    string              `json:"reviewed_by"`      // 审批人用户 ID
+	ReviewComment string              `json:"review_comment"`   // 审批意见
+	Error         string              `json:"error"`            // 应用失败原因
+	CreatedAt     int64               `json:"created_at"`       // 创建时间戳
+	UpdatedAt     int64               `json:"updated_at"`       // 更新时间戳
+	ReviewedAt    int64               `json:"reviewed_at"`      // 审批时间戳
+	AppliedAt     int64               `json:"applied_at"`       // 应用时间戳
+	Revision      int64               `json:"-"`                // etcd mod revision，用于并发控制
+}
+
+// RecordSet Domain 记录集
+type RecordSet struct {
+	IPs []string `json:"ips"`
+	TTL int      `json:"ttl"`
+}
+
+// DomainChangeDiff 单个 Domain 变更与当前状态的差异
+type DomainChangeDiff struct {
+	Op         ChangeOp   `json:"op"`
+	Domain     string     `json:"domain"`
+	Before     *RecordSet `json:"before"`             // 当前记录集，Domain 不存在时为 null
+	After      *RecordSet `json:"after"`              // 变更后记录集，删除时为 null
+	AddedIPs   []string   `json:"added_ips"`          // 新增的 IP
+	RemovedIPs []string   `json:"removed_ips"`        // 移除的 IP
+	Conflict   string     `json:"conflict,omitempty"` // 与当前状态冲突的原因，如 Domain 已存在 / 不存在
+}
+
+// BatchMode 批量变更模式
+type BatchMode string
+
+const (
+	BatchModeAtomic     BatchMode = "atomic"      // 全部成功或全部不生效
+	BatchModeBestEffort BatchMode = "best_effort" // 逐项应用，失败项不影响其他项
+)
+
+// BatchItemStatus 批量变更单项结果状态
+type BatchItemStatus string
+
+const (
+	BatchItemSucceeded BatchItemStatus = "succeeded" // 已应用
+	BatchItemFailed    BatchItemStatus = "failed"    // 校验或应用失败
+	BatchItemSkipped   BatchItemStatus = "skipped"   // atomic 模式下因其他项失败而未应用
+)
+
+// BatchItemResult 批量变更单项结果
+type BatchItemResult struct {
+	Index  int             // 在请求中的序号，从 0 开始
+	Op     ChangeOp        // 操作类型
+	Domain string          // 子域名
+	Status BatchItemStatus // 结果状态
+	Error  string          // 失败原因
+	Result *Domain         // 应用后的 Domain，删除或未成功时为 nil
+}
@@ -2,12 +2,30 @@ package models
 
 // Domain 完整域名模型
 type Domain struct {
-	Zone        string   `json:"zone"`         // 所属 zone，如 example.com
-	Domain      string   `json:"domain"`       // 子域名部分，如 www
-	Name        string   `json:"name"`         // 完整域名，如 www.example.com
-	IPs         []string `json:"ips"`          // IP 地址列表
-	TTL         int      `json:"ttl"`          // TTL (秒)
-	RecordCount int      `json:"record_count"` // IP 记录数量
-	CreatedAt   int64    `json:"created_at"`   // 创建时间戳
-	UpdatedAt   int64    `json:"updated_at"`   // 更新时间戳
+	Zone        string              `json:"zone"`                 // 所属 zone，如 example.com
+	Domain      string              `json:"domain"`               // 子域名部分，如 www
+	Name        string              `json:"name"`                 // 完整域名，如 www.example.com
+	IPs         []string            `json:"ips"`                  // 静态 IP 地址列表，即默认视图 coredns 的记录
+	ViewIPs     map[string][]string `json:"view_ips,omitempty"`   // 按 CoreDNS 视图覆盖的静态 IP，未覆盖的视图使用 IPs
+	DynamicIPs  []string            `json:"-"`                    // 动态注册实例的 IP，读取时填充，不持久化
+	TTL         int                 `json:"ttl"`                  // TTL (秒)
+	RecordCount int                 `json:"record_count"`         // IP 记录数量
+	LeaseID     int64               `json:"lease_id,omitempty"`   // 绑定的 etcd 租约 ID，0 表示永久记录
+	LeaseTTL    int64               `json:"lease_ttl,omitempty"`  // 租约时长 (秒)
+	ExpiresAt   int64               `json:"expires_at,omitempty"` // 租约到期时间戳（最近一次续约后）
+	CreatedAt   int64               `json:"created_at"`           // 创建时间戳
+	UpdatedAt   int64               `json:"updated_at"`           // 更新时间戳
+}
+
+// IsEphemeral 是否为绑定租约的临时 Domain
+func (d *Domain) IsEphemeral() bool {
+	return d.LeaseID != 0
+}
+
+// IPsFor 返回 Domain 在指定 CoreDNS 视图中的静态 IP
+func (d *Domain) IPsFor(view string) []string {
+	if ips, ok := d.ViewIPs[view]; ok {
+		return ips
+	}
+	return d.IPs
 }
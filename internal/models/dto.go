@@ -1,9 +1,12 @@
 package models
 
+import "encoding/json"
+
 // 请求 DTO
 
 // LoginRequest 登录请求
 type LoginRequest struct {
+	Tenant   string `json:"tenant"` // 用户所属租户，不填时为默认租户
 	Username string `json:"username" validate:"required"`
 	Password string `json:"password" validate:"required"`
 }
@@ -12,7 +15,7 @@ type LoginRequest struct {
 type CreateUserRequest struct {
 	Username string   `json:"username" validate:"required,min=3,max=32"`
 	Password string   `json:"password" validate:"required,min=6,max=72"`
-	UserType UserType `json:"user_type" validate:"required,oneof=admin normal"`
+	UserType UserType `json:"user_type" validate:"required,oneof=super_admin admin normal"`
 }
 
 // UpdateUserRequest 更新用户请求
@@ -20,7 +23,17 @@ type UpdateUserRequest struct {
 	ID       string   `json:"id" validate:"required"`
 	Username string   `json:"username" validate:"omitempty,min=3,max=32"`
 	Password string   `json:"password" validate:"omitempty,min=6,max=72"`
-	UserType UserType `json:"user_type" validate:"omitempty,oneof=admin normal"`
+	UserType UserType `json:"user_type" validate:"omitempty,oneof=super_admin admin normal"`
+}
+
+// ListUsersRequest 列出用户请求
+type ListUsersRequest struct {
+	PageRequest
+	Sort         string   `json:"sort" query:"sort" validate:"omitempty,oneof=id username created_at updated_at"` // 排序字段，默认 id
+	NamePrefix   string   `json:"name_prefix" query:"name_prefix"`                                                // 用户名前缀
+	NameContains string   `json:"name_contains" query:"name_contains"`                                            // 用户名包含（不区分大小写）
+	UserType     UserType `json:"user_type" query:"user_type" validate:"omitempty,oneof=super_admin admin normal"`
+	UpdatedSince int64    `json:"updated_since" query:"updated_since" validate:"omitempty,min=0"` // 更新时间不早于该时间戳
 }
 
 // DeleteUserRequest 删除用户请求
@@ -36,8 +49,14 @@ type ChangePasswordRequest struct {
 
 // Zone 相关请求
 
-// ListZonesRequest 列出所有 Zone 请求
-type ListZonesRequest struct{}
+// ListZonesRequest 列出 Zone 请求，所有字段均可选
+type ListZonesRequest struct {
+	PageRequest
+	Sort         string `json:"sort" query:"sort" validate:"omitempty,oneof=name record_count created_at updated_at"` // 排序字段，默认 name
+	NamePrefix   string `json:"name_prefix" query:"name_prefix"`                                                      // Zone 名称前缀
+	NameContains string `json:"name_contains" query:"name_contains"`                                                  // Zone 名称包含（不区分大小写）
+	UpdatedSince int64  `json:"updated_since" query:"updated_since" validate:"omitempty,min=0"`                       // 更新时间不早于该时间戳
+}
 
 // GetZoneRequest 获取 Zone 详情请求
 type GetZoneRequest struct {
@@ -46,17 +65,22 @@ type GetZoneRequest struct {
 
 // CreateZoneRequest 创建 Zone 请求
 type CreateZoneRequest struct {
-	Zone string `json:"zone" validate:"required,fqdn"`
+	Zone             string   `json:"zone" validate:"required,fqdn"`
+	ApprovalRequired bool     `json:"approval_required"`                             // 可选，Domain 变更是否必须经过审批
+	Publishers       []string `json:"publishers" validate:"omitempty,dive,required"` // 可选，发布目标名称，默认只发布到 coredns
 }
 
 // UpdateZoneRequest 更新 Zone 请求
 type UpdateZoneRequest struct {
-	Zone string `json:"zone" validate:"required,fqdn"`
+	Zone             string   `json:"zone" validate:"required,fqdn"`
+	ApprovalRequired *bool    `json:"approval_required"`                             // 可选，不填则保持原值
+	Publishers       []string `json:"publishers" validate:"omitempty,dive,required"` // 可选，不填则保持原值，空数组恢复为只发布到 coredns
 }
 
 // DeleteZoneRequest 删除 Zone 请求
 type DeleteZoneRequest struct {
-	Zone string `json:"zone" validate:"required,fqdn"`
+	Zone    string `json:"zone" validate:"required,fqdn"`
+	Confirm string `json:"confirm"` // Zone 下仍有 Domain 时必须填写 Zone 名称以确认删除
 }
 
 // Domain 相关请求
@@ -64,6 +88,14 @@ type DeleteZoneRequest struct {
 // ListDomainsRequest 列出 Zone 下所有 Domain 请求
 type ListDomainsRequest struct {
 	Zone string `json:"zone" validate:"required,fqdn"`
+	PageRequest
+	Sort         string `json:"sort" query:"sort" validate:"omitempty,oneof=name ttl created_at updated_at"` // 排序字段，默认 name
+	NamePrefix   string `json:"name_prefix" query:"name_prefix"`                                             // 子域名前缀
+	NameContains string `json:"name_contains" query:"name_contains"`                                         // 子域名包含（不区分大小写）
+	IP           string `json:"ip" query:"ip" validate:"omitempty,ip|cidr"`                                  // 包含该 IP 或属于该网段的 IP（含动态实例）
+	TTLMin       int    `json:"ttl_min" query:"ttl_min" validate:"omitempty,min=0"`                          // TTL 下限
+	TTLMax       int    `json:"ttl_max" query:"ttl_max" validate:"omitempty,min=0"`                          // TTL 上限
+	UpdatedSince int64  `json:"updated_since" query:"updated_since" validate:"omitempty,min=0"`              // 更新时间不早于该时间戳
 }
 
 // GetDomainRequest 获取 Domain 详情请求
@@ -74,18 +106,21 @@ type GetDomainRequest struct {
 
 // CreateDomainRequest 创建 Domain 请求
 type CreateDomainRequest struct {
-	Zone   string   `json:"zone" validate:"required,fqdn"`
-	Domain string   `json:"domain" validate:"required"`
-	IPs    []string `json:"ips" validate:"required,dive,ip"`
-	TTL    int      `json:"ttl" validate:"required,min=1"`
+	Zone     string              `json:"zone" validate:"required,fqdn"`
+	Domain   string              `json:"domain" validate:"required"`
+	IPs      []string            `json:"ips" validate:"required,dive,ip"`
+	ViewIPs  map[string][]string `json:"view_ips" validate:"omitempty,dive,keys,required,endkeys,min=1,dive,ip"` // 可选，按 CoreDNS 视图覆盖的 IP
+	TTL      int                 `json:"ttl" validate:"required,min=1"`
+	LeaseTTL int64               `json:"lease_ttl" validate:"omitempty,min=30,max=86400"` // 可选，设置后 Domain 在租约到期后自动删除
 }
 
 // UpdateDomainRequest 更新 Domain 请求
 type UpdateDomainRequest struct {
-	Zone   string   `json:"zone" validate:"required,fqdn"`
-	Domain string   `json:"domain" validate:"required"`
-	IPs    []string `json:"ips" validate:"required,dive,ip"`
-	TTL    int      `json:"ttl" validate:"omitempty,min=1"`
+	Zone    string              `json:"zone" validate:"required,fqdn"`
+	Domain  string              `json:"domain" validate:"required"`
+	IPs     []string            `json:"ips" validate:"required,dive,ip"`
+	ViewIPs map[string][]string `json:"view_ips" validate:"omitempty,dive,keys,required,endkeys,min=1,dive,ip"` // 不填时保持不变，传 {} 清除所有视图覆盖
+	TTL     int                 `json:"ttl" validate:"omitempty,min=1"`
 }
 
 // DeleteDomainRequest 删除 Domain 请求
@@ -94,6 +129,261 @@ type DeleteDomainRequest struct {
 	Domain string `json:"domain" validate:"required"`
 }
 
+// RenewDomainRequest 续约临时 Domain 请求
+type RenewDomainRequest struct {
+	Zone   string `json:"zone" validate:"required,fqdn"`
+	Domain string `json:"domain" validate:"required"`
+}
+
+// API Token 相关请求
+
+// CreateAPITokenRequest 创建 API Token 请求
+type CreateAPITokenRequest struct {
+	Name    string   `json:"name" validate:"required,max=64"`
+	Zone    string   `json:"zone" validate:"required,fqdn"`
+	Domains []string `json:"domains" validate:"omitempty,dive,required"`
+}
+
+// DeleteAPITokenRequest 删除 API Token 请求
+type DeleteAPITokenRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// 服务注册相关请求
+
+// RegisterInstanceRequest 注册动态实例请求
+type RegisterInstanceRequest struct {
+	Zone       string `json:"zone" validate:"required,fqdn"`
+	Domain     string `json:"domain" validate:"required"`
+	InstanceID string `json:"instance_id" validate:"required,max=64,hostname_rfc1123"`
+	IP         string `json:"ip" validate:"required,ip"`
+	LeaseTTL   int64  `json:"lease_ttl" validate:"omitempty,min=5,max=3600"` // 可选，默认 30 秒
+}
+
+// HeartbeatInstanceRequest 实例心跳请求
+type HeartbeatInstanceRequest struct {
+	Zone       string `json:"zone" validate:"required,fqdn"`
+	Domain     string `json:"domain" validate:"required"`
+	InstanceID string `json:"instance_id" validate:"required"`
+}
+
+// DeregisterInstanceRequest 注销动态实例请求
+type DeregisterInstanceRequest struct {
+	Zone       string `json:"zone" validate:"required,fqdn"`
+	Domain     string `json:"domain" validate:"required"`
+	InstanceID string `json:"instance_id" validate:"required"`
+}
+
+// ListInstancesRequest 列出 Domain 下动态实例请求
+type ListInstancesRequest struct {
+	Zone   string `json:"zone" validate:"required,fqdn"`
+	Domain string `json:"domain" validate:"required"`
+}
+
+// 定时变更相关请求
+
+// ListSchedulesRequest 列出定时变更请求
+type ListSchedulesRequest struct {
+	Zone   string         `json:"zone" validate:"omitempty,fqdn"`
+	Status ScheduleStatus `json:"status" validate:"omitempty,oneof=pending running succeeded failed cancelled"`
+}
+
+// GetScheduleRequest 获取定时变更请求
+type GetScheduleRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// CreateScheduleRequest 创建定时变更请求
+type CreateScheduleRequest struct {
+	Zone         string   `json:"zone" validate:"required,fqdn"`
+	Domain       string   `json:"domain" validate:"required"`
+	IPs          []string `json:"ips" validate:"required,dive,ip"`
+	TTL          int      `json:"ttl" validate:"omitempty,min=1"`                   // 可选，不填则保持原值
+	ExecuteAt    int64    `json:"execute_at" validate:"required"`                   // 执行时间 (Unix 时间戳)，必须晚于当前时间
+	LowerTTL     int      `json:"lower_ttl" validate:"omitempty,min=1"`             // 可选，执行前提前降低到的 TTL
+	LowerTTLLead int64    `json:"lower_ttl_lead" validate:"required_with=LowerTTL"` // 提前降低 TTL 的时长 (秒)
+}
+
+// CancelScheduleRequest 取消定时变更请求
+type CancelScheduleRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// BatchDomainsRequest 批量变更 Domain 请求
+type BatchDomainsRequest struct {
+	Zone  string          `json:"zone" validate:"required,fqdn"`
+	Mode  BatchMode       `json:"mode" validate:"omitempty,oneof=atomic best_effort"` // 可选，默认 atomic
+	Items []*DomainChange `json:"items" validate:"required,min=1,max=1000"`           // 单项在处理时逐一校验
+}
+
+// SearchDomainsRequest 跨 Zone 搜索 Domain 请求
+type SearchDomainsRequest struct {
+	Query string     `json:"query" validate:"required,max=253"`
+	Type  SearchType `json:"type" validate:"omitempty,oneof=auto name ip cidr text"` // 可选，默认 auto
+	Zone  string     `json:"zone" validate:"omitempty,fqdn"`                         // 可选，限定 Zone
+	Limit int        `json:"limit" validate:"omitempty,min=1,max=1000"`              // 可选，默认 100
+}
+
+// 变更申请相关请求
+
+// ListChangeRequestsRequest 列出变更申请请求
+type ListChangeRequestsRequest struct {
+	Zone   string              `json:"zone" validate:"omitempty,fqdn"`
+	Status ChangeRequestStatus `json:"status" validate:"omitempty,oneof=pending approved applied rejected failed"`
+}
+
+// GetChangeRequestRequest 获取变更申请请求
+type GetChangeRequestRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// ProposeChangeRequest 提交变更申请请求
+type ProposeChangeRequest struct {
+	Zone    string          `json:"zone" validate:"required,fqdn"`
+	Title   string          `json:"title" validate:"required,max=200"`
+	Changes []*DomainChange `json:"changes" validate:"required,min=1,dive,required"`
+}
+
+// ReviewChangeRequest 审批（批准 / 驳回）变更申请请求
+type ReviewChangeRequest struct {
+	ID      string `json:"id" validate:"required"`
+	Comment string `json:"comment" validate:"max=500"`
+}
+
+// 事件流相关请求
+
+// StreamEventsRequest 订阅变更事件请求（查询参数）
+type StreamEventsRequest struct {
+	Zone     string      `query:"zone" validate:"omitempty,fqdn"`                         // 可选，限定 Zone
+	Types    []EventType `query:"types" validate:"omitempty,dive,oneof=zone domain user"` // 可选，可重复，默认全部
+	Revision int64       `query:"revision" validate:"min=0"`                              // 可选，从该 revision 之后开始推送
+}
+
+// Webhook 相关请求
+
+// CreateWebhookRequest 创建 Webhook 订阅请求
+type CreateWebhookRequest struct {
+	Name    string      `json:"name" validate:"required,max=64"`
+	URL     string      `json:"url" validate:"required,http_url,max=2048"`
+	Secret  string      `json:"secret" validate:"omitempty,min=16,max=256"`               // 可选，默认随机生成
+	Zones   []string    `json:"zones" validate:"omitempty,dive,fqdn"`                     // 可选，默认全部 Zone
+	Types   []EventType `json:"types" validate:"omitempty,dive,oneof=zone domain"`        // 可选，默认全部
+	Ops     []EventOp   `json:"ops" validate:"omitempty,dive,oneof=create update delete"` // 可选，默认全部
+	Enabled *bool       `json:"enabled"`                                                  // 可选，默认启用
+}
+
+// UpdateWebhookRequest 更新 Webhook 订阅请求，name / url / 过滤条件整体替换
+type UpdateWebhookRequest struct {
+	ID           string      `json:"id" validate:"required"`
+	Name         string      `json:"name" validate:"required,max=64"`
+	URL          string      `json:"url" validate:"required,http_url,max=2048"`
+	Zones        []string    `json:"zones" validate:"omitempty,dive,fqdn"`
+	Types        []EventType `json:"types" validate:"omitempty,dive,oneof=zone domain"`
+	Ops          []EventOp   `json:"ops" validate:"omitempty,dive,oneof=create update delete"`
+	Enabled      *bool       `json:"enabled"`       // 可选，不传保持原值
+	RotateSecret bool        `json:"rotate_secret"` // 是否重新生成签名密钥
+}
+
+// GetWebhookRequest 获取 / 删除 Webhook 订阅请求
+type GetWebhookRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// ListWebhookDeliveriesRequest 列出投递记录请求
+type ListWebhookDeliveriesRequest struct {
+	WebhookID string         `json:"webhook_id" validate:"required"`
+	Status    DeliveryStatus `json:"status" validate:"omitempty,oneof=pending succeeded dead"` // 可选，dead 即死信列表
+	Limit     int            `json:"limit" validate:"omitempty,min=1,max=1000"`                // 可选，默认 100
+}
+
+// RedeliverWebhookRequest 重新投递请求
+type RedeliverWebhookRequest struct {
+	WebhookID string `json:"webhook_id" validate:"required"`
+	ID        string `json:"id" validate:"required"`
+}
+
+// DomainHistoryRequest 查询 Domain 历史版本请求
+type DomainHistoryRequest struct {
+	Zone   string `json:"zone" validate:"required,fqdn"`
+	Domain string `json:"domain" validate:"required"`
+	Limit  int    `json:"limit" validate:"omitempty,min=1,max=1000"` // 可选，默认返回全部保留的版本
+}
+
+// DiffDomainVersionsRequest 对比 Domain 两个版本请求
+type DiffDomainVersionsRequest struct {
+	Zone         string `json:"zone" validate:"required,fqdn"`
+	Domain       string `json:"domain" validate:"required"`
+	FromRevision int64  `json:"from_revision" validate:"required,min=1"`
+	ToRevision   int64  `json:"to_revision" validate:"omitempty,min=1"` // 可选，默认与当前状态对比
+}
+
+// RollbackDomainRequest 回滚 Domain 到历史版本请求
+type RollbackDomainRequest struct {
+	Zone     string `json:"zone" validate:"required,fqdn"`
+	Domain   string `json:"domain" validate:"required"`
+	Revision int64  `json:"revision" validate:"required,min=1"`
+}
+
+// ListTrashRequest 列出回收站条目请求
+type ListTrashRequest struct {
+	Zone string `json:"zone" validate:"omitempty,fqdn"` // 可选，只列出该 Zone 的条目
+}
+
+// TrashEntryRequest 恢复 / 永久删除回收站条目请求
+type TrashEntryRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// CreateTenantRequest 创建租户请求，同时创建租户的第一个管理员
+type CreateTenantRequest struct {
+	ID            string `json:"id" validate:"required,max=63,hostname_rfc1123,excludes=.,lowercase"` // 小写字母、数字与连字符
+	Name          string `json:"name" validate:"required,max=128"`
+	AdminUsername string `json:"admin_username" validate:"required,min=3,max=32"`
+	AdminPassword string `json:"admin_password" validate:"required,min=6,max=72"`
+}
+
+// TenantRequest 获取 / 删除租户请求
+type TenantRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// ExportBackupRequest 导出备份请求
+type ExportBackupRequest struct {
+	ExcludePasswordHashes bool   `json:"exclude_password_hashes"`               // 为 true 时不导出用户密码哈希
+	Passphrase            string `json:"passphrase" validate:"omitempty,min=8"` // 可选，设置后备份以该口令加密
+}
+
+// SyncRequest 声明式同步请求，zones 为期望状态
+type SyncRequest struct {
+	Prune bool           `json:"prune"` // 为 true 时删除期望状态中没有的 Zone 与 Domain
+	Zones []*DesiredZone `json:"zones"` // 在服务层逐项校验，问题在计划中报告
+}
+
+// RestoreBackupRequest 恢复备份请求（multipart 表单，备份文件在 archive 字段中）
+type RestoreBackupRequest struct {
+	Mode       RestoreMode `form:"mode" validate:"required,oneof=replace merge"`
+	DryRun     bool        `form:"dry_run"`    // 为 true 时只校验并返回将要进行的修改
+	Passphrase string      `form:"passphrase"` // 加密备份的口令
+}
+
+// RollbackZoneRequest 回滚 Zone 下所有 Domain 到指定时间点请求
+type RollbackZoneRequest struct {
+	Zone      string `json:"zone" validate:"required,fqdn"`
+	Timestamp int64  `json:"timestamp" validate:"required,min=1"`
+	DryRun    bool   `json:"dry_run"` // 为 true 时只返回将要应用的变更
+}
+
+// VerifyDomainRequest 校验 Domain 或获取最近一次校验结果请求
+type VerifyDomainRequest struct {
+	Zone   string `json:"zone" validate:"required,fqdn"`
+	Domain string `json:"domain" validate:"required"`
+}
+
+// VerifyZoneRequest 校验 Zone 或获取 Zone 最近一次校验结果请求
+type VerifyZoneRequest struct {
+	Zone string `json:"zone" validate:"required,fqdn"`
+}
+
 // 响应 DTO
 
 // Response 统一响应结构
@@ -119,35 +409,277 @@ type UserDTO struct {
 
 // UserListDTO 用户列表 DTO
 type UserListDTO struct {
-	Users []*UserDTO `json:"users"`
+	Users      []*UserDTO `json:"users"`
+	Total      int        `json:"total"`                 // 满足过滤条件的总数
+	NextCursor string     `json:"next_cursor,omitempty"` // 下一页游标
 }
 
 // ZoneDTO Zone DTO
 type ZoneDTO struct {
-	Zone        string `json:"zone"`
-	RecordCount int    `json:"record_count"`
-	CreatedAt   int64  `json:"created_at"`
-	UpdatedAt   int64  `json:"updated_at"`
+	Zone             string   `json:"zone"`
+	RecordCount      int      `json:"record_count"`
+	ApprovalRequired bool     `json:"approval_required"`
+	Publishers       []string `json:"publishers"`
+	CreatedAt        int64    `json:"created_at"`
+	UpdatedAt        int64    `json:"updated_at"`
 }
 
 // ZoneListDTO Zone 列表 DTO
 type ZoneListDTO struct {
-	Zones []*ZoneDTO `json:"zones"`
+	Zones      []*ZoneDTO `json:"zones"`
+	Total      int        `json:"total"`                 // 满足过滤条件的总数
+	NextCursor string     `json:"next_cursor,omitempty"` // 下一页游标
 }
 
 // DomainDTO Domain DTO
 type DomainDTO struct {
-	Zone        string   `json:"zone"`
-	Domain      string   `json:"domain"`
-	Name        string   `json:"name"`
-	IPs         []string `json:"ips"`
-	TTL         int      `json:"ttl"`
-	RecordCount int      `json:"record_count"`
-	CreatedAt   int64    `json:"created_at"`
-	UpdatedAt   int64    `json:"updated_at"`
+	Zone        string              `json:"zone"`
+	Domain      string              `json:"domain"`
+	Name        string              `json:"name"`
+	IPs         []string            `json:"ips"`
+	ViewIPs     map[string][]string `json:"view_ips,omitempty"`
+	DynamicIPs  []string            `json:"dynamic_ips,omitempty"`
+	TTL         int                 `json:"ttl"`
+	RecordCount int                 `json:"record_count"`
+	LeaseTTL    int64               `json:"lease_ttl,omitempty"`
+	ExpiresAt   int64               `json:"expires_at,omitempty"`
+	CreatedAt   int64               `json:"created_at"`
+	UpdatedAt   int64               `json:"updated_at"`
 }
 
 // DomainListDTO Domain 列表 DTO
 type DomainListDTO struct {
-	Domains []*DomainDTO `json:"domains"`
+	Domains    []*DomainDTO `json:"domains"`
+	Total      int          `json:"total"`                 // 满足过滤条件的总数
+	NextCursor string       `json:"next_cursor,omitempty"` // 下一页游标
+}
+
+// SearchDomainsDTO Domain 搜索结果 DTO
+type SearchDomainsDTO struct {
+	Domains  []*DomainDTO `json:"domains"`
+	Total    int          `json:"total"`    // 匹配总数，可能大于返回条数
+	Revision int64        `json:"revision"` // 索引对应的 etcd revision
+}
+
+// APITokenDTO API Token DTO（不含 Token 摘要）
+type APITokenDTO struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Zone      string   `json:"zone"`
+	Domains   []string `json:"domains"`
+	CreatedBy string   `json:"created_by"`
+	CreatedAt int64    `json:"created_at"`
+}
+
+// APITokenCreatedDTO 创建 API Token 响应，明文 Token 仅返回这一次
+type APITokenCreatedDTO struct {
+	*APITokenDTO
+	Token string `json:"token"`
+}
+
+// APITokenListDTO API Token 列表 DTO
+type APITokenListDTO struct {
+	Tokens []*APITokenDTO `json:"tokens"`
+}
+
+// InstanceDTO 动态实例 DTO
+type InstanceDTO struct {
+	Zone          string `json:"zone"`
+	Domain        string `json:"domain"`
+	InstanceID    string `json:"instance_id"`
+	IP            string `json:"ip"`
+	LeaseTTL      int64  `json:"lease_ttl"`
+	RegisteredAt  int64  `json:"registered_at"`
+	LastHeartbeat int64  `json:"last_heartbeat"`
+}
+
+// InstanceListDTO 动态实例列表 DTO
+type InstanceListDTO struct {
+	Instances []*InstanceDTO `json:"instances"`
+}
+
+// ScheduleDTO 定时变更 DTO
+type ScheduleDTO struct {
+	ID           string         `json:"id"`
+	Zone         string         `json:"zone"`
+	Domain       string         `json:"domain"`
+	IPs          []string       `json:"ips"`
+	TTL          int            `json:"ttl"`
+	ExecuteAt    int64          `json:"execute_at"`
+	LowerTTL     int            `json:"lower_ttl,omitempty"`
+	LowerTTLLead int64          `json:"lower_ttl_lead,omitempty"`
+	TTLLoweredAt int64          `json:"ttl_lowered_at,omitempty"`
+	Status       ScheduleStatus `json:"status"`
+	Error        string         `json:"error,omitempty"`
+	CreatedBy    string         `json:"created_by"`
+	CreatedAt    int64          `json:"created_at"`
+	UpdatedAt    int64          `json:"updated_at"`
+	ExecutedAt   int64          `json:"executed_at,omitempty"`
+}
+
+// ScheduleListDTO 定时变更列表 DTO
+type ScheduleListDTO struct {
+	Schedules []*ScheduleDTO `json:"schedules"`
+}
+
+// BatchItemResultDTO 批量变更单项结果 DTO
+type BatchItemResultDTO struct {
+	Index  int             `json:"index"`
+	Op     ChangeOp        `json:"op"`
+	Domain string          `json:"domain"`
+	Status BatchItemStatus `json:"status"`
+	Error  string          `json:"error,omitempty"`
+	Result *DomainDTO      `json:"result,omitempty"`
+}
+
+// BatchDomainsResultDTO 批量变更结果 DTO
+type BatchDomainsResultDTO struct {
+	Zone      string                `json:"zone"`
+	Mode      BatchMode             `json:"mode"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Skipped   int                   `json:"skipped"`
+	Results   []*BatchItemResultDTO `json:"results"`
+}
+
+// ChangeRequestDTO 变更申请 DTO
+type ChangeRequestDTO struct {
+	ID            string              `json:"id"`
+	Zone          string              `json:"zone"`
+	Title         string              `json:"title"`
+	Changes       []*DomainChange     `json:"changes"`
+	Status        ChangeRequestStatus `json:"status"`
+	ProposedBy    string              `json:"proposed_by"`
+	ReviewedBy    string              `json:"reviewed_by,omitempty"`
+	ReviewComment string              `json:"review_comment,omitempty"`
+	Error         string              `json:"error,omitempty"`
+	CreatedAt     int64               `json:"created_at"`
+	UpdatedAt     int64               `json:"updated_at"`
+	ReviewedAt    int64               `json:"reviewed_at,omitempty"`
+	AppliedAt     int64               `json:"applied_at,omitempty"`
+	Diff          []*DomainChangeDiff `json:"diff,omitempty"` // 与当前状态的差异，仅待审批的申请返回
+}
+
+// ChangeRequestListDTO 变更申请列表 DTO
+type ChangeRequestListDTO struct {
+	ChangeRequests []*ChangeRequestDTO `json:"change_requests"`
+}
+
+// ChangeEventDTO 变更事件 DTO
+// data 为 Zone / Domain / 用户的 DTO：create / update 时为变更后的值，delete 时为删除前的值
+type ChangeEventDTO struct {
+	Revision int64       `json:"revision"`
+	Type     EventType   `json:"type"`
+	Op       EventOp     `json:"op"`
+	Zone     string      `json:"zone,omitempty"`
+	Name     string      `json:"name"`
+	Data     interface{} `json:"data,omitempty"`
+}
+
+// WebhookDTO Webhook 订阅 DTO（不含签名密钥）
+type WebhookDTO struct {
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	URL       string      `json:"url"`
+	Zones     []string    `json:"zones"`
+	Types     []EventType `json:"types"`
+	Ops       []EventOp   `json:"ops"`
+	Enabled   bool        `json:"enabled"`
+	CreatedBy string      `json:"created_by"`
+	CreatedAt int64       `json:"created_at"`
+	UpdatedAt int64       `json:"updated_at"`
+}
+
+// WebhookSecretDTO 创建或轮换密钥后的响应，签名密钥仅返回这一次
+type WebhookSecretDTO struct {
+	*WebhookDTO
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookListDTO Webhook 订阅列表 DTO
+type WebhookListDTO struct {
+	Webhooks []*WebhookDTO `json:"webhooks"`
+}
+
+// WebhookDeliveryDTO 投递记录 DTO
+type WebhookDeliveryDTO struct {
+	ID             string          `json:"id"`
+	WebhookID      string          `json:"webhook_id"`
+	EventType      EventType       `json:"event_type"`
+	EventOp        EventOp         `json:"event_op"`
+	Zone           string          `json:"zone"`
+	Name           string          `json:"name"`
+	Revision       int64           `json:"revision"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  int64           `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  int64           `json:"last_attempt_at,omitempty"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	CreatedAt      int64           `json:"created_at"`
+	DeliveredAt    int64           `json:"delivered_at,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+}
+
+// WebhookDeliveryListDTO 投递记录列表 DTO
+type WebhookDeliveryListDTO struct {
+	Deliveries []*WebhookDeliveryDTO `json:"deliveries"`
+	Total      int                   `json:"total"` // 满足过滤条件的总数，可能大于返回条数
+}
+
+// DomainVersionDTO Domain 历史版本 DTO
+type DomainVersionDTO struct {
+	Revision  int64      `json:"revision"`
+	Op        EventOp    `json:"op"`
+	Timestamp int64      `json:"timestamp"`
+	Domain    *DomainDTO `json:"domain"` // delete 版本为 null
+}
+
+// DomainHistoryDTO Domain 历史版本列表 DTO，按 revision 倒序
+type DomainHistoryDTO struct {
+	Zone     string              `json:"zone"`
+	Domain   string              `json:"domain"`
+	Versions []*DomainVersionDTO `json:"versions"`
+}
+
+// ZoneRollbackDTO Zone 回滚结果 DTO
+type ZoneRollbackDTO struct {
+	Zone      string              `json:"zone"`
+	Timestamp int64               `json:"timestamp"`
+	DryRun    bool                `json:"dry_run"`
+	Changes   []*DomainChangeDiff `json:"changes"`
+	Skipped   []*RollbackSkip     `json:"skipped"`
+}
+
+// TrashEntryDTO 回收站条目 DTO
+type TrashEntryDTO struct {
+	ID          string     `json:"id"`
+	Type        TrashType  `json:"type"`
+	Zone        string     `json:"zone"`
+	Domain      string     `json:"domain,omitempty"`
+	Record      *DomainDTO `json:"record,omitempty"`       // 删除前的 Domain，仅 Domain 条目
+	DomainCount int        `json:"domain_count,omitempty"` // 随 Zone 一起删除的 Domain 数量，仅 Zone 条目
+	DeletedAt   int64      `json:"deleted_at"`
+	ExpiresAt   int64      `json:"expires_at"`
+}
+
+// TrashListDTO 回收站条目列表 DTO
+type TrashListDTO struct {
+	Entries []*TrashEntryDTO `json:"entries"`
+}
+
+// ListPublishedRequest 读取发布目标上 Zone 的记录请求
+type ListPublishedRequest struct {
+	Publisher string `json:"publisher" validate:"required"`
+	Zone      string `json:"zone" validate:"required,fqdn"`
+}
+
+// TenantListDTO 租户列表 DTO
+type TenantListDTO struct {
+	Tenants []*Tenant `json:"tenants"`
+}
+
+// PublisherListDTO 发布目标列表 DTO
+type PublisherListDTO struct {
+	Publishers []*PublisherInfo `json:"publishers"`
 }
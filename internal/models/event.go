@@ -0,0 +1,33 @@
+package models
+
+// EventType 变更事件的资源类型
+type EventType string
+
+const (
+	EventTypeZone   EventType = "zone"
+	EventTypeDomain EventType = "domain"
+	EventTypeUser   EventType = "user"
+)
+
+// EventOp 变更事件的操作
+type EventOp string
+
+const (
+	EventOpCreate EventOp = "create"
+	EventOpUpdate EventOp = "update"
+	EventOpDelete EventOp = "delete"
+)
+
+// ChangeEvent 一条资源变更事件，由 etcd watch 产生
+// 按 Type 只填充 Zone / Domain / User 之一：create / update 时为变更后的值，delete 时为删除前的值（可能为空）
+type ChangeEvent struct {
+	Revision int64
+	Tenant   string // 事件所属租户
+	Type     EventType
+	Op       EventOp
+	ZoneName string // 事件所属 Zone，用户事件为空
+	Name     string // Zone 名、Domain 短名或用户 ID
+	Zone     *Zone
+	Domain   *Domain
+	User     *User
+}
@@ -0,0 +1,30 @@
+package models
+
+// DomainVersion Domain 的一个历史版本
+// 来源于 etcd 中尚未压缩的 revision，或由历史记录器保存的快照
+type DomainVersion struct {
+	Revision  int64   `json:"revision"`         // 产生该版本的 etcd revision
+	Op        EventOp `json:"op"`               // create / update / delete
+	Timestamp int64   `json:"timestamp"`        // 版本生效时间戳：create / update 取 Domain 的更新时间，delete 取记录器观察到删除的时间
+	Domain    *Domain `json:"domain,omitempty"` // 该版本的 Domain，delete 版本为 nil
+	Tenant    string  `json:"tenant,omitempty"` // 所属租户，为空时属于默认租户；Zone 被删除后可能由其他租户重新创建
+	ZoneName  string  `json:"-"`                // 所属 Zone，由快照 key 确定
+	Name      string  `json:"-"`                // Domain 短名，由快照 key 确定
+}
+
+// Exists 该版本中 Domain 是否存在
+func (v *DomainVersion) Exists() bool {
+	return v.Op != EventOpDelete && v.Domain != nil
+}
+
+// RollbackSkip Zone 回滚时未处理的 Domain 及原因
+type RollbackSkip struct {
+	Domain string `json:"domain"`
+	Reason string `json:"reason"`
+}
+
+// ZoneRollbackResult Zone 回滚到指定时间点的结果
+type ZoneRollbackResult struct {
+	Changes []*DomainChangeDiff // 需要（或已经）应用的变更
+	Skipped []*RollbackSkip     // 无法确定目标状态或不支持回滚的 Domain
+}
@@ -0,0 +1,14 @@
+package models
+
+// Instance 动态注册到 Domain 的服务实例
+type Instance struct {
+	Zone          string `json:"zone"`           // 所属 zone
+	Domain        string `json:"domain"`         // 所属 Domain
+	InstanceID    string `json:"instance_id"`    // 实例 ID，在 Domain 内唯一
+	IP            string `json:"ip"`             // 实例 IP
+	LeaseID       int64  `json:"lease_id"`       // 实例租约 ID
+	LeaseTTL      int64  `json:"lease_ttl"`      // 租约时长 (秒)
+	TokenID       string `json:"token_id"`       // 注册时使用的 API Token ID
+	RegisteredAt  int64  `json:"registered_at"`  // 注册时间戳
+	LastHeartbeat int64  `json:"last_heartbeat"` // 最近一次心跳时间戳
+}
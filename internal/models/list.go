@@ -0,0 +1,31 @@
+package models
+
+// SortOrder 排序方向
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// PageRequest 列表分页参数，嵌入到各列表请求中
+type PageRequest struct {
+	Cursor string    `json:"cursor" query:"cursor"`                                   // 上一页返回的 next_cursor，为空表示第一页
+	Limit  int       `json:"limit" query:"limit" validate:"omitempty,min=1,max=1000"` // 每页条数，不填返回全部
+	Order  SortOrder `json:"order" query:"order" validate:"omitempty,oneof=asc desc"` // 排序方向，默认 asc
+}
+
+// ListQuery 存储层列表查询条件
+type ListQuery[T any] struct {
+	PageRequest
+	KeyPrefix string            // 名称前缀，仅对以名称为 key 的数据有效，用于缩小 etcd 读取范围
+	Match     func(T) bool      // 过滤条件，nil 表示不过滤
+	Less      func(a, b T) bool // 排序规则，nil 表示按 etcd key 排序
+}
+
+// Page 分页查询结果
+type Page[T any] struct {
+	Items      []T
+	Total      int    // 满足过滤条件的总数
+	NextCursor string // 下一页游标，没有更多数据时为空
+}
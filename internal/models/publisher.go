@@ -0,0 +1,21 @@
+package models
+
+// PublishedRecordSet 一个域名发布到 DNS 后端的地址记录
+type PublishedRecordSet struct {
+	Name string   `json:"name"` // 完整域名，如 www.example.com
+	IPs  []string `json:"ips"`  // 静态 IP 与动态实例 IP
+	TTL  int      `json:"ttl"`
+}
+
+// PublisherInfo 已配置的发布目标
+type PublisherInfo struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // coredns / rfc2136 / bindfile
+}
+
+// PublishedRecords 发布目标上某个 Zone 当前的地址记录
+type PublishedRecords struct {
+	Publisher string                `json:"publisher"`
+	Zone      string                `json:"zone"`
+	Records   []*PublishedRecordSet `json:"records"`
+}
@@ -0,0 +1,105 @@
+package models
+
+// /api/v2 资源接口的请求与响应
+// 成功响应统一为 {"data": ..., "meta": ...}，错误响应为 {"error": {"code": ..., "message": ...}}
+
+// ResourceEnvelope v2 成功响应
+type ResourceEnvelope struct {
+	Data interface{} `json:"data"`
+	Meta *ListMeta   `json:"meta,omitempty"` // 仅列表
+}
+
+// ListMeta v2 列表元信息
+type ListMeta struct {
+	Total      int    `json:"total"`                 // 满足过滤条件的总数
+	NextCursor string `json:"next_cursor,omitempty"` // 下一页游标
+}
+
+// ZoneResource v2 Zone 资源，id 为 Zone 名称
+type ZoneResource struct {
+	ID               string   `json:"id"`
+	Zone             string   `json:"zone"`
+	ApprovalRequired bool     `json:"approval_required"`
+	Publishers       []string `json:"publishers"`
+	RecordCount      int      `json:"record_count"`
+	CreatedAt        int64    `json:"created_at"`
+	UpdatedAt        int64    `json:"updated_at"`
+}
+
+// DomainResource v2 Domain 资源，id 为 {zone}/{name}
+type DomainResource struct {
+	ID          string              `json:"id"`
+	Zone        string              `json:"zone"`
+	Name        string              `json:"name"` // 子域名，如 www
+	FQDN        string              `json:"fqdn"` // 完整域名，如 www.example.com
+	IPs         []string            `json:"ips"`
+	ViewIPs     map[string][]string `json:"view_ips"` // 按 CoreDNS 视图覆盖的 IP
+	DynamicIPs  []string            `json:"dynamic_ips"`
+	TTL         int                 `json:"ttl"`
+	RecordCount int                 `json:"record_count"`
+	LeaseTTL    int64               `json:"lease_ttl"`  // 0 表示永久 Domain
+	ExpiresAt   int64               `json:"expires_at"` // 仅临时 Domain
+	CreatedAt   int64               `json:"created_at"`
+	UpdatedAt   int64               `json:"updated_at"`
+}
+
+// UserResource v2 用户资源
+type UserResource struct {
+	ID        string   `json:"id"`
+	Username  string   `json:"username"`
+	UserType  UserType `json:"user_type"`
+	CreatedAt int64    `json:"created_at"`
+	UpdatedAt int64    `json:"updated_at"`
+}
+
+// CreateZoneResource POST /zones 请求
+type CreateZoneResource struct {
+	Zone             string   `json:"zone" validate:"required,fqdn"`
+	ApprovalRequired bool     `json:"approval_required"`
+	Publishers       []string `json:"publishers" validate:"omitempty,dive,required"`
+}
+
+// PutZoneResource PUT /zones/{zone} 请求，不存在时创建，存在时以请求内容替换
+type PutZoneResource struct {
+	ApprovalRequired bool     `json:"approval_required"`
+	Publishers       []string `json:"publishers" validate:"omitempty,dive,required"` // 不填时只发布到 coredns
+}
+
+// PatchZoneResource PATCH /zones/{zone} 请求，只修改提供的字段
+type PatchZoneResource struct {
+	ApprovalRequired *bool    `json:"approval_required"`
+	Publishers       []string `json:"publishers" validate:"omitempty,dive,required"`
+}
+
+// CreateDomainResource POST /zones/{zone}/domains 请求
+type CreateDomainResource struct {
+	Name     string              `json:"name" validate:"required"`
+	IPs      []string            `json:"ips" validate:"required,min=1,dive,ip"`
+	ViewIPs  map[string][]string `json:"view_ips" validate:"omitempty,dive,keys,required,endkeys,min=1,dive,ip"`
+	TTL      int                 `json:"ttl" validate:"required,min=1"`
+	LeaseTTL int64               `json:"lease_ttl" validate:"omitempty,min=30,max=86400"` // 可选，设置后为临时 Domain
+}
+
+// PutDomainResource PUT /zones/{zone}/domains/{name} 请求，不存在时创建，存在时以请求内容替换
+type PutDomainResource struct {
+	IPs     []string            `json:"ips" validate:"required,min=1,dive,ip"`
+	ViewIPs map[string][]string `json:"view_ips" validate:"omitempty,dive,keys,required,endkeys,min=1,dive,ip"` // 不填时清除所有视图覆盖
+	TTL     int                 `json:"ttl" validate:"required,min=1"`
+}
+
+// PatchDomainResource PATCH /zones/{zone}/domains/{name} 请求，只修改提供的字段
+type PatchDomainResource struct {
+	IPs     []string            `json:"ips" validate:"omitempty,min=1,dive,ip"`
+	ViewIPs map[string][]string `json:"view_ips" validate:"omitempty,dive,keys,required,endkeys,min=1,dive,ip"` // 传 {} 清除所有视图覆盖
+	TTL     *int                `json:"ttl" validate:"omitempty,min=1"`
+}
+
+// CreateUserResource POST /users 请求
+type CreateUserResource = CreateUserRequest
+
+// PatchUserResource PATCH /users/{id} 请求，只修改提供的字段
+type PatchUserResource struct {
+	Username string   `json:"username" validate:"omitempty,min=3,max=32"`
+	Password string   `json:"password" validate:"omitempty,min=6,max=72"`
+	UserType UserType `json:"user_type" validate:"omitempty,oneof=super_admin admin normal"`
+}
@@ -0,0 +1,40 @@
+package models
+
+// ScheduleStatus 定时变更状态
+type ScheduleStatus string
+
+const (
+	ScheduleStatusPending   ScheduleStatus = "pending"   // 等待执行
+	ScheduleStatusRunning   ScheduleStatus = "running"   // 执行中（leader 中断后由新 leader 重新执行）
+	ScheduleStatusSucceeded ScheduleStatus = "succeeded" // 执行成功
+	ScheduleStatusFailed    ScheduleStatus = "failed"    // 执行失败
+	ScheduleStatusCancelled ScheduleStatus = "cancelled" // 已取消
+)
+
+// ScheduledChange 定时 DNS 变更
+// 到达 ExecuteAt 时将目标 Domain 的记录集替换为 IPs / TTL，Domain 不存在时创建
+type ScheduledChange struct {
+	ID           string         `json:"id"`
+	Tenant       string         `json:"tenant,omitempty"` // 所属租户，为空时属于默认租户
+	Zone         string         `json:"zone"`             // 目标 zone
+	Domain       string         `json:"domain"`           // 目标 Domain
+	IPs          []string       `json:"ips"`              // 新的 IP 列表
+	TTL          int            `json:"ttl"`              // 新的 TTL (秒)，0 表示保持原值
+	ExecuteAt    int64          `json:"execute_at"`       // 执行时间戳
+	LowerTTL     int            `json:"lower_ttl"`        // 提前降低的 TTL (秒)，0 表示不降低
+	LowerTTLLead int64          `json:"lower_ttl_lead"`   // 提前降低 TTL 的时长 (秒)
+	TTLLoweredAt int64          `json:"ttl_lowered_at"`   // TTL 实际降低的时间戳
+	OriginalTTL  int            `json:"original_ttl"`     // 降低前的 TTL，TTL 为 0 时执行变更后恢复该值
+	Status       ScheduleStatus `json:"status"`           // 状态
+	Error        string         `json:"error"`            // 失败原因
+	CreatedBy    string         `json:"created_by"`       // 创建者用户 ID
+	CreatedAt    int64          `json:"created_at"`       // 创建时间戳
+	UpdatedAt    int64          `json:"updated_at"`       // 更新时间戳
+	ExecutedAt   int64          `json:"executed_at"`      // 执行完成时间戳
+	Revision     int64          `json:"-"`                // etcd mod revision，用于并发控制
+}
+
+// LowerTTLDue 是否到达提前降低 TTL 的时间
+func (s *ScheduledChange) LowerTTLDue(now int64) bool {
+	return s.LowerTTL > 0 && s.TTLLoweredAt == 0 && now >= s.ExecuteAt-s.LowerTTLLead
+}
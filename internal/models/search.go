@@ -0,0 +1,19 @@
+package models
+
+// SearchType 搜索类型
+type SearchType string
+
+const (
+	SearchAuto SearchType = "auto" // 根据查询串自动判断
+	SearchName SearchType = "name" // 完整域名子串
+	SearchIP   SearchType = "ip"   // 精确 IP
+	SearchCIDR SearchType = "cidr" // IP 网段
+	SearchText SearchType = "text" // 域名或记录内容子串
+)
+
+// SearchResult 搜索结果
+type SearchResult struct {
+	Domains  []*Domain
+	Total    int   // 匹配总数，可能大于返回条数
+	Revision int64 // 索引对应的 etcd revision
+}
@@ -0,0 +1,78 @@
+package models
+
+// DesiredZone 声明式期望状态中的 Zone 及其全部 Domain
+type DesiredZone struct {
+	Zone             string           `json:"zone" yaml:"zone" validate:"required,fqdn"`
+	ApprovalRequired bool             `json:"approval_required" yaml:"approval_required"`
+	Domains          []*DesiredDomain `json:"domains" yaml:"domains"`
+}
+
+// DesiredDomain 声明式期望状态中的 Domain
+type DesiredDomain struct {
+	Domain string   `json:"domain" yaml:"domain" validate:"required"`
+	IPs    []string `json:"ips" yaml:"ips" validate:"required,min=1,unique,dive,ip"`
+	TTL    int      `json:"ttl" yaml:"ttl" validate:"required,min=1"`
+}
+
+// SyncOp 同步计划中 Zone 的操作
+type SyncOp string
+
+const (
+	SyncOpCreate SyncOp = "create" // 创建 Zone
+	SyncOpUpdate SyncOp = "update" // 修改 Zone 属性
+	SyncOpDelete SyncOp = "delete" // 删除 Zone（仅 prune），连同 Domain 移入回收站
+	SyncOpNone   SyncOp = "none"   // Zone 属性不变，只有 Domain 变更
+)
+
+// SyncStatus Zone 同步的执行结果
+type SyncStatus string
+
+const (
+	SyncStatusApplied  SyncStatus = "applied"  // 已应用
+	SyncStatusProposed SyncStatus = "proposed" // Zone 要求审批，Domain 变更已提交为变更申请
+	SyncStatusFailed   SyncStatus = "failed"   // 应用失败，该 Zone 的 Domain 变更均未生效
+)
+
+// SyncSkip 同步时未处理的 Domain 及原因
+type SyncSkip struct {
+	Domain string `json:"domain"`
+	Reason string `json:"reason"`
+}
+
+// ZoneSyncPlan 单个 Zone 的同步计划及执行结果
+type ZoneSyncPlan struct {
+	Op               SyncOp              `json:"op"`
+	Zone             string              `json:"zone"`
+	ApprovalRequired bool                `json:"approval_required"`      // 同步后的值
+	DomainCount      int                 `json:"domain_count,omitempty"` // 随 Zone 一起删除的 Domain 数量，仅 delete
+	Changes          []*DomainChangeDiff `json:"changes"`
+	Skipped          []*SyncSkip         `json:"skipped,omitempty"`
+	Status           SyncStatus          `json:"status,omitempty"`            // 仅 apply
+	ChangeRequestID  string              `json:"change_request_id,omitempty"` // 仅 proposed
+	Error            string              `json:"error,omitempty"`             // 仅 failed
+}
+
+// SyncSummary 同步计划统计
+type SyncSummary struct {
+	ZonesCreated   int `json:"zones_created"`
+	ZonesUpdated   int `json:"zones_updated"`
+	ZonesDeleted   int `json:"zones_deleted"`
+	DomainsCreated int `json:"domains_created"`
+	DomainsUpdated int `json:"domains_updated"`
+	DomainsDeleted int `json:"domains_deleted"`
+}
+
+// SyncPlan 声明式同步计划
+// Problems 不为空时期望状态未通过校验，不会应用
+type SyncPlan struct {
+	Prune    bool            `json:"prune"`
+	Applied  bool            `json:"applied"`
+	Problems []string        `json:"problems"`
+	Zones    []*ZoneSyncPlan `json:"zones"` // 只包含有变更的 Zone
+	Summary  SyncSummary     `json:"summary"`
+}
+
+// Empty 计划中是否没有任何变更
+func (p *SyncPlan) Empty() bool {
+	return len(p.Zones) == 0
+}
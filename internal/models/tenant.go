@@ -0,0 +1,9 @@
+package models
+
+// Tenant 租户，用户、Zone 与 Domain 按租户隔离；升级前的数据属于默认租户
+type Tenant struct {
+	ID        string `json:"id"`         // 租户 ID，作为 etcd key 的一部分，创建后不可修改
+	Name      string `json:"name"`       // 租户名称
+	CreatedAt int64  `json:"created_at"` // 创建时间戳
+	UpdatedAt int64  `json:"updated_at"` // 更新时间戳
+}
@@ -0,0 +1,29 @@
+package models
+
+// APIToken 服务注册使用的 API Token
+type APIToken struct {
+	ID        string   `json:"id"`
+	Tenant    string   `json:"tenant,omitempty"` // 所属租户，为空时属于默认租户；使用 Token 的请求以该租户的身份访问数据
+	Name      string   `json:"name"`             // Token 名称（用途说明）
+	TokenHash string   `json:"token_hash"`       // Token 的 SHA-256 摘要，明文仅在创建时返回
+	Zone      string   `json:"zone"`             // 允许注册的 Zone
+	Domains   []string `json:"domains"`          // 允许注册的 Domain 列表，为空表示 Zone 下所有 Domain
+	CreatedBy string   `json:"created_by"`       // 创建者用户 ID
+	CreatedAt int64    `json:"created_at"`       // 创建时间戳
+}
+
+// Allows 检查 Token 是否允许操作指定 Domain
+func (t *APIToken) Allows(zone, domain string) bool {
+	if t.Zone != zone {
+		return false
+	}
+	if len(t.Domains) == 0 {
+		return true
+	}
+	for _, d := range t.Domains {
+		if d == domain {
+			return true
+		}
+	}
+	return false
+}
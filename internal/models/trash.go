@@ -0,0 +1,24 @@
+package models
+
+// TrashType 回收站条目类型
+type TrashType string
+
+const (
+	TrashTypeZone   TrashType = "zone"   // 删除的 Zone 及其下所有 Domain
+	TrashTypeDomain TrashType = "domain" // 单独删除的 Domain
+)
+
+// TrashEntry 回收站条目，保存删除前的完整记录，保留期内可以恢复
+// Zone 条目下的 Domain 记录数量不定，单独保存，不在条目中
+type TrashEntry struct {
+	ID          string    `json:"id"`
+	Type        TrashType `json:"type"`
+	Tenant      string    `json:"tenant,omitempty"`       // 所属租户，为空时属于默认租户
+	Zone        string    `json:"zone"`                   // 所属 Zone
+	Domain      string    `json:"domain,omitempty"`       // 子域名，仅 Domain 条目
+	ZoneRecord  *Zone     `json:"zone_record,omitempty"`  // 删除前的 Zone，仅 Zone 条目
+	Record      *Domain   `json:"record,omitempty"`       // 删除前的 Domain，仅 Domain 条目
+	DomainCount int       `json:"domain_count,omitempty"` // 随 Zone 一起删除的 Domain 数量，仅 Zone 条目
+	DeletedAt   int64     `json:"deleted_at"`             // 删除时间戳
+	ExpiresAt   int64     `json:"expires_at"`             // 到期时间戳，之后被永久删除
+}
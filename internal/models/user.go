@@ -2,11 +2,20 @@ package models
 
 type UserType string
 
+// DefaultAdminID 服务首次启动时创建的默认管理员 admin 的 ID，属于默认租户
+const DefaultAdminID = "10000"
+
 const (
-	UserTypeAdmin  UserType = "admin"
-	UserTypeNormal UserType = "normal"
+	UserTypeSuperAdmin UserType = "super_admin" // 超级管理员，属于默认租户，可以管理租户
+	UserTypeAdmin      UserType = "admin"
+	UserTypeNormal     UserType = "normal"
 )
 
+// IsAdmin 是否为管理员（超级管理员同时拥有所在租户的管理员权限）
+func (t UserType) IsAdmin() bool {
+	return t == UserTypeAdmin || t == UserTypeSuperAdmin
+}
+
 type User struct {
 	ID        string   `json:"id"`
 	Username  string   `json:"username"`
@@ -20,4 +29,5 @@ type CurrentUser struct {
 	ID       string   `json:"id"`
 	Username string   `json:"username"`
 	UserType UserType `json:"user_type"`
+	Tenant   string   `json:"tenant"`
 }
@@ -0,0 +1,49 @@
+package models
+
+// VerificationStatus DNS 校验结果
+type VerificationStatus string
+
+const (
+	VerificationMatch    VerificationStatus = "match"    // 应答与 Dancer 中的记录一致
+	VerificationMismatch VerificationStatus = "mismatch" // 应答缺少或多出 IP，或 TTL 不一致
+	VerificationError    VerificationStatus = "error"    // 查询失败，如超时、SERVFAIL、REFUSED
+)
+
+// VerificationAnswer DNS 服务器应答中的一条地址记录
+type VerificationAnswer struct {
+	Type string `json:"type"` // A / AAAA
+	IP   string `json:"ip"`
+	TTL  int    `json:"ttl"`
+}
+
+// DomainVerification 向 DNS 服务器查询 Domain 并与 Dancer 中的记录对比的结果
+type DomainVerification struct {
+	Zone          string                `json:"zone"`
+	Domain        string                `json:"domain"`
+	Name          string                `json:"name"`     // 查询的完整域名
+	Server        string                `json:"server"`   // 查询的 DNS 服务器
+	Protocol      string                `json:"protocol"` // udp / tcp
+	Status        VerificationStatus    `json:"status"`
+	ExpectedIPs   []string              `json:"expected_ips"`           // 静态 IP 与动态实例 IP
+	ExpectedTTL   int                   `json:"expected_ttl"`           // Domain 的 TTL
+	Answers       []*VerificationAnswer `json:"answers"`                // 应答中的 A / AAAA 记录
+	Missing       []string              `json:"missing,omitempty"`      // 记录中有、应答中没有的 IP
+	Unexpected    []string              `json:"unexpected,omitempty"`   // 应答中有、记录中没有的 IP
+	TTLMismatch   []string              `json:"ttl_mismatch,omitempty"` // TTL 与记录不一致的 IP
+	Authoritative bool                  `json:"authoritative"`          // 应答是否带 AA 标志，非权威应答的 TTL 只要求不超过记录
+	Rcode         string                `json:"rcode,omitempty"`        // 应答码，如 NOERROR、NXDOMAIN
+	Error         string                `json:"error,omitempty"`        // 查询失败的原因
+	LatencyMs     float64               `json:"latency_ms"`             // 查询耗时 (毫秒)
+	CheckedAt     int64                 `json:"checked_at"`             // 校验时间戳
+}
+
+// ZoneVerification Zone 内所有 Domain 的校验结果
+type ZoneVerification struct {
+	Zone       string                `json:"zone"`
+	CheckedAt  int64                 `json:"checked_at"` // 最近一次校验的时间戳，没有结果时为 0
+	Total      int                   `json:"total"`
+	Matched    int                   `json:"matched"`
+	Mismatched int                   `json:"mismatched"`
+	Failed     int                   `json:"failed"`
+	Results    []*DomainVerification `json:"results"` // 按 Domain 短名排序
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+
+	"dancer/internal/tenant"
+)
+
+// Webhook 变更事件订阅，匹配的 Zone / Domain 变更会以 HTTP POST 推送到 URL
+type Webhook struct {
+	ID        string      `json:"id"`
+	Tenant    string      `json:"tenant,omitempty"` // 所属租户，为空时属于默认租户；只推送该租户的事件
+	Name      string      `json:"name"`             // 订阅名称（用途说明）
+	URL       string      `json:"url"`              // 推送地址
+	Secret    string      `json:"secret"`           // HMAC-SHA256 签名密钥，仅在创建或轮换时返回
+	Zones     []string    `json:"zones"`            // 关注的 Zone，为空表示全部
+	Types     []EventType `json:"types"`            // 关注的资源类型 (zone / domain)，为空表示全部
+	Ops       []EventOp   `json:"ops"`              // 关注的操作，为空表示全部
+	Enabled   bool        `json:"enabled"`          // 停用期间不产生新的投递，已有的待投递记录在重新启用后继续投递
+	CreatedBy string      `json:"created_by"`       // 创建者用户 ID
+	CreatedAt int64       `json:"created_at"`       // 创建时间戳
+	UpdatedAt int64       `json:"updated_at"`       // 更新时间戳
+}
+
+// Matches 事件是否需要推送给该订阅，停用的订阅、用户变更事件及其他租户的事件不推送
+func (w *Webhook) Matches(event *ChangeEvent) bool {
+	if !w.Enabled || event.Type == EventTypeUser || tenant.Of(w.Tenant) != tenant.Of(event.Tenant) {
+		return false
+	}
+	if len(w.Zones) > 0 && !slices.Contains(w.Zones, event.ZoneName) {
+		return false
+	}
+	if len(w.Types) > 0 && !slices.Contains(w.Types, event.Type) {
+		return false
+	}
+	return len(w.Ops) == 0 || slices.Contains(w.Ops, event.Op)
+}
+
+// WebhookEventReset 事件历史被压缩、部分事件无法投递时推送给每个启用的订阅的事件类型，不受订阅的过滤条件限制
+// 订阅方收到后应重新全量读取 Zone / Domain
+const WebhookEventReset EventType = "reset"
+
+// WebhookReset reset 推送携带的数据，from_revision 之后、to_revision 及之前的事件未投递
+type WebhookReset struct {
+	FromRevision int64 `json:"from_revision"` // 已处理到的 revision
+	ToRevision   int64 `json:"to_revision"`   // 投递从该 revision 之后继续
+}
+
+// DeliveryStatus 投递状态
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"   // 等待投递或等待重试
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded" // 投递成功
+	DeliveryStatusDead      DeliveryStatus = "dead"      // 重试次数用尽，进入死信列表
+)
+
+// WebhookDelivery 一次事件投递及其结果
+type WebhookDelivery struct {
+	ID             string          `json:"id"`              // 由事件 revision 生成，按时间有序
+	WebhookID      string          `json:"webhook_id"`      // 所属订阅
+	EventType      EventType       `json:"event_type"`      // 事件资源类型
+	EventOp        EventOp         `json:"event_op"`        // 事件操作
+	Zone           string          `json:"zone"`            // 事件所属 Zone
+	Name           string          `json:"name"`            // Zone 名或 Domain 短名
+	Revision       int64           `json:"revision"`        // 事件对应的 etcd revision
+	Payload        json.RawMessage `json:"payload"`         // 推送的请求体，重试时原样发送
+	Status         DeliveryStatus  `json:"status"`          // 投递状态
+	Attempts       int             `json:"attempts"`        // 已尝试次数
+	NextAttemptAt  int64           `json:"next_attempt_at"` // 下次尝试时间戳
+	LastAttemptAt  int64           `json:"last_attempt_at"` // 最近一次尝试时间戳
+	ResponseStatus int             `json:"response_status"` // 最近一次响应的 HTTP 状态码，请求失败时为 0
+	Error          string          `json:"error"`           // 最近一次失败原因
+	CreatedAt      int64           `json:"created_at"`      // 创建时间戳
+	DeliveredAt    int64           `json:"delivered_at"`    // 投递成功时间戳
+	ModRevision    int64           `json:"-"`               // etcd mod revision，用于并发控制
+}
+
+// WebhookPayload 推送给订阅方的请求体
+type WebhookPayload struct {
+	DeliveryID string      `json:"delivery_id"` // 投递 ID，重试时不变，可用于去重
+	WebhookID  string      `json:"webhook_id"`
+	Revision   int64       `json:"revision"`
+	Type       EventType   `json:"type"`
+	Op         EventOp     `json:"op"`
+	Zone       string      `json:"zone"`
+	Name       string      `json:"name"`
+	OccurredAt int64       `json:"occurred_at"`    // 事件被处理的时间戳
+	Data       interface{} `json:"data,omitempty"` // Zone 或 Domain：create / update 时为变更后的值，delete 时为删除前的值
+}
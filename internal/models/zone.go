@@ -1,9 +1,32 @@
 package models
 
+// DefaultPublisher 内置的发布目标，将记录写入 etcd 供 CoreDNS etcd 插件读取
+const DefaultPublisher = "coredns"
+
 // Zone 二级域名（Zone）模型
 type Zone struct {
-	Zone        string `json:"zone"`         // 二级域名，如 example.com
-	RecordCount int    `json:"record_count"` // 该 zone 下的域名数量
-	CreatedAt   int64  `json:"created_at"`   // 创建时间戳
-	UpdatedAt   int64  `json:"updated_at"`   // 更新时间戳
+	Zone             string   `json:"zone"`                 // 二级域名，如 example.com
+	RecordCount      int      `json:"record_count"`         // 该 zone 下的域名数量
+	ApprovalRequired bool     `json:"approval_required"`    // 是否要求 Domain 变更经过审批
+	Publishers       []string `json:"publishers,omitempty"` // 记录的发布目标，为空时只发布到 coredns
+	CreatedAt        int64    `json:"created_at"`           // 创建时间戳
+	UpdatedAt        int64    `json:"updated_at"`           // 更新时间戳
+}
+
+// PublisherNames 返回 Zone 的发布目标，未设置时为 coredns
+func (z *Zone) PublisherNames() []string {
+	if len(z.Publishers) == 0 {
+		return []string{DefaultPublisher}
+	}
+	return z.Publishers
+}
+
+// PublishesTo 是否发布到指定的发布目标
+func (z *Zone) PublishesTo(name string) bool {
+	for _, p := range z.PublisherNames() {
+		if p == name {
+			return true
+		}
+	}
+	return false
 }
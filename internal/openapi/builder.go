@@ -0,0 +1,282 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Security 接口的认证方式
+type Security int
+
+const (
+	Public     Security = iota // 无需认证
+	JWT                        // JWT
+	Admin                      // JWT，且需要管理员权限
+	SuperAdmin                 // JWT，且需要超级管理员权限
+	APIToken                   // API Token（服务注册）
+)
+
+// Operation 单个路由的接口描述，请求与响应由 Go 类型反射生成
+type Operation struct {
+	Tag         string
+	Summary     string
+	Description string
+	Security    Security
+	Params      []*Parameter // 额外的参数；路径参数按路由自动生成
+	Query       interface{}  // 查询参数结构体，按 query tag 生成参数
+	Body        interface{}  // JSON 请求体
+	Form        interface{}  // multipart 表单结构体，按 form tag 生成字段
+	FormFile    string       // multipart 表单中的文件字段
+	Response    interface{}  // 成功响应体，nil 表示没有响应体或响应体不是 JSON
+	ContentType string       // 成功响应的类型，默认 application/json
+	Status      []int        // 成功状态码，默认 200
+}
+
+// Spec 生成文档所需的信息
+type Spec struct {
+	Title          string
+	Version        string
+	Description    string
+	ResourcePrefix string                // 该前缀下的接口成功响应包装为 {"data": ..., "meta": ...}，错误响应包装为 {"error": ...}
+	ErrorResponse  interface{}           // 错误响应体
+	ListMeta       interface{}           // 资源列表的 meta
+	Operations     map[string]*Operation // key 为 "方法 路径"，路径与 echo 路由一致，如 "GET /api/v2/zones/:zone"
+}
+
+// standardMethods 生成文档的请求方法，echo 内部注册的其他路由（如 RouteNotFound）忽略
+var standardMethods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// Build 按 echo 路由表生成文档
+// problems 列出没有接口描述的路由与没有对应路由的接口描述，不为空时文档与路由不一致
+func Build(spec *Spec, routes []*echo.Route) (*Document, []string) {
+	b := &builder{spec: spec, registry: newSchemaRegistry()}
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: spec.Title, Version: spec.Version, Description: spec.Description},
+		Paths:   make(map[string]PathItem),
+	}
+
+	var problems []string
+	seen := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		if !standardMethods[route.Method] {
+			continue
+		}
+		key := route.Method + " " + route.Path
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		op, ok := spec.Operations[key]
+		if !ok {
+			problems = append(problems, "route without operation: "+key)
+			continue
+		}
+		path := openAPIPath(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(PathItem)
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = b.operation(route.Method, route.Path, op)
+	}
+	for key := range spec.Operations {
+		if !seen[key] {
+			problems = append(problems, "operation without route: "+key)
+		}
+	}
+	sort.Strings(problems)
+
+	doc.Components = Components{
+		Schemas: b.registry.schemas,
+		SecuritySchemes: map[string]*SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "登录返回的 JWT"},
+			"apiToken":   {Type: "http", Scheme: "bearer", Description: "服务注册使用的 API Token"},
+		},
+	}
+	return doc, problems
+}
+
+// builder 生成文档期间的状态
+type builder struct {
+	spec     *Spec
+	registry *schemaRegistry
+}
+
+// operation 生成单个接口
+func (b *builder) operation(method, path string, op *Operation) *OperationObject {
+	resource := b.spec.ResourcePrefix != "" && strings.HasPrefix(path, b.spec.ResourcePrefix)
+	obj := &OperationObject{
+		Summary:     op.Summary,
+		Description: op.Description,
+		OperationID: operationID(method, path),
+		Responses:   make(map[string]*ResponseObject),
+	}
+	if op.Tag != "" {
+		obj.Tags = []string{op.Tag}
+	}
+
+	// 参数：路径参数、额外参数、查询参数
+	for _, segment := range strings.Split(path, "/") {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			obj.Parameters = append(obj.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+	obj.Parameters = append(obj.Parameters, op.Params...)
+	if op.Query != nil {
+		for _, f := range structFields(indirect(reflect.TypeOf(op.Query)), "query") {
+			s := b.registry.schemaOf(f.typ)
+			required := applyValidate(s, f.validate)
+			obj.Parameters = append(obj.Parameters, &Parameter{Name: f.name, In: "query", Required: required, Schema: s})
+		}
+	}
+
+	// 请求体
+	switch {
+	case op.Body != nil:
+		obj.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{echo.MIMEApplicationJSON: {Schema: b.registry.schemaOf(reflect.TypeOf(op.Body))}},
+		}
+	case op.Form != nil || op.FormFile != "":
+		form := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		if op.Form != nil {
+			for _, f := range structFields(indirect(reflect.TypeOf(op.Form)), "form") {
+				s := b.registry.schemaOf(f.typ)
+				if applyValidate(s, f.validate) {
+					form.Required = append(form.Required, f.name)
+				}
+				form.Properties[f.name] = s
+			}
+		}
+		if op.FormFile != "" {
+			form.Properties[op.FormFile] = &Schema{Type: "string", Format: "binary"}
+			form.Required = append(form.Required, op.FormFile)
+		}
+		obj.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{echo.MIMEMultipartForm: {Schema: form}},
+		}
+	}
+
+	// 成功响应
+	statuses := op.Status
+	if len(statuses) == 0 {
+		statuses = []int{http.StatusOK}
+	}
+	for _, status := range statuses {
+		resp := &ResponseObject{Description: http.StatusText(status)}
+		if status != http.StatusNoContent && (op.Response != nil || op.ContentType != "") {
+			resp.Content = b.responseContent(op, resource)
+		}
+		if status == http.StatusCreated {
+			resp.Headers = map[string]*Header{
+				echo.HeaderLocation: {Description: "新建资源的地址", Schema: &Schema{Type: "string"}},
+			}
+		}
+		obj.Responses[strconv.Itoa(status)] = resp
+	}
+
+	// 错误响应
+	errSchema := b.errorSchema(resource)
+	errContent := map[string]*MediaType{echo.MIMEApplicationJSON: {Schema: errSchema}}
+	if len(obj.Parameters) > 0 || obj.RequestBody != nil {
+		obj.Responses["400"] = &ResponseObject{Description: "请求参数无效", Content: errContent}
+	}
+	switch op.Security {
+	case JWT, Admin, SuperAdmin:
+		obj.Security = []map[string][]string{{"bearerAuth": {}}}
+	case APIToken:
+		obj.Security = []map[string][]string{{"apiToken": {}}}
+	}
+	if op.Security != Public {
+		obj.Responses["401"] = &ResponseObject{Description: "未认证或 Token 无效", Content: errContent}
+	}
+	switch op.Security {
+	case Admin:
+		obj.Responses["403"] = &ResponseObject{Description: "需要管理员权限", Content: errContent}
+	case SuperAdmin:
+		obj.Responses["403"] = &ResponseObject{Description: "需要超级管理员权限", Content: errContent}
+	}
+	obj.Responses["default"] = &ResponseObject{Description: "其他错误，code 为错误码", Content: errContent}
+
+	return obj
+}
+
+// responseContent 生成成功响应体
+func (b *builder) responseContent(op *Operation, resource bool) map[string]*MediaType {
+	contentType := op.ContentType
+	if contentType == "" {
+		contentType = echo.MIMEApplicationJSON
+	}
+	if op.Response == nil {
+		// 非 JSON 响应，如备份文件与事件流
+		schema := &Schema{Type: "string"}
+		if contentType == echo.MIMEOctetStream {
+			schema.Format = "binary"
+		}
+		return map[string]*MediaType{contentType: {Schema: schema}}
+	}
+
+	t := reflect.TypeOf(op.Response)
+	schema := b.registry.schemaOf(t)
+	if resource {
+		envelope := &Schema{Type: "object", Properties: map[string]*Schema{"data": schema}, Required: []string{"data"}}
+		if t.Kind() == reflect.Slice && b.spec.ListMeta != nil {
+			envelope.Properties["meta"] = b.registry.schemaOf(reflect.TypeOf(b.spec.ListMeta))
+			envelope.Required = append(envelope.Required, "meta")
+		}
+		schema = envelope
+	}
+	return map[string]*MediaType{contentType: {Schema: schema}}
+}
+
+// errorSchema 生成错误响应体
+func (b *builder) errorSchema(resource bool) *Schema {
+	schema := b.registry.schemaOf(reflect.TypeOf(b.spec.ErrorResponse))
+	if resource {
+		return &Schema{Type: "object", Properties: map[string]*Schema{"error": schema}, Required: []string{"error"}}
+	}
+	return schema
+}
+
+// openAPIPath 将 echo 路径参数 :name 转换为 {name}
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID 由方法与路径生成唯一的 operationId，如 GET /api/v2/zones/:zone 为 getApiV2ZonesZone
+func operationID(method, path string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(method))
+	for _, word := range strings.FieldsFunc(path, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) {
+		sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return sb.String()
+}
+
+// indirect 返回指针指向的类型
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
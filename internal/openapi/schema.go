@@ -0,0 +1,206 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// schemaRefPrefix components 中 Schema 的引用前缀
+const schemaRefPrefix = "#/components/schemas/"
+
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// validateFormats validate 规则对应的 format
+var validateFormats = map[string]string{
+	"ip":               "ip",
+	"ipv4":             "ipv4",
+	"ipv6":             "ipv6",
+	"cidr":             "cidr",
+	"fqdn":             "hostname",
+	"hostname":         "hostname",
+	"hostname_rfc1123": "hostname",
+	"url":              "uri",
+	"http_url":         "uri",
+	"email":            "email",
+}
+
+// schemaRegistry 由 Go 类型生成 Schema，具名结构体注册到 components 中并以 $ref 引用
+type schemaRegistry struct {
+	schemas map[string]*Schema
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{schemas: make(map[string]*Schema)}
+}
+
+// schemaOf 返回类型 t 的 Schema
+func (r *schemaRegistry) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == rawMessageType {
+		return &Schema{Description: "任意 JSON"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		if _, ok := r.schemas[t.Name()]; !ok {
+			// 先占位再生成，支持自引用的结构体
+			s := &Schema{}
+			r.schemas[t.Name()] = s
+			*s = *r.structSchema(t)
+		}
+		return &Schema{Ref: schemaRefPrefix + t.Name()}
+	}
+	// interface{} 等任意值
+	return &Schema{}
+}
+
+// structSchema 按 json tag 与 validate tag 生成结构体的 Schema
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, f := range structFields(t, "json") {
+		prop := r.schemaOf(f.typ)
+		if applyValidate(prop, f.validate) {
+			s.Required = append(s.Required, f.name)
+		}
+		s.Properties[f.name] = prop
+	}
+	return s
+}
+
+// structField 参与序列化的结构体字段
+type structField struct {
+	name     string
+	typ      reflect.Type
+	validate string
+}
+
+// structFields 按 tag 列出结构体字段，展开匿名嵌入的结构体
+// tag 为 json 时与 encoding/json 一致，没有 tag 的字段使用字段名；其他 tag 只列出设置了该 tag 的字段
+func structFields(t reflect.Type, tag string) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			fields = append(fields, structFields(ft, tag)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			if tag != "json" {
+				continue
+			}
+			name = f.Name
+		}
+		fields = append(fields, structField{name: name, typ: f.Type, validate: f.Tag.Get("validate")})
+	}
+	return fields
+}
+
+// applyValidate 将 validate 规则转换为 Schema 约束，返回字段是否必填
+// dive 之后的规则作用于数组元素；$ref 引用的 Schema 不能附加约束，相应规则被忽略
+func applyValidate(s *Schema, rules string) bool {
+	required := false
+	target := s
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			if target == s {
+				required = true
+			}
+		case "dive":
+			target = target.Items
+		case "min", "max", "len":
+			applyBound(target, name, param)
+		case "oneof":
+			if target != nil && target.Ref == "" {
+				target.Enum = strings.Fields(param)
+			}
+		case "unique":
+			if target != nil && target.Type == "array" {
+				target.UniqueItems = true
+			}
+		default:
+			if format, ok := validateFormats[name]; ok && target != nil && target.Type == "string" {
+				target.Format = format
+			}
+		}
+		if target == nil {
+			break
+		}
+	}
+	return required
+}
+
+// applyBound 按类型将 min / max / len 转换为长度、数量或取值范围
+func applyBound(s *Schema, name, param string) {
+	if s == nil {
+		return
+	}
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return
+	}
+	lower, upper := name != "max", name != "min"
+
+	switch s.Type {
+	case "string":
+		if lower {
+			s.MinLength = &n
+		}
+		if upper {
+			s.MaxLength = &n
+		}
+	case "array":
+		if lower {
+			s.MinItems = &n
+		}
+		if upper {
+			s.MaxItems = &n
+		}
+	case "integer", "number":
+		v := float64(n)
+		if lower {
+			s.Minimum = &v
+		}
+		if upper {
+			s.Maximum = &v
+		}
+	}
+}
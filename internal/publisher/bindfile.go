@@ -0,0 +1,208 @@
+package publisher
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"dancer/internal/config"
+	"dancer/internal/models"
+	"github.com/miekg/dns"
+)
+
+// SOA 的刷新参数
+const (
+	bindSOARefresh = 7200
+	bindSOARetry   = 1800
+	bindSOAExpire  = 1209600
+	bindSOAMinimum = 300
+)
+
+// BindFile 在目录中为每个 Zone 维护一个 BIND 格式的区域文件 {zone}.zone
+// 文件由 Dancer 完整生成：SOA 与 NS 按配置合成，每次修改递增 SOA 序列号；通过写入临时文件再重命名替换，BIND 不会读到写了一半的文件
+type BindFile struct {
+	dir         string
+	nameservers []string
+	hostmaster  string
+	ttl         int
+
+	mu sync.Mutex // 串行化对区域文件的读-改-写
+}
+
+// NewBindFile 创建区域文件发布目标，目录不存在时创建
+func NewBindFile(cfg config.PublisherConfig) (*BindFile, error) {
+	if cfg.Directory == "" {
+		return nil, fmt.Errorf("directory is required")
+	}
+	if err := os.MkdirAll(cfg.Directory, 0o755); err != nil {
+		return nil, err
+	}
+	return &BindFile{
+		dir:         cfg.Directory,
+		nameservers: cfg.Nameservers,
+		hostmaster:  cfg.Hostmaster,
+		ttl:         cfg.TTL,
+	}, nil
+}
+
+func (p *BindFile) Publish(ctx context.Context, zone string, rs *models.PublishedRecordSet) error {
+	return p.modify(zone, func(records map[string][]dns.RR) {
+		records[dns.CanonicalName(rs.Name)] = addressRecords(rs)
+	})
+}
+
+func (p *BindFile) Remove(ctx context.Context, zone, name string) error {
+	return p.modify(zone, func(records map[string][]dns.RR) {
+		delete(records, dns.CanonicalName(name))
+	})
+}
+
+func (p *BindFile) List(ctx context.Context, zone string) ([]*models.PublishedRecordSet, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, rrs, err := p.read(zone)
+	if err != nil {
+		return nil, err
+	}
+	return recordSets(rrs), nil
+}
+
+// modify 读取区域文件中的地址记录，按域名分组后交给 fn 修改，再写回文件
+func (p *BindFile) modify(zone string, fn func(records map[string][]dns.RR)) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	serial, rrs, err := p.read(zone)
+	if err != nil {
+		return err
+	}
+
+	records := make(map[string][]dns.RR)
+	for _, rr := range rrs {
+		name := dns.CanonicalName(rr.Header().Name)
+		records[name] = append(records[name], rr)
+	}
+	fn(records)
+
+	// 序列号取当前时间戳，同一秒内多次修改时在上次的基础上递增
+	return p.write(zone, max(serial+1, uint32(time.Now().Unix())), records)
+}
+
+// read 读取区域文件，返回 SOA 序列号与地址记录；文件不存在时返回空
+func (p *BindFile) read(zone string) (uint32, []dns.RR, error) {
+	f, err := os.Open(p.path(zone))
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil, nil
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	defer f.Close()
+
+	var serial uint32
+	var rrs []dns.RR
+	zp := dns.NewZoneParser(f, dns.Fqdn(zone), f.Name())
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		switch rr := rr.(type) {
+		case *dns.SOA:
+			serial = rr.Serial
+		case *dns.A, *dns.AAAA:
+			rrs = append(rrs, rr)
+		}
+	}
+	if err := zp.Err(); err != nil {
+		return 0, nil, fmt.Errorf("failed to parse %s: %w", f.Name(), err)
+	}
+	return serial, rrs, nil
+}
+
+// write 生成区域文件，先写临时文件再重命名替换
+func (p *BindFile) write(zone string, serial uint32, records map[string][]dns.RR) error {
+	origin := dns.Fqdn(zone)
+	tmp, err := os.CreateTemp(p.dir, "."+zone+".zone.*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	fmt.Fprintf(w, "; Generated by Dancer, do not edit.\n$ORIGIN %s\n$TTL %d\n", origin, p.ttl)
+	fmt.Fprintln(w, &dns.SOA{
+		Hdr:     dns.RR_Header{Name: origin, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: uint32(p.ttl)},
+		Ns:      p.nameserverNames(origin)[0],
+		Mbox:    p.mbox(origin),
+		Serial:  serial,
+		Refresh: bindSOARefresh,
+		Retry:   bindSOARetry,
+		Expire:  bindSOAExpire,
+		Minttl:  bindSOAMinimum,
+	})
+	for _, ns := range p.nameserverNames(origin) {
+		fmt.Fprintln(w, &dns.NS{Hdr: dns.RR_Header{Name: origin, Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: uint32(p.ttl)}, Ns: ns})
+	}
+	for _, rr := range sortedRecords(records) {
+		fmt.Fprintln(w, rr)
+	}
+
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p.path(zone))
+}
+
+// path 区域文件路径
+func (p *BindFile) path(zone string) string {
+	return filepath.Join(p.dir, zone+".zone")
+}
+
+// nameserverNames 返回 NS 记录的目标，未配置时为 ns.dns.{zone}
+func (p *BindFile) nameserverNames(origin string) []string {
+	if len(p.nameservers) == 0 {
+		return []string{"ns.dns." + origin}
+	}
+	names := make([]string, len(p.nameservers))
+	for i, ns := range p.nameservers {
+		names[i] = dns.Fqdn(ns)
+	}
+	return names
+}
+
+// mbox 返回 SOA 的管理员邮箱（@ 替换为 .），未配置时为 hostmaster.{zone}
+func (p *BindFile) mbox(origin string) string {
+	if p.hostmaster == "" {
+		return "hostmaster." + origin
+	}
+	return dns.Fqdn(strings.Replace(p.hostmaster, "@", ".", 1))
+}
+
+// sortedRecords 按域名排序展开记录，使生成的文件内容稳定
+func sortedRecords(records map[string][]dns.RR) []dns.RR {
+	names := make([]string, 0, len(records))
+	for name := range records {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var rrs []dns.RR
+	for _, name := range names {
+		rrs = append(rrs, records[name]...)
+	}
+	return rrs
+}
@@ -0,0 +1,154 @@
+package publisher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"dancer/internal/config"
+	"dancer/internal/models"
+	"github.com/miekg/dns"
+)
+
+// readZoneFile 解析区域文件，返回 SOA 与全部记录的文本形式
+func readZoneFile(t *testing.T, path string) (*dns.SOA, []string) {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open zone file: %v", err)
+	}
+	defer f.Close()
+
+	var soa *dns.SOA
+	var rrs []string
+	zp := dns.NewZoneParser(f, "", path)
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		if s, isSOA := rr.(*dns.SOA); isSOA {
+			soa = s
+			continue
+		}
+		rrs = append(rrs, rr.String())
+	}
+	if err := zp.Err(); err != nil {
+		t.Fatalf("zone file does not parse: %v", err)
+	}
+	if soa == nil {
+		t.Fatal("zone file has no SOA")
+	}
+	return soa, rrs
+}
+
+// TestBindFile 生成的区域文件包含 SOA、NS 与地址记录，每次修改递增序列号
+func TestBindFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "zones")
+	p, err := NewBindFile(config.PublisherConfig{
+		Directory:   dir,
+		Nameservers: []string{"ns1.example.net", "ns2.example.net."},
+		Hostmaster:  "admin@example.net",
+		TTL:         600,
+	})
+	if err != nil {
+		t.Fatalf("NewBindFile: %v", err)
+	}
+	ctx := context.Background()
+	path := filepath.Join(dir, "example.com.zone")
+
+	before := uint32(time.Now().Unix())
+	if err := p.Publish(ctx, testZone, &models.PublishedRecordSet{Name: "www.example.com", IPs: []string{"192.0.2.1", "2001:db8::1"}, TTL: 300}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	soa, rrs := readZoneFile(t, path)
+	if soa.Serial < before {
+		t.Errorf("serial = %d, want at least the current time %d", soa.Serial, before)
+	}
+	if soa.Hdr.Name != "example.com." || soa.Ns != "ns1.example.net." || soa.Mbox != "admin.example.net." || soa.Hdr.Ttl != 600 {
+		t.Errorf("SOA = %s", soa)
+	}
+	want := []string{
+		"example.com.\t600\tIN\tNS\tns1.example.net.",
+		"example.com.\t600\tIN\tNS\tns2.example.net.",
+		"www.example.com.\t300\tIN\tA\t192.0.2.1",
+		"www.example.com.\t300\tIN\tAAAA\t2001:db8::1",
+	}
+	if !reflect.DeepEqual(rrs, want) {
+		t.Errorf("records = %q, want %q", rrs, want)
+	}
+
+	// 同一秒内的修改在上次的序列号上递增
+	serial := soa.Serial
+	if err := p.Publish(ctx, testZone, &models.PublishedRecordSet{Name: "api.example.com", IPs: []string{"192.0.2.9"}, TTL: 60}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := p.Remove(ctx, testZone, "www.example.com"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	soa, rrs = readZoneFile(t, path)
+	if soa.Serial < serial+2 {
+		t.Errorf("serial = %d after two more changes, want at least %d", soa.Serial, serial+2)
+	}
+	want = []string{
+		"example.com.\t600\tIN\tNS\tns1.example.net.",
+		"example.com.\t600\tIN\tNS\tns2.example.net.",
+		"api.example.com.\t60\tIN\tA\t192.0.2.9",
+	}
+	if !reflect.DeepEqual(rrs, want) {
+		t.Errorf("records = %q, want %q", rrs, want)
+	}
+
+	sets, err := p.List(ctx, testZone)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	wantSets := []*models.PublishedRecordSet{{Name: "api.example.com", IPs: []string{"192.0.2.9"}, TTL: 60}}
+	if !reflect.DeepEqual(sets, wantSets) {
+		t.Errorf("List = %+v, want %+v", formatSets(sets), formatSets(wantSets))
+	}
+
+	// 写入完成后不留下临时文件
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			t.Errorf("temporary file %s left behind", e.Name())
+		}
+	}
+}
+
+// TestBindFileSerialFromFile 序列号在文件中已有的序列号基础上递增，即使它大于当前时间
+func TestBindFileSerialFromFile(t *testing.T) {
+	dir := t.TempDir()
+	future := uint32(time.Now().Add(24 * time.Hour).Unix())
+	content := "$ORIGIN example.com.\n$TTL 300\n" +
+		"@ 300 IN SOA ns.dns.example.com. hostmaster.example.com. " + strconv.FormatUint(uint64(future), 10) + " 7200 1800 1209600 300\n" +
+		"www 300 IN A 192.0.2.1\n"
+	if err := os.WriteFile(filepath.Join(dir, "example.com.zone"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewBindFile(config.PublisherConfig{Directory: dir, TTL: 300})
+	if err != nil {
+		t.Fatalf("NewBindFile: %v", err)
+	}
+	if err := p.Remove(context.Background(), testZone, "www.example.com"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+
+	soa, rrs := readZoneFile(t, filepath.Join(dir, "example.com.zone"))
+	if soa.Serial != future+1 {
+		t.Errorf("serial = %d, want %d", soa.Serial, future+1)
+	}
+	if soa.Ns != "ns.dns.example.com." || soa.Mbox != "hostmaster.example.com." {
+		t.Errorf("SOA = %s, want the default nameserver and hostmaster", soa)
+	}
+	if want := []string{"example.com.\t300\tIN\tNS\tns.dns.example.com."}; !reflect.DeepEqual(rrs, want) {
+		t.Errorf("records = %q, want %q", rrs, want)
+	}
+}
@@ -0,0 +1,186 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"dancer/internal/config"
+	"dancer/internal/models"
+	"github.com/miekg/dns"
+)
+
+// 发布目标类型
+const (
+	TypeCoreDNS  = "coredns" // 内置的 CoreDNS 视图，写入 etcd 供 CoreDNS etcd 插件读取
+	TypeRFC2136  = "rfc2136"
+	TypeBindFile = "bindfile"
+)
+
+// Publisher 将 Dancer 的记录发布到某个 DNS 后端
+// 只管理域名的 A / AAAA 记录，同一域名下的其他类型记录保持不变
+type Publisher interface {
+	// Publish 将 rs.Name 的地址记录替换为 rs 中的记录
+	Publish(ctx context.Context, zone string, rs *models.PublishedRecordSet) error
+	// Remove 撤下 name 的地址记录，name 没有记录时不返回错误
+	Remove(ctx context.Context, zone, name string) error
+	// List 列出 Zone 下已发布的地址记录，按域名排序
+	List(ctx context.Context, zone string) ([]*models.PublishedRecordSet, error)
+}
+
+// Registry 按名称索引的发布目标
+// 每个 CoreDNS 视图都是一个 coredns 类型的发布目标，名称即视图名称
+type Registry struct {
+	publishers map[string]Publisher
+	types      map[string]string
+	views      map[string]string // 发布目标 -> 发布哪个 CoreDNS 视图的记录
+	names      []string
+}
+
+// NewRegistry 按配置创建发布目标，view 按名称返回内置的 CoreDNS 视图发布目标（默认视图 coredns 与 cfg.Views）
+// 名称重复、与视图重名、类型或视图未知时返回错误
+func NewRegistry(cfg *config.Config, view func(name string) Publisher) (*Registry, error) {
+	r := &Registry{
+		publishers: make(map[string]Publisher),
+		types:      make(map[string]string),
+		views:      make(map[string]string),
+	}
+	viewNames := []string{config.DefaultView}
+	for _, vc := range cfg.Views {
+		viewNames = append(viewNames, vc.Name)
+	}
+	for _, name := range viewNames {
+		r.publishers[name] = view(name)
+		r.types[name] = TypeCoreDNS
+		r.views[name] = name
+		r.names = append(r.names, name)
+	}
+
+	for _, pc := range cfg.Publishers {
+		if pc.Name == "" {
+			return nil, fmt.Errorf("publisher name is required")
+		}
+		if _, ok := r.publishers[pc.Name]; ok {
+			return nil, fmt.Errorf("duplicate publisher name %q", pc.Name)
+		}
+		if r.types[pc.View] != TypeCoreDNS {
+			return nil, fmt.Errorf("publisher %q: unknown view %q", pc.Name, pc.View)
+		}
+
+		var p Publisher
+		var err error
+		switch pc.Type {
+		case TypeRFC2136:
+			p, err = NewRFC2136(pc)
+		case TypeBindFile:
+			p, err = NewBindFile(pc)
+		default:
+			err = fmt.Errorf("unknown type %q", pc.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("publisher %q: %w", pc.Name, err)
+		}
+
+		r.publishers[pc.Name] = p
+		r.types[pc.Name] = pc.Type
+		r.views[pc.Name] = pc.View
+		r.names = append(r.names, pc.Name)
+	}
+	return r, nil
+}
+
+// Get 按名称获取发布目标
+func (r *Registry) Get(name string) (Publisher, bool) {
+	p, ok := r.publishers[name]
+	return p, ok
+}
+
+// Type 返回发布目标的类型
+func (r *Registry) Type(name string) string {
+	return r.types[name]
+}
+
+// View 返回发布目标发布哪个 CoreDNS 视图的记录
+func (r *Registry) View(name string) string {
+	return r.views[name]
+}
+
+// Names 返回所有发布目标的名称，CoreDNS 视图在最前（默认视图 coredns 第一），其余按配置顺序
+func (r *Registry) Names() []string {
+	return r.names
+}
+
+// Views 返回 CoreDNS 视图的名称，默认视图 coredns 在最前
+func (r *Registry) Views() []string {
+	var names []string
+	for _, name := range r.names {
+		if r.types[name] == TypeCoreDNS {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// External 返回 CoreDNS 视图以外的发布目标名称，这些发布目标由 PublishService 异步同步
+func (r *Registry) External() []string {
+	var names []string
+	for _, name := range r.names {
+		if r.types[name] != TypeCoreDNS {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// addressRecords 生成 rs 的 A / AAAA 记录，无法解析的 IP 被忽略
+func addressRecords(rs *models.PublishedRecordSet) []dns.RR {
+	owner := dns.Fqdn(rs.Name)
+	rrs := make([]dns.RR, 0, len(rs.IPs))
+	for _, s := range rs.IPs {
+		ip := net.ParseIP(s)
+		switch {
+		case ip == nil:
+			continue
+		case ip.To4() != nil:
+			hdr := dns.RR_Header{Name: owner, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: uint32(rs.TTL)}
+			rrs = append(rrs, &dns.A{Hdr: hdr, A: ip.To4()})
+		default:
+			hdr := dns.RR_Header{Name: owner, Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: uint32(rs.TTL)}
+			rrs = append(rrs, &dns.AAAA{Hdr: hdr, AAAA: ip})
+		}
+	}
+	return rrs
+}
+
+// recordSets 将 A / AAAA 记录按域名分组，TTL 取每个域名的第一条记录，其他类型的记录被忽略
+func recordSets(rrs []dns.RR) []*models.PublishedRecordSet {
+	byName := make(map[string]*models.PublishedRecordSet)
+	for _, rr := range rrs {
+		var ip string
+		switch rr := rr.(type) {
+		case *dns.A:
+			ip = rr.A.String()
+		case *dns.AAAA:
+			ip = rr.AAAA.String()
+		default:
+			continue
+		}
+
+		name := strings.TrimSuffix(dns.CanonicalName(rr.Header().Name), ".")
+		rs, ok := byName[name]
+		if !ok {
+			rs = &models.PublishedRecordSet{Name: name, TTL: int(rr.Header().Ttl)}
+			byName[name] = rs
+		}
+		rs.IPs = append(rs.IPs, ip)
+	}
+
+	sets := make([]*models.PublishedRecordSet, 0, len(byName))
+	for _, rs := range byName {
+		sets = append(sets, rs)
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i].Name < sets[j].Name })
+	return sets
+}
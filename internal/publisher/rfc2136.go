@@ -0,0 +1,121 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"dancer/internal/config"
+	"dancer/internal/models"
+	"github.com/miekg/dns"
+)
+
+// tsigFudge TSIG 签名允许的时钟偏差(秒)
+const tsigFudge = 300
+
+// RFC2136 通过 DNS UPDATE（RFC 2136）将记录发布到主服务器，如 BIND / Knot / PowerDNS
+// 每次发布在一个 UPDATE 中删除域名原有的 A / AAAA 记录集并写入新记录；列出记录使用 AXFR
+type RFC2136 struct {
+	server    string
+	network   string
+	timeout   time.Duration
+	tsigName  string
+	tsigAlgo  string
+	tsigCreds map[string]string
+}
+
+// NewRFC2136 创建 RFC 2136 发布目标，server 未写端口时使用 53
+func NewRFC2136(cfg config.PublisherConfig) (*RFC2136, error) {
+	if cfg.Server == "" {
+		return nil, fmt.Errorf("server is required")
+	}
+	if cfg.Protocol != "udp" && cfg.Protocol != "tcp" {
+		return nil, fmt.Errorf("unknown protocol %q", cfg.Protocol)
+	}
+
+	server := cfg.Server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	p := &RFC2136{
+		server:  server,
+		network: cfg.Protocol,
+		timeout: time.Duration(cfg.Timeout) * time.Second,
+	}
+	if cfg.TSIGName != "" {
+		p.tsigName = dns.Fqdn(cfg.TSIGName)
+		p.tsigAlgo = dns.Fqdn(cfg.TSIGAlgorithm)
+		p.tsigCreds = map[string]string{p.tsigName: cfg.TSIGSecret}
+	}
+	return p, nil
+}
+
+func (p *RFC2136) Publish(ctx context.Context, zone string, rs *models.PublishedRecordSet) error {
+	m := p.updateMsg(zone, rs.Name)
+	m.Insert(addressRecords(rs))
+	return p.update(ctx, m)
+}
+
+func (p *RFC2136) Remove(ctx context.Context, zone, name string) error {
+	return p.update(ctx, p.updateMsg(zone, name))
+}
+
+func (p *RFC2136) List(ctx context.Context, zone string) ([]*models.PublishedRecordSet, error) {
+	m := new(dns.Msg)
+	m.SetAxfr(dns.Fqdn(zone))
+	p.sign(m)
+
+	t := &dns.Transfer{
+		DialTimeout:  p.timeout,
+		ReadTimeout:  p.timeout,
+		WriteTimeout: p.timeout,
+		TsigSecret:   p.tsigCreds,
+	}
+	ch, err := t.In(m, p.server)
+	if err != nil {
+		return nil, err
+	}
+
+	var rrs []dns.RR
+	for env := range ch {
+		if env.Error != nil {
+			return nil, fmt.Errorf("zone transfer failed: %w", env.Error)
+		}
+		rrs = append(rrs, env.RR...)
+	}
+	return recordSets(rrs), nil
+}
+
+// updateMsg 生成删除 name 的 A / AAAA 记录集的 UPDATE 消息
+func (p *RFC2136) updateMsg(zone, name string) *dns.Msg {
+	owner := dns.Fqdn(name)
+	m := new(dns.Msg)
+	m.SetUpdate(dns.Fqdn(zone))
+	m.RemoveRRset([]dns.RR{
+		&dns.A{Hdr: dns.RR_Header{Name: owner, Rrtype: dns.TypeA, Class: dns.ClassINET}},
+		&dns.AAAA{Hdr: dns.RR_Header{Name: owner, Rrtype: dns.TypeAAAA, Class: dns.ClassINET}},
+	})
+	return m
+}
+
+// update 发送 UPDATE 消息，服务器拒绝时返回错误
+func (p *RFC2136) update(ctx context.Context, m *dns.Msg) error {
+	p.sign(m)
+	client := &dns.Client{Net: p.network, Timeout: p.timeout, TsigSecret: p.tsigCreds}
+	resp, _, err := client.ExchangeContext(ctx, m, p.server)
+	if err != nil {
+		return err
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("update rejected by %s: %s", p.server, dns.RcodeToString[resp.Rcode])
+	}
+	return nil
+}
+
+// sign 配置了 TSIG 时为消息签名
+func (p *RFC2136) sign(m *dns.Msg) {
+	if p.tsigName != "" {
+		m.SetTsig(p.tsigName, p.tsigAlgo, tsigFudge, time.Now().Unix())
+	}
+}
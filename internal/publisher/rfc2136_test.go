@@ -0,0 +1,250 @@
+package publisher
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"dancer/internal/config"
+	"dancer/internal/models"
+	"github.com/miekg/dns"
+)
+
+const (
+	testZone       = "example.com"
+	testTSIGName   = "dancer."
+	testTSIGSecret = "c2VjcmV0LXNlY3JldC1zZWNyZXQtc2VjcmV0"
+)
+
+// updateServer 接受 DNS UPDATE 与 AXFR 的进程内主服务器，记录保存在内存中
+// 只接受用 testTSIGName 签名的请求
+type updateServer struct {
+	addr string
+
+	mu  sync.Mutex
+	rrs []dns.RR
+}
+
+// startUpdateServer 在 127.0.0.1 上启动 TCP DNS 服务器，zone 中预先有一条 MX 记录，用于检查其他类型的记录不受影响
+func startUpdateServer(t *testing.T) *updateServer {
+	t.Helper()
+
+	mx, err := dns.NewRR("example.com. 300 IN MX 10 mail.example.com.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &updateServer{rrs: []dns.RR{mx}}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s.addr = l.Addr().String()
+
+	started := make(chan struct{})
+	server := &dns.Server{
+		Listener:          l,
+		Net:               "tcp",
+		Handler:           s,
+		TsigSecret:        map[string]string{testTSIGName: testTSIGSecret},
+		MsgAcceptFunc:     acceptUpdates,
+		NotifyStartedFunc: func() { close(started) },
+	}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+	<-started
+
+	return s
+}
+
+func (s *updateServer) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	resp := new(dns.Msg)
+	resp.SetReply(req)
+
+	switch {
+	case req.IsTsig() == nil || w.TsigStatus() != nil:
+		resp.Rcode = dns.RcodeNotAuth
+	case req.Question[0].Name != dns.Fqdn(testZone):
+		resp.Rcode = dns.RcodeNotZone
+	case req.Opcode == dns.OpcodeUpdate:
+		s.apply(req.Ns)
+	case req.Question[0].Qtype == dns.TypeAXFR:
+		s.transfer(w, req)
+		return
+	default:
+		resp.Rcode = dns.RcodeRefused
+	}
+	_ = w.WriteMsg(resp)
+}
+
+// acceptUpdates 在默认规则的基础上接受 UPDATE 消息，默认规则只接受查询与 NOTIFY
+func acceptUpdates(dh dns.Header) dns.MsgAcceptAction {
+	if int(dh.Bits>>11)&0xF == dns.OpcodeUpdate && dh.Bits&(1<<15) == 0 {
+		return dns.MsgAccept
+	}
+	return dns.DefaultMsgAcceptFunc(dh)
+}
+
+// apply 按 RFC 2136 3.4.2 执行更新段：ANY 删除记录集，其余为添加（发布目标不会删除单条记录）
+func (s *updateServer) apply(updates []dns.RR) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range updates {
+		h := u.Header()
+		switch h.Class {
+		case dns.ClassANY:
+			s.rrs = filterRRs(s.rrs, func(rr dns.RR) bool {
+				return rr.Header().Name == h.Name && (h.Rrtype == dns.TypeANY || rr.Header().Rrtype == h.Rrtype)
+			})
+		default:
+			s.rrs = append(s.rrs, u)
+		}
+	}
+}
+
+// transfer 应答 AXFR：SOA、全部记录、SOA
+func (s *updateServer) transfer(w dns.ResponseWriter, req *dns.Msg) {
+	soa, _ := dns.NewRR("example.com. 300 IN SOA ns.example.com. hostmaster.example.com. 1 7200 1800 1209600 300")
+
+	s.mu.Lock()
+	rrs := append([]dns.RR{soa}, s.rrs...)
+	s.mu.Unlock()
+	rrs = append(rrs, soa)
+
+	ch := make(chan *dns.Envelope, 1)
+	tr := new(dns.Transfer)
+	go func() {
+		ch <- &dns.Envelope{RR: rrs}
+		close(ch)
+	}()
+	_ = tr.Out(w, req, ch)
+	_ = w.Close()
+}
+
+// records 返回服务器上的全部记录
+func (s *updateServer) records() []dns.RR {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]dns.RR(nil), s.rrs...)
+}
+
+// filterRRs 返回不满足 drop 的记录
+func filterRRs(rrs []dns.RR, drop func(dns.RR) bool) []dns.RR {
+	kept := rrs[:0]
+	for _, rr := range rrs {
+		if !drop(rr) {
+			kept = append(kept, rr)
+		}
+	}
+	return kept
+}
+
+// newTestRFC2136 创建发布到 addr 的 RFC2136 发布目标
+func newTestRFC2136(t *testing.T, addr, secret string) *RFC2136 {
+	t.Helper()
+	p, err := NewRFC2136(config.PublisherConfig{
+		Server:        addr,
+		Protocol:      "tcp",
+		Timeout:       2,
+		TSIGName:      testTSIGName,
+		TSIGSecret:    secret,
+		TSIGAlgorithm: "hmac-sha256",
+	})
+	if err != nil {
+		t.Fatalf("NewRFC2136: %v", err)
+	}
+	return p
+}
+
+// TestRFC2136 发布替换域名的地址记录，撤下只删除地址记录，列出的结果与服务器上的记录一致
+func TestRFC2136(t *testing.T) {
+	server := startUpdateServer(t)
+	p := newTestRFC2136(t, server.addr, testTSIGSecret)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// 第一次发布
+	if err := p.Publish(ctx, testZone, &models.PublishedRecordSet{Name: "www.example.com", IPs: []string{"192.0.2.1", "2001:db8::1"}, TTL: 300}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := p.Publish(ctx, testZone, &models.PublishedRecordSet{Name: "api.example.com", IPs: []string{"192.0.2.9"}, TTL: 60}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	// 再次发布替换原有的记录集
+	if err := p.Publish(ctx, testZone, &models.PublishedRecordSet{Name: "www.example.com", IPs: []string{"192.0.2.2"}, TTL: 120}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	assertRRs(t, server.records(), []string{
+		"example.com.\t300\tIN\tMX\t10 mail.example.com.",
+		"api.example.com.\t60\tIN\tA\t192.0.2.9",
+		"www.example.com.\t120\tIN\tA\t192.0.2.2",
+	})
+
+	sets, err := p.List(ctx, testZone)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	want := []*models.PublishedRecordSet{
+		{Name: "api.example.com", IPs: []string{"192.0.2.9"}, TTL: 60},
+		{Name: "www.example.com", IPs: []string{"192.0.2.2"}, TTL: 120},
+	}
+	if !reflect.DeepEqual(sets, want) {
+		t.Errorf("List = %+v, want %+v", formatSets(sets), formatSets(want))
+	}
+
+	// 撤下后只剩其他域名与其他类型的记录，撤下不存在的域名不报错
+	if err := p.Remove(ctx, testZone, "www.example.com"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := p.Remove(ctx, testZone, "missing.example.com"); err != nil {
+		t.Fatalf("Remove missing: %v", err)
+	}
+	assertRRs(t, server.records(), []string{
+		"example.com.\t300\tIN\tMX\t10 mail.example.com.",
+		"api.example.com.\t60\tIN\tA\t192.0.2.9",
+	})
+}
+
+// TestRFC2136Rejected 服务器拒绝更新（TSIG 密钥错误）时返回错误
+func TestRFC2136Rejected(t *testing.T) {
+	server := startUpdateServer(t)
+	p := newTestRFC2136(t, server.addr, "d3Jvbmctc2VjcmV0LXdyb25nLXNlY3JldA==")
+
+	err := p.Publish(context.Background(), testZone, &models.PublishedRecordSet{Name: "www.example.com", IPs: []string{"192.0.2.1"}, TTL: 300})
+	if err == nil {
+		t.Fatal("Publish succeeded, want error")
+	}
+	if n := len(server.records()); n != 1 {
+		t.Errorf("server has %d records, want only the MX record", n)
+	}
+}
+
+// assertRRs 检查记录与期望的文本形式一致（不区分顺序）
+func assertRRs(t *testing.T, rrs []dns.RR, want []string) {
+	t.Helper()
+	got := make(map[string]bool, len(rrs))
+	for _, rr := range rrs {
+		got[rr.String()] = true
+	}
+	wanted := make(map[string]bool, len(want))
+	for _, s := range want {
+		wanted[s] = true
+	}
+	if !reflect.DeepEqual(got, wanted) {
+		t.Errorf("records = %v, want %v", got, wanted)
+	}
+}
+
+// formatSets 展开记录集指针，用于失败信息
+func formatSets(sets []*models.PublishedRecordSet) []models.PublishedRecordSet {
+	out := make([]models.PublishedRecordSet, len(sets))
+	for i, rs := range sets {
+		out[i] = *rs
+	}
+	return out
+}
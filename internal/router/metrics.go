@@ -0,0 +1,35 @@
+package router
+
+import (
+	"strconv"
+	"time"
+
+	"dancer/internal/metrics"
+	"github.com/labstack/echo/v4"
+)
+
+// unmatchedRoute 没有匹配到路由的请求使用的 route 标签，避免按原始路径产生大量时间序列
+const unmatchedRoute = "unmatched"
+
+// Metrics 请求指标中间件，按方法、路由模板与状态码记录请求数与耗时
+// 错误在此处交给 HTTPErrorHandler 写入响应，以便记录最终的状态码
+func Metrics() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = unmatchedRoute
+			}
+			labels := []string{c.Request().Method, route, strconv.Itoa(c.Response().Status)}
+			metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+			metrics.HTTPRequestDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+			return nil
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/openapi"
+	"dancer/internal/services"
+	"github.com/labstack/echo/v4"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// swaggerInitializer Swagger UI 初始化脚本，加载本服务的 OpenAPI 文档
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "/api/openapi.json",
+    dom_id: '#swagger-ui',
+    deepLinking: true,
+    persistAuthorization: true,
+    presets: [
+      SwaggerUIBundle.presets.apis,
+      SwaggerUIStandalonePreset
+    ],
+    plugins: [
+      SwaggerUIBundle.plugins.DownloadUrl
+    ],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// operations 各路由的接口描述，key 为 "方法 路径"
+// 新增路由时必须在此添加描述，否则 openapi_test.go 中的检查失败
+var operations = map[string]*openapi.Operation{
+	// 健康检查
+	"GET /api/health":         {Tag: "health", Summary: "健康检查，etcd 不可用时返回 503", Response: services.HealthStatus{}, Status: []int{http.StatusOK, http.StatusServiceUnavailable}},
+	"POST /api/health":        {Tag: "health", Summary: "健康检查，etcd 不可用时返回 503", Response: services.HealthStatus{}, Status: []int{http.StatusOK, http.StatusServiceUnavailable}},
+	"GET /api/health/details": {Tag: "health", Summary: "详细健康状态：etcd 节点、CoreDNS 同步、默认管理员初始化、后台任务与构建信息", Security: openapi.SuperAdmin, Response: services.HealthDetails{}},
+	"GET /livez":              {Tag: "health", Summary: "存活检查", Response: services.HealthStatus{}},
+	"GET /readyz":             {Tag: "health", Summary: "就绪检查，etcd 未连接、默认管理员未初始化或搜索索引未加载时返回 503", Response: services.HealthStatus{}, Status: []int{http.StatusOK, http.StatusServiceUnavailable}},
+
+	// 指标
+	"GET /metrics": {Tag: "metrics", Summary: "Prometheus 指标（文本格式）", ContentType: "text/plain"},
+
+	// 认证
+	"POST /api/auth/login":         {Tag: "auth", Summary: "登录", Body: models.LoginRequest{}, Response: models.LoginResponse{}},
+	"POST /api/auth/refresh":       {Tag: "auth", Summary: "刷新 Token，data 为 LoginResponse", Security: openapi.JWT, Response: models.Response{}},
+	"POST /api/me":                 {Tag: "auth", Summary: "获取当前用户", Security: openapi.JWT, Response: models.UserDTO{}},
+	"POST /api/me/change-password": {Tag: "auth", Summary: "修改密码", Security: openapi.JWT, Body: models.ChangePasswordRequest{}, Response: models.Response{}},
+
+	// 用户管理
+	"POST /api/user/list":   {Tag: "users", Summary: "列出用户", Security: openapi.Admin, Body: models.ListUsersRequest{}, Response: models.UserListDTO{}},
+	"POST /api/user/create": {Tag: "users", Summary: "创建用户", Security: openapi.Admin, Body: models.CreateUserRequest{}, Response: models.UserDTO{}},
+	"POST /api/user/update": {Tag: "users", Summary: "更新用户", Security: openapi.Admin, Body: models.UpdateUserRequest{}, Response: models.Response{}},
+	"POST /api/user/delete": {Tag: "users", Summary: "删除用户", Security: openapi.Admin, Body: models.DeleteUserRequest{}, Response: models.Response{}},
+
+	// Zone 管理
+	"POST /api/dns/zones/list":          {Tag: "zones", Summary: "列出 Zone", Security: openapi.Admin, Body: models.ListZonesRequest{}, Response: models.ZoneListDTO{}},
+	"POST /api/dns/zones/get":           {Tag: "zones", Summary: "获取 Zone", Security: openapi.Admin, Body: models.GetZoneRequest{}, Response: models.ZoneDTO{}},
+	"POST /api/dns/zones/create":        {Tag: "zones", Summary: "创建 Zone", Security: openapi.Admin, Body: models.CreateZoneRequest{}, Response: models.ZoneDTO{}},
+	"POST /api/dns/zones/update":        {Tag: "zones", Summary: "更新 Zone", Security: openapi.Admin, Body: models.UpdateZoneRequest{}, Response: models.ZoneDTO{}},
+	"POST /api/dns/zones/delete":        {Tag: "zones", Summary: "删除 Zone，连同 Domain 移入回收站", Security: openapi.Admin, Body: models.DeleteZoneRequest{}, Response: models.Response{}},
+	"POST /api/dns/zones/rollback":      {Tag: "zones", Summary: "回滚 Zone 下所有 Domain 到指定时间点", Security: openapi.Admin, Body: models.RollbackZoneRequest{}, Response: models.ZoneRollbackDTO{}},
+	"POST /api/dns/zones/trash":         {Tag: "trash", Summary: "列出回收站中的 Zone", Security: openapi.Admin, Body: models.ListTrashRequest{}, Response: models.TrashListDTO{}},
+	"POST /api/dns/zones/restore":       {Tag: "trash", Summary: "从回收站恢复 Zone", Security: openapi.Admin, Body: models.TrashEntryRequest{}, Response: models.ZoneDTO{}},
+	"POST /api/dns/zones/purge":         {Tag: "trash", Summary: "永久删除回收站中的 Zone", Security: openapi.Admin, Body: models.TrashEntryRequest{}, Response: models.Response{}},
+	"POST /api/dns/zones/verify":        {Tag: "verification", Summary: "向 DNS 服务器查询 Zone 下所有 Domain 并与记录对比", Security: openapi.Admin, Body: models.VerifyZoneRequest{}, Response: models.ZoneVerification{}},
+	"POST /api/dns/zones/verifications": {Tag: "verification", Summary: "获取 Zone 下各 Domain 最近一次校验结果", Security: openapi.Admin, Body: models.VerifyZoneRequest{}, Response: models.ZoneVerification{}},
+
+	// Domain 管理
+	"POST /api/dns/domains/list":         {Tag: "domains", Summary: "列出 Zone 下的 Domain", Security: openapi.JWT, Body: models.ListDomainsRequest{}, Response: models.DomainListDTO{}},
+	"POST /api/dns/domains/get":          {Tag: "domains", Summary: "获取 Domain", Security: openapi.JWT, Body: models.GetDomainRequest{}, Response: models.DomainDTO{}},
+	"POST /api/dns/domains/create":       {Tag: "domains", Summary: "创建 Domain", Security: openapi.JWT, Body: models.CreateDomainRequest{}, Response: models.DomainDTO{}},
+	"POST /api/dns/domains/update":       {Tag: "domains", Summary: "更新 Domain", Security: openapi.JWT, Body: models.UpdateDomainRequest{}, Response: models.DomainDTO{}},
+	"POST /api/dns/domains/delete":       {Tag: "domains", Summary: "删除 Domain，移入回收站", Security: openapi.JWT, Body: models.DeleteDomainRequest{}, Response: models.Response{}},
+	"POST /api/dns/domains/renew":        {Tag: "domains", Summary: "续约临时 Domain", Security: openapi.JWT, Body: models.RenewDomainRequest{}, Response: models.DomainDTO{}},
+	"POST /api/dns/domains/batch":        {Tag: "domains", Summary: "批量变更 Domain", Security: openapi.JWT, Body: models.BatchDomainsRequest{}, Response: models.BatchDomainsResultDTO{}},
+	"POST /api/dns/domains/instances":    {Tag: "registry", Summary: "列出 Domain 下的动态实例", Security: openapi.JWT, Body: models.ListInstancesRequest{}, Response: models.InstanceListDTO{}},
+	"POST /api/dns/domains/history":      {Tag: "history", Summary: "查询 Domain 历史版本", Security: openapi.JWT, Body: models.DomainHistoryRequest{}, Response: models.DomainHistoryDTO{}},
+	"POST /api/dns/domains/diff":         {Tag: "history", Summary: "对比 Domain 两个版本", Security: openapi.JWT, Body: models.DiffDomainVersionsRequest{}, Response: models.DomainChangeDiff{}},
+	"POST /api/dns/domains/rollback":     {Tag: "history", Summary: "回滚 Domain 到历史版本", Description: "目标为删除版本时 Domain 被删除，返回 Response", Security: openapi.JWT, Body: models.RollbackDomainRequest{}, Response: models.DomainDTO{}},
+	"POST /api/dns/domains/trash":        {Tag: "trash", Summary: "列出回收站中的 Domain", Security: openapi.JWT, Body: models.ListTrashRequest{}, Response: models.TrashListDTO{}},
+	"POST /api/dns/domains/restore":      {Tag: "trash", Summary: "从回收站恢复 Domain", Security: openapi.JWT, Body: models.TrashEntryRequest{}, Response: models.DomainDTO{}},
+	"POST /api/dns/domains/purge":        {Tag: "trash", Summary: "永久删除回收站中的 Domain", Security: openapi.Admin, Body: models.TrashEntryRequest{}, Response: models.Response{}},
+	"POST /api/dns/domains/verify":       {Tag: "verification", Summary: "向 DNS 服务器查询 Domain 并与记录对比", Security: openapi.JWT, Body: models.VerifyDomainRequest{}, Response: models.DomainVerification{}},
+	"POST /api/dns/domains/verification": {Tag: "verification", Summary: "获取 Domain 最近一次校验结果", Security: openapi.JWT, Body: models.VerifyDomainRequest{}, Response: models.DomainVerification{}},
+
+	// 发布目标
+	"POST /api/dns/publishers/list":    {Tag: "publishers", Summary: "列出已配置的发布目标", Security: openapi.Admin, Response: models.PublisherListDTO{}},
+	"POST /api/dns/publishers/records": {Tag: "publishers", Summary: "读取发布目标上 Zone 当前的地址记录", Security: openapi.Admin, Body: models.ListPublishedRequest{}, Response: models.PublishedRecords{}},
+
+	// 搜索与事件
+	"POST /api/dns/search": {Tag: "search", Summary: "跨 Zone 搜索 Domain", Security: openapi.JWT, Body: models.SearchDomainsRequest{}, Response: models.SearchDomainsDTO{}},
+	"GET /api/dns/events": {
+		Tag:         "events",
+		Summary:     "订阅变更事件（Server-Sent Events）",
+		Description: "断线重连时通过 revision 参数或 Last-Event-ID 头继续；每条消息的 data 为 ChangeEventDTO",
+		Security:    openapi.JWT,
+		Params:      []*openapi.Parameter{{Name: "token", In: "query", Description: "JWT，供无法设置请求头的客户端使用", Schema: &openapi.Schema{Type: "string"}}},
+		Query:       models.StreamEventsRequest{},
+		ContentType: "text/event-stream",
+	},
+
+	// 定时变更
+	"POST /api/dns/schedules/list":   {Tag: "schedules", Summary: "列出定时变更", Security: openapi.JWT, Body: models.ListSchedulesRequest{}, Response: models.ScheduleListDTO{}},
+	"POST /api/dns/schedules/get":    {Tag: "schedules", Summary: "获取定时变更", Security: openapi.JWT, Body: models.GetScheduleRequest{}, Response: models.ScheduleDTO{}},
+	"POST /api/dns/schedules/create": {Tag: "schedules", Summary: "创建定时变更", Security: openapi.JWT, Body: models.CreateScheduleRequest{}, Response: models.ScheduleDTO{}},
+	"POST /api/dns/schedules/cancel": {Tag: "schedules", Summary: "取消定时变更", Security: openapi.JWT, Body: models.CancelScheduleRequest{}, Response: models.ScheduleDTO{}},
+
+	// 变更申请
+	"POST /api/dns/changes/list":    {Tag: "changes", Summary: "列出变更申请", Security: openapi.JWT, Body: models.ListChangeRequestsRequest{}, Response: models.ChangeRequestListDTO{}},
+	"POST /api/dns/changes/get":     {Tag: "changes", Summary: "获取变更申请，待审批时附带差异", Security: openapi.JWT, Body: models.GetChangeRequestRequest{}, Response: models.ChangeRequestDTO{}},
+	"POST /api/dns/changes/propose": {Tag: "changes", Summary: "提交变更申请", Security: openapi.JWT, Body: models.ProposeChangeRequest{}, Response: models.ChangeRequestDTO{}},
+	"POST /api/dns/changes/approve": {Tag: "changes", Summary: "批准并应用变更申请", Security: openapi.Admin, Body: models.ReviewChangeRequest{}, Response: models.ChangeRequestDTO{}},
+	"POST /api/dns/changes/reject":  {Tag: "changes", Summary: "驳回变更申请", Security: openapi.Admin, Body: models.ReviewChangeRequest{}, Response: models.ChangeRequestDTO{}},
+
+	// API Token
+	"POST /api/tokens/list":   {Tag: "tokens", Summary: "列出 API Token", Security: openapi.Admin, Response: models.APITokenListDTO{}},
+	"POST /api/tokens/create": {Tag: "tokens", Summary: "创建 API Token，明文只返回一次", Security: openapi.Admin, Body: models.CreateAPITokenRequest{}, Response: models.APITokenCreatedDTO{}},
+	"POST /api/tokens/delete": {Tag: "tokens", Summary: "删除 API Token", Security: openapi.Admin, Body: models.DeleteAPITokenRequest{}, Response: models.Response{}},
+
+	// Webhook
+	"POST /api/webhooks/list":       {Tag: "webhooks", Summary: "列出 Webhook 订阅", Security: openapi.Admin, Response: models.WebhookListDTO{}},
+	"POST /api/webhooks/get":        {Tag: "webhooks", Summary: "获取 Webhook 订阅", Security: openapi.Admin, Body: models.GetWebhookRequest{}, Response: models.WebhookDTO{}},
+	"POST /api/webhooks/create":     {Tag: "webhooks", Summary: "创建 Webhook 订阅", Security: openapi.Admin, Body: models.CreateWebhookRequest{}, Response: models.WebhookSecretDTO{}},
+	"POST /api/webhooks/update":     {Tag: "webhooks", Summary: "更新 Webhook 订阅", Security: openapi.Admin, Body: models.UpdateWebhookRequest{}, Response: models.WebhookSecretDTO{}},
+	"POST /api/webhooks/delete":     {Tag: "webhooks", Summary: "删除 Webhook 订阅", Security: openapi.Admin, Body: models.GetWebhookRequest{}, Response: models.Response{}},
+	"POST /api/webhooks/deliveries": {Tag: "webhooks", Summary: "列出投递记录", Security: openapi.Admin, Body: models.ListWebhookDeliveriesRequest{}, Response: models.WebhookDeliveryListDTO{}},
+	"POST /api/webhooks/redeliver":  {Tag: "webhooks", Summary: "重新投递", Security: openapi.Admin, Body: models.RedeliverWebhookRequest{}, Response: models.WebhookDeliveryDTO{}},
+
+	// 备份与恢复
+	"POST /api/backup/export":  {Tag: "backup", Summary: "导出全量备份", Security: openapi.Admin, Body: models.ExportBackupRequest{}, ContentType: echo.MIMEOctetStream},
+	"POST /api/backup/restore": {Tag: "backup", Summary: "从备份文件恢复", Security: openapi.Admin, Form: models.RestoreBackupRequest{}, FormFile: "archive", Response: models.RestoreResult{}},
+
+	// 租户管理
+	"POST /api/tenants/list":   {Tag: "tenants", Summary: "列出租户", Security: openapi.SuperAdmin, Response: models.TenantListDTO{}},
+	"POST /api/tenants/get":    {Tag: "tenants", Summary: "获取租户", Security: openapi.SuperAdmin, Body: models.TenantRequest{}, Response: models.Tenant{}},
+	"POST /api/tenants/create": {Tag: "tenants", Summary: "创建租户及其第一个管理员", Security: openapi.SuperAdmin, Body: models.CreateTenantRequest{}, Response: models.Tenant{}},
+	"POST /api/tenants/delete": {Tag: "tenants", Summary: "删除没有 Zone 的租户及其用户", Security: openapi.SuperAdmin, Body: models.TenantRequest{}, Response: models.Response{}},
+
+	// 声明式同步
+	"POST /api/sync/plan":  {Tag: "sync", Summary: "生成同步计划", Security: openapi.Admin, Body: models.SyncRequest{}, Response: models.SyncPlan{}},
+	"POST /api/sync/apply": {Tag: "sync", Summary: "生成并应用同步计划", Security: openapi.Admin, Body: models.SyncRequest{}, Response: models.SyncPlan{}},
+
+	// v2 资源接口
+	"GET /api/v2/zones":         {Tag: "v2", Summary: "列出 Zone", Security: openapi.Admin, Query: models.ListZonesRequest{}, Response: []models.ZoneResource{}},
+	"POST /api/v2/zones":        {Tag: "v2", Summary: "创建 Zone", Security: openapi.Admin, Body: models.CreateZoneResource{}, Response: models.ZoneResource{}, Status: []int{http.StatusCreated}},
+	"GET /api/v2/zones/:zone":   {Tag: "v2", Summary: "获取 Zone", Security: openapi.Admin, Response: models.ZoneResource{}},
+	"PUT /api/v2/zones/:zone":   {Tag: "v2", Summary: "写入 Zone，不存在时创建", Security: openapi.Admin, Body: models.PutZoneResource{}, Response: models.ZoneResource{}, Status: []int{http.StatusOK, http.StatusCreated}},
+	"PATCH /api/v2/zones/:zone": {Tag: "v2", Summary: "修改 Zone 的部分字段", Security: openapi.Admin, Body: models.PatchZoneResource{}, Response: models.ZoneResource{}},
+	"DELETE /api/v2/zones/:zone": {
+		Tag:      "v2",
+		Summary:  "删除 Zone，连同 Domain 移入回收站",
+		Security: openapi.Admin,
+		Params:   []*openapi.Parameter{{Name: "confirm", In: "query", Description: "Zone 下仍有 Domain 时必须填写 Zone 名称", Schema: &openapi.Schema{Type: "string"}}},
+		Status:   []int{http.StatusNoContent},
+	},
+	"GET /api/v2/zones/:zone/domains":          {Tag: "v2", Summary: "列出 Zone 下的 Domain", Security: openapi.JWT, Query: models.ListDomainsRequest{}, Response: []models.DomainResource{}},
+	"POST /api/v2/zones/:zone/domains":         {Tag: "v2", Summary: "创建 Domain", Security: openapi.JWT, Body: models.CreateDomainResource{}, Response: models.DomainResource{}, Status: []int{http.StatusCreated}},
+	"GET /api/v2/zones/:zone/domains/:name":    {Tag: "v2", Summary: "获取 Domain", Security: openapi.JWT, Response: models.DomainResource{}},
+	"PUT /api/v2/zones/:zone/domains/:name":    {Tag: "v2", Summary: "写入 Domain，不存在时创建", Security: openapi.JWT, Body: models.PutDomainResource{}, Response: models.DomainResource{}, Status: []int{http.StatusOK, http.StatusCreated}},
+	"PATCH /api/v2/zones/:zone/domains/:name":  {Tag: "v2", Summary: "修改 Domain 的部分字段", Security: openapi.JWT, Body: models.PatchDomainResource{}, Response: models.DomainResource{}},
+	"DELETE /api/v2/zones/:zone/domains/:name": {Tag: "v2", Summary: "删除 Domain，移入回收站", Security: openapi.JWT, Status: []int{http.StatusNoContent}},
+	"GET /api/v2/users":                        {Tag: "v2", Summary: "列出用户", Security: openapi.Admin, Query: models.ListUsersRequest{}, Response: []models.UserResource{}},
+	"POST /api/v2/users":                       {Tag: "v2", Summary: "创建用户", Security: openapi.Admin, Body: models.CreateUserResource{}, Response: models.UserResource{}, Status: []int{http.StatusCreated}},
+	"GET /api/v2/users/:id":                    {Tag: "v2", Summary: "获取用户", Security: openapi.Admin, Response: models.UserResource{}},
+	"PATCH /api/v2/users/:id":                  {Tag: "v2", Summary: "修改用户的部分字段", Security: openapi.Admin, Body: models.PatchUserResource{}, Response: models.UserResource{}},
+	"DELETE /api/v2/users/:id":                 {Tag: "v2", Summary: "删除用户", Security: openapi.Admin, Status: []int{http.StatusNoContent}},
+
+	// 服务注册
+	"POST /api/registry/register":   {Tag: "registry", Summary: "注册动态实例", Security: openapi.APIToken, Body: models.RegisterInstanceRequest{}, Response: models.InstanceDTO{}},
+	"POST /api/registry/heartbeat":  {Tag: "registry", Summary: "实例心跳", Security: openapi.APIToken, Body: models.HeartbeatInstanceRequest{}, Response: models.InstanceDTO{}},
+	"POST /api/registry/deregister": {Tag: "registry", Summary: "注销动态实例", Security: openapi.APIToken, Body: models.DeregisterInstanceRequest{}, Response: models.Response{}},
+}
+
+// registerOpenAPI 按已注册的路由生成 OpenAPI 文档，提供 /api/openapi.json 与 Swagger UI（/api/docs/）
+// 路由与 operations 不一致时记录错误日志，缺少描述的路由不出现在文档中；一致性由 openapi_test.go 在测试中保证
+func registerOpenAPI(e *echo.Echo) {
+	doc, problems := buildOpenAPI(e.Routes())
+	if len(problems) > 0 {
+		logger.Log.Errorf("OpenAPI operations do not match routes:\n  %s", strings.Join(problems, "\n  "))
+	}
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		logger.Log.WithError(err).Error("Failed to marshal OpenAPI document, /api/openapi.json disabled")
+		return
+	}
+
+	e.GET("/api/openapi.json", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, spec)
+	})
+
+	files := http.StripPrefix("/api/docs/", http.FileServer(http.FS(swaggerFiles.FS)))
+	e.GET("/api/docs", func(c echo.Context) error {
+		return c.Redirect(http.StatusMovedPermanently, "/api/docs/")
+	})
+	e.GET("/api/docs/swagger-initializer.js", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJavaScript, []byte(swaggerInitializer))
+	})
+	e.GET("/api/docs/*", echo.WrapHandler(files))
+}
+
+// buildOpenAPI 按路由与 operations 生成 OpenAPI 文档，返回两者不一致之处
+func buildOpenAPI(routes []*echo.Route) (*openapi.Document, []string) {
+	return openapi.Build(&openapi.Spec{
+		Title:          "Dancer DNS Management API",
+		Version:        "1.0.0",
+		Description:    "CoreDNS 的 DNS 管理服务。/api/v2 下的接口成功响应为 {\"data\": ..., \"meta\": ...}，错误响应为 {\"error\": ...}",
+		ResourcePrefix: "/api/v2/",
+		ErrorResponse:  Response{},
+		ListMeta:       models.ListMeta{},
+		Operations:     operations,
+	}, routes)
+}
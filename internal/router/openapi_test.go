@@ -0,0 +1,70 @@
+package router
+
+import (
+	"strings"
+	"testing"
+
+	"dancer/internal/handlers"
+	"dancer/internal/logger"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// newTestRouter 使用零值处理器构建路由，只用于检查路由表，不处理请求
+func newTestRouter() *echo.Echo {
+	if logger.Log == nil {
+		logger.Log = logrus.New()
+	}
+	return New(
+		&handlers.UserHandler{},
+		&handlers.ZoneHandler{},
+		&handlers.DomainHandler{},
+		&handlers.HealthHandler{},
+		&handlers.APITokenHandler{},
+		&handlers.RegistryHandler{},
+		&handlers.ScheduleHandler{},
+		&handlers.ChangeRequestHandler{},
+		&handlers.SearchHandler{},
+		&handlers.EventHandler{},
+		&handlers.WebhookHandler{},
+		&handlers.HistoryHandler{},
+		&handlers.TrashHandler{},
+		&handlers.BackupHandler{},
+		&handlers.SyncHandler{},
+		&handlers.VerificationHandler{},
+		&handlers.PublisherHandler{},
+		&handlers.TenantHandler{},
+		&handlers.V2Handler{},
+	)
+}
+
+// TestOperationsMatchRoutes 每个路由都有 OpenAPI 描述，每个描述都对应已注册的路由
+func TestOperationsMatchRoutes(t *testing.T) {
+	e := newTestRouter()
+
+	// 文档自身的路由在生成文档之后注册，不在 operations 中
+	var routes []*echo.Route
+	for _, route := range e.Routes() {
+		if route.Path == "/api/openapi.json" || strings.HasPrefix(route.Path, "/api/docs") {
+			continue
+		}
+		routes = append(routes, route)
+	}
+
+	_, problems := buildOpenAPI(routes)
+	if len(problems) > 0 {
+		t.Fatalf("routes and operations do not match:\n  %s", strings.Join(problems, "\n  "))
+	}
+}
+
+// TestOpenAPIServed 文档路由已注册
+func TestOpenAPIServed(t *testing.T) {
+	e := newTestRouter()
+
+	for _, route := range e.Routes() {
+		if route.Method == "GET" && route.Path == "/api/openapi.json" {
+			return
+		}
+	}
+	t.Fatal("GET /api/openapi.json is not registered")
+}
@@ -3,11 +3,13 @@ package router
 import (
 	"errors"
 	"net/http"
+	"strings"
 
 	"dancer/internal/auth"
 	apperrors "dancer/internal/errors"
 	"dancer/internal/handlers"
 	"dancer/internal/logger"
+	"dancer/internal/metrics"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
@@ -24,6 +26,21 @@ func New(
 	zoneHandler *handlers.ZoneHandler,
 	domainHandler *handlers.DomainHandler,
 	healthHandler *handlers.HealthHandler,
+	tokenHandler *handlers.APITokenHandler,
+	registryHandler *handlers.RegistryHandler,
+	scheduleHandler *handlers.ScheduleHandler,
+	changeHandler *handlers.ChangeRequestHandler,
+	searchHandler *handlers.SearchHandler,
+	eventHandler *handlers.EventHandler,
+	webhookHandler *handlers.WebhookHandler,
+	historyHandler *handlers.HistoryHandler,
+	trashHandler *handlers.TrashHandler,
+	backupHandler *handlers.BackupHandler,
+	syncHandler *handlers.SyncHandler,
+	verificationHandler *handlers.VerificationHandler,
+	publisherHandler *handlers.PublisherHandler,
+	tenantHandler *handlers.TenantHandler,
+	v2Handler *handlers.V2Handler,
 ) *echo.Echo {
 	e := echo.New()
 	e.HideBanner = true // 隐藏 Echo 默认 Banner
@@ -33,10 +50,12 @@ func New(
 
 	// 中间件
 	e.Use(CustomLogger()) // 自定义访问日志中间件
+	e.Use(Tracing())      // 链路追踪，解析上游的 traceparent
+	e.Use(Metrics())      // 请求指标，须在 Recover 之外以记录 panic 的请求
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"},
-		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
+		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions},
 		AllowHeaders: []string{"Authorization", "Content-Type"},
 	}))
 
@@ -46,6 +65,7 @@ func New(
 	// 健康检查（公开端点，支持 GET 和 POST）
 	api.GET("/health", healthHandler.Check)
 	api.POST("/health", healthHandler.Check)
+	api.GET("/health/details", healthHandler.Details, auth.JWTMiddleware(), auth.RequireSuperAdmin())
 
 	// 公开路由
 	authGroup := api.Group("/auth")
@@ -71,6 +91,12 @@ func New(
 	zones.POST("/create", zoneHandler.CreateZone)
 	zones.POST("/update", zoneHandler.UpdateZone)
 	zones.POST("/delete", zoneHandler.DeleteZone)
+	zones.POST("/rollback", historyHandler.RollbackZone)
+	zones.POST("/trash", trashHandler.ListZoneTrash)
+	zones.POST("/restore", trashHandler.RestoreZone)
+	zones.POST("/purge", trashHandler.PurgeZone)
+	zones.POST("/verify", verificationHandler.VerifyZone)
+	zones.POST("/verifications", verificationHandler.ListZoneVerifications)
 
 	// DNS Domain 管理（需要认证）
 	domains := api.Group("/dns/domains", auth.JWTMiddleware())
@@ -79,138 +105,418 @@ func New(
 	domains.POST("/create", domainHandler.CreateDomain)
 	domains.POST("/update", domainHandler.UpdateDomain)
 	domains.POST("/delete", domainHandler.DeleteDomain)
+	domains.POST("/renew", domainHandler.RenewDomain)
+	domains.POST("/batch", domainHandler.BatchDomains)
+	domains.POST("/instances", registryHandler.ListInstances)
+	domains.POST("/history", historyHandler.DomainHistory)
+	domains.POST("/diff", historyHandler.DiffDomainVersions)
+	domains.POST("/rollback", historyHandler.RollbackDomain)
+	domains.POST("/trash", trashHandler.ListDomainTrash)
+	domains.POST("/restore", trashHandler.RestoreDomain)
+	domains.POST("/purge", trashHandler.PurgeDomain, auth.RequireAdmin())
+	domains.POST("/verify", verificationHandler.VerifyDomain)
+	domains.POST("/verification", verificationHandler.GetDomainVerification)
+
+	// 发布目标（需要管理员权限）
+	publishers := api.Group("/dns/publishers", auth.JWTMiddleware(), auth.RequireAdmin())
+	publishers.POST("/list", publisherHandler.ListPublishers)
+	publishers.POST("/records", publisherHandler.ListPublished)
+
+	// 跨 Zone 搜索（需要认证）
+	api.POST("/dns/search", searchHandler.SearchDomains, auth.JWTMiddleware())
+
+	// 变更事件流（需要认证，支持通过查询参数 token 传递 JWT）
+	api.GET("/dns/events", eventHandler.StreamEvents, auth.QueryTokenMiddleware(), auth.JWTMiddleware())
+
+	// 定时变更（需要认证）
+	schedules := api.Group("/dns/schedules", auth.JWTMiddleware())
+	schedules.POST("/list", scheduleHandler.ListSchedules)
+	schedules.POST("/get", scheduleHandler.GetSchedule)
+	schedules.POST("/create", scheduleHandler.CreateSchedule)
+	schedules.POST("/cancel", scheduleHandler.CancelSchedule)
+
+	// 变更申请（需要认证，审批需要管理员权限）
+	changes := api.Group("/dns/changes", auth.JWTMiddleware())
+	changes.POST("/list", changeHandler.ListChangeRequests)
+	changes.POST("/get", changeHandler.GetChangeRequest)
+	changes.POST("/propose", changeHandler.ProposeChangeRequest)
+	changes.POST("/approve", changeHandler.ApproveChangeRequest, auth.RequireAdmin())
+	changes.POST("/reject", changeHandler.RejectChangeRequest, auth.RequireAdmin())
+
+	// API Token 管理（需要管理员权限）
+	tokens := api.Group("/tokens", auth.JWTMiddleware(), auth.RequireAdmin())
+	tokens.POST("/list", tokenHandler.ListTokens)
+	tokens.POST("/create", tokenHandler.CreateToken)
+	tokens.POST("/delete", tokenHandler.DeleteToken)
+
+	// Webhook 订阅管理（需要管理员权限）
+	webhooks := api.Group("/webhooks", auth.JWTMiddleware(), auth.RequireAdmin())
+	webhooks.POST("/list", webhookHandler.ListWebhooks)
+	webhooks.POST("/get", webhookHandler.GetWebhook)
+	webhooks.POST("/create", webhookHandler.CreateWebhook)
+	webhooks.POST("/update", webhookHandler.UpdateWebhook)
+	webhooks.POST("/delete", webhookHandler.DeleteWebhook)
+	webhooks.POST("/deliveries", webhookHandler.ListDeliveries)
+	webhooks.POST("/redeliver", webhookHandler.Redeliver)
+
+	// 备份与恢复（需要管理员权限）
+	backup := api.Group("/backup", auth.JWTMiddleware(), auth.RequireAdmin())
+	backup.POST("/export", backupHandler.ExportBackup)
+	backup.POST("/restore", backupHandler.RestoreBackup)
+
+	// 租户管理（需要超级管理员权限）
+	tenants := api.Group("/tenants", auth.JWTMiddleware(), auth.RequireSuperAdmin())
+	tenants.POST("/list", tenantHandler.ListTenants)
+	tenants.POST("/get", tenantHandler.GetTenant)
+	tenants.POST("/create", tenantHandler.CreateTenant)
+	tenants.POST("/delete", tenantHandler.DeleteTenant)
+
+	// 声明式同步（需要管理员权限）
+	sync := api.Group("/sync", auth.JWTMiddleware(), auth.RequireAdmin())
+	sync.POST("/plan", syncHandler.Plan)
+	sync.POST("/apply", syncHandler.Apply)
+
+	// v2 资源接口（需要认证，Zone 与用户管理需要管理员权限）
+	v2 := api.Group("/v2", auth.JWTMiddleware())
+	v2.GET("/zones", v2Handler.ListZones, auth.RequireAdmin())
+	v2.POST("/zones", v2Handler.CreateZone, auth.RequireAdmin())
+	v2.GET("/zones/:zone", v2Handler.GetZone, auth.RequireAdmin())
+	v2.PUT("/zones/:zone", v2Handler.PutZone, auth.RequireAdmin())
+	v2.PATCH("/zones/:zone", v2Handler.PatchZone, auth.RequireAdmin())
+	v2.DELETE("/zones/:zone", v2Handler.DeleteZone, auth.RequireAdmin())
+	v2.GET("/zones/:zone/domains", v2Handler.ListDomains)
+	v2.POST("/zones/:zone/domains", v2Handler.CreateDomain)
+	v2.GET("/zones/:zone/domains/:name", v2Handler.GetDomain)
+	v2.PUT("/zones/:zone/domains/:name", v2Handler.PutDomain)
+	v2.PATCH("/zones/:zone/domains/:name", v2Handler.PatchDomain)
+	v2.DELETE("/zones/:zone/domains/:name", v2Handler.DeleteDomain)
+	v2.GET("/users", v2Handler.ListUsers, auth.RequireAdmin())
+	v2.POST("/users", v2Handler.CreateUser, auth.RequireAdmin())
+	v2.GET("/users/:id", v2Handler.GetUser, auth.RequireAdmin())
+	v2.PATCH("/users/:id", v2Handler.PatchUser, auth.RequireAdmin())
+	v2.DELETE("/users/:id", v2Handler.DeleteUser, auth.RequireAdmin())
+
+	// 服务注册（API Token 认证）
+	registry := api.Group("/registry", auth.APITokenMiddleware(tokenHandler.Authenticate))
+	registry.POST("/register", registryHandler.Register)
+	registry.POST("/heartbeat", registryHandler.Heartbeat)
+	registry.POST("/deregister", registryHandler.Deregister)
+
+	// 存活 / 就绪检查（公开端点，供编排系统探测）
+	e.GET("/livez", healthHandler.Livez)
+	e.GET("/readyz", healthHandler.Readyz)
+
+	// Prometheus 指标（公开端点）
+	e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+
+	// OpenAPI 文档与 Swagger UI（公开端点），须在注册完所有路由后生成
+	registerOpenAPI(e)
 
 	return e
 }
 
 // customHTTPErrorHandler 自定义全局错误处理器
+// /api/v2 下的错误以 {"error": {...}} 包装，其余直接返回 Response
 func customHTTPErrorHandler(err error, c echo.Context) {
 	// 如果响应已经写入，直接返回
 	if c.Response().Committed {
 		return
 	}
 
+	status, resp := errorResponse(err)
+	if strings.HasPrefix(c.Request().URL.Path, "/api/v2/") {
+		c.JSON(status, map[string]Response{"error": resp})
+		return
+	}
+	c.JSON(status, resp)
+}
+
+// errorResponse 将错误映射为 HTTP 状态码与响应
+func errorResponse(err error) (int, Response) {
 	// 处理 echo 的 HTTPError
 	var httpErr *echo.HTTPError
 	if errors.As(err, &httpErr) {
-		c.JSON(httpErr.Code, Response{
+		return httpErr.Code, Response{
 			Code:    "http_error",
 			Message: httpErr.Error(),
-		})
-		return
+		}
 	}
 
 	// 业务错误映射
 	switch {
 	// etcd 不可用
 	case errors.Is(err, apperrors.ErrEtcdUnavailable):
-		c.JSON(http.StatusServiceUnavailable, Response{
+		return http.StatusServiceUnavailable, Response{
 			Code:    "service_unavailable",
 			Message: "etcd service temporarily unavailable, please retry later",
-		})
+		}
 
 	// 用户相关错误
 	case errors.Is(err, apperrors.ErrUserNotFound):
-		c.JSON(http.StatusNotFound, Response{
+		return http.StatusNotFound, Response{
 			Code:    "user_not_found",
 			Message: err.Error(),
-		})
+		}
 	case errors.Is(err, apperrors.ErrUserExists):
-		c.JSON(http.StatusConflict, Response{
+		return http.StatusConflict, Response{
 			Code:    "user_exists",
 			Message: err.Error(),
-		})
+		}
 	case errors.Is(err, apperrors.ErrCannotDeleteDefaultAdmin):
-		c.JSON(http.StatusForbidden, Response{
+		return http.StatusForbidden, Response{
 			Code:    "cannot_delete_default_admin",
 			Message: err.Error(),
-		})
+		}
 	case errors.Is(err, apperrors.ErrInvalidCredentials):
-		c.JSON(http.StatusUnauthorized, Response{
+		return http.StatusUnauthorized, Response{
 			Code:    "invalid_credentials",
 			Message: err.Error(),
-		})
+		}
 	case errors.Is(err, apperrors.ErrWrongPassword):
-		c.JSON(http.StatusBadRequest, Response{
+		return http.StatusBadRequest, Response{
 			Code:    "wrong_password",
 			Message: err.Error(),
-		})
+		}
 
 	// DNS 记录相关错误
 	case errors.Is(err, apperrors.ErrRecordNotFound):
-		c.JSON(http.StatusNotFound, Response{
+		return http.StatusNotFound, Response{
 			Code:    "record_not_found",
 			Message: err.Error(),
-		})
+		}
 	case errors.Is(err, apperrors.ErrRecordExists):
-		c.JSON(http.StatusConflict, Response{
+		return http.StatusConflict, Response{
 			Code:    "record_exists",
 			Message: err.Error(),
-		})
+		}
 
 	// Zone 相关错误
 	case errors.Is(err, apperrors.ErrZoneNotFound):
-		c.JSON(http.StatusNotFound, Response{
+		return http.StatusNotFound, Response{
 			Code:    "zone_not_found",
 			Message: err.Error(),
-		})
+		}
 	case errors.Is(err, apperrors.ErrZoneExists):
-		c.JSON(http.StatusConflict, Response{
+		return http.StatusConflict, Response{
 			Code:    "zone_exists",
 			Message: err.Error(),
-		})
+		}
+	case errors.Is(err, apperrors.ErrZoneNotEmpty):
+		return http.StatusConflict, Response{
+			Code:    "zone_not_empty",
+			Message: err.Error(),
+		}
 
 	// Domain 相关错误
 	case errors.Is(err, apperrors.ErrDomainNotFound):
-		c.JSON(http.StatusNotFound, Response{
+		return http.StatusNotFound, Response{
 			Code:    "domain_not_found",
 			Message: err.Error(),
-		})
+		}
 	case errors.Is(err, apperrors.ErrDomainExists):
-		c.JSON(http.StatusConflict, Response{
+		return http.StatusConflict, Response{
 			Code:    "domain_exists",
 			Message: err.Error(),
-		})
+		}
+	case errors.Is(err, apperrors.ErrDomainNotEphemeral):
+		return http.StatusBadRequest, Response{
+			Code:    "domain_not_ephemeral",
+			Message: err.Error(),
+		}
+
+	// 服务注册相关错误
+	case errors.Is(err, apperrors.ErrTokenNotFound):
+		return http.StatusNotFound, Response{
+			Code:    "token_not_found",
+			Message: err.Error(),
+		}
+	case errors.Is(err, apperrors.ErrInstanceNotFound):
+		return http.StatusNotFound, Response{
+			Code:    "instance_not_found",
+			Message: err.Error(),
+		}
+
+	// 定时变更相关错误
+	case errors.Is(err, apperrors.ErrScheduleNotFound):
+		return http.StatusNotFound, Response{
+			Code:    "schedule_not_found",
+			Message: err.Error(),
+		}
+	case errors.Is(err, apperrors.ErrScheduleNotPending), errors.Is(err, apperrors.ErrScheduleConflict):
+		return http.StatusConflict, Response{
+			Code:    "schedule_not_pending",
+			Message: err.Error(),
+		}
+
+	// 变更审批相关错误
+	case errors.Is(err, apperrors.ErrApprovalRequired):
+		return http.StatusForbidden, Response{
+			Code:    "approval_required",
+			Message: err.Error(),
+		}
+	case errors.Is(err, apperrors.ErrChangeRequestNotFound):
+		return http.StatusNotFound, Response{
+			Code:    "change_request_not_found",
+			Message: err.Error(),
+		}
+	case errors.Is(err, apperrors.ErrChangeRequestNotPending):
+		return http.StatusConflict, Response{
+			Code:    "change_request_not_pending",
+			Message: err.Error(),
+		}
+	case errors.Is(err, apperrors.ErrSelfApproval):
+		return http.StatusForbidden, Response{
+			Code:    "self_approval",
+			Message: err.Error(),
+		}
+	case errors.Is(err, apperrors.ErrChangeConflict):
+		return http.StatusConflict, Response{
+			Code:    "change_conflict",
+			Message: err.Error(),
+		}
+
+	// 搜索相关错误
+	case errors.Is(err, apperrors.ErrSearchIndexNotReady):
+		return http.StatusServiceUnavailable, Response{
+			Code:    "search_index_not_ready",
+			Message: err.Error(),
+		}
+
+	// 事件流相关错误
+	case errors.Is(err, apperrors.ErrRevisionCompacted):
+		return http.StatusGone, Response{
+			Code:    "revision_compacted",
+			Message: err.Error(),
+		}
+
+	// Webhook 相关错误
+	case errors.Is(err, apperrors.ErrWebhookNotFound):
+		return http.StatusNotFound, Response{
+			Code:    "webhook_not_found",
+			Message: err.Error(),
+		}
+	case errors.Is(err, apperrors.ErrDeliveryNotFound):
+		return http.StatusNotFound, Response{
+			Code:    "webhook_delivery_not_found",
+			Message: err.Error(),
+		}
+	case errors.Is(err, apperrors.ErrDeliveryConflict):
+		return http.StatusConflict, Response{
+			Code:    "webhook_delivery_conflict",
+			Message: err.Error(),
+		}
+
+	// 版本历史相关错误
+	case errors.Is(err, apperrors.ErrVersionNotFound):
+		return http.StatusNotFound, Response{
+			Code:    "version_not_found",
+			Message: err.Error(),
+		}
+
+	// 回收站相关错误
+	case errors.Is(err, apperrors.ErrTrashEntryNotFound):
+		return http.StatusNotFound, Response{
+			Code:    "trash_entry_not_found",
+			Message: err.Error(),
+		}
+
+	// 备份恢复相关错误
+	case errors.Is(err, apperrors.ErrInvalidBackup):
+		return http.StatusBadRequest, Response{
+			Code:    "invalid_backup",
+			Message: err.Error(),
+		}
+	case errors.Is(err, apperrors.ErrBackupPassphraseRequired):
+		return http.StatusBadRequest, Response{
+			Code:    "backup_passphrase_required",
+			Message: err.Error(),
+		}
+	case errors.Is(err, apperrors.ErrBackupDecryptFailed):
+		return http.StatusBadRequest, Response{
+			Code:    "backup_decrypt_failed",
+			Message: err.Error(),
+		}
+
+	// DNS 校验相关错误
+	case errors.Is(err, apperrors.ErrVerificationNotConfigured):
+		return http.StatusServiceUnavailable, Response{
+			Code:    "verification_not_configured",
+			Message: err.Error(),
+		}
+	case errors.Is(err, apperrors.ErrVerificationNotFound):
+		return http.StatusNotFound, Response{
+			Code:    "verification_not_found",
+			Message: err.Error(),
+		}
+
+	// 发布目标相关错误
+	case errors.Is(err, apperrors.ErrUnknownPublisher):
+		return http.StatusBadRequest, Response{
+			Code:    "unknown_publisher",
+			Message: err.Error(),
+		}
+	case errors.Is(err, apperrors.ErrUnknownView):
+		return http.StatusBadRequest, Response{
+			Code:    "unknown_view",
+			Message: err.Error(),
+		}
+
+	// 租户相关错误
+	case errors.Is(err, apperrors.ErrTenantNotFound):
+		return http.StatusNotFound, Response{
+			Code:    "tenant_not_found",
+			Message: err.Error(),
+		}
+	case errors.Is(err, apperrors.ErrTenantExists):
+		return http.StatusConflict, Response{
+			Code:    "tenant_exists",
+			Message: err.Error(),
+		}
+	case errors.Is(err, apperrors.ErrTenantNotEmpty):
+		return http.StatusConflict, Response{
+			Code:    "tenant_not_empty",
+			Message: err.Error(),
+		}
 
 	// 认证授权错误
 	case errors.Is(err, apperrors.ErrInvalidToken):
-		c.JSON(http.StatusUnauthorized, Response{
+		return http.StatusUnauthorized, Response{
 			Code:    "invalid_token",
 			Message: err.Error(),
-		})
+		}
 	case errors.Is(err, apperrors.ErrTokenExpired):
-		c.JSON(http.StatusUnauthorized, Response{
+		return http.StatusUnauthorized, Response{
 			Code:    "token_expired",
 			Message: err.Error(),
-		})
+		}
 	case errors.Is(err, apperrors.ErrUnauthorized):
-		c.JSON(http.StatusUnauthorized, Response{
+		return http.StatusUnauthorized, Response{
 			Code:    "unauthorized",
 			Message: err.Error(),
-		})
+		}
 	case errors.Is(err, apperrors.ErrForbidden):
-		c.JSON(http.StatusForbidden, Response{
+		return http.StatusForbidden, Response{
 			Code:    "forbidden",
 			Message: err.Error(),
-		})
+		}
 	// 密码过长错误（需要在 ErrInvalidInput 之前检查）
 	case errors.Is(err, apperrors.ErrPasswordTooLong):
-		c.JSON(http.StatusBadRequest, Response{
+		return http.StatusBadRequest, Response{
 			Code:    "invalid_input",
 			Message: "password exceeds maximum length of 72 bytes",
-		})
+		}
 	case errors.Is(err, apperrors.ErrInvalidInput):
-		c.JSON(http.StatusBadRequest, Response{
+		return http.StatusBadRequest, Response{
 			Code:    "invalid_input",
 			Message: err.Error(),
-		})
+		}
 
 	// 未知错误
 	default:
 		logger.Log.WithError(err).Error("Unhandled error")
-		c.JSON(http.StatusInternalServerError, Response{
+		return http.StatusInternalServerError, Response{
 			Code:    "internal_error",
 			Message: "internal server error",
-		})
+		}
 	}
 }
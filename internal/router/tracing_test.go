@@ -0,0 +1,97 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"dancer/internal/handlers"
+	"dancer/internal/logger"
+	"dancer/internal/services"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/storage/etcd/etcdtest"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestTracingSpans 请求的 span 为根，服务层 span 为其子 span，etcd 操作的 span 为服务层 span 的子 span
+func TestTracingSpans(t *testing.T) {
+	logger.Log = logrus.New()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	client, cfg := etcdtest.Start(t)
+	tenantService := services.NewTenantService(etcd.NewTenantStorage(client, cfg), etcd.NewZoneStorage(client), nil)
+	tenantHandler := handlers.NewTenantHandler(tenantService)
+
+	e := echo.New()
+	e.Use(Tracing())
+	e.POST("/api/tenants/list", tenantHandler.ListTenants)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/tenants/list", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+
+	// 只检查请求所在链路的 span，etcd 客户端的后台操作不在其中
+	var request sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == "POST /api/tenants/list" {
+			request = span
+		}
+	}
+	if request == nil {
+		t.Fatalf("request span not recorded, got %v", spanNames(recorder.Ended()))
+	}
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID() == request.SpanContext().TraceID() {
+			spans[span.Name()] = span
+		}
+	}
+	if request.Parent().IsValid() {
+		t.Errorf("request span has parent %s, want none", request.Parent().SpanID())
+	}
+	if request.SpanKind() != trace.SpanKindServer {
+		t.Errorf("request span kind = %s, want server", request.SpanKind())
+	}
+
+	service, ok := spans["TenantService.ListTenants"]
+	if !ok {
+		t.Fatalf("service span not recorded, got %v", spanNames(recorder.Ended()))
+	}
+	if service.Parent().SpanID() != request.SpanContext().SpanID() {
+		t.Errorf("service span parent = %s, want request span %s", service.Parent().SpanID(), request.SpanContext().SpanID())
+	}
+
+	storage, ok := spans["etcd get"]
+	if !ok {
+		t.Fatalf("etcd span not recorded, got %v", spanNames(recorder.Ended()))
+	}
+	if storage.Parent().SpanID() != service.SpanContext().SpanID() {
+		t.Errorf("etcd span parent = %s, want service span %s", storage.Parent().SpanID(), service.SpanContext().SpanID())
+	}
+	for _, attr := range storage.Attributes() {
+		if attr.Key == "dancer.storage.method" && attr.Value.AsString() != "TenantStorage.ListTenants" {
+			t.Errorf("dancer.storage.method = %q, want TenantStorage.ListTenants", attr.Value.AsString())
+		}
+	}
+}
+
+// spanNames 返回 span 的名称，用于失败信息
+func spanNames(spans []sdktrace.ReadOnlySpan) []string {
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name()
+	}
+	return names
+}
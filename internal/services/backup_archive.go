@@ -0,0 +1,119 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	apperrors "dancer/internal/errors"
+	"dancer/internal/models"
+	"golang.org/x/crypto/scrypt"
+)
+
+// 备份文件格式：
+//   - 未加密：gzip 压缩的 JSON
+//   - 加密：encryptedBackupMagic + salt + nonce + AES-256-GCM 密文（明文为未加密格式），密钥由口令经 scrypt 派生
+const (
+	encryptedBackupMagic = "DANCER-BACKUP-ENC1\n"
+	backupSaltSize       = 16
+	backupKeySize        = 32
+
+	// scrypt 参数
+	backupScryptN = 1 << 15
+	backupScryptR = 8
+	backupScryptP = 1
+)
+
+// encodeBackup 将备份编码为备份文件，passphrase 不为空时加密
+func encodeBackup(backup *models.Backup, passphrase string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(backup); err != nil {
+		return nil, fmt.Errorf("failed to encode backup: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress backup: %w", err)
+	}
+	if passphrase == "" {
+		return buf.Bytes(), nil
+	}
+
+	salt := make([]byte, backupSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := backupCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(encryptedBackupMagic)+len(salt)+len(nonce)+buf.Len()+aead.Overhead())
+	out = append(out, encryptedBackupMagic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, buf.Bytes(), []byte(encryptedBackupMagic)), nil
+}
+
+// decodeBackup 解码备份文件，加密的备份需要提供口令
+func decodeBackup(data []byte, passphrase string) (*models.Backup, error) {
+	if rest, ok := bytes.CutPrefix(data, []byte(encryptedBackupMagic)); ok {
+		if passphrase == "" {
+			return nil, apperrors.ErrBackupPassphraseRequired
+		}
+		if len(rest) < backupSaltSize {
+			return nil, apperrors.ErrInvalidBackup
+		}
+		aead, err := backupCipher(passphrase, rest[:backupSaltSize])
+		if err != nil {
+			return nil, err
+		}
+		rest = rest[backupSaltSize:]
+		if len(rest) < aead.NonceSize() {
+			return nil, apperrors.ErrInvalidBackup
+		}
+		data, err = aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], []byte(encryptedBackupMagic))
+		if err != nil {
+			return nil, apperrors.ErrBackupDecryptFailed
+		}
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperrors.ErrInvalidBackup
+	}
+	defer zr.Close()
+
+	var backup models.Backup
+	if err := json.NewDecoder(zr).Decode(&backup); err != nil {
+		return nil, apperrors.ErrInvalidBackup
+	}
+	if _, err := io.Copy(io.Discard, zr); err != nil {
+		return nil, apperrors.ErrInvalidBackup
+	}
+	if backup.Version < 1 || backup.Version > models.BackupVersion {
+		return nil, apperrors.ErrInvalidBackup
+	}
+	return &backup, nil
+}
+
+// backupCipher 由口令派生备份加密使用的 AES-256-GCM
+func backupCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, backupScryptN, backupScryptR, backupScryptP, backupKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"dancer/internal/models"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tenant"
+	"dancer/internal/tracing"
+	"github.com/go-playground/validator/v10"
+)
+
+// BackupService 全量备份与恢复
+type BackupService struct {
+	backupStorage *etcd.BackupStorage
+	userStorage   *etcd.UserStorage
+	zoneStorage   *etcd.ZoneStorage
+	domainStorage *etcd.DomainStorage
+	validate      *validator.Validate
+}
+
+func NewBackupService(backupStorage *etcd.BackupStorage, userStorage *etcd.UserStorage, zoneStorage *etcd.ZoneStorage, domainStorage *etcd.DomainStorage) *BackupService {
+	return &BackupService{
+		backupStorage: backupStorage,
+		userStorage:   userStorage,
+		zoneStorage:   zoneStorage,
+		domainStorage: domainStorage,
+		validate:      validator.New(),
+	}
+}
+
+// Export 导出备份文件
+func (s *BackupService) Export(ctx context.Context, req *models.ExportBackupRequest) ([]byte, error) {
+	ctx, span := tracing.Start(ctx, "BackupService.Export")
+	defer span.End()
+
+	backup, err := s.backupStorage.Export(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ExcludePasswordHashes {
+		backup.PasswordsIncluded = false
+		for _, user := range backup.Users {
+			user.Password = ""
+		}
+	}
+
+	return encodeBackup(backup, req.Passphrase)
+}
+
+// Restore 校验并恢复备份文件，备份恢复到 ctx 所属租户
+// 备份未通过校验时返回的结果中 Problems 不为空，不做任何修改；dry_run 时只返回将要进行的修改
+func (s *BackupService) Restore(ctx context.Context, caller *models.CurrentUser, data []byte, req *models.RestoreBackupRequest) (*models.RestoreResult, error) {
+	ctx, span := tracing.Start(ctx, "BackupService.Restore")
+	defer span.End()
+
+	backup, err := decodeBackup(data, req.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.RestoreResult{
+		Mode:     req.Mode,
+		DryRun:   req.DryRun,
+		Problems: s.validateBackup(backup),
+		Skipped:  []*models.RestoreSkip{},
+	}
+	if len(result.Problems) > 0 {
+		return result, nil
+	}
+
+	plan, err := s.planRestore(ctx, caller, backup, result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Problems) > 0 || req.DryRun {
+		return result, nil
+	}
+
+	if err := s.backupStorage.Restore(ctx, plan); err != nil {
+		return nil, err
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// validateBackup 校验备份内容本身，返回发现的问题
+func (s *BackupService) validateBackup(backup *models.Backup) []string {
+	problems := []string{}
+
+	userIDs := make(map[string]bool, len(backup.Users))
+	usernames := make(map[string]bool, len(backup.Users))
+	for i, user := range backup.Users {
+		switch {
+		case user == nil || user.ID == "" || user.Username == "":
+			problems = append(problems, fmt.Sprintf("users[%d]: id and username are required", i))
+			continue
+		case userIDs[user.ID]:
+			problems = append(problems, fmt.Sprintf("user %s: duplicate id %s", user.Username, user.ID))
+		case usernames[user.Username]:
+			problems = append(problems, fmt.Sprintf("user %s: duplicate username", user.Username))
+		case !user.UserType.IsAdmin() && user.UserType != models.UserTypeNormal:
+			problems = append(problems, fmt.Sprintf("user %s: invalid user type %q", user.Username, user.UserType))
+		case backup.PasswordsIncluded && user.Password == "":
+			problems = append(problems, fmt.Sprintf("user %s: missing password hash", user.Username))
+		}
+		userIDs[user.ID], usernames[user.Username] = true, true
+	}
+
+	zones := make(map[string]bool, len(backup.Zones))
+	for i, zone := range backup.Zones {
+		switch {
+		case zone == nil || s.validate.Var(zone.Zone, "required,fqdn") != nil:
+			problems = append(problems, fmt.Sprintf("zones[%d]: invalid zone name", i))
+			continue
+		case zones[zone.Zone]:
+			problems = append(problems, fmt.Sprintf("zone %s: duplicate zone", zone.Zone))
+		}
+		zones[zone.Zone] = true
+	}
+
+	domains := make(map[string]bool, len(backup.Domains))
+	for i, domain := range backup.Domains {
+		if domain == nil || domain.Domain == "" {
+			problems = append(problems, fmt.Sprintf("domains[%d]: domain is required", i))
+			continue
+		}
+		name := domain.Domain + "." + domain.Zone
+		switch {
+		case !zones[domain.Zone]:
+			problems = append(problems, fmt.Sprintf("domain %s: zone %s is not in backup", name, domain.Zone))
+		case domains[name]:
+			problems = append(problems, fmt.Sprintf("domain %s: duplicate domain", name))
+		case s.validate.Var(domain.IPs, "required,dive,ip") != nil:
+			problems = append(problems, fmt.Sprintf("domain %s: invalid ips", name))
+		case s.validate.Var(domain.ViewIPs, "omitempty,dive,keys,required,endkeys,min=1,dive,ip") != nil:
+			problems = append(problems, fmt.Sprintf("domain %s: invalid view_ips", name))
+		case domain.TTL < 1:
+			problems = append(problems, fmt.Sprintf("domain %s: invalid ttl", name))
+		case domain.IsEphemeral():
+			problems = append(problems, fmt.Sprintf("domain %s: ephemeral domains cannot be restored", name))
+		}
+		domains[name] = true
+	}
+
+	return problems
+}
+
+// planRestore 对照当前数据生成恢复计划，统计、跳过的数据与冲突记录在 result 中
+// 超级管理员只属于默认租户，且只能由超级管理员恢复或删除；Zone 名称已被其他租户使用时不能恢复
+func (s *BackupService) planRestore(ctx context.Context, caller *models.CurrentUser, backup *models.Backup, result *models.RestoreResult) (*models.RestorePlan, error) {
+	replace := result.Mode == models.RestoreModeReplace
+	plan := &models.RestorePlan{}
+
+	// 用户
+	users, err := s.userStorage.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existingUsers := make(map[string]*models.User, len(users))
+	usersByName := make(map[string]*models.User, len(users))
+	for _, user := range users {
+		existingUsers[user.ID] = user
+		usersByName[user.Username] = user
+	}
+
+	restoredUsers := make(map[string]*models.User, len(backup.Users))
+	for _, user := range backup.Users {
+		existing := existingUsers[user.ID]
+		if user.UserType == models.UserTypeSuperAdmin || (existing != nil && existing.UserType == models.UserTypeSuperAdmin) {
+			if tenant.FromContext(ctx) != tenant.Default || caller.UserType != models.UserTypeSuperAdmin {
+				result.Problems = append(result.Problems, fmt.Sprintf("user %s: super_admin users can only be restored by a super_admin in the default tenant", user.Username))
+				continue
+			}
+		}
+		if !backup.PasswordsIncluded {
+			// 不含密码哈希的备份只能更新已存在的用户，沿用当前密码
+			if existing == nil {
+				result.Skipped = append(result.Skipped, &models.RestoreSkip{Type: "user", Name: user.Username, Reason: "no password hash in backup"})
+				continue
+			}
+			user.Password = existing.Password
+		}
+		if other := usersByName[user.Username]; other != nil && other.ID != user.ID && !replace {
+			result.Problems = append(result.Problems, fmt.Sprintf("user %s: username is used by existing user %s", user.Username, other.ID))
+			continue
+		}
+		restoredUsers[user.ID] = user
+
+		switch {
+		case existing == nil:
+			result.Users.Created++
+		case reflect.DeepEqual(existing, user):
+			result.Users.Unchanged++
+			continue
+		default:
+			result.Users.Updated++
+		}
+		plan.PutUsers = append(plan.PutUsers, user)
+	}
+	if replace {
+		for _, user := range users {
+			if restoredUsers[user.ID] == nil {
+				if user.UserType == models.UserTypeSuperAdmin && caller.UserType != models.UserTypeSuperAdmin {
+					result.Problems = append(result.Problems, fmt.Sprintf("user %s: super_admin users can only be deleted by a super_admin", user.Username))
+					continue
+				}
+				plan.DeleteUsers = append(plan.DeleteUsers, user)
+				result.Users.Deleted++
+			}
+		}
+	}
+
+	// 恢复后必须仍有管理员可以登录
+	remaining := restoredUsers
+	if !replace {
+		remaining = make(map[string]*models.User, len(users)+len(restoredUsers))
+		for _, user := range users {
+			remaining[user.ID] = user
+		}
+		for id, user := range restoredUsers {
+			remaining[id] = user
+		}
+	}
+	hasAdmin := false
+	for _, user := range remaining {
+		if user.UserType.IsAdmin() && user.Password != "" {
+			hasAdmin = true
+			break
+		}
+	}
+	if !hasAdmin {
+		result.Problems = append(result.Problems, "restore would leave no admin user")
+	}
+
+	// Zone 与 Domain
+	zones, err := s.zoneStorage.ListZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existingZones := make(map[string]*models.Zone, len(zones))
+	for _, zone := range zones {
+		existingZones[zone.Zone] = zone
+	}
+
+	domainsByZone := make(map[string][]*models.Domain, len(backup.Zones))
+	for _, domain := range backup.Domains {
+		domainsByZone[domain.Zone] = append(domainsByZone[domain.Zone], domain)
+	}
+
+	restoredZones := make(map[string]bool, len(backup.Zones))
+	for _, zone := range backup.Zones {
+		restoredZones[zone.Zone] = true
+		existing := existingZones[zone.Zone]
+		if existing == nil {
+			owner, err := s.zoneStorage.GetZoneOwner(ctx, zone.Zone)
+			if err != nil {
+				return nil, err
+			}
+			if owner != "" && owner != tenant.FromContext(ctx) {
+				result.Problems = append(result.Problems, fmt.Sprintf("zone %s: zone is owned by another tenant", zone.Zone))
+				continue
+			}
+		}
+
+		var current []*models.Domain
+		if existing != nil {
+			if current, err = s.domainStorage.ListDomainsByZone(ctx, zone.Zone); err != nil {
+				return nil, err
+			}
+		}
+		count := s.planZoneDomains(zone.Zone, current, domainsByZone[zone.Zone], replace, plan, result)
+
+		restored := *zone
+		restored.RecordCount = count
+		switch {
+		case existing == nil:
+			result.Zones.Created++
+		case reflect.DeepEqual(existing, &restored):
+			result.Zones.Unchanged++
+			continue
+		default:
+			result.Zones.Updated++
+		}
+		plan.PutZones = append(plan.PutZones, &restored)
+	}
+	if replace {
+		for _, zone := range zones {
+			if !restoredZones[zone.Zone] {
+				plan.DeleteZones = append(plan.DeleteZones, zone)
+				result.Zones.Deleted++
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// planZoneDomains 生成单个 Zone 下 Domain 的恢复计划，返回恢复后该 Zone 的 Domain 数量
+// 已存在的临时 Domain 保持不变，备份中的同名 Domain 被跳过
+func (s *BackupService) planZoneDomains(zone string, current, restored []*models.Domain, replace bool, plan *models.RestorePlan, result *models.RestoreResult) int {
+	existing := make(map[string]*models.Domain, len(current))
+	for _, domain := range current {
+		existing[domain.Domain] = domain
+	}
+
+	count := len(current)
+	kept := make(map[string]bool, len(restored))
+	for _, domain := range restored {
+		domain.Name = domain.Domain + "." + zone
+		domain.RecordCount = len(domain.IPs)
+
+		old := existing[domain.Domain]
+		if old != nil && old.IsEphemeral() {
+			result.Skipped = append(result.Skipped, &models.RestoreSkip{Type: "domain", Name: domain.Name, Reason: "ephemeral domain exists"})
+			continue
+		}
+		kept[domain.Domain] = true
+
+		switch {
+		case old == nil:
+			result.Domains.Created++
+			count++
+		case sameDomain(old, domain):
+			result.Domains.Unchanged++
+			plan.SyncDomains = append(plan.SyncDomains, domain)
+			continue
+		default:
+			result.Domains.Updated++
+		}
+		plan.PutDomains = append(plan.PutDomains, domain)
+	}
+
+	if replace {
+		for _, domain := range current {
+			if !domain.IsEphemeral() && !kept[domain.Domain] {
+				plan.DeleteDomains = append(plan.DeleteDomains, domain)
+				result.Domains.Deleted++
+				count--
+			}
+		}
+	}
+	return count
+}
+
+// sameDomain 比较两个 Domain 的持久化字段是否一致（忽略读取时填充的动态实例 IP）
+func sameDomain(a, b *models.Domain) bool {
+	x, y := *a, *b
+	x.DynamicIPs, y.DynamicIPs = nil, nil
+	return reflect.DeepEqual(&x, &y)
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tracing"
+)
+
+// ChangeRequestService 变更申请业务逻辑
+// Domain 变更先以申请形式提交，由其他管理员审批后在一个 etcd 事务中原子应用
+type ChangeRequestService struct {
+	changeStorage *etcd.ChangeRequestStorage
+	zoneStorage   *etcd.ZoneStorage
+	domainStorage *etcd.DomainStorage
+}
+
+func NewChangeRequestService(changeStorage *etcd.ChangeRequestStorage, zoneStorage *etcd.ZoneStorage, domainStorage *etcd.DomainStorage) *ChangeRequestService {
+	return &ChangeRequestService{
+		changeStorage: changeStorage,
+		zoneStorage:   zoneStorage,
+		domainStorage: domainStorage,
+	}
+}
+
+// ListChangeRequests 列出变更申请，按创建时间倒序
+func (s *ChangeRequestService) ListChangeRequests(ctx context.Context, req *models.ListChangeRequestsRequest) ([]*models.ChangeRequest, error) {
+	ctx, span := tracing.Start(ctx, "ChangeRequestService.ListChangeRequests")
+	defer span.End()
+
+	changes, err := s.changeStorage.ListChangeRequests(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.ChangeRequest, 0, len(changes))
+	for _, cr := range changes {
+		if req.Zone != "" && cr.Zone != req.Zone {
+			continue
+		}
+		if req.Status != "" && cr.Status != req.Status {
+			continue
+		}
+		result = append(result, cr)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt > result[j].CreatedAt
+	})
+	return result, nil
+}
+
+// GetChangeRequest 获取变更申请
+func (s *ChangeRequestService) GetChangeRequest(ctx context.Context, id string) (*models.ChangeRequest, error) {
+	ctx, span := tracing.Start(ctx, "ChangeRequestService.GetChangeRequest")
+	defer span.End()
+
+	return s.changeStorage.GetChangeRequest(ctx, id)
+}
+
+// DiffChangeRequest 计算变更申请与 Zone 当前状态的差异
+func (s *ChangeRequestService) DiffChangeRequest(ctx context.Context, cr *models.ChangeRequest) ([]*models.DomainChangeDiff, error) {
+	ctx, span := tracing.Start(ctx, "ChangeRequestService.DiffChangeRequest")
+	defer span.End()
+
+	domains, err := s.domainStorage.ListDomainsByZone(ctx, cr.Zone)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]*models.Domain, len(domains))
+	for _, d := range domains {
+		current[d.Domain] = d
+	}
+
+	diffs := make([]*models.DomainChangeDiff, 0, len(cr.Changes))
+	for _, change := range cr.Changes {
+		diff := &models.DomainChangeDiff{Op: change.Op, Domain: change.Domain}
+
+		var beforeIPs, afterIPs []string
+		if d, ok := current[change.Domain]; ok {
+			diff.Before = &models.RecordSet{IPs: d.IPs, TTL: d.TTL}
+			beforeIPs = d.IPs
+		}
+
+		switch change.Op {
+		case models.ChangeOpCreate:
+			if diff.Before != nil {
+				diff.Conflict = "domain already exists"
+			}
+			diff.After = &models.RecordSet{IPs: change.IPs, TTL: change.TTL}
+		case models.ChangeOpUpdate:
+			if diff.Before == nil {
+				diff.Conflict = "domain does not exist"
+				diff.After = &models.RecordSet{IPs: change.IPs, TTL: change.TTL}
+				break
+			}
+			ttl := change.TTL
+			if ttl == 0 {
+				ttl = diff.Before.TTL
+			}
+			diff.After = &models.RecordSet{IPs: change.IPs, TTL: ttl}
+		case models.ChangeOpDelete:
+			if diff.Before == nil {
+				diff.Conflict = "domain does not exist"
+			}
+		}
+		if diff.After != nil {
+			afterIPs = diff.After.IPs
+		}
+
+		diff.AddedIPs = subtractIPs(afterIPs, beforeIPs)
+		diff.RemovedIPs = subtractIPs(beforeIPs, afterIPs)
+		diffs = append(diffs, diff)
+	}
+
+	return diffs, nil
+}
+
+// ProposeChangeRequest 提交变更申请
+func (s *ChangeRequestService) ProposeChangeRequest(ctx context.Context, userID string, req *models.ProposeChangeRequest) (*models.ChangeRequest, error) {
+	ctx, span := tracing.Start(ctx, "ChangeRequestService.ProposeChangeRequest")
+	defer span.End()
+
+	// 检查 Zone 是否存在
+	if _, err := s.zoneStorage.GetZone(ctx, req.Zone); err != nil {
+		return nil, err
+	}
+
+	// 同一申请中每个 Domain 只能出现一次，创建操作必须指定 TTL
+	seen := make(map[string]bool, len(req.Changes))
+	for _, change := range req.Changes {
+		if seen[change.Domain] {
+			return nil, apperrors.ErrInvalidInput
+		}
+		seen[change.Domain] = true
+		if change.Op == models.ChangeOpCreate && change.TTL == 0 {
+			return nil, apperrors.ErrInvalidInput
+		}
+		if change.Op == models.ChangeOpDelete {
+			change.IPs = nil
+			change.TTL = 0
+		}
+	}
+
+	now := time.Now().Unix()
+	cr := &models.ChangeRequest{
+		ID:         fmt.Sprintf("%d", time.Now().UnixMilli()),
+		Zone:       req.Zone,
+		Title:      req.Title,
+		Changes:    req.Changes,
+		Status:     models.ChangeRequestPending,
+		ProposedBy: userID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := s.changeStorage.CreateChangeRequest(ctx, cr); err != nil {
+		return nil, err
+	}
+
+	return cr, nil
+}
+
+// ApproveChangeRequest 批准并应用变更申请
+// 申请人不能审批自己的申请；应用失败时申请标记为 failed 并返回应用错误
+func (s *ChangeRequestService) ApproveChangeRequest(ctx context.Context, reviewerID string, req *models.ReviewChangeRequest) (*models.ChangeRequest, error) {
+	ctx, span := tracing.Start(ctx, "ChangeRequestService.ApproveChangeRequest")
+	defer span.End()
+
+	cr, err := s.claim(ctx, reviewerID, req, models.ChangeRequestApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	applyErr := s.apply(ctx, cr)
+
+	now := time.Now().Unix()
+	if applyErr != nil {
+		cr.Status = models.ChangeRequestFailed
+		cr.Error = applyErr.Error()
+	} else {
+		cr.Status = models.ChangeRequestApplied
+		cr.AppliedAt = now
+	}
+	cr.UpdatedAt = now
+	if err := s.changeStorage.UpdateChangeRequest(ctx, cr); err != nil {
+		logger.Log.WithError(err).WithField("change_request", cr.ID).Error("Failed to record change request result")
+	}
+
+	if applyErr != nil {
+		return nil, applyErr
+	}
+	return cr, nil
+}
+
+// RejectChangeRequest 驳回变更申请
+func (s *ChangeRequestService) RejectChangeRequest(ctx context.Context, reviewerID string, req *models.ReviewChangeRequest) (*models.ChangeRequest, error) {
+	ctx, span := tracing.Start(ctx, "ChangeRequestService.RejectChangeRequest")
+	defer span.End()
+
+	return s.claim(ctx, reviewerID, req, models.ChangeRequestRejected)
+}
+
+// claim 将待审批的申请切换到审批后的状态，并发审批时只有一个能成功
+func (s *ChangeRequestService) claim(ctx context.Context, reviewerID string, req *models.ReviewChangeRequest, status models.ChangeRequestStatus) (*models.ChangeRequest, error) {
+	cr, err := s.changeStorage.GetChangeRequest(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if cr.Status != models.ChangeRequestPending {
+		return nil, apperrors.ErrChangeRequestNotPending
+	}
+	if cr.ProposedBy == reviewerID {
+		return nil, apperrors.ErrSelfApproval
+	}
+
+	now := time.Now().Unix()
+	cr.Status = status
+	cr.ReviewedBy = reviewerID
+	cr.ReviewComment = req.Comment
+	cr.ReviewedAt = now
+	cr.UpdatedAt = now
+	if err := s.changeStorage.UpdateChangeRequest(ctx, cr); err != nil {
+		return nil, err
+	}
+
+	return cr, nil
+}
+
+// apply 应用变更并刷新 Zone 记录数
+func (s *ChangeRequestService) apply(ctx context.Context, cr *models.ChangeRequest) error {
+	if _, err := s.zoneStorage.GetZone(ctx, cr.Zone); err != nil {
+		return err
+	}
+
+	if _, err := s.domainStorage.ApplyChanges(ctx, cr.Zone, cr.Changes); err != nil {
+		if errors.Is(err, apperrors.ErrDomainExists) || errors.Is(err, apperrors.ErrDomainNotFound) {
+			return fmt.Errorf("%w: %v", apperrors.ErrChangeConflict, err)
+		}
+		return err
+	}
+
+	// 更新 Zone 记录数
+	count, _ := s.domainStorage.GetDomainCountByZone(ctx, cr.Zone)
+	s.zoneStorage.UpdateZoneRecordCount(ctx, cr.Zone, count)
+	return nil
+}
+
+// subtractIPs 返回在 a 中但不在 b 中的 IP
+func subtractIPs(a, b []string) []string {
+	exclude := make(map[string]bool, len(b))
+	for _, ip := range b {
+		exclude[ip] = true
+	}
+
+	result := make([]string, 0)
+	for _, ip := range a {
+		if !exclude[ip] {
+			result = append(result, ip)
+		}
+	}
+	return result
+}
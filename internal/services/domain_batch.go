@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/tracing"
+)
+
+// errDuplicateBatchDomain 同一批量请求中 Domain 重复出现
+var errDuplicateBatchDomain = errors.New("domain appears more than once in batch")
+
+// BatchDomains 批量创建 / 更新 / 删除同一 Zone 下的 Domain
+// invalid[i] 为第 i 项的格式校验错误（由调用方完成），非 nil 的项直接标记为失败
+// 所有项先对照 Zone 当前状态校验，再按模式应用：
+//   - atomic: 任一项失败则全部不应用，其余项标记为 skipped
+//   - best_effort: 逐项应用，失败项不影响其他项
+//
+// Zone 记录数在整批处理完成后更新一次
+func (s *DomainService) BatchDomains(ctx context.Context, req *models.BatchDomainsRequest, invalid []error) ([]*models.BatchItemResult, error) {
+	ctx, span := tracing.Start(ctx, "DomainService.BatchDomains")
+	defer span.End()
+
+	zone, err := s.zoneStorage.GetZone(ctx, req.Zone)
+	if err != nil {
+		return nil, apperrors.ErrZoneNotFound
+	}
+	if zone.ApprovalRequired {
+		return nil, apperrors.ErrApprovalRequired
+	}
+
+	domains, err := s.domainStorage.ListDomainsByZone(ctx, req.Zone)
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[string]bool, len(domains))
+	for _, d := range domains {
+		exists[d.Domain] = true
+	}
+
+	// 逐项校验
+	results := make([]*models.BatchItemResult, len(req.Items))
+	seen := make(map[string]bool, len(req.Items))
+	valid := make([]int, 0, len(req.Items))
+	for i, item := range req.Items {
+		result := &models.BatchItemResult{Index: i}
+		results[i] = result
+		if item == nil {
+			result.Status, result.Error = models.BatchItemFailed, apperrors.ErrInvalidInput.Error()
+			continue
+		}
+		result.Op, result.Domain = item.Op, item.Domain
+
+		if err := validateBatchItem(item, invalid[i], exists, seen); err != nil {
+			result.Status, result.Error = models.BatchItemFailed, err.Error()
+			continue
+		}
+		valid = append(valid, i)
+	}
+
+	delta := 0
+	if req.Mode == models.BatchModeBestEffort {
+		for _, i := range valid {
+			item := req.Items[i]
+			domain, err := s.domainStorage.ApplyChange(ctx, req.Zone, item)
+			if err != nil {
+				if errors.Is(err, apperrors.ErrEtcdUnavailable) {
+					return nil, err
+				}
+				results[i].Status, results[i].Error = models.BatchItemFailed, err.Error()
+				continue
+			}
+			results[i].Status, results[i].Result = models.BatchItemSucceeded, domain
+			delta += recordCountDelta(item.Op)
+		}
+	} else {
+		if err := s.applyAtomic(ctx, req, valid, results); err != nil {
+			return nil, err
+		}
+		for _, i := range valid {
+			if results[i].Status == models.BatchItemSucceeded {
+				delta += recordCountDelta(req.Items[i].Op)
+			}
+		}
+	}
+
+	// 更新 Zone 记录数
+	if delta != 0 {
+		if err := s.zoneStorage.IncrementZoneRecordCount(ctx, req.Zone, delta); err != nil {
+			logger.Log.WithError(err).WithField("zone", req.Zone).Warn("Failed to update zone record count after batch")
+		}
+	}
+
+	return results, nil
+}
+
+// applyAtomic 以全部成功或全部不生效的方式应用校验通过的项
+func (s *DomainService) applyAtomic(ctx context.Context, req *models.BatchDomainsRequest, valid []int, results []*models.BatchItemResult) error {
+	// 存在校验失败的项时不应用任何变更
+	if len(valid) < len(req.Items) {
+		for _, i := range valid {
+			results[i].Status = models.BatchItemSkipped
+		}
+		return nil
+	}
+
+	applied, err := s.domainStorage.ApplyChanges(ctx, req.Zone, req.Items)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrEtcdUnavailable) {
+			return err
+		}
+		for _, r := range results {
+			r.Status, r.Error = models.BatchItemFailed, err.Error()
+		}
+		return nil
+	}
+
+	byName := make(map[string]*models.Domain, len(applied))
+	for _, d := range applied {
+		byName[d.Domain] = d
+	}
+	for _, r := range results {
+		r.Status, r.Result = models.BatchItemSucceeded, byName[r.Domain]
+	}
+	return nil
+}
+
+// validateBatchItem 对照 Zone 当前状态校验单项变更
+func validateBatchItem(item *models.DomainChange, invalid error, exists, seen map[string]bool) error {
+	if invalid != nil {
+		return apperrors.ErrInvalidInput
+	}
+	if seen[item.Domain] {
+		return errDuplicateBatchDomain
+	}
+	seen[item.Domain] = true
+
+	switch item.Op {
+	case models.ChangeOpCreate:
+		if item.TTL == 0 {
+			return apperrors.ErrInvalidInput
+		}
+		if exists[item.Domain] {
+			return apperrors.ErrDomainExists
+		}
+	case models.ChangeOpUpdate, models.ChangeOpDelete:
+		if !exists[item.Domain] {
+			return apperrors.ErrDomainNotFound
+		}
+	}
+	return nil
+}
+
+// recordCountDelta 单项变更对 Zone 记录数的影响
+func recordCountDelta(op models.ChangeOp) int {
+	switch op {
+	case models.ChangeOpCreate:
+		return 1
+	case models.ChangeOpDelete:
+		return -1
+	}
+	return 0
+}
@@ -2,11 +2,13 @@ package services
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"dancer/internal/errors"
 	"dancer/internal/models"
 	"dancer/internal/storage/etcd"
+	"dancer/internal/tracing"
 )
 
 // DomainService Domain 业务逻辑
@@ -22,19 +24,57 @@ func NewDomainService(zoneStorage *etcd.ZoneStorage, domainStorage *etcd.DomainS
 	}
 }
 
-// ListDomains 列出 Zone 下所有 Domain
-func (s *DomainService) ListDomains(ctx context.Context, req *models.ListDomainsRequest) ([]*models.Domain, error) {
+// ListDomains 分页列出 Zone 下的 Domain，支持过滤和排序
+func (s *DomainService) ListDomains(ctx context.Context, req *models.ListDomainsRequest) (*models.Page[*models.Domain], error) {
+	ctx, span := tracing.Start(ctx, "DomainService.ListDomains")
+	defer span.End()
+
 	// 检查 Zone 是否存在
 	_, err := s.zoneStorage.GetZone(ctx, req.Zone)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.domainStorage.ListDomainsByZone(ctx, req.Zone)
+	var f filters[*models.Domain]
+	if req.NamePrefix != "" {
+		f.add(func(d *models.Domain) bool { return strings.HasPrefix(d.Domain, req.NamePrefix) })
+	}
+	if req.NameContains != "" {
+		f.add(func(d *models.Domain) bool { return containsFold(d.Domain, req.NameContains) })
+	}
+	if req.IP != "" {
+		matchIP := ipMatcher(req.IP)
+		f.add(func(d *models.Domain) bool { return matchIP(d.IPs) || matchIP(d.DynamicIPs) })
+	}
+	if req.TTLMin > 0 {
+		f.add(func(d *models.Domain) bool { return d.TTL >= req.TTLMin })
+	}
+	if req.TTLMax > 0 {
+		f.add(func(d *models.Domain) bool { return d.TTL <= req.TTLMax })
+	}
+	if req.UpdatedSince > 0 {
+		f.add(func(d *models.Domain) bool { return d.UpdatedAt >= req.UpdatedSince })
+	}
+
+	// 默认按子域名排序，即 etcd key 顺序
+	var less func(a, b *models.Domain) bool
+	switch req.Sort {
+	case "ttl":
+		less = lessBy(func(d *models.Domain) int { return d.TTL })
+	case "created_at":
+		less = lessBy(func(d *models.Domain) int64 { return d.CreatedAt })
+	case "updated_at":
+		less = lessBy(func(d *models.Domain) int64 { return d.UpdatedAt })
+	}
+
+	return s.domainStorage.ListDomainsPage(ctx, req.Zone, listQuery(req.PageRequest, req.NamePrefix, f, less))
 }
 
 // GetDomain 获取 Domain 详情
 func (s *DomainService) GetDomain(ctx context.Context, req *models.GetDomainRequest) (*models.Domain, error) {
+	ctx, span := tracing.Start(ctx, "DomainService.GetDomain")
+	defer span.End()
+
 	// 检查 Zone 是否存在
 	_, err := s.zoneStorage.GetZone(ctx, req.Zone)
 	if err != nil {
@@ -46,11 +86,17 @@ func (s *DomainService) GetDomain(ctx context.Context, req *models.GetDomainRequ
 
 // CreateDomain 创建 Domain
 func (s *DomainService) CreateDomain(ctx context.Context, req *models.CreateDomainRequest) (*models.Domain, error) {
+	ctx, span := tracing.Start(ctx, "DomainService.CreateDomain")
+	defer span.End()
+
 	// 检查 Zone 是否存在
-	_, err := s.zoneStorage.GetZone(ctx, req.Zone)
+	zone, err := s.zoneStorage.GetZone(ctx, req.Zone)
 	if err != nil {
 		return nil, errors.ErrZoneNotFound
 	}
+	if zone.ApprovalRequired {
+		return nil, errors.ErrApprovalRequired
+	}
 
 	// 检查是否已存在
 	exists, err := s.domainStorage.DomainExists(ctx, req.Zone, req.Domain)
@@ -61,11 +107,17 @@ func (s *DomainService) CreateDomain(ctx context.Context, req *models.CreateDoma
 		return nil, errors.ErrDomainExists
 	}
 
+	if err := s.checkViewIPs(req.ViewIPs); err != nil {
+		return nil, err
+	}
+
 	domain := &models.Domain{
-		Zone:   req.Zone,
-		Domain: req.Domain,
-		IPs:    req.IPs,
-		TTL:    req.TTL,
+		Zone:     req.Zone,
+		Domain:   req.Domain,
+		IPs:      req.IPs,
+		ViewIPs:  nonEmptyViewIPs(req.ViewIPs),
+		TTL:      req.TTL,
+		LeaseTTL: req.LeaseTTL,
 	}
 
 	if err := s.domainStorage.CreateDomain(ctx, domain); err != nil {
@@ -81,11 +133,17 @@ func (s *DomainService) CreateDomain(ctx context.Context, req *models.CreateDoma
 
 // UpdateDomain 更新 Domain
 func (s *DomainService) UpdateDomain(ctx context.Context, req *models.UpdateDomainRequest) (*models.Domain, error) {
+	ctx, span := tracing.Start(ctx, "DomainService.UpdateDomain")
+	defer span.End()
+
 	// 检查 Zone 是否存在
-	_, err := s.zoneStorage.GetZone(ctx, req.Zone)
+	zone, err := s.zoneStorage.GetZone(ctx, req.Zone)
 	if err != nil {
 		return nil, errors.ErrZoneNotFound
 	}
+	if zone.ApprovalRequired {
+		return nil, errors.ErrApprovalRequired
+	}
 
 	// 获取现有记录
 	existing, err := s.domainStorage.GetDomain(ctx, req.Zone, req.Domain)
@@ -93,8 +151,14 @@ func (s *DomainService) UpdateDomain(ctx context.Context, req *models.UpdateDoma
 		return nil, err
 	}
 
-	// 更新字段
+	// 更新字段，未指定 view_ips 时保持不变
 	existing.IPs = req.IPs
+	if req.ViewIPs != nil {
+		if err := s.checkViewIPs(req.ViewIPs); err != nil {
+			return nil, err
+		}
+		existing.ViewIPs = nonEmptyViewIPs(req.ViewIPs)
+	}
 	if req.TTL > 0 {
 		existing.TTL = req.TTL
 	}
@@ -107,22 +171,41 @@ func (s *DomainService) UpdateDomain(ctx context.Context, req *models.UpdateDoma
 	return existing, nil
 }
 
+// checkViewIPs 校验按视图覆盖的 IP 只引用已配置的非默认 CoreDNS 视图，默认视图的 IP 由 ips 指定
+func (s *DomainService) checkViewIPs(viewIPs map[string][]string) error {
+	for name := range viewIPs {
+		if name == models.DefaultPublisher || s.domainStorage.View(name) == nil {
+			return errors.ErrUnknownView
+		}
+	}
+	return nil
+}
+
+// nonEmptyViewIPs 空的视图覆盖按未设置保存
+func nonEmptyViewIPs(viewIPs map[string][]string) map[string][]string {
+	if len(viewIPs) == 0 {
+		return nil
+	}
+	return viewIPs
+}
+
 // DeleteDomain 删除 Domain
 func (s *DomainService) DeleteDomain(ctx context.Context, req *models.DeleteDomainRequest) error {
+	ctx, span := tracing.Start(ctx, "DomainService.DeleteDomain")
+	defer span.End()
+
 	// 检查 Zone 是否存在
-	_, err := s.zoneStorage.GetZone(ctx, req.Zone)
+	zone, err := s.zoneStorage.GetZone(ctx, req.Zone)
 	if err != nil {
 		return errors.ErrZoneNotFound
 	}
-
-	// 检查 Domain 是否存在
-	_, err = s.domainStorage.GetDomain(ctx, req.Zone, req.Domain)
-	if err != nil {
-		return err
+	if zone.ApprovalRequired {
+		return errors.ErrApprovalRequired
 	}
 
-	// 删除 Domain
-	if err := s.domainStorage.DeleteDomain(ctx, req.Zone, req.Domain); err != nil {
+	// 删除 Domain，非临时 Domain 移入回收站
+	change := &models.DomainChange{Op: models.ChangeOpDelete, Domain: req.Domain}
+	if _, err := s.domainStorage.ApplyChange(ctx, req.Zone, change); err != nil {
 		return err
 	}
 
@@ -132,3 +215,17 @@ func (s *DomainService) DeleteDomain(ctx context.Context, req *models.DeleteDoma
 
 	return nil
 }
+
+// RenewDomain 续约临时 Domain
+func (s *DomainService) RenewDomain(ctx context.Context, req *models.RenewDomainRequest) (*models.Domain, error) {
+	ctx, span := tracing.Start(ctx, "DomainService.RenewDomain")
+	defer span.End()
+
+	// 检查 Zone 是否存在
+	_, err := s.zoneStorage.GetZone(ctx, req.Zone)
+	if err != nil {
+		return nil, errors.ErrZoneNotFound
+	}
+
+	return s.domainStorage.RenewDomain(ctx, req.Zone, req.Domain)
+}
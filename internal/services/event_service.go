@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"slices"
+
+	"dancer/internal/models"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tracing"
+)
+
+// EventService 资源变更事件订阅
+type EventService struct {
+	eventStorage *etcd.EventStorage
+}
+
+func NewEventService(eventStorage *etcd.EventStorage) *EventService {
+	return &EventService{eventStorage: eventStorage}
+}
+
+// StartRevision 确定订阅的起始 revision，revision 为 0 时从当前开始
+func (s *EventService) StartRevision(ctx context.Context, revision int64) (int64, error) {
+	ctx, span := tracing.Start(ctx, "EventService.StartRevision")
+	defer span.End()
+
+	return s.eventStorage.StartRevision(ctx, revision)
+}
+
+// Stream 推送 revision 之后满足过滤条件的变更事件，阻塞直到 ctx 取消、send 返回错误或历史已被压缩
+// 只推送用户所属租户的事件，用户变更事件仅推送给管理员；指定 Zone 时只推送该 Zone 及其 Domain 的事件
+func (s *EventService) Stream(ctx context.Context, req *models.StreamEventsRequest, user *models.CurrentUser, revision int64, send func(event *models.ChangeEvent) error) error {
+	match := eventFilter(req, user)
+	return s.eventStorage.WatchEvents(ctx, revision, func(events []*models.ChangeEvent) error {
+		for _, event := range events {
+			if !match(event) {
+				continue
+			}
+			if err := send(event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// eventFilter 根据订阅条件和用户权限生成事件过滤函数
+func eventFilter(req *models.StreamEventsRequest, user *models.CurrentUser) func(event *models.ChangeEvent) bool {
+	return func(event *models.ChangeEvent) bool {
+		if event.Tenant != user.Tenant {
+			return false
+		}
+		if event.Type == models.EventTypeUser && !user.UserType.IsAdmin() {
+			return false
+		}
+		if len(req.Types) > 0 && !slices.Contains(req.Types, event.Type) {
+			return false
+		}
+		return req.Zone == "" || event.ZoneName == req.Zone
+	}
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+
+	"dancer/internal/buildinfo"
+	"dancer/internal/storage/etcd"
+)
+
+// 健康状态
+const (
+	HealthUp   = "up"
+	HealthDown = "down"
+)
+
+// HealthStatus 存活 / 就绪检查结果
+type HealthStatus struct {
+	Status     string            `json:"status"` // up / down
+	Components map[string]string `json:"components,omitempty"`
+}
+
+// HealthDetails 详细健康状态
+type HealthDetails struct {
+	HealthStatus
+	Build     buildinfo.Info         `json:"build"`
+	Etcd      *etcd.ClusterStatus    `json:"etcd"`
+	CoreDNS   etcd.CoreDNSSyncStatus `json:"coredns"`
+	Bootstrap BootstrapStatus        `json:"bootstrap"`
+	Workers   []WorkerStatus         `json:"workers"`
+}
+
+// WorkerStatus 后台任务状态
+type WorkerStatus struct {
+	Name     string `json:"name"`
+	Mode     string `json:"mode"`               // leader：多副本时只在 leader 上运行；replica：每个副本各自运行
+	State    string `json:"state"`              // leader 模式为 candidate / leader / stopped，replica 模式为 loading / ready
+	Since    int64  `json:"since,omitempty"`    // 进入当前状态的时间戳
+	Revision int64  `json:"revision,omitempty"` // 索引 / 缓存已同步到的 etcd revision
+	Error    string `json:"error,omitempty"`
+}
+
+// HealthService 存活、就绪与详细健康检查
+type HealthService struct {
+	etcdClient    *etcd.Client
+	userService   *UserService
+	searchService *SearchService
+}
+
+func NewHealthService(etcdClient *etcd.Client, userService *UserService, searchService *SearchService) *HealthService {
+	return &HealthService{
+		etcdClient:    etcdClient,
+		userService:   userService,
+		searchService: searchService,
+	}
+}
+
+// Liveness 进程存活即为 up，不检查依赖
+func (s *HealthService) Liveness() *HealthStatus {
+	return &HealthStatus{Status: HealthUp}
+}
+
+// Readiness 能否处理请求：etcd 已连接、默认管理员初始化完成、搜索索引已加载
+func (s *HealthService) Readiness() *HealthStatus {
+	ready, _ := s.searchService.IndexStatus()
+	return newHealthStatus(map[string]bool{
+		"etcd":         s.etcdClient.IsConnected(),
+		"bootstrap":    s.userService.BootstrapStatus().Completed,
+		"search_index": ready,
+	})
+}
+
+// EtcdHealth 仅检查 etcd 连接，用于 /api/health
+func (s *HealthService) EtcdHealth() *HealthStatus {
+	return newHealthStatus(map[string]bool{"etcd": s.etcdClient.IsConnected()})
+}
+
+// Details 详细健康状态，会逐个请求 etcd 节点
+func (s *HealthService) Details(ctx context.Context) *HealthDetails {
+	return &HealthDetails{
+		HealthStatus: *s.Readiness(),
+		Build:        buildinfo.Get(),
+		Etcd:         s.etcdClient.ClusterStatus(ctx),
+		CoreDNS:      s.etcdClient.CoreDNSSyncStatus(),
+		Bootstrap:    s.userService.BootstrapStatus(),
+		Workers:      s.workers(),
+	}
+}
+
+// workers 汇总 leader 选举任务、搜索索引与读缓存的状态
+func (s *HealthService) workers() []WorkerStatus {
+	workers := make([]WorkerStatus, 0)
+	for _, es := range s.etcdClient.ElectionStatus() {
+		workers = append(workers, WorkerStatus{Name: es.Name, Mode: "leader", State: es.Role, Since: es.Since, Error: es.Error})
+	}
+
+	ready, revision := s.searchService.IndexStatus()
+	workers = append(workers, WorkerStatus{Name: "search_index", Mode: "replica", State: loadState(ready), Revision: revision})
+
+	for _, cs := range s.etcdClient.CacheStatus() {
+		workers = append(workers, WorkerStatus{Name: "cache:" + cs.Prefix, Mode: "replica", State: loadState(cs.Ready), Revision: cs.Revision})
+	}
+	return workers
+}
+
+// newHealthStatus 由各组件是否正常生成结果，任一组件异常时为 down
+func newHealthStatus(components map[string]bool) *HealthStatus {
+	status := &HealthStatus{Status: HealthUp, Components: make(map[string]string, len(components))}
+	for name, ok := range components {
+		if ok {
+			status.Components[name] = HealthUp
+			continue
+		}
+		status.Components[name] = HealthDown
+		status.Status = HealthDown
+	}
+	return status
+}
+
+// loadState 索引 / 缓存的加载状态
+func loadState(ready bool) string {
+	if ready {
+		return "ready"
+	}
+	return "loading"
+}
@@ -0,0 +1,368 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tracing"
+)
+
+// HistoryService Domain 版本历史、对比与回滚
+// 历史记录器由 leader 副本执行：监听 Domain 变更并保存版本快照，使历史在 etcd 压缩后仍然可查
+type HistoryService struct {
+	historyStorage *etcd.HistoryStorage
+	eventStorage   *etcd.EventStorage
+	zoneStorage    *etcd.ZoneStorage
+	domainStorage  *etcd.DomainStorage
+	etcdClient     *etcd.Client
+}
+
+func NewHistoryService(historyStorage *etcd.HistoryStorage, eventStorage *etcd.EventStorage, zoneStorage *etcd.ZoneStorage, domainStorage *etcd.DomainStorage, etcdClient *etcd.Client) *HistoryService {
+	return &HistoryService{
+		historyStorage: historyStorage,
+		eventStorage:   eventStorage,
+		zoneStorage:    zoneStorage,
+		domainStorage:  domainStorage,
+		etcdClient:     etcdClient,
+	}
+}
+
+// ListVersions 列出 Domain 的历史版本，按 revision 倒序，limit 为 0 时返回全部保留的版本
+func (s *HistoryService) ListVersions(ctx context.Context, req *models.DomainHistoryRequest) ([]*models.DomainVersion, error) {
+	ctx, span := tracing.Start(ctx, "HistoryService.ListVersions")
+	defer span.End()
+
+	// 检查 Zone 是否存在
+	if _, err := s.zoneStorage.GetZone(ctx, req.Zone); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.historyStorage.ListDomainVersions(ctx, req.Zone, req.Domain)
+	if err != nil {
+		return nil, err
+	}
+	if req.Limit > 0 && len(versions) > req.Limit {
+		versions = versions[:req.Limit]
+	}
+	return versions, nil
+}
+
+// DiffVersions 对比 Domain 的两个版本，未指定 to_revision 时与当前状态对比
+func (s *HistoryService) DiffVersions(ctx context.Context, req *models.DiffDomainVersionsRequest) (*models.DomainChangeDiff, error) {
+	ctx, span := tracing.Start(ctx, "HistoryService.DiffVersions")
+	defer span.End()
+
+	// 检查 Zone 是否存在
+	if _, err := s.zoneStorage.GetZone(ctx, req.Zone); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.historyStorage.ListDomainVersions(ctx, req.Zone, req.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	from := findVersion(versions, req.FromRevision)
+	if from == nil {
+		return nil, apperrors.ErrVersionNotFound
+	}
+
+	var before, after *models.Domain
+	if from.Exists() {
+		before = from.Domain
+	}
+	if req.ToRevision > 0 {
+		to := findVersion(versions, req.ToRevision)
+		if to == nil {
+			return nil, apperrors.ErrVersionNotFound
+		}
+		if to.Exists() {
+			after = to.Domain
+		}
+	} else if after, err = s.currentDomain(ctx, req.Zone, req.Domain); err != nil {
+		return nil, err
+	}
+
+	op := models.ChangeOpUpdate
+	switch {
+	case before == nil && after != nil:
+		op = models.ChangeOpCreate
+	case before != nil && after == nil:
+		op = models.ChangeOpDelete
+	}
+	return domainDiff(op, req.Domain, before, after), nil
+}
+
+// RollbackDomain 将 Domain 恢复到指定版本并同步 CoreDNS 记录
+// 目标为删除版本时删除 Domain，返回 nil；已处于目标状态时不做修改
+func (s *HistoryService) RollbackDomain(ctx context.Context, req *models.RollbackDomainRequest) (*models.Domain, error) {
+	ctx, span := tracing.Start(ctx, "HistoryService.RollbackDomain")
+	defer span.End()
+
+	// 检查 Zone 是否存在
+	zone, err := s.zoneStorage.GetZone(ctx, req.Zone)
+	if err != nil {
+		return nil, apperrors.ErrZoneNotFound
+	}
+	if zone.ApprovalRequired {
+		return nil, apperrors.ErrApprovalRequired
+	}
+
+	versions, err := s.historyStorage.ListDomainVersions(ctx, req.Zone, req.Domain)
+	if err != nil {
+		return nil, err
+	}
+	target := findVersion(versions, req.Revision)
+	if target == nil {
+		return nil, apperrors.ErrVersionNotFound
+	}
+
+	current, err := s.currentDomain(ctx, req.Zone, req.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	change := rollbackChange(req.Domain, current, target)
+	if change == nil {
+		return current, nil
+	}
+
+	domain, err := s.domainStorage.ApplyChange(ctx, req.Zone, change)
+	if err != nil {
+		return nil, err
+	}
+
+	// 更新 Zone 记录数
+	if delta := recordCountDelta(change.Op); delta != 0 {
+		if err := s.zoneStorage.IncrementZoneRecordCount(ctx, req.Zone, delta); err != nil {
+			logger.Log.WithError(err).WithField("zone", req.Zone).Warn("Failed to update zone record count after rollback")
+		}
+	}
+
+	return domain, nil
+}
+
+// RollbackZone 将 Zone 下所有 Domain 恢复到 timestamp 时间点的状态
+// 每个 Domain 取时间戳不晚于 timestamp 的最新版本；在该时间点之后创建的 Domain 会被删除，之前删除的会被重新创建
+// 历史不足以确定目标状态的 Domain 及临时 Domain 不做修改并列入 Skipped；其余变更原子应用
+func (s *HistoryService) RollbackZone(ctx context.Context, req *models.RollbackZoneRequest) (*models.ZoneRollbackResult, error) {
+	ctx, span := tracing.Start(ctx, "HistoryService.RollbackZone")
+	defer span.End()
+
+	// 检查 Zone 是否存在
+	zone, err := s.zoneStorage.GetZone(ctx, req.Zone)
+	if err != nil {
+		return nil, apperrors.ErrZoneNotFound
+	}
+	if zone.ApprovalRequired {
+		return nil, apperrors.ErrApprovalRequired
+	}
+
+	versions, err := s.historyStorage.ListZoneVersions(ctx, req.Zone, req.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	domains, err := s.domainStorage.ListDomainsByZone(ctx, req.Zone)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]*models.Domain, len(domains))
+	names := make([]string, 0, len(domains)+len(versions))
+	for _, d := range domains {
+		current[d.Domain] = d
+		names = append(names, d.Domain)
+	}
+	for name := range versions {
+		if current[name] == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	result := &models.ZoneRollbackResult{
+		Changes: make([]*models.DomainChangeDiff, 0),
+		Skipped: make([]*models.RollbackSkip, 0),
+	}
+	var changes []*models.DomainChange
+	for _, name := range names {
+		cur := current[name]
+		if cur != nil && cur.IsEphemeral() {
+			result.Skipped = append(result.Skipped, &models.RollbackSkip{Domain: name, Reason: "ephemeral domain"})
+			continue
+		}
+
+		target, ok := versionAt(versions[name], req.Timestamp)
+		if !ok {
+			result.Skipped = append(result.Skipped, &models.RollbackSkip{Domain: name, Reason: "no version at or before timestamp"})
+			continue
+		}
+
+		change := rollbackChange(name, cur, target)
+		if change == nil {
+			continue
+		}
+		var after *models.Domain
+		if target.Exists() {
+			after = target.Domain
+		}
+		changes = append(changes, change)
+		result.Changes = append(result.Changes, domainDiff(change.Op, name, cur, after))
+	}
+
+	if req.DryRun || len(changes) == 0 {
+		return result, nil
+	}
+
+	if _, err := s.domainStorage.ApplyChanges(ctx, req.Zone, changes); err != nil {
+		return nil, err
+	}
+
+	// 更新 Zone 记录数
+	count, _ := s.domainStorage.GetDomainCountByZone(ctx, req.Zone)
+	s.zoneStorage.UpdateZoneRecordCount(ctx, req.Zone, count)
+
+	return result, nil
+}
+
+// currentDomain 获取 Domain 当前状态，不存在时返回 nil
+func (s *HistoryService) currentDomain(ctx context.Context, zone, domain string) (*models.Domain, error) {
+	d, err := s.domainStorage.GetDomain(ctx, zone, domain)
+	if err == apperrors.ErrDomainNotFound {
+		return nil, nil
+	}
+	return d, err
+}
+
+// Run 启动历史记录器，仅 leader 副本执行，阻塞直到 ctx 取消
+func (s *HistoryService) Run(ctx context.Context) {
+	s.etcdClient.RunAsLeader(ctx, "history", s.record)
+}
+
+// record 从上次处理到的 revision 开始监听变更事件并保存 Domain 版本快照，阻塞直到 ctx 取消
+func (s *HistoryService) record(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := s.recordOnce(ctx); err != nil && ctx.Err() == nil {
+			logger.Log.WithError(err).Warn("Domain history recording interrupted, retrying")
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+// recordOnce 执行一轮事件监听
+// 上次处理到的 revision 已被压缩时，从当前 revision 继续并记录错误日志，期间的版本只能从 etcd 中尚存的 revision 获取
+func (s *HistoryService) recordOnce(ctx context.Context) error {
+	cursor, err := s.historyStorage.GetCursor(ctx)
+	if err != nil {
+		return err
+	}
+
+	revision, err := s.eventStorage.StartRevision(ctx, cursor)
+	if err == apperrors.ErrRevisionCompacted {
+		logger.Log.WithField("revision", cursor).Error("Domain history compacted before it was recorded, versions since this revision are lost")
+		revision, err = s.eventStorage.StartRevision(ctx, 0)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.eventStorage.WatchEvents(ctx, revision, func(events []*models.ChangeEvent) error {
+		return s.save(ctx, events)
+	})
+}
+
+// save 将一批事件中的 Domain 变更保存为版本快照，临时 Domain 不记录
+func (s *HistoryService) save(ctx context.Context, events []*models.ChangeEvent) error {
+	now := time.Now().Unix()
+	var versions []*models.DomainVersion
+	for _, event := range events {
+		if event.Type != models.EventTypeDomain || (event.Domain != nil && event.Domain.IsEphemeral()) {
+			continue
+		}
+
+		v := &models.DomainVersion{
+			Revision: event.Revision,
+			Op:       event.Op,
+			Tenant:   event.Tenant,
+			ZoneName: event.ZoneName,
+			Name:     event.Name,
+		}
+		if event.Op == models.EventOpDelete {
+			v.Timestamp = now
+		} else if event.Domain != nil {
+			v.Domain, v.Timestamp = event.Domain, event.Domain.UpdatedAt
+		}
+		versions = append(versions, v)
+	}
+
+	return s.historyStorage.SaveVersions(ctx, versions, events[len(events)-1].Revision)
+}
+
+// findVersion 按 revision 查找版本
+func findVersion(versions []*models.DomainVersion, revision int64) *models.DomainVersion {
+	for _, v := range versions {
+		if v.Revision == revision {
+			return v
+		}
+	}
+	return nil
+}
+
+// versionAt 确定 timestamp 时间点生效的版本（versions 按 revision 倒序）
+// 最早的已知版本晚于该时间点时：若为创建版本，说明当时 Domain 不存在，返回一个删除版本；否则无法确定，ok 为 false
+func versionAt(versions []*models.DomainVersion, timestamp int64) (v *models.DomainVersion, ok bool) {
+	for _, v := range versions {
+		if v.Timestamp <= timestamp {
+			return v, true
+		}
+	}
+	if len(versions) > 0 && versions[len(versions)-1].Op == models.EventOpCreate {
+		return &models.DomainVersion{Op: models.EventOpDelete}, true
+	}
+	return nil, false
+}
+
+// rollbackChange 生成将 Domain 从当前状态恢复到目标版本所需的变更，已处于目标状态时返回 nil
+func rollbackChange(name string, current *models.Domain, target *models.DomainVersion) *models.DomainChange {
+	switch {
+	case !target.Exists() && current == nil:
+		return nil
+	case !target.Exists():
+		return &models.DomainChange{Op: models.ChangeOpDelete, Domain: name}
+	case current == nil:
+		return &models.DomainChange{Op: models.ChangeOpCreate, Domain: name, IPs: target.Domain.IPs, TTL: target.Domain.TTL}
+	}
+
+	if current.TTL == target.Domain.TTL && len(current.IPs) == len(target.Domain.IPs) &&
+		len(subtractIPs(current.IPs, target.Domain.IPs)) == 0 && len(subtractIPs(target.Domain.IPs, current.IPs)) == 0 {
+		return nil
+	}
+	return &models.DomainChange{Op: models.ChangeOpUpdate, Domain: name, IPs: target.Domain.IPs, TTL: target.Domain.TTL}
+}
+
+// domainDiff 生成 Domain 两个状态之间的差异，不存在的一方为 nil
+func domainDiff(op models.ChangeOp, name string, before, after *models.Domain) *models.DomainChangeDiff {
+	diff := &models.DomainChangeDiff{Op: op, Domain: name}
+
+	var beforeIPs, afterIPs []string
+	if before != nil {
+		diff.Before = &models.RecordSet{IPs: before.IPs, TTL: before.TTL}
+		beforeIPs = before.IPs
+	}
+	if after != nil {
+		diff.After = &models.RecordSet{IPs: after.IPs, TTL: after.TTL}
+		afterIPs = after.IPs
+	}
+
+	diff.AddedIPs = subtractIPs(afterIPs, beforeIPs)
+	diff.RemovedIPs = subtractIPs(beforeIPs, afterIPs)
+	return diff
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"cmp"
+	"net"
+	"strings"
+
+	"dancer/internal/models"
+)
+
+// 列表过滤与排序的通用工具
+
+// filters 多个过滤条件的组合，全部满足才算匹配
+type filters[T any] []func(T) bool
+
+// add 添加过滤条件
+func (f *filters[T]) add(match func(T) bool) {
+	*f = append(*f, match)
+}
+
+// match 返回组合后的过滤函数，没有任何条件时返回 nil 表示不过滤
+func (f filters[T]) match() func(T) bool {
+	if len(f) == 0 {
+		return nil
+	}
+	return func(item T) bool {
+		for _, match := range f {
+			if !match(item) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// containsFold 不区分大小写判断 s 是否包含 substr
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// ipMatcher 根据过滤条件生成 IP 匹配函数，条件可以是单个 IP 或 CIDR 网段
+func ipMatcher(filter string) func(ips []string) bool {
+	if _, network, err := net.ParseCIDR(filter); err == nil {
+		return func(ips []string) bool {
+			for _, ip := range ips {
+				if parsed := net.ParseIP(ip); parsed != nil && network.Contains(parsed) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	target := net.ParseIP(filter)
+	return func(ips []string) bool {
+		for _, ip := range ips {
+			if parsed := net.ParseIP(ip); parsed != nil && parsed.Equal(target) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// lessBy 生成按字段比较的排序函数
+func lessBy[T any, K cmp.Ordered](key func(T) K) func(a, b T) bool {
+	return func(a, b T) bool {
+		return key(a) < key(b)
+	}
+}
+
+// listQuery 由分页参数、名称前缀及过滤条件组装存储层查询
+func listQuery[T any](page models.PageRequest, keyPrefix string, f filters[T], less func(a, b T) bool) *models.ListQuery[T] {
+	return &models.ListQuery[T]{
+		PageRequest: page,
+		KeyPrefix:   keyPrefix,
+		Match:       f.match(),
+		Less:        less,
+	}
+}
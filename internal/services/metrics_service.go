@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+
+	"dancer/internal/metrics"
+	"dancer/internal/storage/etcd"
+)
+
+// MetricsService 为 /metrics 统计数据量
+// Domain 与记录数量取自搜索索引，抓取时不遍历 etcd 中的 Domain
+type MetricsService struct {
+	etcdClient    *etcd.Client
+	zoneStorage   *etcd.ZoneStorage
+	searchService *SearchService
+}
+
+func NewMetricsService(etcdClient *etcd.Client, zoneStorage *etcd.ZoneStorage, searchService *SearchService) *MetricsService {
+	return &MetricsService{
+		etcdClient:    etcdClient,
+		zoneStorage:   zoneStorage,
+		searchService: searchService,
+	}
+}
+
+// Register 注册 etcd 连接状态与数据量指标
+func (s *MetricsService) Register() {
+	metrics.RegisterEtcdState(func() int { return int(s.etcdClient.GetState()) })
+	metrics.RegisterInventory(s.Inventory)
+}
+
+// Inventory 统计所有租户的 Zone、Domain 与记录数量，记录包括静态 IP 与动态实例
+func (s *MetricsService) Inventory(ctx context.Context) (*metrics.Inventory, error) {
+	domains, staticIPs, instances, err := s.searchService.Counts()
+	if err != nil {
+		return nil, err
+	}
+
+	zones, err := s.zoneStorage.ListAllZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metrics.Inventory{
+		Zones:     len(zones),
+		Domains:   domains,
+		Records:   staticIPs + instances,
+		Instances: instances,
+	}, nil
+}
@@ -0,0 +1,567 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/metrics"
+	"dancer/internal/models"
+	"dancer/internal/publisher"
+	"dancer/internal/storage"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tenant"
+	"dancer/internal/tracing"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// publishRetryInterval 同步失败后重试的间隔
+const publishRetryInterval = 5 * time.Second
+
+// PublishService 将记录同步到 coredns 以外的发布目标
+// 同步由 leader 副本执行：通过 etcd watch 在内存中维护 Zone、Domain 与动态实例，变化的域名排队后逐个发布或撤下；
+// 首次加载及 watch 中断重建后对所有 Zone 全量对账，修复发布目标上被改动或遗漏的记录
+type PublishService struct {
+	publishedStorage *etcd.PublishedStorage
+	zoneStorage      *etcd.ZoneStorage
+	etcdClient       *etcd.Client
+	publishers       *publisher.Registry
+
+	mu        sync.Mutex
+	zones     map[string]*models.Zone      // zone -> Zone
+	domains   map[string]*models.Domain    // zone/domain -> Domain
+	instances map[string]map[string]string // zone/domain -> 实例 ID -> IP
+	loaded    map[string]bool              // 已完成首次加载的 watch 前缀
+	pending   map[publishTask]bool         // 待同步的工作项
+	wake      chan struct{}                // 有新的工作项
+}
+
+// publishTask 待同步的工作项
+// domain 为空时对 Zone 全量对账，zone 也为空时对发布目标上的所有 Zone 全量对账
+type publishTask struct {
+	publisher string
+	zone      string
+	domain    string
+}
+
+func NewPublishService(publishedStorage *etcd.PublishedStorage, zoneStorage *etcd.ZoneStorage, etcdClient *etcd.Client, publishers *publisher.Registry) *PublishService {
+	return &PublishService{
+		publishedStorage: publishedStorage,
+		zoneStorage:      zoneStorage,
+		etcdClient:       etcdClient,
+		publishers:       publishers,
+		wake:             make(chan struct{}, 1),
+	}
+}
+
+// ListPublishers 列出已配置的发布目标，coredns 在最前
+func (s *PublishService) ListPublishers() []*models.PublisherInfo {
+	names := s.publishers.Names()
+	result := make([]*models.PublisherInfo, len(names))
+	for i, name := range names {
+		result[i] = &models.PublisherInfo{Name: name, Type: s.publishers.Type(name)}
+	}
+	return result
+}
+
+// ListPublished 从发布目标读取 Zone 当前的地址记录，包括不由 Dancer 写入的记录
+// 其他租户的 Zone 返回 ErrZoneNotFound；不由 Dancer 管理的 Zone 只有默认租户可以读取
+func (s *PublishService) ListPublished(ctx context.Context, req *models.ListPublishedRequest) (*models.PublishedRecords, error) {
+	ctx, span := tracing.Start(ctx, "PublishService.ListPublished")
+	defer span.End()
+
+	p, ok := s.publishers.Get(req.Publisher)
+	if !ok {
+		return nil, apperrors.ErrUnknownPublisher
+	}
+
+	owner, err := s.zoneStorage.GetZoneOwner(ctx, req.Zone)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.Of(owner) != tenant.FromContext(ctx) {
+		return nil, apperrors.ErrZoneNotFound
+	}
+
+	records, err := p.List(ctx, req.Zone)
+	if err != nil {
+		return nil, err
+	}
+	if records == nil {
+		records = make([]*models.PublishedRecordSet, 0)
+	}
+	return &models.PublishedRecords{Publisher: req.Publisher, Zone: req.Zone, Records: records}, nil
+}
+
+// Run 启动外部发布目标同步，仅 leader 副本执行，阻塞直到 ctx 取消；没有配置外部发布目标时直接返回
+func (s *PublishService) Run(ctx context.Context) {
+	if len(s.publishers.External()) == 0 {
+		return
+	}
+	s.etcdClient.RunAsLeader(ctx, "publish", s.run)
+}
+
+// run 当选 leader 后重新加载状态并处理工作项，阻塞直到 ctx 取消
+func (s *PublishService) run(ctx context.Context) {
+	s.mu.Lock()
+	s.zones = make(map[string]*models.Zone)
+	s.domains = make(map[string]*models.Domain)
+	s.instances = make(map[string]map[string]string)
+	s.loaded = make(map[string]bool)
+	s.pending = make(map[publishTask]bool)
+	s.mu.Unlock()
+
+	watchers := map[string]etcd.WatchHandler{
+		storage.ZoneKeyPrefix:     &publishZoneWatcher{s},
+		storage.DomainKeyPrefix:   &publishDomainWatcher{s},
+		storage.InstanceKeyPrefix: &publishInstanceWatcher{s},
+	}
+	var wg sync.WaitGroup
+	for prefix, h := range watchers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.etcdClient.WatchPrefix(ctx, prefix, h)
+		}()
+	}
+
+	s.work(ctx)
+	wg.Wait()
+}
+
+// work 逐个处理工作项，失败的工作项在 publishRetryInterval 后重试
+func (s *PublishService) work(ctx context.Context) {
+	var retry <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-retry:
+			retry = nil
+		}
+
+		for _, task := range s.takePending() {
+			if ctx.Err() != nil {
+				return
+			}
+			err := s.process(ctx, task)
+			metrics.ObservePublish(task.publisher, err)
+			if err == nil {
+				continue
+			}
+
+			logger.Log.WithError(err).
+				WithField("publisher", task.publisher).
+				WithField("zone", task.zone).
+				WithField("domain", task.domain).
+				Warn("Failed to sync publisher, retrying")
+			s.mu.Lock()
+			s.pending[task] = true
+			s.mu.Unlock()
+			if retry == nil {
+				retry = time.After(publishRetryInterval)
+			}
+		}
+	}
+}
+
+// takePending 取出所有工作项；三个 watch 都完成首次加载前不处理
+func (s *PublishService) takePending() []publishTask {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.loaded) < 3 {
+		return nil
+	}
+	tasks := make([]publishTask, 0, len(s.pending))
+	for task := range s.pending {
+		tasks = append(tasks, task)
+	}
+	s.pending = make(map[publishTask]bool)
+
+	sort.Slice(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if a.publisher != b.publisher {
+			return a.publisher < b.publisher
+		}
+		if a.zone != b.zone {
+			return a.zone < b.zone
+		}
+		return a.domain < b.domain
+	})
+	return tasks
+}
+
+// enqueue 添加工作项并唤醒 work，调用方需持有锁
+func (s *PublishService) enqueue(task publishTask) {
+	s.pending[task] = true
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// enqueueAll 为每个外部发布目标添加全量对账工作项，调用方需持有锁
+func (s *PublishService) enqueueAll() {
+	for _, name := range s.publishers.External() {
+		s.enqueue(publishTask{publisher: name})
+	}
+}
+
+// enqueueDomain 为 Domain 所属 Zone 的每个外部发布目标添加工作项，调用方需持有锁
+// Zone 尚未加载时忽略，Zone 加载后会全量对账
+func (s *PublishService) enqueueDomain(zone, domain string) {
+	z, ok := s.zones[zone]
+	if !ok {
+		return
+	}
+	for _, name := range s.externalPublishers(z) {
+		s.enqueue(publishTask{publisher: name, zone: zone, domain: domain})
+	}
+}
+
+// externalPublishers 返回 Zone 使用的已配置的外部发布目标，z 为 nil 时返回空
+func (s *PublishService) externalPublishers(z *models.Zone) []string {
+	if z == nil {
+		return nil
+	}
+	var names []string
+	for _, name := range z.PublisherNames() {
+		if s.publishers.Type(name) == publisher.TypeCoreDNS {
+			continue
+		}
+		if _, ok := s.publishers.Get(name); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// process 处理一个工作项
+func (s *PublishService) process(ctx context.Context, task publishTask) error {
+	p, ok := s.publishers.Get(task.publisher)
+	if !ok {
+		return nil
+	}
+
+	switch {
+	case task.zone == "":
+		return s.reconcileAll(ctx, p, task.publisher)
+	case task.domain == "":
+		return s.reconcile(ctx, p, task.publisher, task.zone)
+	}
+
+	name := task.domain + "." + task.zone
+	s.mu.Lock()
+	rs := s.desired(task.publisher, task.zone, task.domain)
+	s.mu.Unlock()
+	if rs != nil {
+		if err := p.Publish(ctx, task.zone, rs); err != nil {
+			return err
+		}
+		return s.publishedStorage.MarkPublished(ctx, task.publisher, task.zone, rs)
+	}
+
+	// 只撤下由 Dancer 发布过的域名
+	published, err := s.publishedStorage.IsPublished(ctx, task.publisher, task.zone, name)
+	if err != nil || !published {
+		return err
+	}
+	if err := p.Remove(ctx, task.zone, name); err != nil {
+		return err
+	}
+	return s.publishedStorage.UnmarkPublished(ctx, task.publisher, task.zone, name)
+}
+
+// reconcileAll 对发布目标上的所有 Zone 全量对账，包括已删除或不再使用该发布目标、但仍有已发布记录的 Zone
+func (s *PublishService) reconcileAll(ctx context.Context, p publisher.Publisher, publisherName string) error {
+	zones, err := s.publishedStorage.ListPublishedZones(ctx, publisherName)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	for zone, z := range s.zones {
+		if slices.Contains(s.externalPublishers(z), publisherName) && !slices.Contains(zones, zone) {
+			zones = append(zones, zone)
+		}
+	}
+	s.mu.Unlock()
+	sort.Strings(zones)
+
+	for _, zone := range zones {
+		if err := s.reconcile(ctx, p, publisherName, zone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcile 对 Zone 全量对账：与期望不一致的域名重新发布，不再需要的已发布域名撤下
+// 发布目标上不由 Dancer 发布的域名保持不变
+func (s *PublishService) reconcile(ctx context.Context, p publisher.Publisher, publisherName, zone string) error {
+	s.mu.Lock()
+	desired := make(map[string]*models.PublishedRecordSet)
+	prefix := zone + "/"
+	for key, d := range s.domains {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if rs := s.desired(publisherName, zone, d.Domain); rs != nil {
+			desired[rs.Name] = rs
+		}
+	}
+	s.mu.Unlock()
+
+	current, err := p.List(ctx, zone)
+	if err != nil {
+		return err
+	}
+	published := make(map[string]*models.PublishedRecordSet, len(current))
+	for _, rs := range current {
+		published[rs.Name] = rs
+	}
+	tracked, err := s.publishedStorage.ListPublishedNames(ctx, publisherName, zone)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rs := desired[name]
+		if sameRecordSet(published[name], rs) && slices.Contains(tracked, name) {
+			continue
+		}
+		if err := p.Publish(ctx, zone, rs); err != nil {
+			return err
+		}
+		if err := s.publishedStorage.MarkPublished(ctx, publisherName, zone, rs); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range tracked {
+		if desired[name] != nil {
+			continue
+		}
+		if err := p.Remove(ctx, zone, name); err != nil {
+			return err
+		}
+		if err := s.publishedStorage.UnmarkPublished(ctx, publisherName, zone, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// desired 计算 Domain 在发布目标上应有的记录：静态 IP 与动态实例 IP，TTL 取 Domain 的 TTL
+// Domain 或 Zone 不存在、Zone 不使用该发布目标或没有 IP 时返回 nil，调用方需持有锁
+func (s *PublishService) desired(publisherName, zone, domain string) *models.PublishedRecordSet {
+	if !slices.Contains(s.externalPublishers(s.zones[zone]), publisherName) {
+		return nil
+	}
+	d, ok := s.domains[zone+"/"+domain]
+	if !ok {
+		return nil
+	}
+
+	static := d.IPsFor(s.publishers.View(publisherName))
+	ips := make([]string, 0, len(static))
+	seen := make(map[string]bool)
+	for _, ip := range static {
+		if !seen[normalizeIP(ip)] {
+			seen[normalizeIP(ip)] = true
+			ips = append(ips, ip)
+		}
+	}
+	dynamic := make([]string, 0, len(s.instances[zone+"/"+domain]))
+	for _, ip := range s.instances[zone+"/"+domain] {
+		if !seen[normalizeIP(ip)] {
+			seen[normalizeIP(ip)] = true
+			dynamic = append(dynamic, ip)
+		}
+	}
+	sort.Strings(dynamic)
+	ips = append(ips, dynamic...)
+	if len(ips) == 0 {
+		return nil
+	}
+	return &models.PublishedRecordSet{Name: domain + "." + zone, IPs: ips, TTL: d.TTL}
+}
+
+// sameRecordSet 比较已发布的记录与期望的记录，IP 不区分顺序与写法
+func sameRecordSet(published, desired *models.PublishedRecordSet) bool {
+	if published == nil || published.TTL != desired.TTL || len(published.IPs) != len(desired.IPs) {
+		return false
+	}
+	ips := make(map[string]bool, len(published.IPs))
+	for _, ip := range published.IPs {
+		ips[normalizeIP(ip)] = true
+	}
+	for _, ip := range desired.IPs {
+		if !ips[normalizeIP(ip)] {
+			return false
+		}
+	}
+	return true
+}
+
+// publishZoneWatcher 将 /dancer/zones/ 的变化同步到内存，Zone 的外部发布目标变化时对 Zone 全量对账
+type publishZoneWatcher struct {
+	s *PublishService
+}
+
+func (w *publishZoneWatcher) Reset(kvs []*mvccpb.KeyValue, revision int64) {
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+
+	w.s.zones = make(map[string]*models.Zone, len(kvs))
+	for _, kv := range kvs {
+		var z models.Zone
+		if _, zone, ok := storage.SplitZoneKey(string(kv.Key)); ok && json.Unmarshal(kv.Value, &z) == nil {
+			w.s.zones[zone] = &z
+		}
+	}
+	w.s.loaded[storage.ZoneKeyPrefix] = true
+	w.s.enqueueAll()
+}
+
+func (w *publishZoneWatcher) Apply(events []*clientv3.Event, revision int64) {
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+
+	for _, ev := range events {
+		_, zone, ok := storage.SplitZoneKey(string(ev.Kv.Key))
+		if !ok {
+			continue
+		}
+		before := w.s.externalPublishers(w.s.zones[zone])
+		if ev.Type == clientv3.EventTypeDelete {
+			delete(w.s.zones, zone)
+		} else {
+			var z models.Zone
+			if err := json.Unmarshal(ev.Kv.Value, &z); err != nil {
+				continue
+			}
+			w.s.zones[zone] = &z
+		}
+		after := w.s.externalPublishers(w.s.zones[zone])
+
+		for _, name := range w.s.publishers.External() {
+			if slices.Contains(before, name) != slices.Contains(after, name) {
+				w.s.enqueue(publishTask{publisher: name, zone: zone})
+			}
+		}
+	}
+}
+
+// publishDomainWatcher 将 /dancer/domains/ 的变化同步到内存，变化的 Domain 加入工作项
+type publishDomainWatcher struct {
+	s *PublishService
+}
+
+func (w *publishDomainWatcher) Reset(kvs []*mvccpb.KeyValue, revision int64) {
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+
+	w.s.domains = make(map[string]*models.Domain, len(kvs))
+	for _, kv := range kvs {
+		_, key, ok := storage.SplitDomainKey(string(kv.Key))
+		if !ok {
+			continue
+		}
+		if d, ok := decodeDomain(kv.Value); ok {
+			w.s.domains[key] = d
+		}
+	}
+	w.s.loaded[storage.DomainKeyPrefix] = true
+	w.s.enqueueAll()
+}
+
+func (w *publishDomainWatcher) Apply(events []*clientv3.Event, revision int64) {
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+
+	for _, ev := range events {
+		_, key, ok := storage.SplitDomainKey(string(ev.Kv.Key))
+		if !ok {
+			continue
+		}
+		zone, domain, _ := strings.Cut(key, "/")
+		if ev.Type == clientv3.EventTypeDelete {
+			delete(w.s.domains, key)
+		} else if d, ok := decodeDomain(ev.Kv.Value); ok {
+			w.s.domains[key] = d
+		}
+		w.s.enqueueDomain(zone, domain)
+	}
+}
+
+// publishInstanceWatcher 将 /dancer/instances/ 的变化同步到内存，实例所属的 Domain 加入工作项
+type publishInstanceWatcher struct {
+	s *PublishService
+}
+
+func (w *publishInstanceWatcher) Reset(kvs []*mvccpb.KeyValue, revision int64) {
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+
+	w.s.instances = make(map[string]map[string]string)
+	for _, kv := range kvs {
+		var inst models.Instance
+		if err := json.Unmarshal(kv.Value, &inst); err == nil {
+			w.s.putInstance(inst.Zone+"/"+inst.Domain, inst.InstanceID, inst.IP)
+		}
+	}
+	w.s.loaded[storage.InstanceKeyPrefix] = true
+	w.s.enqueueAll()
+}
+
+func (w *publishInstanceWatcher) Apply(events []*clientv3.Event, revision int64) {
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+
+	for _, ev := range events {
+		// {zone}/{domain}/{instance_id}
+		key := strings.TrimPrefix(string(ev.Kv.Key), storage.InstanceKeyPrefix)
+		i := strings.LastIndex(key, "/")
+		if i < 0 {
+			continue
+		}
+		domainKey, id := key[:i], key[i+1:]
+		if ev.Type == clientv3.EventTypeDelete {
+			delete(w.s.instances[domainKey], id)
+			if len(w.s.instances[domainKey]) == 0 {
+				delete(w.s.instances, domainKey)
+			}
+		} else {
+			var inst models.Instance
+			if err := json.Unmarshal(ev.Kv.Value, &inst); err != nil {
+				continue
+			}
+			w.s.putInstance(domainKey, id, inst.IP)
+		}
+
+		zone, domain, _ := strings.Cut(domainKey, "/")
+		w.s.enqueueDomain(zone, domain)
+	}
+}
+
+// putInstance 记录动态实例的 IP，调用方需持有锁
+func (s *PublishService) putInstance(domainKey, id, ip string) {
+	if s.instances[domainKey] == nil {
+		s.instances[domainKey] = make(map[string]string)
+	}
+	s.instances[domainKey][id] = ip
+}
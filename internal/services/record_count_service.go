@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"dancer/internal/logger"
+	"dancer/internal/storage"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tenant"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// RecordCountService 校正 Zone 的记录数量
+// Domain 的增删由服务层在写入后更新所在 Zone 的记录数量；临时 Domain 的租约到期时 key 由 etcd 直接删除，不经过服务层，
+// 由 leader 副本监听 Domain 的删除事件，按 key 重新统计被删除的临时 Domain 所在的 Zone
+type RecordCountService struct {
+	zoneStorage *etcd.ZoneStorage
+	etcdClient  *etcd.Client
+}
+
+func NewRecordCountService(zoneStorage *etcd.ZoneStorage, etcdClient *etcd.Client) *RecordCountService {
+	return &RecordCountService{
+		zoneStorage: zoneStorage,
+		etcdClient:  etcdClient,
+	}
+}
+
+// Run 启动记录数量校正，仅 leader 副本执行，阻塞直到 ctx 取消
+func (s *RecordCountService) Run(ctx context.Context) {
+	s.etcdClient.RunAsLeader(ctx, "record-count", func(ctx context.Context) {
+		s.etcdClient.WatchPrefix(ctx, storage.DomainKeyPrefix, &recordCounter{ctx: ctx, s: s})
+	})
+}
+
+// recordCounter 实现 WatchHandler
+type recordCounter struct {
+	ctx context.Context
+	s   *RecordCountService
+}
+
+// Reset 成为 leader 或 watch 中断后校正所有 Zone，期间到期的 Domain 不会产生事件
+func (h *recordCounter) Reset(kvs []*mvccpb.KeyValue, revision int64) {
+	updated, err := h.s.zoneStorage.ReconcileRecordCounts(h.ctx)
+	if err != nil {
+		if h.ctx.Err() == nil {
+			logger.Log.WithError(err).Error("Failed to reconcile zone record counts")
+		}
+		return
+	}
+	if updated > 0 {
+		logger.Log.WithField("zones", updated).Info("Reconciled zone record counts")
+	}
+}
+
+// Apply 重新统计删除了临时 Domain 的 Zone；经过服务层的删除也会触发统计，结果相同
+func (h *recordCounter) Apply(events []*clientv3.Event, revision int64) {
+	zones := make(map[string]bool) // {tenant}/{zone}
+	for _, ev := range events {
+		if ev.Type != clientv3.EventTypeDelete || ev.PrevKv == nil || ev.PrevKv.Lease == 0 {
+			continue
+		}
+		t, name, ok := storage.SplitDomainKey(string(ev.Kv.Key))
+		if !ok {
+			continue
+		}
+		zone, _, _ := strings.Cut(name, "/")
+		zones[tenant.Of(t)+"/"+zone] = true
+	}
+
+	for key := range zones {
+		t, zone, _ := strings.Cut(key, "/")
+		if _, err := h.s.zoneStorage.ReconcileRecordCount(tenant.WithTenant(h.ctx, t), zone); err != nil && h.ctx.Err() == nil {
+			logger.Log.WithError(err).WithField("zone", zone).Error("Failed to reconcile zone record count")
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/storage/etcd/etcdtest"
+	"dancer/internal/tenant"
+	"github.com/sirupsen/logrus"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// waitRecordCount 等待 example.com 的记录数量变为 want
+func waitRecordCount(t *testing.T, zoneStorage *etcd.ZoneStorage, want int) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		zone, err := zoneStorage.GetZone(context.Background(), "example.com")
+		if err != nil {
+			t.Fatalf("GetZone: %v", err)
+		}
+		if zone.RecordCount == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("record_count = %d, want %d", zone.RecordCount, want)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// TestRecordCountLeaseExpiry 临时 Domain 的租约到期后 Zone 的记录数量随之减少，启动时校正已有的偏差
+func TestRecordCountLeaseExpiry(t *testing.T) {
+	logger.Log = logrus.New()
+	client, cfg := etcdtest.Start(t)
+	zoneStorage := etcd.NewZoneStorage(client)
+	domainService := NewDomainService(zoneStorage, etcd.NewDomainStorage(client, cfg))
+	ctx := context.Background()
+
+	if err := zoneStorage.CreateZone(ctx, &models.Zone{Zone: "example.com"}); err != nil {
+		t.Fatalf("CreateZone: %v", err)
+	}
+	for _, req := range []*models.CreateDomainRequest{
+		{Zone: "example.com", Domain: "www", IPs: []string{"192.0.2.1"}, TTL: 300},
+		{Zone: "example.com", Domain: "tmp", IPs: []string{"192.0.2.2"}, TTL: 300, LeaseTTL: 3600},
+	} {
+		if _, err := domainService.CreateDomain(ctx, req); err != nil {
+			t.Fatalf("CreateDomain: %v", err)
+		}
+	}
+
+	// 启动前的偏差在成为 leader 后被校正
+	if err := zoneStorage.UpdateZoneRecordCount(ctx, "example.com", 7); err != nil {
+		t.Fatalf("UpdateZoneRecordCount: %v", err)
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		NewRecordCountService(zoneStorage, client).Run(runCtx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	waitRecordCount(t, zoneStorage, 2)
+
+	// 撤销租约与租约到期相同：etcd 删除绑定的 key，不经过服务层
+	resp, err := client.GetClient().Get(ctx, storage.DomainPrefix(tenant.Default)+"example.com/tmp")
+	if err != nil || len(resp.Kvs) != 1 || resp.Kvs[0].Lease == 0 {
+		t.Fatalf("leased domain not found: %v", err)
+	}
+	if _, err := client.GetClient().Revoke(ctx, clientv3.LeaseID(resp.Kvs[0].Lease)); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	waitRecordCount(t, zoneStorage, 1)
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+
+	"dancer/internal/errors"
+	"dancer/internal/models"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tracing"
+)
+
+// defaultInstanceLeaseTTL 动态实例默认租约时长 (秒)
+const defaultInstanceLeaseTTL = 30
+
+// RegistryService 服务注册业务逻辑
+// 动态实例只向 Domain 追加自己的 IP，不修改 Domain 的静态 IP 列表
+type RegistryService struct {
+	zoneStorage   *etcd.ZoneStorage
+	domainStorage *etcd.DomainStorage
+}
+
+func NewRegistryService(zoneStorage *etcd.ZoneStorage, domainStorage *etcd.DomainStorage) *RegistryService {
+	return &RegistryService{
+		zoneStorage:   zoneStorage,
+		domainStorage: domainStorage,
+	}
+}
+
+// Register 注册动态实例
+func (s *RegistryService) Register(ctx context.Context, token *models.APIToken, req *models.RegisterInstanceRequest) (*models.Instance, error) {
+	ctx, span := tracing.Start(ctx, "RegistryService.Register")
+	defer span.End()
+
+	if !token.Allows(req.Zone, req.Domain) {
+		return nil, errors.ErrForbidden
+	}
+
+	// 检查 Zone 是否存在
+	_, err := s.zoneStorage.GetZone(ctx, req.Zone)
+	if err != nil {
+		return nil, errors.ErrZoneNotFound
+	}
+
+	leaseTTL := req.LeaseTTL
+	if leaseTTL == 0 {
+		leaseTTL = defaultInstanceLeaseTTL
+	}
+
+	inst := &models.Instance{
+		Zone:       req.Zone,
+		Domain:     req.Domain,
+		InstanceID: req.InstanceID,
+		IP:         req.IP,
+		LeaseTTL:   leaseTTL,
+		TokenID:    token.ID,
+	}
+
+	if err := s.domainStorage.RegisterInstance(ctx, inst); err != nil {
+		return nil, err
+	}
+
+	return inst, nil
+}
+
+// Heartbeat 实例心跳
+func (s *RegistryService) Heartbeat(ctx context.Context, token *models.APIToken, req *models.HeartbeatInstanceRequest) (*models.Instance, error) {
+	ctx, span := tracing.Start(ctx, "RegistryService.Heartbeat")
+	defer span.End()
+
+	if !token.Allows(req.Zone, req.Domain) {
+		return nil, errors.ErrForbidden
+	}
+
+	if err := s.checkOwner(ctx, token, req.Zone, req.Domain, req.InstanceID); err != nil {
+		return nil, err
+	}
+
+	return s.domainStorage.HeartbeatInstance(ctx, req.Zone, req.Domain, req.InstanceID)
+}
+
+// Deregister 注销动态实例
+func (s *RegistryService) Deregister(ctx context.Context, token *models.APIToken, req *models.DeregisterInstanceRequest) error {
+	ctx, span := tracing.Start(ctx, "RegistryService.Deregister")
+	defer span.End()
+
+	if !token.Allows(req.Zone, req.Domain) {
+		return errors.ErrForbidden
+	}
+
+	if err := s.checkOwner(ctx, token, req.Zone, req.Domain, req.InstanceID); err != nil {
+		return err
+	}
+
+	return s.domainStorage.DeregisterInstance(ctx, req.Zone, req.Domain, req.InstanceID)
+}
+
+// checkOwner 检查实例是否由该 Token 注册，其他 Token 不能操作
+func (s *RegistryService) checkOwner(ctx context.Context, token *models.APIToken, zone, domain, instanceID string) error {
+	inst, err := s.domainStorage.GetInstance(ctx, zone, domain, instanceID)
+	if err != nil {
+		return err
+	}
+	if inst.TokenID != token.ID {
+		return errors.ErrForbidden
+	}
+	return nil
+}
+
+// ListInstances 列出 Domain 下所有动态实例
+func (s *RegistryService) ListInstances(ctx context.Context, req *models.ListInstancesRequest) ([]*models.Instance, error) {
+	ctx, span := tracing.Start(ctx, "RegistryService.ListInstances")
+	defer span.End()
+
+	// 检查 Domain 是否存在
+	_, err := s.domainStorage.GetDomain(ctx, req.Zone, req.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.domainStorage.ListInstances(ctx, req.Zone, req.Domain)
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/storage/etcd/etcdtest"
+	"github.com/sirupsen/logrus"
+)
+
+// TestRegistryInstanceOwner 实例只能由注册它的 Token 重新注册、心跳和注销
+func TestRegistryInstanceOwner(t *testing.T) {
+	logger.Log = logrus.New()
+	client, cfg := etcdtest.Start(t)
+	zoneStorage := etcd.NewZoneStorage(client)
+	domainService := NewDomainService(zoneStorage, etcd.NewDomainStorage(client, cfg))
+	s := NewRegistryService(zoneStorage, etcd.NewDomainStorage(client, cfg))
+
+	ctx := context.Background()
+	if err := zoneStorage.CreateZone(ctx, &models.Zone{Zone: "example.com"}); err != nil {
+		t.Fatalf("CreateZone: %v", err)
+	}
+	if _, err := domainService.CreateDomain(ctx, &models.CreateDomainRequest{
+		Zone: "example.com", Domain: "www", IPs: []string{"192.0.2.1"},
+	}); err != nil {
+		t.Fatalf("CreateDomain: %v", err)
+	}
+
+	owner := &models.APIToken{ID: "1", Zone: "example.com"}
+	other := &models.APIToken{ID: "2", Zone: "example.com"}
+	if _, err := s.Register(ctx, owner, &models.RegisterInstanceRequest{
+		Zone: "example.com", Domain: "www", InstanceID: "web-1", IP: "10.0.0.1", LeaseTTL: 30,
+	}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	if _, err := s.Register(ctx, other, &models.RegisterInstanceRequest{
+		Zone: "example.com", Domain: "www", InstanceID: "web-1", IP: "10.0.0.2", LeaseTTL: 30,
+	}); err != errors.ErrForbidden {
+		t.Errorf("Register by other token: err = %v, want ErrForbidden", err)
+	}
+	if _, err := s.Heartbeat(ctx, other, &models.HeartbeatInstanceRequest{
+		Zone: "example.com", Domain: "www", InstanceID: "web-1",
+	}); err != errors.ErrForbidden {
+		t.Errorf("Heartbeat by other token: err = %v, want ErrForbidden", err)
+	}
+	if err := s.Deregister(ctx, other, &models.DeregisterInstanceRequest{
+		Zone: "example.com", Domain: "www", InstanceID: "web-1",
+	}); err != errors.ErrForbidden {
+		t.Errorf("Deregister by other token: err = %v, want ErrForbidden", err)
+	}
+
+	instances, err := s.ListInstances(ctx, &models.ListInstancesRequest{Zone: "example.com", Domain: "www"})
+	if err != nil {
+		t.Fatalf("ListInstances: %v", err)
+	}
+	if len(instances) != 1 || instances[0].IP != "10.0.0.1" {
+		t.Fatalf("instances = %+v, want web-1 at 10.0.0.1", instances)
+	}
+
+	if _, err := s.Heartbeat(ctx, owner, &models.HeartbeatInstanceRequest{
+		Zone: "example.com", Domain: "www", InstanceID: "web-1",
+	}); err != nil {
+		t.Errorf("Heartbeat by owner: %v", err)
+	}
+	if err := s.Deregister(ctx, owner, &models.DeregisterInstanceRequest{
+		Zone: "example.com", Domain: "www", InstanceID: "web-1",
+	}); err != nil {
+		t.Errorf("Deregister by owner: %v", err)
+	}
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"dancer/internal/config"
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tenant"
+	"dancer/internal/tracing"
+)
+
+// scheduleOpTimeout 单个定时变更执行的超时时间
+const scheduleOpTimeout = 30 * time.Second
+
+// ScheduleService 定时变更业务逻辑
+// 变更由 leader 副本在到期时通过 DomainService 执行
+type ScheduleService struct {
+	scheduleStorage *etcd.ScheduleStorage
+	zoneStorage     *etcd.ZoneStorage
+	domainService   *DomainService
+	etcdClient      *etcd.Client
+	pollInterval    time.Duration
+}
+
+func NewScheduleService(scheduleStorage *etcd.ScheduleStorage, zoneStorage *etcd.ZoneStorage, domainService *DomainService, etcdClient *etcd.Client, cfg *config.Config) *ScheduleService {
+	return &ScheduleService{
+		scheduleStorage: scheduleStorage,
+		zoneStorage:     zoneStorage,
+		domainService:   domainService,
+		etcdClient:      etcdClient,
+		pollInterval:    time.Duration(cfg.Scheduler.PollInterval) * time.Second,
+	}
+}
+
+// ListSchedules 列出定时变更，按执行时间排序
+func (s *ScheduleService) ListSchedules(ctx context.Context, req *models.ListSchedulesRequest) ([]*models.ScheduledChange, error) {
+	ctx, span := tracing.Start(ctx, "ScheduleService.ListSchedules")
+	defer span.End()
+
+	schedules, err := s.scheduleStorage.ListSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.ScheduledChange, 0, len(schedules))
+	for _, sc := range schedules {
+		if req.Zone != "" && sc.Zone != req.Zone {
+			continue
+		}
+		if req.Status != "" && sc.Status != req.Status {
+			continue
+		}
+		result = append(result, sc)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ExecuteAt < result[j].ExecuteAt
+	})
+	return result, nil
+}
+
+// GetSchedule 获取定时变更
+func (s *ScheduleService) GetSchedule(ctx context.Context, id string) (*models.ScheduledChange, error) {
+	ctx, span := tracing.Start(ctx, "ScheduleService.GetSchedule")
+	defer span.End()
+
+	return s.scheduleStorage.GetSchedule(ctx, id)
+}
+
+// CreateSchedule 创建定时变更
+func (s *ScheduleService) CreateSchedule(ctx context.Context, userID string, req *models.CreateScheduleRequest) (*models.ScheduledChange, error) {
+	ctx, span := tracing.Start(ctx, "ScheduleService.CreateSchedule")
+	defer span.End()
+
+	now := time.Now().Unix()
+	if req.ExecuteAt <= now {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	// 检查 Zone 是否存在，需要审批的 Zone 不允许定时变更
+	zone, err := s.zoneStorage.GetZone(ctx, req.Zone)
+	if err != nil {
+		return nil, err
+	}
+	if zone.ApprovalRequired {
+		return nil, apperrors.ErrApprovalRequired
+	}
+
+	sc := &models.ScheduledChange{
+		ID:           fmt.Sprintf("%d", time.Now().UnixMilli()),
+		Zone:         req.Zone,
+		Domain:       req.Domain,
+		IPs:          req.IPs,
+		TTL:          req.TTL,
+		ExecuteAt:    req.ExecuteAt,
+		LowerTTL:     req.LowerTTL,
+		LowerTTLLead: req.LowerTTLLead,
+		Status:       models.ScheduleStatusPending,
+		CreatedBy:    userID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := s.scheduleStorage.CreateSchedule(ctx, sc); err != nil {
+		return nil, err
+	}
+
+	return sc, nil
+}
+
+// CancelSchedule 取消定时变更，仅 pending 状态可取消
+// 已提前降低 TTL 时先恢复降低前的 TTL，恢复失败时定时变更保持 pending，可以重新取消
+func (s *ScheduleService) CancelSchedule(ctx context.Context, id string) (*models.ScheduledChange, error) {
+	ctx, span := tracing.Start(ctx, "ScheduleService.CancelSchedule")
+	defer span.End()
+
+	sc, err := s.scheduleStorage.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sc.Status != models.ScheduleStatusPending {
+		return nil, apperrors.ErrScheduleNotPending
+	}
+
+	if err := s.restoreTTL(ctx, sc); err != nil {
+		return nil, err
+	}
+
+	sc.Status = models.ScheduleStatusCancelled
+	sc.UpdatedAt = time.Now().Unix()
+	if err := s.scheduleStorage.UpdateSchedule(ctx, sc); err != nil {
+		return nil, err
+	}
+
+	return sc, nil
+}
+
+// Run 启动定时变更执行器，仅 leader 副本执行变更，阻塞直到 ctx 取消
+func (s *ScheduleService) Run(ctx context.Context) {
+	s.etcdClient.RunAsLeader(ctx, "scheduler", s.loop)
+}
+
+// loop leader 期间的轮询循环
+func (s *ScheduleService) loop(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick 检查并执行所有租户到期的定时变更，每个定时变更以所属租户的身份执行
+func (s *ScheduleService) tick(ctx context.Context) {
+	schedules, err := s.scheduleStorage.ListAllSchedules(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Log.WithError(err).Error("Failed to list scheduled changes")
+		}
+		return
+	}
+
+	now := time.Now().Unix()
+	for _, sc := range schedules {
+		if ctx.Err() != nil {
+			return
+		}
+
+		scCtx := tenant.WithTenant(ctx, sc.Tenant)
+		switch sc.Status {
+		case models.ScheduleStatusPending:
+			if sc.LowerTTLDue(now) && now < sc.ExecuteAt {
+				s.lowerTTL(scCtx, sc)
+			}
+			if now >= sc.ExecuteAt {
+				s.execute(scCtx, sc)
+			}
+		case models.ScheduleStatusRunning:
+			// 上一任 leader 执行中断，记录集替换是幂等的，直接重新执行
+			s.execute(scCtx, sc)
+		}
+	}
+}
+
+// lowerTTL 执行前提前降低 Domain 的 TTL，使旧记录尽快从缓存中过期
+func (s *ScheduleService) lowerTTL(ctx context.Context, sc *models.ScheduledChange) {
+	opCtx, cancel := context.WithTimeout(ctx, scheduleOpTimeout)
+	defer cancel()
+
+	log := logger.Log.WithField("schedule", sc.ID)
+
+	domain, err := s.domainService.GetDomain(opCtx, &models.GetDomainRequest{Zone: sc.Zone, Domain: sc.Domain})
+	switch {
+	case errors.Is(err, apperrors.ErrDomainNotFound):
+		// Domain 尚不存在，无需降低
+	case err != nil:
+		log.WithError(err).Error("Failed to load domain for TTL lowering")
+		return
+	case domain.TTL > sc.LowerTTL:
+		// 先记录降低前的 TTL，降低后记录失败时不会丢失原值
+		sc.OriginalTTL = domain.TTL
+		sc.UpdatedAt = time.Now().Unix()
+		if err := s.scheduleStorage.UpdateSchedule(opCtx, sc); err != nil {
+			log.WithError(err).Error("Failed to record original TTL before lowering")
+			return
+		}
+
+		_, err := s.domainService.UpdateDomain(opCtx, &models.UpdateDomainRequest{
+			Zone:   sc.Zone,
+			Domain: sc.Domain,
+			IPs:    domain.IPs,
+			TTL:    sc.LowerTTL,
+		})
+		if err != nil {
+			log.WithError(err).Error("Failed to lower TTL ahead of scheduled change")
+			return
+		}
+		log.Infof("Lowered TTL of %s.%s from %d to %d", sc.Domain, sc.Zone, domain.TTL, sc.LowerTTL)
+	}
+
+	sc.TTLLoweredAt = time.Now().Unix()
+	sc.UpdatedAt = sc.TTLLoweredAt
+	if err := s.scheduleStorage.UpdateSchedule(opCtx, sc); err != nil {
+		log.WithError(err).Error("Failed to record TTL lowering")
+	}
+}
+
+// restoreTTL 将提前降低的 TTL 恢复为降低前的值
+// 降低 TTL 后记录降低时间失败时也会恢复；Domain 已被删除或 TTL 不是降低后的值时保持不变
+func (s *ScheduleService) restoreTTL(ctx context.Context, sc *models.ScheduledChange) error {
+	if sc.OriginalTTL == 0 {
+		return nil
+	}
+
+	domain, err := s.domainService.GetDomain(ctx, &models.GetDomainRequest{Zone: sc.Zone, Domain: sc.Domain})
+	if errors.Is(err, apperrors.ErrDomainNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if domain.TTL != sc.LowerTTL {
+		return nil
+	}
+
+	if _, err := s.domainService.UpdateDomain(ctx, &models.UpdateDomainRequest{
+		Zone:   sc.Zone,
+		Domain: sc.Domain,
+		IPs:    domain.IPs,
+		TTL:    sc.OriginalTTL,
+	}); err != nil {
+		return fmt.Errorf("failed to restore ttl of %s.%s: %w", sc.Domain, sc.Zone, err)
+	}
+	logger.Log.WithField("schedule", sc.ID).Infof("Restored TTL of %s.%s to %d after cancellation", sc.Domain, sc.Zone, sc.OriginalTTL)
+	return nil
+}
+
+// execute 执行定时变更并记录结果
+func (s *ScheduleService) execute(ctx context.Context, sc *models.ScheduledChange) {
+	opCtx, cancel := context.WithTimeout(ctx, scheduleOpTimeout)
+	defer cancel()
+
+	log := logger.Log.WithField("schedule", sc.ID)
+
+	// 先标记为执行中，与取消操作互斥
+	if sc.Status == models.ScheduleStatusPending {
+		sc.Status = models.ScheduleStatusRunning
+		sc.UpdatedAt = time.Now().Unix()
+		if err := s.scheduleStorage.UpdateSchedule(opCtx, sc); err != nil {
+			log.WithError(err).Warn("Failed to claim scheduled change")
+			return
+		}
+	}
+
+	if err := s.apply(opCtx, sc); err != nil {
+		sc.Status = models.ScheduleStatusFailed
+		sc.Error = err.Error()
+		log.WithError(err).Error("Scheduled change failed")
+	} else {
+		sc.Status = models.ScheduleStatusSucceeded
+		sc.Error = ""
+		log.Infof("Scheduled change applied to %s.%s", sc.Domain, sc.Zone)
+	}
+
+	sc.ExecutedAt = time.Now().Unix()
+	sc.UpdatedAt = sc.ExecutedAt
+	if err := s.scheduleStorage.UpdateSchedule(opCtx, sc); err != nil {
+		log.WithError(err).Error("Failed to record scheduled change result")
+	}
+}
+
+// apply 将定时变更的记录集写入 Domain，Domain 不存在时创建
+func (s *ScheduleService) apply(ctx context.Context, sc *models.ScheduledChange) error {
+	ttl := sc.TTL
+	if ttl == 0 {
+		ttl = sc.OriginalTTL
+	}
+
+	_, err := s.domainService.UpdateDomain(ctx, &models.UpdateDomainRequest{
+		Zone:   sc.Zone,
+		Domain: sc.Domain,
+		IPs:    sc.IPs,
+		TTL:    ttl,
+	})
+	if !errors.Is(err, apperrors.ErrDomainNotFound) {
+		return err
+	}
+
+	if ttl == 0 {
+		return fmt.Errorf("domain %s.%s does not exist and no ttl was given", sc.Domain, sc.Zone)
+	}
+	_, err = s.domainService.CreateDomain(ctx, &models.CreateDomainRequest{
+		Zone:   sc.Zone,
+		Domain: sc.Domain,
+		IPs:    sc.IPs,
+		TTL:    ttl,
+	})
+	return err
+}
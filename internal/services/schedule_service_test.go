@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/storage/etcd/etcdtest"
+	"github.com/sirupsen/logrus"
+)
+
+// newTestScheduleService 连接进程内 etcd，创建 example.com 及 TTL 为 300 的 www
+func newTestScheduleService(t *testing.T) (*ScheduleService, *DomainService) {
+	t.Helper()
+	logger.Log = logrus.New()
+
+	client, cfg := etcdtest.Start(t)
+	cfg.Scheduler.PollInterval = 1
+	zoneStorage := etcd.NewZoneStorage(client)
+	domainService := NewDomainService(zoneStorage, etcd.NewDomainStorage(client, cfg))
+	s := NewScheduleService(etcd.NewScheduleStorage(client), zoneStorage, domainService, client, cfg)
+
+	ctx := context.Background()
+	if err := zoneStorage.CreateZone(ctx, &models.Zone{Zone: "example.com"}); err != nil {
+		t.Fatalf("CreateZone: %v", err)
+	}
+	if _, err := domainService.CreateDomain(ctx, &models.CreateDomainRequest{
+		Zone: "example.com", Domain: "www", IPs: []string{"192.0.2.1"}, TTL: 300,
+	}); err != nil {
+		t.Fatalf("CreateDomain: %v", err)
+	}
+	return s, domainService
+}
+
+// createLoweringSchedule 创建一小时后执行、现在就应降低 TTL 到 30 的定时变更，并执行一次检查使 TTL 降低
+func createLoweringSchedule(t *testing.T, s *ScheduleService, domainService *DomainService) *models.ScheduledChange {
+	t.Helper()
+	ctx := context.Background()
+
+	sc, err := s.CreateSchedule(ctx, "1", &models.CreateScheduleRequest{
+		Zone:         "example.com",
+		Domain:       "www",
+		IPs:          []string{"192.0.2.2"},
+		ExecuteAt:    time.Now().Add(time.Hour).Unix(),
+		LowerTTL:     30,
+		LowerTTLLead: 7200,
+	})
+	if err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+
+	s.tick(ctx)
+	if ttl := domainTTL(t, domainService); ttl != 30 {
+		t.Fatalf("ttl = %d after lowering, want 30", ttl)
+	}
+	return sc
+}
+
+// domainTTL 返回 www.example.com 的 TTL
+func domainTTL(t *testing.T, domainService *DomainService) int {
+	t.Helper()
+	domain, err := domainService.GetDomain(context.Background(), &models.GetDomainRequest{Zone: "example.com", Domain: "www"})
+	if err != nil {
+		t.Fatalf("GetDomain: %v", err)
+	}
+	return domain.TTL
+}
+
+// TestCancelScheduleRestoresTTL 提前降低 TTL 后取消，TTL 恢复为降低前的值，IP 保持不变
+func TestCancelScheduleRestoresTTL(t *testing.T) {
+	s, domainService := newTestScheduleService(t)
+	sc := createLoweringSchedule(t, s, domainService)
+
+	cancelled, err := s.CancelSchedule(context.Background(), sc.ID)
+	if err != nil {
+		t.Fatalf("CancelSchedule: %v", err)
+	}
+	if cancelled.Status != models.ScheduleStatusCancelled {
+		t.Errorf("status = %s, want cancelled", cancelled.Status)
+	}
+
+	domain, err := domainService.GetDomain(context.Background(), &models.GetDomainRequest{Zone: "example.com", Domain: "www"})
+	if err != nil {
+		t.Fatalf("GetDomain: %v", err)
+	}
+	if domain.TTL != 300 {
+		t.Errorf("ttl = %d after cancel, want 300", domain.TTL)
+	}
+	if len(domain.IPs) != 1 || domain.IPs[0] != "192.0.2.1" {
+		t.Errorf("ips = %v after cancel, want [192.0.2.1]", domain.IPs)
+	}
+}
+
+// TestCancelScheduleKeepsChangedTTL TTL 在降低后被修改过时，取消不覆盖修改后的值
+func TestCancelScheduleKeepsChangedTTL(t *testing.T) {
+	s, domainService := newTestScheduleService(t)
+	sc := createLoweringSchedule(t, s, domainService)
+
+	if _, err := domainService.UpdateDomain(context.Background(), &models.UpdateDomainRequest{
+		Zone: "example.com", Domain: "www", IPs: []string{"192.0.2.1"}, TTL: 60,
+	}); err != nil {
+		t.Fatalf("UpdateDomain: %v", err)
+	}
+
+	if _, err := s.CancelSchedule(context.Background(), sc.ID); err != nil {
+		t.Fatalf("CancelSchedule: %v", err)
+	}
+	if ttl := domainTTL(t, domainService); ttl != 60 {
+		t.Errorf("ttl = %d after cancel, want 60", ttl)
+	}
+}
+
+// TestCancelAfterLostTTLLowering 降低 TTL 后记录降低时间失败时，降低前的 TTL 已经保存，取消时仍能恢复
+func TestCancelAfterLostTTLLowering(t *testing.T) {
+	s, domainService := newTestScheduleService(t)
+	sc := createLoweringSchedule(t, s, domainService)
+	ctx := context.Background()
+
+	// 模拟降低 TTL 后写入降低时间失败
+	stored, err := s.scheduleStorage.GetSchedule(ctx, sc.ID)
+	if err != nil {
+		t.Fatalf("GetSchedule: %v", err)
+	}
+	if stored.OriginalTTL != 300 {
+		t.Fatalf("original ttl = %d, want 300", stored.OriginalTTL)
+	}
+	stored.TTLLoweredAt = 0
+	if err := s.scheduleStorage.UpdateSchedule(ctx, stored); err != nil {
+		t.Fatalf("UpdateSchedule: %v", err)
+	}
+
+	if _, err := s.CancelSchedule(ctx, sc.ID); err != nil {
+		t.Fatalf("CancelSchedule: %v", err)
+	}
+	if ttl := domainTTL(t, domainService); ttl != 300 {
+		t.Errorf("ttl = %d after cancel, want 300", ttl)
+	}
+}
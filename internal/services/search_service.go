@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	apperrors "dancer/internal/errors"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tenant"
+	"dancer/internal/tracing"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// defaultSearchLimit 搜索默认返回条数
+const defaultSearchLimit = 100
+
+// SearchService 跨 Zone 的 Domain 搜索
+// 在内存中维护所有 Domain 及动态实例的索引，通过 etcd watch 保持与存储一致，搜索不访问 etcd
+type SearchService struct {
+	etcdClient *etcd.Client
+
+	mu        sync.RWMutex
+	domains   map[string]*models.Domain  // zone/domain -> Domain
+	tenants   map[string]string          // zone/domain -> 所属租户
+	staticIPs map[string]map[string]bool // 规范化后的 IP -> zone/domain 集合
+	instances map[string]*models.Instance
+	revision  int64
+	domainsOK bool // Domain 索引已完成首次加载
+	instOK    bool // 动态实例索引已完成首次加载
+}
+
+func NewSearchService(etcdClient *etcd.Client) *SearchService {
+	return &SearchService{
+		etcdClient: etcdClient,
+		domains:    make(map[string]*models.Domain),
+		tenants:    make(map[string]string),
+		staticIPs:  make(map[string]map[string]bool),
+		instances:  make(map[string]*models.Instance),
+	}
+}
+
+// Run 启动索引同步，阻塞直到 ctx 取消
+func (s *SearchService) Run(ctx context.Context) {
+	go s.etcdClient.WatchPrefix(ctx, storage.InstanceKeyPrefix, &instanceIndexer{s})
+	s.etcdClient.WatchPrefix(ctx, storage.DomainKeyPrefix, &domainIndexer{s})
+}
+
+// Search 搜索 ctx 所属租户的 Domain，结果按完整域名排序
+func (s *SearchService) Search(ctx context.Context, req *models.SearchDomainsRequest) (*models.SearchResult, error) {
+	ctx, span := tracing.Start(ctx, "SearchService.Search")
+	defer span.End()
+
+	kind, query := resolveSearch(req)
+	match, err := searchMatcher(kind, query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.domainsOK || !s.instOK {
+		return nil, apperrors.ErrSearchIndexNotReady
+	}
+
+	dynamicIPs := make(map[string][]string)
+	for _, inst := range s.instances {
+		key := inst.Zone + "/" + inst.Domain
+		dynamicIPs[key] = append(dynamicIPs[key], inst.IP)
+	}
+
+	t := tenant.FromContext(ctx)
+	var hits []*models.Domain
+	for key, d := range s.candidates(kind, query) {
+		if s.tenants[key] != t || (req.Zone != "" && d.Zone != req.Zone) {
+			continue
+		}
+		// 返回副本，避免调用方修改索引
+		domain := *d
+		domain.DynamicIPs = dynamicIPs[key]
+		if match(&domain) {
+			hits = append(hits, &domain)
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		return hits[i].Name < hits[j].Name
+	})
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	result := &models.SearchResult{Total: len(hits), Revision: s.revision}
+	result.Domains = hits[:min(limit, len(hits))]
+	return result, nil
+}
+
+// IndexStatus 返回索引是否已完成首次加载及已同步到的 revision
+func (s *SearchService) IndexStatus() (ready bool, revision int64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.domainsOK && s.instOK, s.revision
+}
+
+// Counts 返回索引中的 Domain、静态 IP 与动态实例数量，索引未就绪时返回 ErrSearchIndexNotReady
+func (s *SearchService) Counts() (domains, staticIPs, instances int, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.domainsOK || !s.instOK {
+		return 0, 0, 0, apperrors.ErrSearchIndexNotReady
+	}
+	for _, d := range s.domains {
+		staticIPs += len(d.IPs)
+	}
+	return len(s.domains), staticIPs, len(s.instances), nil
+}
+
+// candidates 返回需要逐一匹配的 Domain，按 IP 精确搜索时直接查 IP 索引，调用方需持有读锁
+func (s *SearchService) candidates(kind models.SearchType, query string) map[string]*models.Domain {
+	if kind != models.SearchIP {
+		return s.domains
+	}
+
+	ip := normalizeIP(query)
+	result := make(map[string]*models.Domain)
+	for key := range s.staticIPs[ip] {
+		result[key] = s.domains[key]
+	}
+	for _, inst := range s.instances {
+		key := inst.Zone + "/" + inst.Domain
+		if d, ok := s.domains[key]; ok && normalizeIP(inst.IP) == ip {
+			result[key] = d
+		}
+	}
+	return result
+}
+
+// resolveSearch 确定搜索类型
+// auto 模式下查询串是 IP 时按 IP 搜索，是 CIDR 时按网段搜索，否则按名称搜索
+func resolveSearch(req *models.SearchDomainsRequest) (models.SearchType, string) {
+	query := strings.TrimSpace(req.Query)
+	if req.Type != "" && req.Type != models.SearchAuto {
+		return req.Type, query
+	}
+
+	switch {
+	case net.ParseIP(query) != nil:
+		return models.SearchIP, query
+	case strings.Contains(query, "/"):
+		return models.SearchCIDR, query
+	default:
+		return models.SearchName, query
+	}
+}
+
+// searchMatcher 根据搜索类型生成匹配函数
+func searchMatcher(kind models.SearchType, query string) (func(d *models.Domain) bool, error) {
+	switch kind {
+	case models.SearchIP:
+		if net.ParseIP(query) == nil {
+			return nil, apperrors.ErrInvalidInput
+		}
+		matchIP := ipMatcher(query)
+		return func(d *models.Domain) bool { return matchIP(d.IPs) || matchIP(d.DynamicIPs) }, nil
+
+	case models.SearchCIDR:
+		if _, _, err := net.ParseCIDR(query); err != nil {
+			return nil, apperrors.ErrInvalidInput
+		}
+		matchIP := ipMatcher(query)
+		return func(d *models.Domain) bool { return matchIP(d.IPs) || matchIP(d.DynamicIPs) }, nil
+
+	case models.SearchText:
+		// 在完整域名及记录内容（IP）中做子串匹配
+		return func(d *models.Domain) bool {
+			if containsFold(d.Name, query) {
+				return true
+			}
+			for _, ips := range [][]string{d.IPs, d.DynamicIPs} {
+				for _, ip := range ips {
+					if containsFold(ip, query) {
+						return true
+					}
+				}
+			}
+			return false
+		}, nil
+
+	default:
+		query = strings.TrimSuffix(query, ".")
+		return func(d *models.Domain) bool { return containsFold(d.Name, query) }, nil
+	}
+}
+
+// putDomain 写入 Domain 索引，调用方需持有写锁
+func (s *SearchService) putDomain(key, tenantID string, d *models.Domain) {
+	s.deleteDomain(key)
+	s.domains[key] = d
+	s.tenants[key] = tenant.Of(tenantID)
+	for _, ip := range d.IPs {
+		ip = normalizeIP(ip)
+		if s.staticIPs[ip] == nil {
+			s.staticIPs[ip] = make(map[string]bool)
+		}
+		s.staticIPs[ip][key] = true
+	}
+}
+
+// deleteDomain 删除 Domain 索引，调用方需持有写锁
+func (s *SearchService) deleteDomain(key string) {
+	old, ok := s.domains[key]
+	if !ok {
+		return
+	}
+	delete(s.domains, key)
+	delete(s.tenants, key)
+	for _, ip := range old.IPs {
+		ip = normalizeIP(ip)
+		delete(s.staticIPs[ip], key)
+		if len(s.staticIPs[ip]) == 0 {
+			delete(s.staticIPs, ip)
+		}
+	}
+}
+
+// normalizeIP 规范化 IP 文本，IPv6 不同写法得到相同结果
+func normalizeIP(ip string) string {
+	if parsed := net.ParseIP(ip); parsed != nil {
+		return parsed.String()
+	}
+	return ip
+}
+
+// domainIndexer 将 /dancer/domains/ 的变化同步到索引
+type domainIndexer struct {
+	s *SearchService
+}
+
+func (x *domainIndexer) Reset(kvs []*mvccpb.KeyValue, revision int64) {
+	x.s.mu.Lock()
+	defer x.s.mu.Unlock()
+
+	x.s.domains = make(map[string]*models.Domain, len(kvs))
+	x.s.tenants = make(map[string]string, len(kvs))
+	x.s.staticIPs = make(map[string]map[string]bool)
+	for _, kv := range kvs {
+		t, key, ok := storage.SplitDomainKey(string(kv.Key))
+		if !ok {
+			continue
+		}
+		if d, ok := decodeDomain(kv.Value); ok {
+			x.s.putDomain(key, t, d)
+		}
+	}
+	x.s.revision = revision
+	x.s.domainsOK = true
+}
+
+func (x *domainIndexer) Apply(events []*clientv3.Event, revision int64) {
+	x.s.mu.Lock()
+	defer x.s.mu.Unlock()
+
+	for _, ev := range events {
+		t, key, ok := storage.SplitDomainKey(string(ev.Kv.Key))
+		if !ok {
+			continue
+		}
+		if ev.Type == clientv3.EventTypeDelete {
+			x.s.deleteDomain(key)
+			continue
+		}
+		if d, ok := decodeDomain(ev.Kv.Value); ok {
+			x.s.putDomain(key, t, d)
+		}
+	}
+	x.s.revision = max(x.s.revision, revision)
+}
+
+// instanceIndexer 将 /dancer/instances/ 的变化同步到索引
+type instanceIndexer struct {
+	s *SearchService
+}
+
+func (x *instanceIndexer) Reset(kvs []*mvccpb.KeyValue, revision int64) {
+	x.s.mu.Lock()
+	defer x.s.mu.Unlock()
+
+	x.s.instances = make(map[string]*models.Instance, len(kvs))
+	for _, kv := range kvs {
+		var inst models.Instance
+		if err := json.Unmarshal(kv.Value, &inst); err == nil {
+			x.s.instances[string(kv.Key)] = &inst
+		}
+	}
+	x.s.instOK = true
+}
+
+func (x *instanceIndexer) Apply(events []*clientv3.Event, revision int64) {
+	x.s.mu.Lock()
+	defer x.s.mu.Unlock()
+
+	for _, ev := range events {
+		key := string(ev.Kv.Key)
+		if ev.Type == clientv3.EventTypeDelete {
+			delete(x.s.instances, key)
+			continue
+		}
+		var inst models.Instance
+		if err := json.Unmarshal(ev.Kv.Value, &inst); err == nil {
+			x.s.instances[key] = &inst
+		}
+	}
+}
+
+// decodeDomain 解码 Domain 元数据
+func decodeDomain(data []byte) (*models.Domain, bool) {
+	var d models.Domain
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, false
+	}
+	return &d, true
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"dancer/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// desiredDocument 期望状态文件内容
+type desiredDocument struct {
+	Zones []*models.DesiredZone `json:"zones" yaml:"zones"`
+}
+
+// LoadDesiredState 从文件或目录读取期望状态
+// 目录下递归读取 .yaml / .yml / .json 文件并按路径顺序合并，每个文件包含一个 zones 列表；同一 Zone 只能在一个文件中定义
+func LoadDesiredState(path string) ([]*models.DesiredZone, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files = files[:0]
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				// 跳过 .git 等隐藏目录
+				if p != path && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			switch strings.ToLower(filepath.Ext(p)) {
+			case ".yaml", ".yml", ".json":
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+
+	var zones []*models.DesiredZone
+	definedIn := make(map[string]string)
+	for _, file := range files {
+		doc, err := readDesiredDocument(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, dz := range doc.Zones {
+			if dz == nil {
+				continue
+			}
+			if other, ok := definedIn[dz.Zone]; ok {
+				return nil, fmt.Errorf("zone %s is defined in both %s and %s", dz.Zone, other, file)
+			}
+			definedIn[dz.Zone] = file
+			zones = append(zones, dz)
+		}
+	}
+	return zones, nil
+}
+
+// readDesiredDocument 解析单个期望状态文件，JSON 作为 YAML 的子集解析；未知字段视为错误
+func readDesiredDocument(file string) (*desiredDocument, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc desiredDocument
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return &doc, nil
+}
+
+// WriteSyncPlan 以可读格式输出同步计划：+ 创建，~ 修改，- 删除
+func WriteSyncPlan(w io.Writer, plan *models.SyncPlan) {
+	if len(plan.Problems) > 0 {
+		fmt.Fprintln(w, "Desired state is invalid:")
+		for _, problem := range plan.Problems {
+			fmt.Fprintf(w, "  %s\n", problem)
+		}
+		return
+	}
+	if plan.Empty() {
+		fmt.Fprintln(w, "No changes. Current state matches the desired state.")
+		return
+	}
+
+	symbols := map[string]string{"create": "+", "update": "~", "delete": "-", "none": " "}
+	for _, zp := range plan.Zones {
+		line := fmt.Sprintf("%s zone %s", symbols[string(zp.Op)], zp.Zone)
+		switch zp.Op {
+		case models.SyncOpDelete:
+			line += fmt.Sprintf(" (%d domains)", zp.DomainCount)
+		case models.SyncOpCreate, models.SyncOpUpdate:
+			line += fmt.Sprintf(" (approval_required=%t)", zp.ApprovalRequired)
+		}
+		if zp.Status != "" {
+			line += " [" + string(zp.Status)
+			switch {
+			case zp.ChangeRequestID != "":
+				line += " " + zp.ChangeRequestID
+			case zp.Error != "":
+				line += ": " + zp.Error
+			}
+			line += "]"
+		}
+		fmt.Fprintln(w, line)
+
+		for _, diff := range zp.Changes {
+			line := fmt.Sprintf("    %s %s", symbols[string(diff.Op)], diff.Domain)
+			if diff.After != nil {
+				line += fmt.Sprintf(" %s ttl=%d", strings.Join(diff.After.IPs, ","), diff.After.TTL)
+			}
+			fmt.Fprintln(w, line)
+		}
+		for _, skip := range zp.Skipped {
+			fmt.Fprintf(w, "    ! %s skipped: %s\n", skip.Domain, skip.Reason)
+		}
+	}
+
+	s := plan.Summary
+	fmt.Fprintf(w, "\nZones: +%d ~%d -%d, Domains: +%d ~%d -%d\n",
+		s.ZonesCreated, s.ZonesUpdated, s.ZonesDeleted, s.DomainsCreated, s.DomainsUpdated, s.DomainsDeleted)
+}
+
+// MarshalDesiredState 将期望状态编码为可被 LoadDesiredState 读取的文件内容，path 以 .json 结尾时为 JSON，否则为 YAML
+func MarshalDesiredState(path string, zones []*models.DesiredZone) ([]byte, error) {
+	doc := &desiredDocument{Zones: zones}
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
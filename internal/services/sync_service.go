@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tracing"
+	"github.com/go-playground/validator/v10"
+)
+
+// syncChangeRequestTitle 同步提交的变更申请标题
+const syncChangeRequestTitle = "Declarative sync"
+
+// SyncService 声明式同步：对照当前数据生成期望状态的同步计划，并通过 Zone / Domain / 变更申请服务应用
+type SyncService struct {
+	zoneService   *ZoneService
+	domainService *DomainService
+	changeService *ChangeRequestService
+	zoneStorage   *etcd.ZoneStorage
+	domainStorage *etcd.DomainStorage
+	validate      *validator.Validate
+}
+
+func NewSyncService(zoneService *ZoneService, domainService *DomainService, changeService *ChangeRequestService, zoneStorage *etcd.ZoneStorage, domainStorage *etcd.DomainStorage) *SyncService {
+	return &SyncService{
+		zoneService:   zoneService,
+		domainService: domainService,
+		changeService: changeService,
+		zoneStorage:   zoneStorage,
+		domainStorage: domainStorage,
+		validate:      validator.New(),
+	}
+}
+
+// Plan 生成同步计划
+// 期望状态中的 Zone 与其下 Domain 完全由期望状态决定；prune 时删除期望状态中没有的 Zone 与 Domain，否则保留
+// 临时 Domain 不受同步影响；要求审批的 Zone 不能通过同步关闭审批，否则 Domain 变更会绕过变更申请，此时不生成计划
+func (s *SyncService) Plan(ctx context.Context, req *models.SyncRequest) (*models.SyncPlan, error) {
+	ctx, span := tracing.Start(ctx, "SyncService.Plan")
+	defer span.End()
+
+	plan := &models.SyncPlan{
+		Prune:    req.Prune,
+		Problems: s.validateDesired(req.Zones),
+		Zones:    []*models.ZoneSyncPlan{},
+	}
+	if len(plan.Problems) > 0 {
+		return plan, nil
+	}
+
+	zones, err := s.zoneStorage.ListZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]*models.Zone, len(zones))
+	for _, zone := range zones {
+		existing[zone.Zone] = zone
+	}
+
+	desired := make(map[string]bool, len(req.Zones))
+	for _, dz := range req.Zones {
+		desired[dz.Zone] = true
+
+		zp := &models.ZoneSyncPlan{
+			Op:               models.SyncOpNone,
+			Zone:             dz.Zone,
+			ApprovalRequired: dz.ApprovalRequired,
+			Changes:          []*models.DomainChangeDiff{},
+		}
+		var current []*models.Domain
+		if zone := existing[dz.Zone]; zone == nil {
+			zp.Op = models.SyncOpCreate
+			plan.Summary.ZonesCreated++
+		} else {
+			if zone.ApprovalRequired && !dz.ApprovalRequired {
+				plan.Problems = append(plan.Problems, fmt.Sprintf("zone %s: approval_required cannot be turned off by sync, turn it off on the zone first", dz.Zone))
+				continue
+			}
+			if zone.ApprovalRequired != dz.ApprovalRequired {
+				zp.Op = models.SyncOpUpdate
+				plan.Summary.ZonesUpdated++
+			}
+			if current, err = s.domainStorage.ListDomainsByZone(ctx, dz.Zone); err != nil {
+				return nil, err
+			}
+		}
+
+		planDomains(zp, current, dz.Domains, req.Prune, &plan.Summary)
+		if zp.Op != models.SyncOpNone || len(zp.Changes) > 0 || len(zp.Skipped) > 0 {
+			plan.Zones = append(plan.Zones, zp)
+		}
+	}
+
+	if len(plan.Problems) > 0 {
+		plan.Zones = []*models.ZoneSyncPlan{}
+		plan.Summary = models.SyncSummary{}
+		return plan, nil
+	}
+
+	if req.Prune {
+		for _, zone := range zones {
+			if desired[zone.Zone] {
+				continue
+			}
+			count, err := s.domainStorage.GetDomainCountByZone(ctx, zone.Zone)
+			if err != nil {
+				return nil, err
+			}
+			plan.Zones = append(plan.Zones, &models.ZoneSyncPlan{
+				Op:               models.SyncOpDelete,
+				Zone:             zone.Zone,
+				ApprovalRequired: zone.ApprovalRequired,
+				DomainCount:      count,
+				Changes:          []*models.DomainChangeDiff{},
+			})
+			plan.Summary.ZonesDeleted++
+		}
+	}
+
+	return plan, nil
+}
+
+// Apply 生成并应用同步计划，返回带执行结果的计划
+// 各 Zone 独立应用，同一 Zone 的 Domain 变更以 atomic 模式批量应用；某个 Zone 失败不影响其他 Zone。
+// 同步前后都要求审批的 Zone，其 Domain 变更提交为变更申请，由 userID 作为申请人
+func (s *SyncService) Apply(ctx context.Context, userID string, req *models.SyncRequest) (*models.SyncPlan, error) {
+	ctx, span := tracing.Start(ctx, "SyncService.Apply")
+	defer span.End()
+
+	plan, err := s.Plan(ctx, req)
+	if err != nil || len(plan.Problems) > 0 {
+		return plan, err
+	}
+
+	for _, zp := range plan.Zones {
+		if err := s.applyZone(ctx, userID, zp); err != nil {
+			zp.Status, zp.Error = models.SyncStatusFailed, err.Error()
+			logger.Log.WithError(err).WithField("zone", zp.Zone).Error("Failed to sync zone")
+			continue
+		}
+		if zp.Status == "" {
+			zp.Status = models.SyncStatusApplied
+		}
+	}
+
+	plan.Applied = true
+	return plan, nil
+}
+
+// applyZone 应用单个 Zone 的同步计划
+func (s *SyncService) applyZone(ctx context.Context, userID string, zp *models.ZoneSyncPlan) error {
+	switch zp.Op {
+	case models.SyncOpDelete:
+		_, err := s.zoneService.DeleteZone(ctx, &models.DeleteZoneRequest{Zone: zp.Zone, Confirm: zp.Zone})
+		return err
+
+	case models.SyncOpCreate:
+		// 先以不要求审批的状态创建，写入 Domain 后再开启审批
+		if _, err := s.zoneService.CreateZone(ctx, &models.CreateZoneRequest{Zone: zp.Zone}); err != nil {
+			return err
+		}
+		if err := s.applyDomains(ctx, zp); err != nil {
+			return err
+		}
+		return s.updateApproval(ctx, zp)
+
+	case models.SyncOpUpdate:
+		// 同步只会开启审批（关闭审批的计划不会生成），先写入 Domain 再开启
+		if err := s.applyDomains(ctx, zp); err != nil {
+			return err
+		}
+		return s.updateApproval(ctx, zp)
+	}
+
+	// Zone 属性不变：要求审批的 Zone 提交变更申请
+	if len(zp.Changes) == 0 {
+		return nil
+	}
+	if zp.ApprovalRequired {
+		cr, err := s.changeService.ProposeChangeRequest(ctx, userID, &models.ProposeChangeRequest{
+			Zone:    zp.Zone,
+			Title:   syncChangeRequestTitle,
+			Changes: syncChanges(zp),
+		})
+		if err != nil {
+			return err
+		}
+		zp.Status, zp.ChangeRequestID = models.SyncStatusProposed, cr.ID
+		return nil
+	}
+	return s.applyDomains(ctx, zp)
+}
+
+// applyDomains 以 atomic 模式批量应用 Zone 的 Domain 变更
+func (s *SyncService) applyDomains(ctx context.Context, zp *models.ZoneSyncPlan) error {
+	if len(zp.Changes) == 0 {
+		return nil
+	}
+
+	changes := syncChanges(zp)
+	results, err := s.domainService.BatchDomains(ctx, &models.BatchDomainsRequest{
+		Zone:  zp.Zone,
+		Mode:  models.BatchModeAtomic,
+		Items: changes,
+	}, make([]error, len(changes)))
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		if r.Status == models.BatchItemFailed {
+			return fmt.Errorf("%s %s: %s", r.Op, r.Domain, r.Error)
+		}
+	}
+	return nil
+}
+
+// updateApproval 将 Zone 的审批设置更新为期望值
+func (s *SyncService) updateApproval(ctx context.Context, zp *models.ZoneSyncPlan) error {
+	if !zp.ApprovalRequired && zp.Op == models.SyncOpCreate {
+		return nil
+	}
+	approval := zp.ApprovalRequired
+	_, err := s.zoneService.UpdateZone(ctx, &models.UpdateZoneRequest{Zone: zp.Zone, ApprovalRequired: &approval})
+	return err
+}
+
+// validateDesired 校验期望状态，返回发现的问题
+func (s *SyncService) validateDesired(zones []*models.DesiredZone) []string {
+	problems := []string{}
+
+	seenZones := make(map[string]bool, len(zones))
+	for i, dz := range zones {
+		if dz == nil || s.validate.Struct(dz) != nil {
+			problems = append(problems, fmt.Sprintf("zones[%d]: invalid zone", i))
+			continue
+		}
+		if seenZones[dz.Zone] {
+			problems = append(problems, fmt.Sprintf("zone %s: duplicate zone", dz.Zone))
+		}
+		seenZones[dz.Zone] = true
+
+		seenDomains := make(map[string]bool, len(dz.Domains))
+		for j, dd := range dz.Domains {
+			if dd == nil {
+				problems = append(problems, fmt.Sprintf("zone %s: domains[%d]: invalid domain", dz.Zone, j))
+				continue
+			}
+			if err := s.validate.Struct(dd); err != nil {
+				problems = append(problems, fmt.Sprintf("zone %s: domain %q: invalid domain, ips or ttl", dz.Zone, dd.Domain))
+				continue
+			}
+			if seenDomains[dd.Domain] {
+				problems = append(problems, fmt.Sprintf("zone %s: domain %s: duplicate domain", dz.Zone, dd.Domain))
+			}
+			seenDomains[dd.Domain] = true
+		}
+	}
+
+	return problems
+}
+
+// planDomains 对照 Zone 当前的 Domain 生成 Domain 变更，写入 zp 并累计统计
+func planDomains(zp *models.ZoneSyncPlan, current []*models.Domain, desired []*models.DesiredDomain, prune bool, summary *models.SyncSummary) {
+	existing := make(map[string]*models.Domain, len(current))
+	for _, domain := range current {
+		existing[domain.Domain] = domain
+	}
+
+	declared := make(map[string]bool, len(desired))
+	for _, dd := range desired {
+		declared[dd.Domain] = true
+		target := &models.Domain{Domain: dd.Domain, IPs: dd.IPs, TTL: dd.TTL}
+
+		old := existing[dd.Domain]
+		switch {
+		case old != nil && old.IsEphemeral():
+			zp.Skipped = append(zp.Skipped, &models.SyncSkip{Domain: dd.Domain, Reason: "ephemeral domain exists"})
+		case old == nil:
+			zp.Changes = append(zp.Changes, domainDiff(models.ChangeOpCreate, dd.Domain, nil, target))
+			summary.DomainsCreated++
+		case old.TTL != dd.TTL || len(subtractIPs(old.IPs, dd.IPs)) > 0 || len(subtractIPs(dd.IPs, old.IPs)) > 0:
+			zp.Changes = append(zp.Changes, domainDiff(models.ChangeOpUpdate, dd.Domain, old, target))
+			summary.DomainsUpdated++
+		}
+	}
+
+	if prune {
+		for _, domain := range current {
+			if !declared[domain.Domain] && !domain.IsEphemeral() {
+				zp.Changes = append(zp.Changes, domainDiff(models.ChangeOpDelete, domain.Domain, domain, nil))
+				summary.DomainsDeleted++
+			}
+		}
+	}
+
+	sort.SliceStable(zp.Changes, func(i, j int) bool { return zp.Changes[i].Domain < zp.Changes[j].Domain })
+}
+
+// syncChanges 将 Zone 同步计划中的差异转换为 Domain 变更
+func syncChanges(zp *models.ZoneSyncPlan) []*models.DomainChange {
+	changes := make([]*models.DomainChange, len(zp.Changes))
+	for i, diff := range zp.Changes {
+		change := &models.DomainChange{Op: diff.Op, Domain: diff.Domain}
+		if diff.After != nil {
+			change.IPs, change.TTL = diff.After.IPs, diff.After.TTL
+		}
+		changes[i] = change
+	}
+	return changes
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/publisher"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/storage/etcd/etcdtest"
+	"github.com/sirupsen/logrus"
+)
+
+// TestSyncCannotDisableApproval 关闭要求审批的 Zone 的审批时拒绝计划，Zone 与 Domain 都不修改
+func TestSyncCannotDisableApproval(t *testing.T) {
+	logger.Log = logrus.New()
+	client, cfg := etcdtest.Start(t)
+	zoneStorage := etcd.NewZoneStorage(client)
+	domainStorage := etcd.NewDomainStorage(client, cfg)
+	publishers, err := publisher.NewRegistry(cfg, func(name string) publisher.Publisher { return domainStorage.View(name) })
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	zoneService := NewZoneService(zoneStorage, domainStorage, etcd.NewTrashStorage(client, cfg), publishers)
+	domainService := NewDomainService(zoneStorage, domainStorage)
+	changeService := NewChangeRequestService(etcd.NewChangeRequestStorage(client), zoneStorage, domainStorage)
+	s := NewSyncService(zoneService, domainService, changeService, zoneStorage, domainStorage)
+
+	ctx := context.Background()
+	if err := zoneStorage.CreateZone(ctx, &models.Zone{Zone: "example.com", ApprovalRequired: true}); err != nil {
+		t.Fatalf("CreateZone: %v", err)
+	}
+
+	plan, err := s.Apply(ctx, "1", &models.SyncRequest{Zones: []*models.DesiredZone{{
+		Zone:    "example.com",
+		Domains: []*models.DesiredDomain{{Domain: "www", IPs: []string{"192.0.2.1"}, TTL: 300}},
+	}}})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(plan.Problems) != 1 || plan.Applied || len(plan.Zones) != 0 {
+		t.Fatalf("plan = %d problems, applied %v, %d zones; want 1 problem and nothing applied", len(plan.Problems), plan.Applied, len(plan.Zones))
+	}
+
+	zone, err := zoneStorage.GetZone(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("GetZone: %v", err)
+	}
+	if !zone.ApprovalRequired {
+		t.Error("approval_required was turned off by sync")
+	}
+	if exists, err := domainStorage.DomainExists(ctx, "example.com", "www"); err != nil || exists {
+		t.Errorf("DomainExists = %v, %v; want false", exists, err)
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tenant"
+	"dancer/internal/tracing"
+)
+
+// TenantService 租户业务逻辑
+type TenantService struct {
+	tenantStorage *etcd.TenantStorage
+	zoneStorage   *etcd.ZoneStorage
+	userService   *UserService
+}
+
+func NewTenantService(tenantStorage *etcd.TenantStorage, zoneStorage *etcd.ZoneStorage, userService *UserService) *TenantService {
+	return &TenantService{
+		tenantStorage: tenantStorage,
+		zoneStorage:   zoneStorage,
+		userService:   userService,
+	}
+}
+
+// Init 创建默认租户，并将升级前的数据迁移到默认租户下
+func (s *TenantService) Init(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "TenantService.Init")
+	defer span.End()
+
+	if err := s.tenantStorage.MigrateLayout(ctx); err != nil {
+		return fmt.Errorf("failed to migrate tenant layout: %w", err)
+	}
+	return nil
+}
+
+// ListTenants 列出所有租户
+func (s *TenantService) ListTenants(ctx context.Context) ([]*models.Tenant, error) {
+	ctx, span := tracing.Start(ctx, "TenantService.ListTenants")
+	defer span.End()
+
+	return s.tenantStorage.ListTenants(ctx)
+}
+
+// GetTenant 获取租户
+func (s *TenantService) GetTenant(ctx context.Context, id string) (*models.Tenant, error) {
+	ctx, span := tracing.Start(ctx, "TenantService.GetTenant")
+	defer span.End()
+
+	return s.tenantStorage.GetTenant(ctx, id)
+}
+
+// CreateTenant 创建租户及其第一个管理员，管理员创建失败时删除租户
+func (s *TenantService) CreateTenant(ctx context.Context, caller *models.CurrentUser, req *models.CreateTenantRequest) (*models.Tenant, error) {
+	ctx, span := tracing.Start(ctx, "TenantService.CreateTenant")
+	defer span.End()
+
+	now := time.Now().Unix()
+	t := &models.Tenant{
+		ID:        req.ID,
+		Name:      req.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.tenantStorage.CreateTenant(ctx, t); err != nil {
+		return nil, err
+	}
+
+	_, err := s.userService.CreateUser(tenant.WithTenant(ctx, t.ID), caller, &models.CreateUserRequest{
+		Username: req.AdminUsername,
+		Password: req.AdminPassword,
+		UserType: models.UserTypeAdmin,
+	})
+	if err != nil {
+		if delErr := s.tenantStorage.DeleteTenant(ctx, t.ID); delErr != nil {
+			logger.Log.WithError(delErr).WithField("tenant", t.ID).Error("Failed to remove tenant after admin creation failed")
+		}
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// DeleteTenant 删除租户及其用户，默认租户不能删除，租户下还有 Zone 时返回 ErrTenantNotEmpty
+func (s *TenantService) DeleteTenant(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "TenantService.DeleteTenant")
+	defer span.End()
+
+	if id == tenant.Default {
+		return apperrors.ErrForbidden
+	}
+
+	if _, err := s.tenantStorage.GetTenant(ctx, id); err != nil {
+		return err
+	}
+
+	zones, err := s.zoneStorage.ListZones(tenant.WithTenant(ctx, id))
+	if err != nil {
+		return err
+	}
+	if len(zones) > 0 {
+		return apperrors.ErrTenantNotEmpty
+	}
+
+	return s.tenantStorage.DeleteTenant(ctx, id)
+}
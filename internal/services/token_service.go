@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"dancer/internal/auth"
+	"dancer/internal/errors"
+	"dancer/internal/models"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tracing"
+)
+
+// APITokenService API Token 业务逻辑
+type APITokenService struct {
+	tokenStorage *etcd.APITokenStorage
+	zoneStorage  *etcd.ZoneStorage
+}
+
+func NewAPITokenService(tokenStorage *etcd.APITokenStorage, zoneStorage *etcd.ZoneStorage) *APITokenService {
+	return &APITokenService{
+		tokenStorage: tokenStorage,
+		zoneStorage:  zoneStorage,
+	}
+}
+
+// Init 为升级前创建的 API Token 补写摘要索引
+func (s *APITokenService) Init(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "APITokenService.Init")
+	defer span.End()
+
+	if err := s.tokenStorage.IndexTokenHashes(ctx); err != nil {
+		return fmt.Errorf("failed to index api tokens: %w", err)
+	}
+	return nil
+}
+
+// ListTokens 列出所有 API Token
+func (s *APITokenService) ListTokens(ctx context.Context) ([]*models.APIToken, error) {
+	ctx, span := tracing.Start(ctx, "APITokenService.ListTokens")
+	defer span.End()
+
+	return s.tokenStorage.ListTokens(ctx)
+}
+
+// CreateToken 创建 API Token，返回 Token 及其明文
+func (s *APITokenService) CreateToken(ctx context.Context, userID string, req *models.CreateAPITokenRequest) (*models.APIToken, string, error) {
+	ctx, span := tracing.Start(ctx, "APITokenService.CreateToken")
+	defer span.End()
+
+	// 检查 Zone 是否存在
+	_, err := s.zoneStorage.GetZone(ctx, req.Zone)
+	if err != nil {
+		return nil, "", err
+	}
+
+	plain, hash, err := auth.GenerateAPIToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate api token: %w", err)
+	}
+
+	token := &models.APIToken{
+		ID:        fmt.Sprintf("%d", time.Now().UnixMilli()),
+		Name:      req.Name,
+		TokenHash: hash,
+		Zone:      req.Zone,
+		Domains:   req.Domains,
+		CreatedBy: userID,
+		CreatedAt: time.Now().Unix(),
+	}
+
+	if err := s.tokenStorage.CreateToken(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	return token, plain, nil
+}
+
+// DeleteToken 删除 API Token
+func (s *APITokenService) DeleteToken(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "APITokenService.DeleteToken")
+	defer span.End()
+
+	return s.tokenStorage.DeleteToken(ctx, id)
+}
+
+// Authenticate 校验 API Token 明文
+func (s *APITokenService) Authenticate(ctx context.Context, token string) (*models.APIToken, error) {
+	ctx, span := tracing.Start(ctx, "APITokenService.Authenticate")
+	defer span.End()
+
+	t, err := s.tokenStorage.GetTokenByHash(ctx, auth.HashAPIToken(token))
+	if err != nil {
+		if err == errors.ErrTokenNotFound {
+			return nil, errors.ErrInvalidToken
+		}
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tracing"
+)
+
+// trashPurgeInterval 检查回收站到期条目的间隔
+const trashPurgeInterval = time.Minute
+
+// TrashService 回收站查询、恢复及到期清理
+// 清理由 leader 副本执行
+type TrashService struct {
+	trashStorage *etcd.TrashStorage
+	zoneStorage  *etcd.ZoneStorage
+	etcdClient   *etcd.Client
+}
+
+func NewTrashService(trashStorage *etcd.TrashStorage, zoneStorage *etcd.ZoneStorage, etcdClient *etcd.Client) *TrashService {
+	return &TrashService{
+		trashStorage: trashStorage,
+		zoneStorage:  zoneStorage,
+		etcdClient:   etcdClient,
+	}
+}
+
+// ListEntries 列出指定类型的回收站条目，按删除时间倒序；zone 不为空时只列出该 Zone 的条目
+func (s *TrashService) ListEntries(ctx context.Context, typ models.TrashType, zone string) ([]*models.TrashEntry, error) {
+	ctx, span := tracing.Start(ctx, "TrashService.ListEntries")
+	defer span.End()
+
+	entries, err := s.trashStorage.ListEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.TrashEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type == typ && (zone == "" || entry.Zone == zone) {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+// RestoreDomain 从回收站恢复 Domain，所属 Zone 必须存在且不能已有同名 Domain
+func (s *TrashService) RestoreDomain(ctx context.Context, id string) (*models.Domain, error) {
+	ctx, span := tracing.Start(ctx, "TrashService.RestoreDomain")
+	defer span.End()
+
+	entry, err := s.getEntry(ctx, models.TrashTypeDomain, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 检查 Zone 是否存在
+	zone, err := s.zoneStorage.GetZone(ctx, entry.Zone)
+	if err != nil {
+		return nil, err
+	}
+	if zone.ApprovalRequired {
+		return nil, apperrors.ErrApprovalRequired
+	}
+
+	domain, err := s.trashStorage.RestoreDomain(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	// 更新 Zone 记录数
+	if err := s.zoneStorage.IncrementZoneRecordCount(ctx, entry.Zone, 1); err != nil {
+		logger.Log.WithError(err).WithField("zone", entry.Zone).Warn("Failed to update zone record count after restore")
+	}
+
+	return domain, nil
+}
+
+// RestoreZone 从回收站恢复 Zone 及其下所有 Domain，Zone 不能已经存在
+func (s *TrashService) RestoreZone(ctx context.Context, id string) (*models.Zone, error) {
+	ctx, span := tracing.Start(ctx, "TrashService.RestoreZone")
+	defer span.End()
+
+	entry, err := s.getEntry(ctx, models.TrashTypeZone, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 检查是否已存在
+	exists, err := s.zoneStorage.ZoneExists(ctx, entry.Zone)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, apperrors.ErrZoneExists
+	}
+
+	return s.trashStorage.RestoreZone(ctx, entry)
+}
+
+// Purge 永久删除回收站条目
+func (s *TrashService) Purge(ctx context.Context, typ models.TrashType, id string) error {
+	ctx, span := tracing.Start(ctx, "TrashService.Purge")
+	defer span.End()
+
+	if _, err := s.getEntry(ctx, typ, id); err != nil {
+		return err
+	}
+	return s.trashStorage.DeleteEntry(ctx, id)
+}
+
+// getEntry 获取指定类型的回收站条目，类型不符时视为不存在
+func (s *TrashService) getEntry(ctx context.Context, typ models.TrashType, id string) (*models.TrashEntry, error) {
+	entry, err := s.trashStorage.GetEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Type != typ {
+		return nil, apperrors.ErrTrashEntryNotFound
+	}
+	return entry, nil
+}
+
+// Run 启动回收站清理，仅 leader 副本执行，阻塞直到 ctx 取消
+func (s *TrashService) Run(ctx context.Context) {
+	s.etcdClient.RunAsLeader(ctx, "trash", s.loop)
+}
+
+// loop leader 期间定期清理到期条目
+func (s *TrashService) loop(ctx context.Context) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		s.purgeExpired(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeExpired 永久删除所有租户的到期条目
+func (s *TrashService) purgeExpired(ctx context.Context) {
+	entries, err := s.trashStorage.ListAllEntries(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Log.WithError(err).Error("Failed to list trash entries")
+		}
+		return
+	}
+
+	now := time.Now().Unix()
+	for _, entry := range entries {
+		if entry.ExpiresAt > now {
+			continue
+		}
+		if err := s.trashStorage.DeleteEntry(ctx, entry.ID); err != nil && err != apperrors.ErrTrashEntryNotFound {
+			logger.Log.WithError(err).WithField("id", entry.ID).Error("Failed to purge trash entry")
+			continue
+		}
+		logger.Log.WithField("id", entry.ID).WithField("zone", entry.Zone).Info("Purged expired trash entry")
+	}
+}
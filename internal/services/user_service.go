@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"dancer/internal/auth"
@@ -11,19 +13,55 @@ import (
 	"dancer/internal/logger"
 	"dancer/internal/models"
 	"dancer/internal/storage/etcd"
+	"dancer/internal/tenant"
+	"dancer/internal/tracing"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserService struct {
 	userStorage *etcd.UserStorage
+
+	bootstrapMu sync.Mutex
+	bootstrap   BootstrapStatus
+}
+
+// BootstrapStatus 默认管理员初始化状态
+type BootstrapStatus struct {
+	Completed   bool   `json:"completed"`
+	CompletedAt int64  `json:"completed_at,omitempty"` // 完成时间戳
+	Error       string `json:"error,omitempty"`        // 最近一次失败的原因，完成后清空
 }
 
 func NewUserService(userStorage *etcd.UserStorage) *UserService {
 	return &UserService{userStorage: userStorage}
 }
 
-// InitDefaultAdmin 初始化默认管理员账户
+// InitDefaultAdmin 初始化默认管理员账户，结果记录在 BootstrapStatus 中
 func (s *UserService) InitDefaultAdmin(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "UserService.InitDefaultAdmin")
+	defer span.End()
+
+	err := s.initDefaultAdmin(ctx)
+
+	s.bootstrapMu.Lock()
+	defer s.bootstrapMu.Unlock()
+	if err != nil {
+		s.bootstrap.Error = err.Error()
+		return err
+	}
+	s.bootstrap = BootstrapStatus{Completed: true, CompletedAt: time.Now().Unix()}
+	return nil
+}
+
+// BootstrapStatus 返回默认管理员初始化状态
+func (s *UserService) BootstrapStatus() BootstrapStatus {
+	s.bootstrapMu.Lock()
+	defer s.bootstrapMu.Unlock()
+	return s.bootstrap
+}
+
+// initDefaultAdmin 默认租户没有任何用户时创建默认管理员，默认管理员是超级管理员
+func (s *UserService) initDefaultAdmin(ctx context.Context) error {
 	// 检查是否已有用户
 	count, err := s.userStorage.CountUsers(ctx)
 	if err != nil {
@@ -43,10 +81,10 @@ func (s *UserService) InitDefaultAdmin(ctx context.Context) error {
 	}
 
 	admin := &models.User{
-		ID:        "10000",
+		ID:        models.DefaultAdminID,
 		Username:  "admin",
 		Password:  hashedPassword,
-		UserType:  models.UserTypeAdmin,
+		UserType:  models.UserTypeSuperAdmin,
 		CreatedAt: time.Now().Unix(),
 		UpdatedAt: time.Now().Unix(),
 	}
@@ -55,12 +93,18 @@ func (s *UserService) InitDefaultAdmin(ctx context.Context) error {
 		return fmt.Errorf("failed to create default admin: %w", err)
 	}
 
-	logger.Log.Info("Default admin user created successfully (ID: 10000)")
+	logger.Log.Infof("Default admin user created successfully (ID: %s)", models.DefaultAdminID)
 	return nil
 }
 
-// Login 用户登录
-func (s *UserService) Login(ctx context.Context, username, password string) (string, *models.User, error) {
+// Login 用户登录，用户名在租户内唯一，tenantID 为空时登录默认租户
+func (s *UserService) Login(ctx context.Context, tenantID, username, password string) (string, *models.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Login")
+	defer span.End()
+
+	tenantID = tenant.Of(tenantID)
+	ctx = tenant.WithTenant(ctx, tenantID)
+
 	user, err := s.userStorage.GetUserByUsername(ctx, username)
 	if err != nil {
 		if err == apperrors.ErrUserNotFound {
@@ -73,7 +117,7 @@ func (s *UserService) Login(ctx context.Context, username, password string) (str
 		return "", nil, apperrors.ErrInvalidCredentials
 	}
 
-	token, err := auth.GenerateToken(user.ID, user.Username, string(user.UserType))
+	token, err := auth.GenerateToken(user.ID, user.Username, string(user.UserType), tenantID)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to generate token: %w", err)
 	}
@@ -83,11 +127,17 @@ func (s *UserService) Login(ctx context.Context, username, password string) (str
 
 // GetCurrentUser 获取当前用户信息
 func (s *UserService) GetCurrentUser(ctx context.Context, userID string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetCurrentUser")
+	defer span.End()
+
 	return s.userStorage.GetUser(ctx, userID)
 }
 
 // ChangePassword 修改密码
 func (s *UserService) ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error {
+	ctx, span := tracing.Start(ctx, "UserService.ChangePassword")
+	defer span.End()
+
 	user, err := s.userStorage.GetUser(ctx, userID)
 	if err != nil {
 		return err
@@ -111,13 +161,48 @@ func (s *UserService) ChangePassword(ctx context.Context, userID, oldPassword, n
 	return s.userStorage.UpdateUser(ctx, user)
 }
 
-// ListUsers 列出所有用户
-func (s *UserService) ListUsers(ctx context.Context) ([]*models.User, error) {
-	return s.userStorage.ListUsers(ctx)
+// ListUsers 分页列出用户，支持过滤和排序
+func (s *UserService) ListUsers(ctx context.Context, req *models.ListUsersRequest) (*models.Page[*models.User], error) {
+	ctx, span := tracing.Start(ctx, "UserService.ListUsers")
+	defer span.End()
+
+	var f filters[*models.User]
+	if req.NamePrefix != "" {
+		f.add(func(u *models.User) bool { return strings.HasPrefix(u.Username, req.NamePrefix) })
+	}
+	if req.NameContains != "" {
+		f.add(func(u *models.User) bool { return containsFold(u.Username, req.NameContains) })
+	}
+	if req.UserType != "" {
+		f.add(func(u *models.User) bool { return u.UserType == req.UserType })
+	}
+	if req.UpdatedSince > 0 {
+		f.add(func(u *models.User) bool { return u.UpdatedAt >= req.UpdatedSince })
+	}
+
+	// 默认按用户 ID 排序，即 etcd key 顺序；用户 key 不含用户名，无法按用户名前缀缩小读取范围
+	var less func(a, b *models.User) bool
+	switch req.Sort {
+	case "username":
+		less = lessBy(func(u *models.User) string { return u.Username })
+	case "created_at":
+		less = lessBy(func(u *models.User) int64 { return u.CreatedAt })
+	case "updated_at":
+		less = lessBy(func(u *models.User) int64 { return u.UpdatedAt })
+	}
+
+	return s.userStorage.ListUsersPage(ctx, listQuery(req.PageRequest, "", f, less))
 }
 
-// CreateUser 创建用户
-func (s *UserService) CreateUser(ctx context.Context, req *models.CreateUserRequest) (*models.User, error) {
+// CreateUser 创建用户，只有超级管理员可以创建超级管理员
+func (s *UserService) CreateUser(ctx context.Context, caller *models.CurrentUser, req *models.CreateUserRequest) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.CreateUser")
+	defer span.End()
+
+	if req.UserType == models.UserTypeSuperAdmin && caller.UserType != models.UserTypeSuperAdmin {
+		return nil, apperrors.ErrForbidden
+	}
+
 	// 检查用户名是否已存在
 	_, err := s.userStorage.GetUserByUsername(ctx, req.Username)
 	if err == nil {
@@ -148,13 +233,20 @@ func (s *UserService) CreateUser(ctx context.Context, req *models.CreateUserRequ
 	return user, nil
 }
 
-// UpdateUser 更新用户
-func (s *UserService) UpdateUser(ctx context.Context, req *models.UpdateUserRequest) error {
+// UpdateUser 更新用户，只有超级管理员可以修改超级管理员或将用户设为超级管理员
+func (s *UserService) UpdateUser(ctx context.Context, caller *models.CurrentUser, req *models.UpdateUserRequest) error {
+	ctx, span := tracing.Start(ctx, "UserService.UpdateUser")
+	defer span.End()
+
 	user, err := s.userStorage.GetUser(ctx, req.ID)
 	if err != nil {
 		return err
 	}
 
+	if (user.UserType == models.UserTypeSuperAdmin || req.UserType == models.UserTypeSuperAdmin) && caller.UserType != models.UserTypeSuperAdmin {
+		return apperrors.ErrForbidden
+	}
+
 	// 如果修改了用户名，检查是否已存在
 	if req.Username != "" && req.Username != user.Username {
 		existing, _ := s.userStorage.GetUserByUsername(ctx, req.Username)
@@ -186,12 +278,26 @@ func (s *UserService) UpdateUser(ctx context.Context, req *models.UpdateUserRequ
 	return s.userStorage.UpdateUser(ctx, user)
 }
 
-// DeleteUser 删除用户
-func (s *UserService) DeleteUser(ctx context.Context, userID string) error {
+// DeleteUser 删除用户，只有超级管理员可以删除超级管理员
+func (s *UserService) DeleteUser(ctx context.Context, caller *models.CurrentUser, userID string) error {
+	ctx, span := tracing.Start(ctx, "UserService.DeleteUser")
+	defer span.End()
+
 	// 检查是否是默认管理员
-	if userID == "10000" {
+	if userID == models.DefaultAdminID && tenant.FromContext(ctx) == tenant.Default {
 		return apperrors.ErrCannotDeleteDefaultAdmin
 	}
 
+	user, err := s.userStorage.GetUser(ctx, userID)
+	if err != nil {
+		if err == apperrors.ErrUserNotFound {
+			return nil
+		}
+		return err
+	}
+	if user.UserType == models.UserTypeSuperAdmin && caller.UserType != models.UserTypeSuperAdmin {
+		return apperrors.ErrForbidden
+	}
+
 	return s.userStorage.DeleteUser(ctx, userID)
 }
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"dancer/internal/config"
+	"dancer/internal/dnscheck"
+	apperrors "dancer/internal/errors"
+	"dancer/internal/metrics"
+	"dancer/internal/models"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tracing"
+)
+
+// VerificationService 向 DNS 服务器查询 Domain，确认 CoreDNS 实际返回的记录与 Dancer 一致
+// 每个 Domain 保留最近一次校验结果，供界面展示
+type VerificationService struct {
+	zoneStorage         *etcd.ZoneStorage
+	domainStorage       *etcd.DomainStorage
+	verificationStorage *etcd.VerificationStorage
+	resolver            *dnscheck.Resolver // 未配置 verify.server 时为 nil
+	concurrency         int
+}
+
+func NewVerificationService(zoneStorage *etcd.ZoneStorage, domainStorage *etcd.DomainStorage, verificationStorage *etcd.VerificationStorage, cfg *config.Config) *VerificationService {
+	s := &VerificationService{
+		zoneStorage:         zoneStorage,
+		domainStorage:       domainStorage,
+		verificationStorage: verificationStorage,
+		concurrency:         cfg.Verify.Concurrency,
+	}
+	if cfg.Verify.Server != "" {
+		s.resolver = dnscheck.NewResolver(cfg.Verify.Server, cfg.Verify.Protocol, time.Duration(cfg.Verify.Timeout)*time.Second)
+	}
+	return s
+}
+
+// VerifyDomain 查询 Domain 并与记录对比，保存并返回结果
+func (s *VerificationService) VerifyDomain(ctx context.Context, req *models.VerifyDomainRequest) (*models.DomainVerification, error) {
+	ctx, span := tracing.Start(ctx, "VerificationService.VerifyDomain")
+	defer span.End()
+
+	if s.resolver == nil {
+		return nil, apperrors.ErrVerificationNotConfigured
+	}
+
+	domain, err := s.domainStorage.GetDomain(ctx, req.Zone, req.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	v := s.verify(ctx, domain)
+	if err := s.verificationStorage.SaveVerification(ctx, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// GetDomainVerification 获取 Domain 最近一次校验结果
+func (s *VerificationService) GetDomainVerification(ctx context.Context, req *models.VerifyDomainRequest) (*models.DomainVerification, error) {
+	ctx, span := tracing.Start(ctx, "VerificationService.GetDomainVerification")
+	defer span.End()
+
+	// 检查 Domain 是否存在，已删除 Domain 的旧结果不再返回
+	if _, err := s.domainStorage.GetDomain(ctx, req.Zone, req.Domain); err != nil {
+		return nil, err
+	}
+
+	return s.verificationStorage.GetVerification(ctx, req.Zone, req.Domain)
+}
+
+// VerifyZone 并发查询 Zone 下所有 Domain，用结果替换 Zone 之前的校验结果
+func (s *VerificationService) VerifyZone(ctx context.Context, req *models.VerifyZoneRequest) (*models.ZoneVerification, error) {
+	ctx, span := tracing.Start(ctx, "VerificationService.VerifyZone")
+	defer span.End()
+
+	if s.resolver == nil {
+		return nil, apperrors.ErrVerificationNotConfigured
+	}
+
+	if _, err := s.zoneStorage.GetZone(ctx, req.Zone); err != nil {
+		return nil, err
+	}
+
+	domains, err := s.domainStorage.ListDomainsByZone(ctx, req.Zone)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*models.DomainVerification, len(domains))
+	sem := make(chan struct{}, s.concurrency)
+	var wg sync.WaitGroup
+	for i, domain := range domains {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = s.verify(ctx, domain)
+		}()
+	}
+	wg.Wait()
+
+	// 请求被取消时查询结果都是失败，不覆盖之前的结果
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.verificationStorage.ReplaceZoneVerifications(ctx, req.Zone, results); err != nil {
+		return nil, err
+	}
+	return summarizeVerifications(req.Zone, results), nil
+}
+
+// ListZoneVerifications 获取 Zone 下各 Domain 最近一次校验结果，不包含已删除 Domain 的结果
+func (s *VerificationService) ListZoneVerifications(ctx context.Context, req *models.VerifyZoneRequest) (*models.ZoneVerification, error) {
+	ctx, span := tracing.Start(ctx, "VerificationService.ListZoneVerifications")
+	defer span.End()
+
+	if _, err := s.zoneStorage.GetZone(ctx, req.Zone); err != nil {
+		return nil, err
+	}
+
+	domains, err := s.domainStorage.ListDomainsByZone(ctx, req.Zone)
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[string]bool, len(domains))
+	for _, d := range domains {
+		exists[d.Domain] = true
+	}
+
+	stored, err := s.verificationStorage.ListZoneVerifications(ctx, req.Zone)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*models.DomainVerification, 0, len(stored))
+	for _, v := range stored {
+		if exists[v.Domain] {
+			results = append(results, v)
+		}
+	}
+	return summarizeVerifications(req.Zone, results), nil
+}
+
+// verify 查询 Domain 的完整域名并与静态 IP、动态实例 IP 及 TTL 对比
+func (s *VerificationService) verify(ctx context.Context, domain *models.Domain) *models.DomainVerification {
+	expected := make([]string, 0, len(domain.IPs)+len(domain.DynamicIPs))
+	expected = append(expected, domain.IPs...)
+	expected = append(expected, domain.DynamicIPs...)
+
+	v := &models.DomainVerification{
+		Zone:        domain.Zone,
+		Domain:      domain.Domain,
+		Name:        domain.Name,
+		Server:      s.resolver.Server(),
+		Protocol:    s.resolver.Network(),
+		ExpectedIPs: expected,
+		ExpectedTTL: domain.TTL,
+		Answers:     []*models.VerificationAnswer{},
+		CheckedAt:   time.Now().Unix(),
+	}
+
+	result, err := s.resolver.Lookup(ctx, domain.Name)
+	if result != nil {
+		v.Authoritative = result.Authoritative
+		v.Rcode = result.Rcode
+		v.LatencyMs = float64(result.Latency.Microseconds()) / 1000
+	}
+	if err != nil {
+		v.Status = models.VerificationError
+		v.Error = err.Error()
+		metrics.DNSVerifications.WithLabelValues(string(v.Status)).Inc()
+		return v
+	}
+
+	for _, a := range result.Answers {
+		v.Answers = append(v.Answers, &models.VerificationAnswer{Type: a.Type, IP: a.IP, TTL: a.TTL})
+	}
+	diff := dnscheck.Compare(expected, domain.TTL, result)
+	v.Missing, v.Unexpected, v.TTLMismatch = diff.Missing, diff.Unexpected, diff.TTLMismatch
+	v.Status = models.VerificationMatch
+	if !diff.Empty() {
+		v.Status = models.VerificationMismatch
+	}
+	metrics.DNSVerifications.WithLabelValues(string(v.Status)).Inc()
+	return v
+}
+
+// summarizeVerifications 按状态统计校验结果，结果按 Domain 短名排序
+func summarizeVerifications(zone string, results []*models.DomainVerification) *models.ZoneVerification {
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Domain < results[j].Domain
+	})
+
+	summary := &models.ZoneVerification{Zone: zone, Total: len(results), Results: results}
+	for _, v := range results {
+		summary.CheckedAt = max(summary.CheckedAt, v.CheckedAt)
+		switch v.Status {
+		case models.VerificationMatch:
+			summary.Matched++
+		case models.VerificationMismatch:
+			summary.Mismatched++
+		default:
+			summary.Failed++
+		}
+	}
+	return summary
+}
@@ -0,0 +1,574 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"dancer/internal/config"
+	apperrors "dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/tracing"
+)
+
+const (
+	// webhookPollInterval 检查待投递记录的间隔
+	webhookPollInterval = time.Second
+	// webhookPurgeEvery 每隔多少次检查清理一次过多的成功投递记录
+	webhookPurgeEvery = 60
+	// webhookConcurrency 同时进行的投递数
+	webhookConcurrency = 8
+	// defaultDeliveryLimit 投递记录默认返回条数
+	defaultDeliveryLimit = 100
+)
+
+// WebhookService Webhook 订阅管理及事件投递
+// 投递由 leader 副本执行：监听 Zone / Domain 变更，为匹配的订阅生成投递记录，再按退避策略推送
+type WebhookService struct {
+	webhookStorage *etcd.WebhookStorage
+	eventStorage   *etcd.EventStorage
+	etcdClient     *etcd.Client
+	httpClient     *http.Client
+
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	historySize    int
+}
+
+func NewWebhookService(webhookStorage *etcd.WebhookStorage, eventStorage *etcd.EventStorage, etcdClient *etcd.Client, cfg *config.Config) *WebhookService {
+	return &WebhookService{
+		webhookStorage: webhookStorage,
+		eventStorage:   eventStorage,
+		etcdClient:     etcdClient,
+		httpClient: &http.Client{
+			Timeout: time.Duration(cfg.Webhook.Timeout) * time.Second,
+			// 不跟随重定向，3xx 视为投递失败
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		maxAttempts:    cfg.Webhook.MaxAttempts,
+		initialBackoff: time.Duration(cfg.Webhook.InitialBackoff) * time.Second,
+		maxBackoff:     time.Duration(cfg.Webhook.MaxBackoff) * time.Second,
+		historySize:    cfg.Webhook.HistorySize,
+	}
+}
+
+// ListWebhooks 列出所有 Webhook 订阅
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]*models.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.ListWebhooks")
+	defer span.End()
+
+	return s.webhookStorage.ListWebhooks(ctx)
+}
+
+// GetWebhook 获取 Webhook 订阅
+func (s *WebhookService) GetWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetWebhook")
+	defer span.End()
+
+	return s.webhookStorage.GetWebhook(ctx, id)
+}
+
+// CreateWebhook 创建 Webhook 订阅，未指定签名密钥时随机生成
+func (s *WebhookService) CreateWebhook(ctx context.Context, userID string, req *models.CreateWebhookRequest) (*models.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.CreateWebhook")
+	defer span.End()
+
+	secret := req.Secret
+	if secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	now := time.Now()
+	wh := &models.Webhook{
+		ID:        fmt.Sprintf("%d", now.UnixMilli()),
+		Name:      req.Name,
+		URL:       req.URL,
+		Secret:    secret,
+		Zones:     req.Zones,
+		Types:     req.Types,
+		Ops:       req.Ops,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+		CreatedBy: userID,
+		CreatedAt: now.Unix(),
+		UpdatedAt: now.Unix(),
+	}
+
+	if err := s.webhookStorage.CreateWebhook(ctx, wh); err != nil {
+		return nil, err
+	}
+	return wh, nil
+}
+
+// UpdateWebhook 更新 Webhook 订阅，返回更新后的订阅及新的签名密钥（未轮换时为空）
+func (s *WebhookService) UpdateWebhook(ctx context.Context, req *models.UpdateWebhookRequest) (*models.Webhook, string, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.UpdateWebhook")
+	defer span.End()
+
+	wh, err := s.webhookStorage.GetWebhook(ctx, req.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	wh.Name = req.Name
+	wh.URL = req.URL
+	wh.Zones = req.Zones
+	wh.Types = req.Types
+	wh.Ops = req.Ops
+	if req.Enabled != nil {
+		wh.Enabled = *req.Enabled
+	}
+
+	var secret string
+	if req.RotateSecret {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, "", err
+		}
+		wh.Secret = secret
+	}
+	wh.UpdatedAt = time.Now().Unix()
+
+	if err := s.webhookStorage.SaveWebhook(ctx, wh); err != nil {
+		return nil, "", err
+	}
+	return wh, secret, nil
+}
+
+// DeleteWebhook 删除 Webhook 订阅及其投递记录
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeleteWebhook")
+	defer span.End()
+
+	return s.webhookStorage.DeleteWebhook(ctx, id)
+}
+
+// ListDeliveries 列出订阅的投递记录，按时间倒序，返回记录及满足过滤条件的总数
+func (s *WebhookService) ListDeliveries(ctx context.Context, req *models.ListWebhookDeliveriesRequest) ([]*models.WebhookDelivery, int, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.ListDeliveries")
+	defer span.End()
+
+	if _, err := s.webhookStorage.GetWebhook(ctx, req.WebhookID); err != nil {
+		return nil, 0, err
+	}
+
+	deliveries, err := s.webhookStorage.ListDeliveries(ctx, req.WebhookID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]*models.WebhookDelivery, 0, len(deliveries))
+	for i := len(deliveries) - 1; i >= 0; i-- {
+		if req.Status == "" || deliveries[i].Status == req.Status {
+			result = append(result, deliveries[i])
+		}
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultDeliveryLimit
+	}
+	return result[:min(limit, len(result))], len(result), nil
+}
+
+// Redeliver 重新投递，重置尝试次数并立即进入等待投递状态
+// 可用于死信记录，也可用于重放已成功的投递
+func (s *WebhookService) Redeliver(ctx context.Context, req *models.RedeliverWebhookRequest) (*models.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Redeliver")
+	defer span.End()
+
+	if _, err := s.webhookStorage.GetWebhook(ctx, req.WebhookID); err != nil {
+		return nil, err
+	}
+
+	d, err := s.webhookStorage.GetDelivery(ctx, req.WebhookID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Status = models.DeliveryStatusPending
+	d.Attempts = 0
+	d.NextAttemptAt = time.Now().Unix()
+	d.DeliveredAt = 0
+	if err := s.webhookStorage.UpdateDelivery(ctx, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Run 启动投递器，仅 leader 副本执行，阻塞直到 ctx 取消
+func (s *WebhookService) Run(ctx context.Context) {
+	s.etcdClient.RunAsLeader(ctx, "webhook", s.loop)
+}
+
+// loop leader 期间同时收集事件和投递
+func (s *WebhookService) loop(ctx context.Context) {
+	if err := s.webhookStorage.IndexPendingDeliveries(ctx); err != nil && ctx.Err() == nil {
+		logger.Log.WithError(err).Error("Failed to index pending webhook deliveries")
+	}
+
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		s.collect(ctx)
+	}()
+	defer func() { <-collected }()
+
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for tick := 1; ; tick++ {
+		s.dispatch(ctx, tick%webhookPurgeEvery == 0)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect 从上次处理到的 revision 开始监听变更事件并生成投递记录，阻塞直到 ctx 取消
+func (s *WebhookService) collect(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := s.collectOnce(ctx); err != nil && ctx.Err() == nil {
+			logger.Log.WithError(err).Warn("Webhook event collection interrupted, retrying")
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+// collectOnce 执行一轮事件监听
+// 上次处理到的 revision 已被压缩时，期间的事件无法投递：向每个启用的订阅投递一条 reset 通知，再从当前 revision 继续
+func (s *WebhookService) collectOnce(ctx context.Context) error {
+	cursor, err := s.webhookStorage.GetCursor(ctx)
+	if err != nil {
+		return err
+	}
+
+	revision, err := s.eventStorage.StartRevision(ctx, cursor)
+	if err == apperrors.ErrRevisionCompacted {
+		logger.Log.WithField("revision", cursor).Error("Webhook event history compacted, events since this revision will not be delivered")
+		if revision, err = s.eventStorage.StartRevision(ctx, 0); err != nil {
+			return err
+		}
+		err = s.enqueueReset(ctx, cursor, revision)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.eventStorage.WatchEvents(ctx, revision, func(events []*models.ChangeEvent) error {
+		return s.enqueue(ctx, events)
+	})
+}
+
+// enqueue 为一批事件生成投递记录并推进处理进度
+// 投递 ID 由事件 revision 及其在同一 revision 中的序号组成，重复处理同一事件得到相同的 ID
+func (s *WebhookService) enqueue(ctx context.Context, events []*models.ChangeEvent) error {
+	webhooks, err := s.webhookStorage.ListAllWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	var deliveries []*models.WebhookDelivery
+	var prevRevision int64
+	seq := 0
+	for _, event := range events {
+		if event.Revision == prevRevision {
+			seq++
+		} else {
+			prevRevision, seq = event.Revision, 0
+		}
+
+		for _, wh := range webhooks {
+			if !wh.Matches(event) {
+				continue
+			}
+
+			id := fmt.Sprintf("%012d-%03d", event.Revision, seq)
+			payload, err := json.Marshal(&models.WebhookPayload{
+				DeliveryID: id,
+				WebhookID:  wh.ID,
+				Revision:   event.Revision,
+				Type:       event.Type,
+				Op:         event.Op,
+				Zone:       event.ZoneName,
+				Name:       event.Name,
+				OccurredAt: now,
+				Data:       webhookEventData(event),
+			})
+			if err != nil {
+				return err
+			}
+
+			deliveries = append(deliveries, &models.WebhookDelivery{
+				ID:            id,
+				WebhookID:     wh.ID,
+				EventType:     event.Type,
+				EventOp:       event.Op,
+				Zone:          event.ZoneName,
+				Name:          event.Name,
+				Revision:      event.Revision,
+				Payload:       payload,
+				Status:        models.DeliveryStatusPending,
+				NextAttemptAt: now,
+				CreatedAt:     now,
+			})
+		}
+	}
+
+	return s.webhookStorage.EnqueueDeliveries(ctx, deliveries, events[len(events)-1].Revision)
+}
+
+// enqueueReset 为每个启用的订阅生成 reset 投递记录，并将事件处理进度推进到 revision
+func (s *WebhookService) enqueueReset(ctx context.Context, cursor, revision int64) error {
+	webhooks, err := s.webhookStorage.ListAllWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	id := fmt.Sprintf("%012d-reset", revision)
+	var deliveries []*models.WebhookDelivery
+	for _, wh := range webhooks {
+		if !wh.Enabled {
+			continue
+		}
+
+		payload, err := json.Marshal(&models.WebhookPayload{
+			DeliveryID: id,
+			WebhookID:  wh.ID,
+			Revision:   revision,
+			Type:       models.WebhookEventReset,
+			OccurredAt: now,
+			Data:       &models.WebhookReset{FromRevision: cursor, ToRevision: revision},
+		})
+		if err != nil {
+			return err
+		}
+
+		deliveries = append(deliveries, &models.WebhookDelivery{
+			ID:            id,
+			WebhookID:     wh.ID,
+			EventType:     models.WebhookEventReset,
+			Revision:      revision,
+			Payload:       payload,
+			Status:        models.DeliveryStatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+
+	return s.webhookStorage.EnqueueDeliveries(ctx, deliveries, revision)
+}
+
+// dispatch 投递所有到期的记录，purge 为 true 时顺带清理过多的成功投递记录
+func (s *WebhookService) dispatch(ctx context.Context, purge bool) {
+	deliveries, err := s.webhookStorage.ListPendingDeliveries(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Log.WithError(err).Error("Failed to list pending webhook deliveries")
+		}
+		return
+	}
+	webhooks, err := s.webhookStorage.ListAllWebhooks(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Log.WithError(err).Error("Failed to list webhooks")
+		}
+		return
+	}
+	byID := make(map[string]*models.Webhook, len(webhooks))
+	for _, wh := range webhooks {
+		byID[wh.ID] = wh
+	}
+
+	now := time.Now().Unix()
+	sem := make(chan struct{}, webhookConcurrency)
+	var wg sync.WaitGroup
+	for _, d := range deliveries {
+		wh := byID[d.WebhookID]
+		if d.Status != models.DeliveryStatusPending || d.NextAttemptAt > now || wh == nil || !wh.Enabled {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.deliver(ctx, wh, d)
+		}()
+	}
+	wg.Wait()
+
+	if purge && ctx.Err() == nil {
+		s.purge(ctx)
+	}
+}
+
+// deliver 执行一次投递并记录结果
+func (s *WebhookService) deliver(ctx context.Context, wh *models.Webhook, d *models.WebhookDelivery) {
+	status, err := s.post(ctx, wh, d)
+	if ctx.Err() != nil {
+		// 失去领导权或退出，由下一任 leader 重新投递
+		return
+	}
+
+	now := time.Now()
+	d.Attempts++
+	d.LastAttemptAt = now.Unix()
+	d.ResponseStatus = status
+
+	log := logger.Log.WithField("webhook", wh.ID).WithField("delivery", d.ID)
+	switch {
+	case err == nil:
+		d.Status = models.DeliveryStatusSucceeded
+		d.DeliveredAt = now.Unix()
+		d.Error = ""
+	case d.Attempts >= s.maxAttempts:
+		d.Status = models.DeliveryStatusDead
+		d.Error = err.Error()
+		log.WithError(err).Warn("Webhook delivery exhausted retries, moved to dead letters")
+	default:
+		d.NextAttemptAt = now.Add(s.backoff(d.Attempts)).Unix()
+		d.Error = err.Error()
+		log.WithError(err).WithField("attempts", d.Attempts).Debug("Webhook delivery failed, will retry")
+	}
+
+	if err := s.webhookStorage.UpdateDelivery(ctx, d); err != nil && err != apperrors.ErrDeliveryConflict {
+		log.WithError(err).Error("Failed to record webhook delivery result")
+	}
+}
+
+// post 发送投递请求，返回响应状态码；非 2xx 响应视为失败
+// 请求头 X-Dancer-Signature 为 "sha256=" + HMAC-SHA256(secret, timestamp + "." + body) 的十六进制编码
+func (s *WebhookService) post(ctx context.Context, wh *models.Webhook, d *models.WebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Dancer-Webhook")
+	req.Header.Set("X-Dancer-Webhook", wh.ID)
+	req.Header.Set("X-Dancer-Delivery", d.ID)
+	event := string(d.EventType)
+	if d.EventOp != "" {
+		event += "." + string(d.EventOp)
+	}
+	req.Header.Set("X-Dancer-Event", event)
+	req.Header.Set("X-Dancer-Timestamp", timestamp)
+	req.Header.Set("X-Dancer-Signature", "sha256="+signWebhook(wh.Secret, timestamp, d.Payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff 第 attempts 次失败后的重试间隔，从 initialBackoff 开始每次翻倍，不超过 maxBackoff
+func (s *WebhookService) backoff(attempts int) time.Duration {
+	d := s.initialBackoff
+	for i := 1; i < attempts && d < s.maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, s.maxBackoff)
+}
+
+// purge 每个订阅只保留最近 historySize 条成功投递记录，死信记录保留到重新投递或订阅被删除
+func (s *WebhookService) purge(ctx context.Context) {
+	deliveries, err := s.webhookStorage.ListDeliveries(ctx, "")
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Log.WithError(err).Error("Failed to list webhook deliveries")
+		}
+		return
+	}
+
+	succeeded := make(map[string][]*models.WebhookDelivery)
+	for _, d := range deliveries {
+		if d.Status == models.DeliveryStatusSucceeded {
+			succeeded[d.WebhookID] = append(succeeded[d.WebhookID], d)
+		}
+	}
+
+	// 投递记录按 ID（即时间）顺序列出，超出部分为最早的记录
+	var expired []*models.WebhookDelivery
+	for _, list := range succeeded {
+		if len(list) <= s.historySize {
+			continue
+		}
+		expired = append(expired, list[:len(list)-s.historySize]...)
+	}
+	if len(expired) == 0 {
+		return
+	}
+
+	if err := s.webhookStorage.DeleteDeliveries(ctx, expired); err != nil {
+		logger.Log.WithError(err).Error("Failed to purge webhook delivery history")
+	}
+}
+
+// webhookEventData 事件携带的 Zone 或 Domain
+func webhookEventData(event *models.ChangeEvent) interface{} {
+	switch {
+	case event.Zone != nil:
+		return event.Zone
+	case event.Domain != nil:
+		return event.Domain
+	}
+	return nil
+}
+
+// signWebhook 计算投递请求签名
+func signWebhook(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// generateWebhookSecret 生成随机签名密钥
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
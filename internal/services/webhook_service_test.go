@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/storage/etcd/etcdtest"
+	"github.com/sirupsen/logrus"
+)
+
+// webhookReceiver 记录收到的投递请求，按 status 返回响应
+type webhookReceiver struct {
+	mu       sync.Mutex
+	requests []receivedWebhook
+	status   atomic.Int32
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	r.requests = append(r.requests, receivedWebhook{header: req.Header.Clone(), body: body})
+	r.mu.Unlock()
+	w.WriteHeader(int(r.status.Load()))
+}
+
+func (r *webhookReceiver) received() []receivedWebhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedWebhook(nil), r.requests...)
+}
+
+// newTestWebhookService 连接进程内 etcd，创建指向 receiver 的订阅及一条待投递记录
+func newTestWebhookService(t *testing.T, receiver *webhookReceiver) (*WebhookService, *etcd.WebhookStorage, *models.WebhookDelivery) {
+	t.Helper()
+	logger.Log = logrus.New()
+
+	client, cfg := etcdtest.Start(t)
+	cfg.Webhook.Timeout = 5
+	cfg.Webhook.MaxAttempts = 3
+	cfg.Webhook.InitialBackoff = 5
+	cfg.Webhook.MaxBackoff = 600
+	cfg.Webhook.HistorySize = 100
+
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	webhookStorage := etcd.NewWebhookStorage(client, cfg)
+	s := NewWebhookService(webhookStorage, etcd.NewEventStorage(client), client, cfg)
+
+	ctx := context.Background()
+	wh, err := s.CreateWebhook(ctx, "1", &models.CreateWebhookRequest{
+		Name:   "test",
+		URL:    server.URL,
+		Secret: "0123456789abcdef",
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	d := &models.WebhookDelivery{
+		ID:            "000000000001-000",
+		WebhookID:     wh.ID,
+		EventType:     models.EventTypeDomain,
+		EventOp:       models.EventOpCreate,
+		Payload:       []byte(`{"delivery_id":"000000000001-000"}`),
+		Status:        models.DeliveryStatusPending,
+		NextAttemptAt: time.Now().Unix(),
+	}
+	if err := webhookStorage.EnqueueDeliveries(ctx, []*models.WebhookDelivery{d}, 1); err != nil {
+		t.Fatalf("EnqueueDeliveries: %v", err)
+	}
+	return s, webhookStorage, d
+}
+
+// getDelivery 读取投递记录的当前状态
+func getDelivery(t *testing.T, webhookStorage *etcd.WebhookStorage, d *models.WebhookDelivery) *models.WebhookDelivery {
+	t.Helper()
+	current, err := webhookStorage.GetDelivery(context.Background(), d.WebhookID, d.ID)
+	if err != nil {
+		t.Fatalf("GetDelivery: %v", err)
+	}
+	return current
+}
+
+// makeDue 将下次投递时间提前到当前，模拟重试间隔已过
+func makeDue(t *testing.T, webhookStorage *etcd.WebhookStorage, d *models.WebhookDelivery) {
+	t.Helper()
+	current := getDelivery(t, webhookStorage, d)
+	current.NextAttemptAt = time.Now().Unix()
+	if err := webhookStorage.UpdateDelivery(context.Background(), current); err != nil {
+		t.Fatalf("UpdateDelivery: %v", err)
+	}
+}
+
+// TestWebhookSignature 签名头为 HMAC-SHA256(secret, timestamp + "." + body)，投递成功后记录状态
+func TestWebhookSignature(t *testing.T) {
+	receiver := &webhookReceiver{}
+	receiver.status.Store(http.StatusOK)
+	s, webhookStorage, d := newTestWebhookService(t, receiver)
+
+	s.dispatch(context.Background(), false)
+
+	requests := receiver.received()
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	req := requests[0]
+	if string(req.body) != string(d.Payload) {
+		t.Errorf("body = %s, want %s", req.body, d.Payload)
+	}
+	want := "sha256=" + signWebhook("0123456789abcdef", req.header.Get("X-Dancer-Timestamp"), req.body)
+	if got := req.header.Get("X-Dancer-Signature"); got != want {
+		t.Errorf("X-Dancer-Signature = %q, want %q", got, want)
+	}
+	if got := req.header.Get("X-Dancer-Delivery"); got != d.ID {
+		t.Errorf("X-Dancer-Delivery = %q, want %q", got, d.ID)
+	}
+	if got := req.header.Get("X-Dancer-Event"); got != "domain.create" {
+		t.Errorf("X-Dancer-Event = %q, want %q", got, "domain.create")
+	}
+
+	current := getDelivery(t, webhookStorage, d)
+	if current.Status != models.DeliveryStatusSucceeded || current.Attempts != 1 || current.ResponseStatus != http.StatusOK {
+		t.Errorf("delivery = %s after %d attempts (status %d), want succeeded after 1 attempt (status 200)",
+			current.Status, current.Attempts, current.ResponseStatus)
+	}
+}
+
+// TestWebhookRetryAndDeadLetter 5xx 响应按退避间隔重试，用尽尝试次数后进入死信，重新投递后再次发送
+func TestWebhookRetryAndDeadLetter(t *testing.T) {
+	receiver := &webhookReceiver{}
+	receiver.status.Store(http.StatusInternalServerError)
+	s, webhookStorage, d := newTestWebhookService(t, receiver)
+	ctx := context.Background()
+
+	// 第一次失败后等待 initialBackoff
+	before := time.Now().Unix()
+	s.dispatch(ctx, false)
+	current := getDelivery(t, webhookStorage, d)
+	if current.Status != models.DeliveryStatusPending || current.Attempts != 1 || current.ResponseStatus != http.StatusInternalServerError {
+		t.Fatalf("delivery = %s after %d attempts (status %d), want pending after 1 attempt (status 500)",
+			current.Status, current.Attempts, current.ResponseStatus)
+	}
+	if wait := current.NextAttemptAt - before; wait < 5 || wait > 6 {
+		t.Errorf("first retry in %ds, want 5s", wait)
+	}
+
+	// 重试间隔未到时不投递
+	s.dispatch(ctx, false)
+	if n := len(receiver.received()); n != 1 {
+		t.Fatalf("got %d requests before the backoff elapsed, want 1", n)
+	}
+
+	// 第二次失败后间隔翻倍
+	makeDue(t, webhookStorage, d)
+	before = time.Now().Unix()
+	s.dispatch(ctx, false)
+	current = getDelivery(t, webhookStorage, d)
+	if current.Attempts != 2 {
+		t.Fatalf("attempts = %d, want 2", current.Attempts)
+	}
+	if wait := current.NextAttemptAt - before; wait < 10 || wait > 11 {
+		t.Errorf("second retry in %ds, want 10s", wait)
+	}
+
+	// 达到 MaxAttempts 后进入死信，不再投递
+	makeDue(t, webhookStorage, d)
+	s.dispatch(ctx, false)
+	current = getDelivery(t, webhookStorage, d)
+	if current.Status != models.DeliveryStatusDead || current.Attempts != 3 {
+		t.Fatalf("delivery = %s after %d attempts, want dead after 3 attempts", current.Status, current.Attempts)
+	}
+	s.dispatch(ctx, false)
+	if n := len(receiver.received()); n != 3 {
+		t.Fatalf("got %d requests, want 3", n)
+	}
+
+	// 重新投递后再次发送相同的内容
+	receiver.status.Store(http.StatusNoContent)
+	redelivered, err := s.Redeliver(ctx, &models.RedeliverWebhookRequest{WebhookID: d.WebhookID, ID: d.ID})
+	if err != nil {
+		t.Fatalf("Redeliver: %v", err)
+	}
+	if redelivered.Status != models.DeliveryStatusPending || redelivered.Attempts != 0 {
+		t.Fatalf("redelivered = %s after %d attempts, want pending after 0 attempts", redelivered.Status, redelivered.Attempts)
+	}
+	s.dispatch(ctx, false)
+
+	requests := receiver.received()
+	if len(requests) != 4 {
+		t.Fatalf("got %d requests, want 4", len(requests))
+	}
+	if string(requests[3].body) != string(d.Payload) {
+		t.Errorf("redelivered body = %s, want %s", requests[3].body, d.Payload)
+	}
+	current = getDelivery(t, webhookStorage, d)
+	if current.Status != models.DeliveryStatusSucceeded || current.Attempts != 1 || current.Error != "" {
+		t.Errorf("delivery = %s after %d attempts (error %q), want succeeded after 1 attempt",
+			current.Status, current.Attempts, current.Error)
+	}
+}
+
+// TestWebhookPendingIndex 投递器只读取等待投递的记录，升级前没有索引的记录补写索引后继续投递
+func TestWebhookPendingIndex(t *testing.T) {
+	receiver := &webhookReceiver{}
+	receiver.status.Store(http.StatusOK)
+	s, webhookStorage, d := newTestWebhookService(t, receiver)
+	ctx := context.Background()
+
+	s.dispatch(ctx, false)
+	pending, err := webhookStorage.ListPendingDeliveries(ctx)
+	if err != nil {
+		t.Fatalf("ListPendingDeliveries: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("got %d pending deliveries after success, want 0", len(pending))
+	}
+
+	// 升级前写入的待投递记录只有记录本身
+	legacy := *d
+	legacy.ID = "000000000002-000"
+	data, err := json.Marshal(&legacy)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if _, err := s.etcdClient.GetClient().Put(ctx, storage.WebhookDeliveryKeyPrefix+d.WebhookID+"/"+legacy.ID, string(data)); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := webhookStorage.IndexPendingDeliveries(ctx); err != nil {
+		t.Fatalf("IndexPendingDeliveries: %v", err)
+	}
+	s.dispatch(ctx, false)
+
+	requests := receiver.received()
+	if len(requests) != 2 || requests[1].header.Get("X-Dancer-Delivery") != legacy.ID {
+		t.Fatalf("got %d requests, want the legacy delivery to be sent second", len(requests))
+	}
+}
+
+// TestWebhookCompactedCursor 已处理到的 revision 被压缩时向订阅推送 reset，并从当前 revision 继续
+func TestWebhookCompactedCursor(t *testing.T) {
+	receiver := &webhookReceiver{}
+	receiver.status.Store(http.StatusOK)
+	s, webhookStorage, d := newTestWebhookService(t, receiver)
+	s.dispatch(context.Background(), false)
+
+	raw := s.etcdClient.GetClient()
+	resp, err := raw.Get(context.Background(), "/")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, err := raw.Compact(context.Background(), resp.Header.Revision); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = s.collectOnce(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	reset := &models.WebhookDelivery{WebhookID: d.WebhookID, ID: fmt.Sprintf("%012d-reset", resp.Header.Revision)}
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, err := webhookStorage.GetDelivery(context.Background(), reset.WebhookID, reset.ID); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no reset delivery after the cursor was compacted")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	cursor, err := webhookStorage.GetCursor(context.Background())
+	if err != nil {
+		t.Fatalf("GetCursor: %v", err)
+	}
+	if cursor != resp.Header.Revision {
+		t.Errorf("cursor = %d, want %d", cursor, resp.Header.Revision)
+	}
+
+	s.dispatch(context.Background(), false)
+	requests := receiver.received()
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	if got := requests[1].header.Get("X-Dancer-Event"); got != "reset" {
+		t.Errorf("X-Dancer-Event = %q, want reset", got)
+	}
+	var payload struct {
+		Type string               `json:"type"`
+		Data *models.WebhookReset `json:"data"`
+	}
+	if err := json.Unmarshal(requests[1].body, &payload); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if payload.Type != "reset" || payload.Data == nil || payload.Data.FromRevision != 1 || payload.Data.ToRevision != resp.Header.Revision {
+		t.Errorf("payload = %s, want reset from revision 1 to %d", requests[1].body, resp.Header.Revision)
+	}
+}
+
+// TestWebhookBackoff 重试间隔从 initialBackoff 开始翻倍，不超过 maxBackoff
+func TestWebhookBackoff(t *testing.T) {
+	s := &WebhookService{initialBackoff: 5 * time.Second, maxBackoff: 60 * time.Second}
+
+	want := []time.Duration{5, 10, 20, 40, 60, 60}
+	for i, w := range want {
+		if got := s.backoff(i + 1); got != w*time.Second {
+			t.Errorf("backoff(%d) = %s, want %s", i+1, got, w*time.Second)
+		}
+	}
+}
@@ -2,38 +2,82 @@ package services
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"time"
 
 	"dancer/internal/errors"
 	"dancer/internal/models"
+	"dancer/internal/publisher"
 	"dancer/internal/storage/etcd"
+	"dancer/internal/tracing"
 )
 
 // ZoneService Zone 业务逻辑
 type ZoneService struct {
 	zoneStorage   *etcd.ZoneStorage
 	domainStorage *etcd.DomainStorage
+	trashStorage  *etcd.TrashStorage
+	publishers    *publisher.Registry
 }
 
-func NewZoneService(zoneStorage *etcd.ZoneStorage, domainStorage *etcd.DomainStorage) *ZoneService {
+func NewZoneService(zoneStorage *etcd.ZoneStorage, domainStorage *etcd.DomainStorage, trashStorage *etcd.TrashStorage, publishers *publisher.Registry) *ZoneService {
 	return &ZoneService{
 		zoneStorage:   zoneStorage,
 		domainStorage: domainStorage,
+		trashStorage:  trashStorage,
+		publishers:    publishers,
 	}
 }
 
-// ListZones 列出所有 Zone
-func (s *ZoneService) ListZones(ctx context.Context) ([]*models.Zone, error) {
-	return s.zoneStorage.ListZones(ctx)
+// ListZones 分页列出 Zone，支持过滤和排序
+func (s *ZoneService) ListZones(ctx context.Context, req *models.ListZonesRequest) (*models.Page[*models.Zone], error) {
+	ctx, span := tracing.Start(ctx, "ZoneService.ListZones")
+	defer span.End()
+
+	var f filters[*models.Zone]
+	if req.NamePrefix != "" {
+		f.add(func(z *models.Zone) bool { return strings.HasPrefix(z.Zone, req.NamePrefix) })
+	}
+	if req.NameContains != "" {
+		f.add(func(z *models.Zone) bool { return containsFold(z.Zone, req.NameContains) })
+	}
+	if req.UpdatedSince > 0 {
+		f.add(func(z *models.Zone) bool { return z.UpdatedAt >= req.UpdatedSince })
+	}
+
+	// 默认按 Zone 名称排序，即 etcd key 顺序
+	var less func(a, b *models.Zone) bool
+	switch req.Sort {
+	case "record_count":
+		less = lessBy(func(z *models.Zone) int { return z.RecordCount })
+	case "created_at":
+		less = lessBy(func(z *models.Zone) int64 { return z.CreatedAt })
+	case "updated_at":
+		less = lessBy(func(z *models.Zone) int64 { return z.UpdatedAt })
+	}
+
+	return s.zoneStorage.ListZonesPage(ctx, listQuery(req.PageRequest, req.NamePrefix, f, less))
 }
 
 // GetZone 获取 Zone 详情
 func (s *ZoneService) GetZone(ctx context.Context, zone string) (*models.Zone, error) {
+	ctx, span := tracing.Start(ctx, "ZoneService.GetZone")
+	defer span.End()
+
 	return s.zoneStorage.GetZone(ctx, zone)
 }
 
 // CreateZone 创建 Zone
 func (s *ZoneService) CreateZone(ctx context.Context, req *models.CreateZoneRequest) (*models.Zone, error) {
+	ctx, span := tracing.Start(ctx, "ZoneService.CreateZone")
+	defer span.End()
+
+	publishers, err := s.checkPublishers(req.Publishers)
+	if err != nil {
+		return nil, err
+	}
+
 	// 检查是否已存在
 	exists, err := s.zoneStorage.ZoneExists(ctx, req.Zone)
 	if err != nil {
@@ -44,10 +88,12 @@ func (s *ZoneService) CreateZone(ctx context.Context, req *models.CreateZoneRequ
 	}
 
 	zone := &models.Zone{
-		Zone:        req.Zone,
-		RecordCount: 0,
-		CreatedAt:   time.Now().Unix(),
-		UpdatedAt:   time.Now().Unix(),
+		Zone:             req.Zone,
+		RecordCount:      0,
+		ApprovalRequired: req.ApprovalRequired,
+		Publishers:       publishers,
+		CreatedAt:        time.Now().Unix(),
+		UpdatedAt:        time.Now().Unix(),
 	}
 
 	if err := s.zoneStorage.CreateZone(ctx, zone); err != nil {
@@ -59,29 +105,85 @@ func (s *ZoneService) CreateZone(ctx context.Context, req *models.CreateZoneRequ
 
 // UpdateZone 更新 Zone
 func (s *ZoneService) UpdateZone(ctx context.Context, req *models.UpdateZoneRequest) (*models.Zone, error) {
+	ctx, span := tracing.Start(ctx, "ZoneService.UpdateZone")
+	defer span.End()
+
 	// 检查是否存在
 	zone, err := s.zoneStorage.GetZone(ctx, req.Zone)
 	if err != nil {
 		return nil, err
 	}
 
+	if req.ApprovalRequired != nil {
+		zone.ApprovalRequired = *req.ApprovalRequired
+	}
+	wasViews := s.zoneViews(zone)
+	if req.Publishers != nil {
+		if zone.Publishers, err = s.checkPublishers(req.Publishers); err != nil {
+			return nil, err
+		}
+	}
 	zone.UpdatedAt = time.Now().Unix()
 
 	if err := s.zoneStorage.UpdateZone(ctx, zone); err != nil {
 		return nil, err
 	}
 
+	// 加入或去掉 CoreDNS 视图时立即写入或撤下视图中的记录，其他发布目标由 PublishService 异步同步
+	if !slices.Equal(s.zoneViews(zone), wasViews) {
+		if err := s.domainStorage.ResyncCoreDNS(ctx, zone); err != nil {
+			return nil, err
+		}
+	}
+
 	return zone, nil
 }
 
-// DeleteZone 删除 Zone（级联删除所有 Domain）
-func (s *ZoneService) DeleteZone(ctx context.Context, req *models.DeleteZoneRequest) error {
+// zoneViews 返回 Zone 发布到的 CoreDNS 视图，按配置顺序
+func (s *ZoneService) zoneViews(zone *models.Zone) []string {
+	var views []string
+	for _, name := range s.publishers.Views() {
+		if zone.PublishesTo(name) {
+			views = append(views, name)
+		}
+	}
+	return views
+}
+
+// checkPublishers 校验发布目标名称均已配置，去掉重复的名称；未指定时返回 nil，即只发布到 coredns
+func (s *ZoneService) checkPublishers(names []string) ([]string, error) {
+	var publishers []string
+	for _, name := range names {
+		if _, ok := s.publishers.Get(name); !ok {
+			return nil, errors.ErrUnknownPublisher
+		}
+		if !slices.Contains(publishers, name) {
+			publishers = append(publishers, name)
+		}
+	}
+	return publishers, nil
+}
+
+// DeleteZone 删除 Zone（级联删除所有 Domain），Zone 及其 Domain 移入回收站
+// Zone 下仍有 Domain 时需要在 confirm 中填写 Zone 名称
+func (s *ZoneService) DeleteZone(ctx context.Context, req *models.DeleteZoneRequest) (*models.TrashEntry, error) {
+	ctx, span := tracing.Start(ctx, "ZoneService.DeleteZone")
+	defer span.End()
+
 	// 检查是否存在
 	_, err := s.zoneStorage.GetZone(ctx, req.Zone)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	count, err := s.domainStorage.GetDomainCountByZone(ctx, req.Zone)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 && req.Confirm != req.Zone {
+		return nil, errors.ErrZoneNotEmpty
 	}
 
-	// 删除 Zone 及所有 Domain
-	return s.zoneStorage.DeleteZone(ctx, req.Zone)
+	// 删除 Zone 及所有 Domain，撤下 CoreDNS 记录
+	return s.trashStorage.TrashZone(ctx, req.Zone)
 }
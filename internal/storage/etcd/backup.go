@@ -0,0 +1,209 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"dancer/internal/config"
+	"dancer/internal/errors"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"dancer/internal/tenant"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// BackupStorage 全量备份与恢复存储操作
+type BackupStorage struct {
+	client  *Client
+	config  *config.Config
+	domains *DomainStorage
+	trash   *TrashStorage
+}
+
+func NewBackupStorage(client *Client, cfg *config.Config) *BackupStorage {
+	return &BackupStorage{
+		client:  client,
+		config:  cfg,
+		domains: NewDomainStorage(client, cfg),
+		trash:   NewTrashStorage(client, cfg),
+	}
+}
+
+// Export 导出 ctx 所属租户的用户、Zone、Domain 及其在默认 CoreDNS 视图中的静态记录，所有数据读取自同一 revision
+// 临时 Domain 与动态实例不导出；其他视图的记录可由 Domain 的 view_ips 重新生成，不导出
+func (s *BackupStorage) Export(ctx context.Context) (*models.Backup, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	t := tenant.FromContext(ctx)
+	resp, err := s.client.client.Get(ctx, storage.UserPrefix(t), clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	rev := resp.Header.Revision
+
+	backup := &models.Backup{
+		Version:           models.BackupVersion,
+		CreatedAt:         time.Now().Unix(),
+		Revision:          rev,
+		CoreDNSPrefix:     s.domains.views[0].prefix(),
+		PasswordsIncluded: true,
+		Users:             make([]*models.User, 0, len(resp.Kvs)),
+	}
+	for _, kv := range resp.Kvs {
+		var user models.User
+		if err := json.Unmarshal(kv.Value, &user); err != nil {
+			continue
+		}
+		backup.Users = append(backup.Users, &user)
+	}
+
+	resp, err = s.client.client.Get(ctx, storage.ZonePrefix(t), clientv3.WithPrefix(), clientv3.WithRev(rev))
+	if err != nil {
+		return nil, err
+	}
+	backup.Zones = make([]*models.Zone, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var zone models.Zone
+		if err := json.Unmarshal(kv.Value, &zone); err != nil {
+			continue
+		}
+		backup.Zones = append(backup.Zones, &zone)
+	}
+
+	resp, err = s.client.client.Get(ctx, storage.DomainPrefix(t), clientv3.WithPrefix(), clientv3.WithRev(rev))
+	if err != nil {
+		return nil, err
+	}
+	backup.Domains = make([]*models.Domain, 0, len(resp.Kvs))
+	recordPrefixes := make(map[string]bool, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var domain models.Domain
+		if err := json.Unmarshal(kv.Value, &domain); err != nil || domain.IsEphemeral() {
+			continue
+		}
+		backup.Domains = append(backup.Domains, &domain)
+		recordPrefixes[s.domains.views[0].domainPrefix(domain.Zone, domain.Domain)] = true
+	}
+
+	// CoreDNS 前缀下可能还有不由 Dancer 管理的记录，只导出属于已导出 Domain 的静态记录
+	resp, err = s.client.client.Get(ctx, s.domains.views[0].prefix(), clientv3.WithPrefix(), clientv3.WithRev(rev))
+	if err != nil {
+		return nil, err
+	}
+	backup.CoreDNSRecords = make([]*models.BackupKeyValue, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		key := string(kv.Key)
+		if !isStaticRecordKey(key) || !recordPrefixes[path.Dir(key)+"/"] {
+			continue
+		}
+		backup.CoreDNSRecords = append(backup.CoreDNSRecords, &models.BackupKeyValue{Key: key, Value: string(kv.Value)})
+	}
+
+	return backup, nil
+}
+
+// Restore 在 ctx 所属租户中执行恢复计划，恢复的 Zone 登记为该租户所有
+// 先将删除的 Zone 移入回收站，再按 etcd 单个事务的操作数上限分块提交其余修改，Domain 的 CoreDNS 记录按当前状态重新同步；
+// 恢复不是原子的，中途失败时可以重新执行
+func (s *BackupStorage) Restore(ctx context.Context, plan *models.RestorePlan) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	for _, zone := range plan.DeleteZones {
+		if _, err := s.trash.TrashZone(ctx, zone.Zone); err != nil && err != errors.ErrZoneNotFound {
+			return err
+		}
+	}
+
+	var ops []clientv3.Op
+	for _, user := range plan.DeleteUsers {
+		ops = append(ops, clientv3.OpDelete(storage.UserPrefix(tenant.FromContext(ctx))+user.ID))
+	}
+	for _, user := range plan.PutUsers {
+		data, err := json.Marshal(user)
+		if err != nil {
+			return fmt.Errorf("failed to marshal user: %w", err)
+		}
+		ops = append(ops, clientv3.OpPut(storage.UserPrefix(tenant.FromContext(ctx))+user.ID, string(data)))
+	}
+	for _, zone := range plan.PutZones {
+		data, err := json.Marshal(zone)
+		if err != nil {
+			return err
+		}
+		ops = append(ops,
+			clientv3.OpPut(storage.ZoneOwnerKeyPrefix+zone.Zone, tenant.FromContext(ctx)),
+			clientv3.OpPut(zoneKey(ctx, zone.Zone), string(data)),
+		)
+	}
+
+	// 删除的 Domain 移入回收站，撤销其动态实例的租约
+	var leases []int64
+	for _, domain := range plan.DeleteDomains {
+		instancePrefix := s.domains.instancePrefix(domain.Zone, domain.Domain)
+		instances, err := s.domains.listInstances(ctx, instancePrefix)
+		if err != nil {
+			return err
+		}
+		for _, inst := range instances {
+			leases = append(leases, inst.LeaseID)
+		}
+		trashOp, err := trashDomainOp(ctx, domain, s.config.Trash.Retention)
+		if err != nil {
+			return err
+		}
+		ops = append(ops,
+			clientv3.OpDelete(s.domains.domainKey(ctx, domain.Zone, domain.Domain)),
+			clientv3.OpDelete(instancePrefix, clientv3.WithPrefix()),
+			trashOp,
+		)
+		ops = append(ops, s.domains.deleteCoreDNSOps(domain.Zone, domain.Domain)...)
+	}
+
+	// Zone 与 Domain 在同一批操作中写入，按恢复后的 Zone 决定是否写入 CoreDNS 记录
+	zones := make(map[string]*models.Zone, len(plan.PutZones))
+	for _, zone := range plan.PutZones {
+		zones[zone.Zone] = zone
+	}
+	for _, domain := range plan.PutDomains {
+		domainOps, err := s.domains.domainPutOps(ctx, domain, zones[domain.Zone])
+		if err != nil {
+			return err
+		}
+		ops = append(ops, domainOps...)
+	}
+	for _, domain := range plan.SyncDomains {
+		coreDNSOps, err := s.domains.planCoreDNSOps(ctx, domain, zones[domain.Zone])
+		if err != nil {
+			return err
+		}
+		ops = append(ops, coreDNSOps...)
+	}
+
+	if err := s.commitChunked(ctx, ops); err != nil {
+		return err
+	}
+
+	for _, leaseID := range leases {
+		s.domains.revokeLease(ctx, leaseID)
+	}
+	return nil
+}
+
+// commitChunked 按 etcd 单个事务的操作数上限分块提交
+func (s *BackupStorage) commitChunked(ctx context.Context, ops []clientv3.Op) error {
+	maxOps := s.config.Etcd.MaxTxnOps
+	for start := 0; start < len(ops); start += maxOps {
+		end := min(start+maxOps, len(ops))
+		if _, err := s.client.client.Txn(ctx).Then(ops[start:end]...).Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package etcd
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// cacheSyncTimeout 读缓存时等待缓存追上本进程最近一次写入的最长时间，超时后直接读 etcd
+const cacheSyncTimeout = time.Second
+
+// prefixCache 单个前缀的只读缓存
+// 启动时全量读取前缀，之后通过 watch 增量更新；watch 中断（压缩、重连）时重新全量读取
+type prefixCache struct {
+	prefix string
+
+	mu       sync.RWMutex
+	kvs      map[string]*mvccpb.KeyValue
+	revision int64         // 缓存已同步到的 etcd revision
+	ready    bool          // 是否已完成首次加载
+	updated  chan struct{} // 每次更新后关闭并替换，用于唤醒等待者
+}
+
+// CacheStatus 缓存状态
+type CacheStatus struct {
+	Prefix   string `json:"prefix"`
+	Ready    bool   `json:"ready"`
+	Revision int64  `json:"revision"`
+	Keys     int    `json:"keys"`
+}
+
+func newPrefixCache(prefix string) *prefixCache {
+	return &prefixCache{
+		prefix:  prefix,
+		kvs:     make(map[string]*mvccpb.KeyValue),
+		updated: make(chan struct{}),
+	}
+}
+
+// Reset 实现 WatchHandler，用全量数据重建缓存
+func (pc *prefixCache) Reset(kvs []*mvccpb.KeyValue, revision int64) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	pc.kvs = make(map[string]*mvccpb.KeyValue, len(kvs))
+	for _, kv := range kvs {
+		pc.kvs[string(kv.Key)] = kv
+	}
+	pc.revision = revision
+	pc.ready = true
+	pc.notify()
+}
+
+// Apply 实现 WatchHandler，应用增量事件
+func (pc *prefixCache) Apply(events []*clientv3.Event, revision int64) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	for _, ev := range events {
+		if ev.Type == clientv3.EventTypeDelete {
+			delete(pc.kvs, string(ev.Kv.Key))
+		} else {
+			pc.kvs[string(ev.Kv.Key)] = ev.Kv
+		}
+	}
+	pc.revision = max(pc.revision, revision)
+	pc.notify()
+}
+
+// notify 唤醒等待缓存更新的读请求，调用方需持有写锁
+func (pc *prefixCache) notify() {
+	close(pc.updated)
+	pc.updated = make(chan struct{})
+}
+
+// caughtUp 缓存是否已同步到 revision，返回用于等待下一次更新的 channel
+func (pc *prefixCache) caughtUp(revision int64) (bool, <-chan struct{}) {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	return pc.ready && pc.revision >= revision, pc.updated
+}
+
+// get 读取单个 key，调用方需确认缓存已同步
+func (pc *prefixCache) get(key string) *mvccpb.KeyValue {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	return pc.kvs[key]
+}
+
+// list 按 key 顺序读取前缀下的数据，调用方需确认缓存已同步
+func (pc *prefixCache) list(prefix string) []*mvccpb.KeyValue {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	kvs := make([]*mvccpb.KeyValue, 0)
+	for key, kv := range pc.kvs {
+		if strings.HasPrefix(key, prefix) {
+			kvs = append(kvs, kv)
+		}
+	}
+	sort.Slice(kvs, func(i, j int) bool {
+		return string(kvs[i].Key) < string(kvs[j].Key)
+	})
+	return kvs
+}
+
+func (pc *prefixCache) status() CacheStatus {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	return CacheStatus{Prefix: pc.prefix, Ready: pc.ready, Revision: pc.revision, Keys: len(pc.kvs)}
+}
+
+// StartCache 为指定前缀启用读缓存，缓存在 ctx 取消前持续同步
+// 未启用缓存、缓存尚未加载完成或未能及时追上本进程的写入时，读取直接访问 etcd
+func (c *Client) StartCache(ctx context.Context, prefixes ...string) {
+	caches := make([]*prefixCache, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		pc := newPrefixCache(prefix)
+		caches = append(caches, pc)
+		go c.WatchPrefix(ctx, prefix, pc)
+	}
+
+	c.stateMu.Lock()
+	c.caches = append(c.caches, caches...)
+	c.stateMu.Unlock()
+}
+
+// CacheStatus 返回所有读缓存的状态
+func (c *Client) CacheStatus() []CacheStatus {
+	c.stateMu.RLock()
+	defer c.stateMu.RUnlock()
+
+	statuses := make([]CacheStatus, len(c.caches))
+	for i, pc := range c.caches {
+		statuses[i] = pc.status()
+	}
+	return statuses
+}
+
+// observeWrite 记录本进程写入后的 revision，之后的缓存读取至少要同步到该 revision
+func (c *Client) observeWrite(revision int64) {
+	for {
+		current := atomic.LoadInt64(&c.writeRevision)
+		if revision <= current || atomic.CompareAndSwapInt64(&c.writeRevision, current, revision) {
+			return
+		}
+	}
+}
+
+// syncedCache 返回覆盖 prefix 且已同步到本进程最近一次写入的缓存
+// 缓存落后时请求 watch 进度通知，使没有新事件的缓存也能推进 revision；等待超时返回 nil
+func (c *Client) syncedCache(ctx context.Context, prefix string) *prefixCache {
+	c.stateMu.RLock()
+	var pc *prefixCache
+	for _, candidate := range c.caches {
+		if strings.HasPrefix(prefix, candidate.prefix) {
+			pc = candidate
+			break
+		}
+	}
+	client := c.client
+	c.stateMu.RUnlock()
+	if pc == nil {
+		return nil
+	}
+
+	revision := atomic.LoadInt64(&c.writeRevision)
+	timeout := time.NewTimer(cacheSyncTimeout)
+	defer timeout.Stop()
+
+	requested := false
+	for {
+		ok, updated := pc.caughtUp(revision)
+		if ok {
+			return pc
+		}
+		if !requested && client != nil {
+			requested = true
+			_ = client.RequestProgress(clientv3.WithRequireLeader(ctx))
+		}
+
+		select {
+		case <-updated:
+		case <-timeout.C:
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// getKV 读取单个 key，优先使用读缓存，key 不存在时返回 nil
+func (c *Client) getKV(ctx context.Context, key string) (*mvccpb.KeyValue, error) {
+	if pc := c.syncedCache(ctx, key); pc != nil {
+		return pc.get(key), nil
+	}
+
+	resp, err := c.client.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	return resp.Kvs[0], nil
+}
+
+// listKVs 按 key 顺序读取前缀下的所有数据，优先使用读缓存
+func (c *Client) listKVs(ctx context.Context, prefix string) ([]*mvccpb.KeyValue, error) {
+	if pc := c.syncedCache(ctx, prefix); pc != nil {
+		return pc.list(prefix), nil
+	}
+
+	resp, err := c.client.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	return resp.Kvs, nil
+}
+
+// trackingKV 包装 etcd KV，记录写操作返回的 revision
+type trackingKV struct {
+	clientv3.KV
+	observe func(revision int64)
+}
+
+func (kv *trackingKV) Put(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	resp, err := kv.KV.Put(ctx, key, val, opts...)
+	if err == nil {
+		kv.observe(resp.Header.Revision)
+	}
+	return resp, err
+}
+
+func (kv *trackingKV) Delete(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	resp, err := kv.KV.Delete(ctx, key, opts...)
+	if err == nil && resp.Deleted > 0 {
+		kv.observe(resp.Header.Revision)
+	}
+	return resp, err
+}
+
+func (kv *trackingKV) Do(ctx context.Context, op clientv3.Op) (clientv3.OpResponse, error) {
+	resp, err := kv.KV.Do(ctx, op)
+	if err == nil && !op.IsGet() {
+		switch {
+		case resp.Put() != nil:
+			kv.observe(resp.Put().Header.Revision)
+		case resp.Del() != nil:
+			kv.observe(resp.Del().Header.Revision)
+		case resp.Txn() != nil:
+			kv.observe(resp.Txn().Header.Revision)
+		}
+	}
+	return resp, err
+}
+
+func (kv *trackingKV) Txn(ctx context.Context) clientv3.Txn {
+	return &trackingTxn{Txn: kv.KV.Txn(ctx), observe: kv.observe}
+}
+
+// trackingTxn 包装 etcd Txn，提交后记录 revision
+type trackingTxn struct {
+	clientv3.Txn
+	observe func(revision int64)
+}
+
+func (t *trackingTxn) If(cs ...clientv3.Cmp) clientv3.Txn {
+	t.Txn = t.Txn.If(cs...)
+	return t
+}
+
+func (t *trackingTxn) Then(ops ...clientv3.Op) clientv3.Txn {
+	t.Txn = t.Txn.Then(ops...)
+	return t
+}
+
+func (t *trackingTxn) Else(ops ...clientv3.Op) clientv3.Txn {
+	t.Txn = t.Txn.Else(ops...)
+	return t
+}
+
+func (t *trackingTxn) Commit() (*clientv3.TxnResponse, error) {
+	resp, err := t.Txn.Commit()
+	if err == nil {
+		t.observe(resp.Header.Revision)
+	}
+	return resp, err
+}
@@ -0,0 +1,137 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"dancer/internal/errors"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"dancer/internal/tenant"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// ChangeRequestStorage 变更申请存储操作，记录所属租户，查询只返回 ctx 所属租户的变更申请
+type ChangeRequestStorage struct {
+	client *Client
+}
+
+func NewChangeRequestStorage(client *Client) *ChangeRequestStorage {
+	return &ChangeRequestStorage{client: client}
+}
+
+// ListChangeRequests 列出 ctx 所属租户的变更申请
+func (s *ChangeRequestStorage) ListChangeRequests(ctx context.Context) ([]*models.ChangeRequest, error) {
+	all, err := s.listAllChangeRequests(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make([]*models.ChangeRequest, 0, len(all))
+	for _, cr := range all {
+		if tenant.Of(cr.Tenant) == tenant.FromContext(ctx) {
+			owned = append(owned, cr)
+		}
+	}
+	return owned, nil
+}
+
+// listAllChangeRequests 列出所有租户的变更申请
+func (s *ChangeRequestStorage) listAllChangeRequests(ctx context.Context) ([]*models.ChangeRequest, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.ChangeKeyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*models.ChangeRequest, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var cr models.ChangeRequest
+		if err := json.Unmarshal(kv.Value, &cr); err != nil {
+			continue
+		}
+		cr.Revision = kv.ModRevision
+		changes = append(changes, &cr)
+	}
+
+	return changes, nil
+}
+
+// GetChangeRequest 获取 ctx 所属租户的变更申请
+func (s *ChangeRequestStorage) GetChangeRequest(ctx context.Context, id string) (*models.ChangeRequest, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.ChangeKeyPrefix+id)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, errors.ErrChangeRequestNotFound
+	}
+
+	var cr models.ChangeRequest
+	if err := json.Unmarshal(resp.Kvs[0].Value, &cr); err != nil {
+		return nil, err
+	}
+	if tenant.Of(cr.Tenant) != tenant.FromContext(ctx) {
+		return nil, errors.ErrChangeRequestNotFound
+	}
+	cr.Revision = resp.Kvs[0].ModRevision
+
+	return &cr, nil
+}
+
+// CreateChangeRequest 创建属于 ctx 所属租户的变更申请，ID 已被占用时改用下一个 ID
+func (s *ChangeRequestStorage) CreateChangeRequest(ctx context.Context, cr *models.ChangeRequest) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	cr.Tenant = tenant.FromContext(ctx)
+	revision, err := s.client.createRecord(ctx, storage.ChangeKeyPrefix, cr.ID, func(id string) ([]byte, error) {
+		cr.ID = id
+		data, err := json.Marshal(cr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal change request: %w", err)
+		}
+		return data, nil
+	})
+	if err != nil {
+		return err
+	}
+	cr.Revision = revision
+	return nil
+}
+
+// UpdateChangeRequest 更新变更申请
+// 仅当 etcd 中的记录自读取后未被修改时才写入，否则返回 ErrChangeRequestNotPending
+func (s *ChangeRequestStorage) UpdateChangeRequest(ctx context.Context, cr *models.ChangeRequest) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	data, err := json.Marshal(cr)
+	if err != nil {
+		return fmt.Errorf("failed to marshal change request: %w", err)
+	}
+
+	key := storage.ChangeKeyPrefix + cr.ID
+	resp, err := s.client.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", cr.Revision)).
+		Then(clientv3.OpPut(key, string(data))).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return errors.ErrChangeRequestNotPending
+	}
+	cr.Revision = resp.Header.Revision
+	return nil
+}
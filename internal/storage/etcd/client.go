@@ -5,10 +5,12 @@ import (
 	"crypto/tls"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"dancer/internal/config"
 	"dancer/internal/logger"
+	"dancer/internal/metrics"
 	"go.etcd.io/etcd/client/v3"
 )
 
@@ -29,6 +31,14 @@ type Client struct {
 	stateMu   sync.RWMutex
 	stopCh    chan struct{}
 	connectCh chan struct{}
+
+	caches        []*prefixCache // 读缓存，见 StartCache
+	writeRevision int64          // 本进程最近一次写入的 revision，原子访问
+	lost          atomic.Bool    // 健康检查发现连接断开，下一次成功连接计为重连
+
+	electionMu sync.Mutex
+	elections  map[string]*ElectionStatus // 本副本参与的 leader 选举，见 RunAsLeader
+	coreDNS    coreDNSTracker             // CoreDNS 记录写入结果
 }
 
 // NewClient 创建 etcd 客户端（异步初始化，允许启动时无连接）
@@ -107,6 +117,7 @@ func (c *Client) tryConnect() error {
 
 	client, err := clientv3.New(etcdCfg)
 	if err != nil {
+		metrics.EtcdConnectFailures.Inc()
 		c.setState(StateDisconnected)
 		return fmt.Errorf("failed to create etcd client: %w", err)
 	}
@@ -117,12 +128,20 @@ func (c *Client) tryConnect() error {
 
 	if _, err := client.Status(ctx, c.config.Etcd.Endpoints[0]); err != nil {
 		client.Close()
+		metrics.EtcdConnectFailures.Inc()
 		c.setState(StateDisconnected)
 		return fmt.Errorf("failed to connect to etcd: %w", err)
 	}
 
+	// 记录写入的 revision，保证本进程写入后的缓存读取能读到写入结果
+	// 内层为每次操作创建 span，并记录各存储方法的耗时与错误
+	client.KV = &trackingKV{KV: newInstrumentedKV(client.KV, coreDNSPrefixes(c.config), &c.coreDNS), observe: c.observeWrite}
+
 	c.client = client
 	c.setState(StateConnected)
+	if c.lost.CompareAndSwap(true, false) {
+		metrics.EtcdReconnects.Inc()
+	}
 	return nil
 }
 
@@ -183,6 +202,7 @@ func (c *Client) healthCheckLoop() {
 
 			if err != nil {
 				logger.Log.WithError(err).Error("Etcd health check failed")
+				c.lost.Store(true)
 				c.setState(StateDisconnected)
 				c.client.Close()
 				c.client = nil
@@ -263,6 +283,11 @@ func (c *Client) GetClient() *clientv3.Client {
 	return c.client
 }
 
+// CoreDNSPrefix 返回 CoreDNS 视图的记录在 etcd 中的前缀，以 / 结尾；视图未配置时返回 false
+func (c *Client) CoreDNSPrefix(view string) (string, bool) {
+	return coreDNSPrefix(c.config, view)
+}
+
 // Close 关闭客户端
 func (c *Client) Close() error {
 	close(c.stopCh)
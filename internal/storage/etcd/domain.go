@@ -3,57 +3,48 @@ package etcd
 import (
 	"context"
 	"encoding/json"
-	"path"
-	"strconv"
-	"strings"
 	"time"
 
 	"dancer/internal/config"
 	"dancer/internal/errors"
 	"dancer/internal/models"
 	"dancer/internal/storage"
+	"dancer/internal/tenant"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	"go.etcd.io/etcd/client/v3"
 )
 
-// DomainStorage Domain 存储操作
+// DomainStorage Domain 存储操作，Domain 元数据按 ctx 所属租户读写
+// CoreDNS 记录与动态实例按 Zone 存放，Zone 名称在所有租户中唯一
+// Domain 的 CoreDNS 记录与 Domain 元数据在同一事务中写入，Zone 发布到的每个 CoreDNS 视图各有一份记录
 type DomainStorage struct {
 	client *Client
 	config *config.Config
+	views  []*SkyDNSPublisher // 默认视图在最前
 }
 
 func NewDomainStorage(client *Client, cfg *config.Config) *DomainStorage {
 	return &DomainStorage{
 		client: client,
 		config: cfg,
+		views:  newViews(client, cfg),
 	}
 }
 
-// getCoreDNSPrefix 获取 CoreDNS etcd 前缀
-func (s *DomainStorage) getCoreDNSPrefix() string {
-	prefix := s.config.Etcd.CorednsPrefix
-	if prefix == "" {
-		prefix = "/skydns/"
-	}
-	if !strings.HasSuffix(prefix, "/") {
-		prefix += "/"
-	}
-	return prefix
-}
-
 // ListDomainsByZone 列出 Zone 下所有 Domain
 func (s *DomainStorage) ListDomainsByZone(ctx context.Context, zone string) ([]*models.Domain, error) {
 	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
 		return nil, errors.ErrEtcdUnavailable
 	}
 
-	prefix := s.domainPrefix(zone)
-	resp, err := s.client.client.Get(ctx, prefix, clientv3.WithPrefix())
+	kvs, err := s.client.listKVs(ctx, s.domainPrefix(ctx, zone))
 	if err != nil {
 		return nil, err
 	}
 
-	domains := make([]*models.Domain, 0, len(resp.Kvs))
-	for _, kv := range resp.Kvs {
+	domains := make([]*models.Domain, 0, len(kvs))
+	for _, kv := range kvs {
 		var domain models.Domain
 		if err := json.Unmarshal(kv.Value, &domain); err != nil {
 			continue
@@ -61,27 +52,55 @@ func (s *DomainStorage) ListDomainsByZone(ctx context.Context, zone string) ([]*
 		domains = append(domains, &domain)
 	}
 
+	if err := s.fillDynamicIPs(ctx, zone, domains...); err != nil {
+		return nil, err
+	}
+
 	return domains, nil
 }
 
+// ListDomainsPage 分页列出 Zone 下的 Domain，过滤条件可使用动态实例 IP
+func (s *DomainStorage) ListDomainsPage(ctx context.Context, zone string, q *models.ListQuery[*models.Domain]) (*models.Page[*models.Domain], error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	dynamicIPs, err := s.dynamicIPsByDomain(ctx, storage.InstanceKeyPrefix+zone+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	return listPage(ctx, s.client, s.domainPrefix(ctx, zone), q, func(kv *mvccpb.KeyValue) (*models.Domain, bool) {
+		var domain models.Domain
+		if err := json.Unmarshal(kv.Value, &domain); err != nil {
+			return nil, false
+		}
+		domain.DynamicIPs = dynamicIPs[domain.Domain]
+		return &domain, true
+	})
+}
+
 // GetDomain 获取 Domain 详情
 func (s *DomainStorage) GetDomain(ctx context.Context, zone, domain string) (*models.Domain, error) {
 	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
 		return nil, errors.ErrEtcdUnavailable
 	}
 
-	key := s.domainKey(zone, domain)
-	resp, err := s.client.client.Get(ctx, key)
+	kv, err := s.client.getKV(ctx, s.domainKey(ctx, zone, domain))
 	if err != nil {
 		return nil, err
 	}
 
-	if len(resp.Kvs) == 0 {
+	if kv == nil {
 		return nil, errors.ErrDomainNotFound
 	}
 
 	var d models.Domain
-	if err := json.Unmarshal(resp.Kvs[0].Value, &d); err != nil {
+	if err := json.Unmarshal(kv.Value, &d); err != nil {
+		return nil, err
+	}
+
+	if err := s.fillDynamicIPs(ctx, zone, &d); err != nil {
 		return nil, err
 	}
 
@@ -107,20 +126,47 @@ func (s *DomainStorage) CreateDomain(ctx context.Context, domain *models.Domain)
 	domain.CreatedAt = now
 	domain.UpdatedAt = now
 
-	// 保存 Domain 元数据
-	key := s.domainKey(domain.Zone, domain.Domain)
-	data, err := json.Marshal(domain)
+	// 临时 Domain：申请租约，元数据与 CoreDNS 记录均绑定到该租约
+	if domain.LeaseTTL > 0 {
+		lease, err := s.client.client.Grant(ctx, domain.LeaseTTL)
+		if err != nil {
+			return err
+		}
+		domain.LeaseID = int64(lease.ID)
+		domain.ExpiresAt = now + lease.TTL
+	}
+
+	// 仅当 Domain 仍不存在时写入
+	key := s.domainKey(ctx, domain.Zone, domain.Domain)
+	err = s.putDomain(ctx, domain, clientv3.Compare(clientv3.CreateRevision(key), "=", 0), errors.ErrDomainExists)
+	if err != nil && domain.IsEphemeral() {
+		// 写入失败时撤销刚申请的租约
+		s.revokeLease(ctx, domain.LeaseID)
+		domain.LeaseID = 0
+		domain.ExpiresAt = 0
+	}
+	return err
+}
+
+// putDomain 在一个事务中保存 Domain 元数据并同步其在各 CoreDNS 视图中的记录
+// cmp 不满足时不写入并返回 errCmp
+func (s *DomainStorage) putDomain(ctx context.Context, domain *models.Domain, cmp clientv3.Cmp, errCmp error) error {
+	ops, err := s.domainPutOps(ctx, domain, nil)
 	if err != nil {
 		return err
 	}
 
-	_, err = s.client.client.Put(ctx, key, string(data))
+	resp, err := s.client.client.Txn(ctx).If(cmp).Then(ops...).Commit()
 	if err != nil {
+		if rpctypes.Error(err) == rpctypes.ErrLeaseNotFound {
+			return errors.ErrDomainNotFound
+		}
 		return err
 	}
-
-	// 同步到 CoreDNS
-	return s.syncToCoreDNS(ctx, domain)
+	if !resp.Succeeded {
+		return errCmp
+	}
+	return nil
 }
 
 // UpdateDomain 更新 Domain
@@ -146,203 +192,182 @@ func (s *DomainStorage) UpdateDomain(ctx context.Context, domain *models.Domain)
 		domain.TTL = existing.TTL
 	}
 
-	// 保存 Domain 元数据
-	key := s.domainKey(domain.Zone, domain.Domain)
-	data, err := json.Marshal(domain)
-	if err != nil {
-		return err
-	}
-
-	_, err = s.client.client.Put(ctx, key, string(data))
-	if err != nil {
-		return err
-	}
+	// 租约信息沿用原值，更新不会改变 Domain 的生命周期
+	domain.LeaseID = existing.LeaseID
+	domain.LeaseTTL = existing.LeaseTTL
+	domain.ExpiresAt = existing.ExpiresAt
 
-	// 同步到 CoreDNS
-	return s.syncToCoreDNS(ctx, domain)
+	// 仅当 Domain 仍存在时写入，临时 Domain 的租约已过期时返回 ErrDomainNotFound
+	key := s.domainKey(ctx, domain.Zone, domain.Domain)
+	return s.putDomain(ctx, domain, clientv3.Compare(clientv3.CreateRevision(key), ">", 0), errors.ErrDomainNotFound)
 }
 
-// DeleteDomain 删除 Domain
-func (s *DomainStorage) DeleteDomain(ctx context.Context, zone, domain string) error {
+// RenewDomain 续约临时 Domain，返回续约后的 Domain
+func (s *DomainStorage) RenewDomain(ctx context.Context, zone, domain string) (*models.Domain, error) {
 	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
-		return errors.ErrEtcdUnavailable
+		return nil, errors.ErrEtcdUnavailable
 	}
 
-	// 删除 Domain 元数据
-	key := s.domainKey(zone, domain)
-	_, err := s.client.client.Delete(ctx, key)
+	d, err := s.GetDomain(ctx, zone, domain)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	// 删除 CoreDNS 记录
-	return s.deleteCoreDNSRecords(ctx, zone, domain)
-}
-
-// DeleteDomainsByZone 删除 Zone 下所有 Domain（级联删除）
-func (s *DomainStorage) DeleteDomainsByZone(ctx context.Context, zone string) error {
-	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
-		return errors.ErrEtcdUnavailable
+	if !d.IsEphemeral() {
+		return nil, errors.ErrDomainNotEphemeral
 	}
 
-	// 获取该 Zone 下所有 Domain
-	domains, err := s.ListDomainsByZone(ctx, zone)
+	resp, err := s.client.client.KeepAliveOnce(ctx, clientv3.LeaseID(d.LeaseID))
 	if err != nil {
-		return err
+		if rpctypes.Error(err) == rpctypes.ErrLeaseNotFound {
+			return nil, errors.ErrDomainNotFound
+		}
+		return nil, err
 	}
 
-	// 删除每个 Domain 的 CoreDNS 记录
-	for _, domain := range domains {
-		if err := s.deleteCoreDNSRecords(ctx, zone, domain.Domain); err != nil {
-			return err
-		}
+	// 记录新的到期时间
+	d.ExpiresAt = time.Now().Unix() + resp.TTL
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.client.client.Put(ctx, s.domainKey(ctx, zone, domain), string(data), leaseOpts(d)...); err != nil {
+		return nil, err
 	}
 
-	// 删除所有 Domain 元数据
-	prefix := s.domainPrefix(zone)
-	_, err = s.client.client.Delete(ctx, prefix, clientv3.WithPrefix())
-	return err
+	return d, nil
 }
 
-// domainKey 生成 Domain 的 etcd key
-func (s *DomainStorage) domainKey(zone, domain string) string {
-	return storage.DomainKeyPrefix + zone + "/" + domain
+// revokeLease 撤销租约，租约已过期时忽略错误
+func (s *DomainStorage) revokeLease(ctx context.Context, leaseID int64) {
+	_, _ = s.client.client.Revoke(ctx, clientv3.LeaseID(leaseID))
 }
 
-// domainPrefix 生成 Domain 前缀
-func (s *DomainStorage) domainPrefix(zone string) string {
-	return storage.DomainKeyPrefix + zone + "/"
+// leaseOpts 生成 Domain 相关 key 写入时的租约选项
+func leaseOpts(domain *models.Domain) []clientv3.OpOption {
+	if !domain.IsEphemeral() {
+		return nil
+	}
+	return []clientv3.OpOption{clientv3.WithLease(clientv3.LeaseID(domain.LeaseID))}
 }
 
-// generateCoreDNSKey 生成 CoreDNS 的 etcd key
-// 格式: {prefix}/{反转zone}/{domain}/x{index}
-// 示例: /skydns/com/example/www/x1
-func (s *DomainStorage) generateCoreDNSKey(zone, domain, index string) string {
-	reversed := reverseZone(zone)
-	prefix := s.getCoreDNSPrefix()
-	key := path.Join(prefix, reversed, domain, "x"+index)
-	if !strings.HasPrefix(key, "/") {
-		key = "/" + key
-	}
-	return key
+// domainKey 生成 ctx 所属租户下 Domain 的 etcd key
+func (s *DomainStorage) domainKey(ctx context.Context, zone, domain string) string {
+	return s.domainPrefix(ctx, zone) + domain
 }
 
-// reverseZone 反转域名层级
-func reverseZone(zone string) string {
-	parts := strings.Split(zone, ".")
-	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
-		parts[i], parts[j] = parts[j], parts[i]
-	}
-	return path.Join(parts...)
+// domainPrefix 生成 ctx 所属租户下 Zone 的 Domain 前缀
+func (s *DomainStorage) domainPrefix(ctx context.Context, zone string) string {
+	return storage.DomainPrefix(tenant.FromContext(ctx)) + zone + "/"
 }
 
-// syncToCoreDNS 同步 Domain 到 CoreDNS
-func (s *DomainStorage) syncToCoreDNS(ctx context.Context, domain *models.Domain) error {
-	// 获取现有的 CoreDNS 记录
-	existingKeys, err := s.getCoreDNSRecordKeys(ctx, domain.Zone, domain.Domain)
+// planCoreDNSOps 生成在每个 CoreDNS 视图中同步 Domain 静态记录的 etcd 操作，记录绑定 Domain 的租约
+// Zone 未发布到的视图撤下已有的静态记录；zone 为空时读取 Domain 所属的 Zone
+func (s *DomainStorage) planCoreDNSOps(ctx context.Context, domain *models.Domain, zone *models.Zone) ([]clientv3.Op, error) {
+	zone, err := s.publishingZone(ctx, domain.Zone, zone)
 	if err != nil {
-		return err
-	}
-
-	// 计算需要添加和删除的记录
-	desiredIPs := make(map[string]bool)
-	for _, ip := range domain.IPs {
-		desiredIPs[ip] = true
+		return nil, err
 	}
 
-	// 找出需要删除的记录
-	for key, ip := range existingKeys {
-		if !desiredIPs[ip] {
-			// 删除不再需要的记录
-			_, err := s.client.client.Delete(ctx, key)
-			if err != nil {
-				return err
-			}
-			delete(existingKeys, key)
+	var ops []clientv3.Op
+	for _, v := range s.views {
+		var ips []string
+		if zone.PublishesTo(v.view) {
+			ips = domain.IPsFor(v.view)
 		}
+		viewOps, err := v.planOps(ctx, domain.Zone, domain.Domain, ips, domain.TTL, leaseOpts(domain)...)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, viewOps...)
 	}
+	return ops, nil
+}
 
-	// 找出需要添加的记录
-	existingIPs := make(map[string]bool)
-	for _, ip := range existingKeys {
-		existingIPs[ip] = true
-	}
-
-	// 添加新记录或更新现有记录
-	for i, ip := range domain.IPs {
-		if !existingIPs[ip] {
-			// 新记录，需要找到下一个可用索引
-			index := strconv.Itoa(i + 1)
-			for {
-				key := s.generateCoreDNSKey(domain.Zone, domain.Domain, index)
-				_, exists := existingKeys[key]
-				if !exists {
-					// 检查这个 key 是否被其他记录占用
-					resp, err := s.client.client.Get(ctx, key)
-					if err != nil {
-						return err
-					}
-					if len(resp.Kvs) == 0 {
-						break
-					}
-				}
-				// 尝试下一个索引
-				idx, _ := strconv.Atoi(index)
-				index = strconv.Itoa(idx + 1)
-			}
+// publishingZone 返回决定发布目标的 Zone；zone 为空时读取，Zone 不存在时按默认值只发布到 coredns
+func (s *DomainStorage) publishingZone(ctx context.Context, name string, zone *models.Zone) (*models.Zone, error) {
+	if zone != nil {
+		return zone, nil
+	}
 
-			key := s.generateCoreDNSKey(domain.Zone, domain.Domain, index)
-			record := map[string]interface{}{
-				"host": ip,
-				"ttl":  domain.TTL,
-			}
-			data, err := json.Marshal(record)
-			if err != nil {
-				return err
-			}
-			_, err = s.client.client.Put(ctx, key, string(data))
-			if err != nil {
-				return err
-			}
-		}
+	kv, err := s.client.getKV(ctx, zoneKey(ctx, name))
+	if err != nil {
+		return nil, err
 	}
+	if kv == nil {
+		return &models.Zone{Zone: name}, nil
+	}
+	var z models.Zone
+	if err := json.Unmarshal(kv.Value, &z); err != nil {
+		return nil, err
+	}
+	return &z, nil
+}
 
-	return nil
+// deleteCoreDNSOps 生成删除 Domain 在所有 CoreDNS 视图中的记录（包括动态实例记录）的 etcd 操作
+func (s *DomainStorage) deleteCoreDNSOps(zone, domain string) []clientv3.Op {
+	ops := make([]clientv3.Op, 0, len(s.views))
+	for _, v := range s.views {
+		ops = append(ops, clientv3.OpDelete(v.domainPrefix(zone, domain), clientv3.WithPrefix()))
+	}
+	return ops
 }
 
-// getCoreDNSRecordKeys 获取 CoreDNS 记录的 keys
-func (s *DomainStorage) getCoreDNSRecordKeys(ctx context.Context, zone, domain string) (map[string]string, error) {
-	prefix := s.getCoreDNSPrefix()
-	reversed := reverseZone(zone)
-	keyPrefix := path.Join(prefix, reversed, domain) + "/"
+// ResyncCoreDNS 按 Zone 当前的发布目标重新同步其下所有 Domain 与动态实例在各 CoreDNS 视图中的记录
+// Zone 加入或去掉 CoreDNS 视图后调用，按 etcd 单个事务的操作数上限分块提交
+func (s *DomainStorage) ResyncCoreDNS(ctx context.Context, zone *models.Zone) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
 
-	resp, err := s.client.client.Get(ctx, keyPrefix, clientv3.WithPrefix())
+	domains, err := s.ListDomainsByZone(ctx, zone.Zone)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	instances, err := s.listInstances(ctx, storage.InstanceKeyPrefix+zone.Zone+"/")
+	if err != nil {
+		return err
 	}
 
-	result := make(map[string]string)
-	for _, kv := range resp.Kvs {
-		var record map[string]interface{}
-		if err := json.Unmarshal(kv.Value, &record); err != nil {
-			continue
+	var ops []clientv3.Op
+	ttls := make(map[string]int, len(domains))
+	for _, d := range domains {
+		domainOps, err := s.planCoreDNSOps(ctx, d, zone)
+		if err != nil {
+			return err
 		}
-		if host, ok := record["host"].(string); ok {
-			result[string(kv.Key)] = host
+		ops = append(ops, domainOps...)
+		ttls[d.Domain] = d.TTL
+	}
+
+	// 动态实例记录绑定实例自己的租约
+	for _, v := range s.views {
+		publish := zone.PublishesTo(v.view)
+		for _, inst := range instances {
+			key := v.instanceKey(inst.Zone, inst.Domain, inst.InstanceID)
+			ttl, ok := ttls[inst.Domain]
+			if !publish || !ok {
+				ops = append(ops, clientv3.OpDelete(key))
+				continue
+			}
+			record, err := json.Marshal(coreDNSRecord{Host: inst.IP, TTL: ttl})
+			if err != nil {
+				return err
+			}
+			ops = append(ops, clientv3.OpPut(key, string(record), clientv3.WithLease(clientv3.LeaseID(inst.LeaseID))))
 		}
 	}
 
-	return result, nil
+	return s.views[0].commit(ctx, ops)
 }
 
-// deleteCoreDNSRecords 删除 CoreDNS 记录
-func (s *DomainStorage) deleteCoreDNSRecords(ctx context.Context, zone, domain string) error {
-	prefix := s.getCoreDNSPrefix()
-	reversed := reverseZone(zone)
-	keyPrefix := path.Join(prefix, reversed, domain) + "/"
-
-	_, err := s.client.client.Delete(ctx, keyPrefix, clientv3.WithPrefix())
-	return err
+// View 返回 CoreDNS 视图的发布目标，视图未配置时返回 nil
+func (s *DomainStorage) View(name string) *SkyDNSPublisher {
+	for _, v := range s.views {
+		if v.view == name {
+			return v
+		}
+	}
+	return nil
 }
 
 // DomainExists 检查 Domain 是否存在
@@ -0,0 +1,302 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// changePlan 单个 Domain 变更对应的 etcd 事务内容
+type changePlan struct {
+	cmps     []clientv3.Cmp
+	ops      []clientv3.Op
+	snapshot []*mvccpb.KeyValue // 变更涉及的 key 在变更前的值，用于回滚
+	created  []string           // 变更前不存在、由变更写入的 key，用于回滚
+	domain   *models.Domain     // 变更后的 Domain，删除时为 nil
+	leases   []int64            // 变更成功后需要撤销的租约
+}
+
+// ApplyChanges 以全部成功或全部不生效的方式应用同一 Zone 下的一批 Domain 变更
+// 每个 Domain 的元数据 key 都会与读取时的版本比较，期间任何一个被并发修改则整批不生效，返回 ErrChangeConflict
+// 变更按 etcd 单个事务的操作数上限分块提交，后续分块失败时回滚已提交的分块
+// 删除的非临时 Domain 在同一事务中移入回收站
+// 返回创建 / 更新后的 Domain（删除的 Domain 不返回）
+func (s *DomainStorage) ApplyChanges(ctx context.Context, zone string, changes []*models.DomainChange) ([]*models.Domain, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	plans := make([]*changePlan, 0, len(changes))
+	for _, change := range changes {
+		plan, err := s.planChange(ctx, zone, change)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+
+	committed := make([]*changePlan, 0, len(plans))
+	for _, chunk := range s.chunkPlans(plans) {
+		if err := s.commitPlans(ctx, chunk); err != nil {
+			if len(committed) > 0 {
+				if rbErr := s.rollbackPlans(ctx, committed); rbErr != nil {
+					logger.Log.WithError(rbErr).WithField("zone", zone).Error("Failed to roll back partially applied domain changes")
+					return nil, fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+				}
+			}
+			return nil, err
+		}
+		committed = append(committed, chunk...)
+	}
+
+	applied := make([]*models.Domain, 0, len(plans))
+	for _, plan := range plans {
+		for _, leaseID := range plan.leases {
+			s.revokeLease(ctx, leaseID)
+		}
+		if plan.domain != nil {
+			applied = append(applied, plan.domain)
+		}
+	}
+	return applied, nil
+}
+
+// ApplyChange 在一个 etcd 事务中应用单个 Domain 变更
+func (s *DomainStorage) ApplyChange(ctx context.Context, zone string, change *models.DomainChange) (*models.Domain, error) {
+	applied, err := s.ApplyChanges(ctx, zone, []*models.DomainChange{change})
+	if err != nil || len(applied) == 0 {
+		return nil, err
+	}
+	return applied[0], nil
+}
+
+// planChange 读取 Domain 当前状态，生成变更所需的比较条件、操作及回滚快照
+func (s *DomainStorage) planChange(ctx context.Context, zone string, change *models.DomainChange) (*changePlan, error) {
+	key := s.domainKey(ctx, zone, change.Domain)
+	resp, err := s.client.client.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &changePlan{}
+	var existing *models.Domain
+	if len(resp.Kvs) > 0 {
+		existing = &models.Domain{}
+		if err := json.Unmarshal(resp.Kvs[0].Value, existing); err != nil {
+			return nil, err
+		}
+		plan.cmps = append(plan.cmps, clientv3.Compare(clientv3.ModRevision(key), "=", resp.Kvs[0].ModRevision))
+	} else {
+		plan.cmps = append(plan.cmps, clientv3.Compare(clientv3.CreateRevision(key), "=", 0))
+	}
+
+	now := time.Now().Unix()
+	switch change.Op {
+	case models.ChangeOpCreate:
+		if existing != nil {
+			return nil, errors.ErrDomainExists
+		}
+		plan.domain = &models.Domain{
+			Zone:        zone,
+			Domain:      change.Domain,
+			Name:        change.Domain + "." + zone,
+			IPs:         change.IPs,
+			TTL:         change.TTL,
+			RecordCount: len(change.IPs),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+
+	case models.ChangeOpUpdate:
+		if existing == nil {
+			return nil, errors.ErrDomainNotFound
+		}
+		domain := *existing
+		domain.IPs = change.IPs
+		if change.TTL > 0 {
+			domain.TTL = change.TTL
+		}
+		domain.Name = domain.Domain + "." + zone
+		domain.RecordCount = len(domain.IPs)
+		domain.UpdatedAt = now
+		plan.domain = &domain
+
+	case models.ChangeOpDelete:
+		if existing == nil {
+			return nil, errors.ErrDomainNotFound
+		}
+		instances, err := s.listInstances(ctx, s.instancePrefix(zone, change.Domain))
+		if err != nil {
+			return nil, err
+		}
+		for _, inst := range instances {
+			plan.leases = append(plan.leases, inst.LeaseID)
+		}
+		if existing.IsEphemeral() {
+			plan.leases = append(plan.leases, existing.LeaseID)
+		}
+		plan.ops = []clientv3.Op{
+			clientv3.OpDelete(key),
+			clientv3.OpDelete(s.instancePrefix(zone, change.Domain), clientv3.WithPrefix()),
+		}
+		plan.ops = append(plan.ops, s.deleteCoreDNSOps(zone, change.Domain)...)
+
+		// 非临时 Domain 连同完整记录移入回收站
+		if !existing.IsEphemeral() {
+			op, err := trashDomainOp(ctx, existing, s.config.Trash.Retention)
+			if err != nil {
+				return nil, err
+			}
+			plan.ops = append(plan.ops, op)
+		}
+
+	default:
+		return nil, errors.ErrInvalidInput
+	}
+
+	if plan.domain != nil {
+		plan.ops, err = s.domainPutOps(ctx, plan.domain, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.snapshotPlan(ctx, zone, change, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// snapshotPlan 记录变更涉及的 key 在变更前的值
+func (s *DomainStorage) snapshotPlan(ctx context.Context, zone string, change *models.DomainChange, plan *changePlan) error {
+	prefixes := []string{s.domainKey(ctx, zone, change.Domain)}
+	for _, v := range s.views {
+		prefixes = append(prefixes, v.domainPrefix(zone, change.Domain))
+	}
+	if change.Op == models.ChangeOpDelete {
+		prefixes = append(prefixes, s.instancePrefix(zone, change.Domain))
+	}
+
+	existing := make(map[string]bool)
+	for i, prefix := range prefixes {
+		var opts []clientv3.OpOption
+		if i > 0 {
+			opts = append(opts, clientv3.WithPrefix())
+		}
+		resp, err := s.client.client.Get(ctx, prefix, opts...)
+		if err != nil {
+			return err
+		}
+		for _, kv := range resp.Kvs {
+			existing[string(kv.Key)] = true
+			plan.snapshot = append(plan.snapshot, kv)
+		}
+	}
+
+	for _, op := range plan.ops {
+		if op.IsPut() && !existing[string(op.KeyBytes())] {
+			plan.created = append(plan.created, string(op.KeyBytes()))
+		}
+	}
+	return nil
+}
+
+// chunkPlans 将变更按 etcd 单个事务的操作数上限分块，同一 Domain 的变更不会被拆分
+func (s *DomainStorage) chunkPlans(plans []*changePlan) [][]*changePlan {
+	maxOps := s.config.Etcd.MaxTxnOps
+
+	var chunks [][]*changePlan
+	var current []*changePlan
+	var numOps, numCmps int
+	for _, plan := range plans {
+		if len(current) > 0 && (numOps+len(plan.ops) > maxOps || numCmps+len(plan.cmps) > maxOps) {
+			chunks = append(chunks, current)
+			current, numOps, numCmps = nil, 0, 0
+		}
+		current = append(current, plan)
+		numOps += len(plan.ops)
+		numCmps += len(plan.cmps)
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// commitPlans 在一个事务中提交一组变更
+func (s *DomainStorage) commitPlans(ctx context.Context, plans []*changePlan) error {
+	var cmps []clientv3.Cmp
+	var ops []clientv3.Op
+	for _, plan := range plans {
+		cmps = append(cmps, plan.cmps...)
+		ops = append(ops, plan.ops...)
+	}
+
+	resp, err := s.client.client.Txn(ctx).If(cmps...).Then(ops...).Commit()
+	if err != nil {
+		// 临时 Domain 的租约已过期，视为被并发修改
+		if rpctypes.Error(err) == rpctypes.ErrLeaseNotFound {
+			return errors.ErrChangeConflict
+		}
+		return err
+	}
+	if !resp.Succeeded {
+		return errors.ErrChangeConflict
+	}
+	return nil
+}
+
+// rollbackPlans 将已提交的变更恢复到变更前的状态
+// 删除变更写入的新 key，并写回变更前的值（租约已过期的 key 不再恢复）
+func (s *DomainStorage) rollbackPlans(ctx context.Context, plans []*changePlan) error {
+	var ops []clientv3.Op
+	for _, plan := range plans {
+		for _, key := range plan.created {
+			ops = append(ops, clientv3.OpDelete(key))
+		}
+		for _, kv := range plan.snapshot {
+			var opts []clientv3.OpOption
+			if kv.Lease != 0 {
+				ttl, err := s.client.client.TimeToLive(ctx, clientv3.LeaseID(kv.Lease))
+				if err != nil || ttl.TTL <= 0 {
+					continue
+				}
+				opts = append(opts, clientv3.WithLease(clientv3.LeaseID(kv.Lease)))
+			}
+			ops = append(ops, clientv3.OpPut(string(kv.Key), string(kv.Value), opts...))
+		}
+	}
+
+	maxOps := s.config.Etcd.MaxTxnOps
+	for start := 0; start < len(ops); start += maxOps {
+		end := min(start+maxOps, len(ops))
+		if _, err := s.client.client.Txn(ctx).Then(ops[start:end]...).Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// domainPutOps 生成写入 Domain 元数据及同步其 CoreDNS 记录的 etcd 操作，zone 为空时读取 Domain 所属的 Zone
+func (s *DomainStorage) domainPutOps(ctx context.Context, domain *models.Domain, zone *models.Zone) ([]clientv3.Op, error) {
+	data, err := json.Marshal(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	coreDNSOps, err := s.planCoreDNSOps(ctx, domain, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	ops := []clientv3.Op{clientv3.OpPut(s.domainKey(ctx, domain.Zone, domain.Domain), string(data), leaseOpts(domain)...)}
+	return append(ops, coreDNSOps...), nil
+}
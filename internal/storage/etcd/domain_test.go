@@ -0,0 +1,96 @@
+package etcd_test
+
+import (
+	"context"
+	"testing"
+
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/storage/etcd/etcdtest"
+	"dancer/internal/tenant"
+	"github.com/sirupsen/logrus"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// TestCreateDomainRevokesLease 创建临时 Domain 失败时撤销申请的租约，不留下任何 key
+func TestCreateDomainRevokesLease(t *testing.T) {
+	logger.Log = logrus.New()
+	client, cfg := etcdtest.Start(t)
+	s := etcd.NewDomainStorage(client, cfg)
+	raw := client.GetClient()
+	ctx := context.Background()
+
+	// Zone 记录损坏，生成 CoreDNS 记录时失败
+	if _, err := raw.Put(ctx, storage.ZonePrefix(tenant.Default)+"example.com", "{"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	domain := &models.Domain{Zone: "example.com", Domain: "tmp", IPs: []string{"192.0.2.1"}, TTL: 60, LeaseTTL: 3600}
+	if err := s.CreateDomain(ctx, domain); err == nil {
+		t.Fatal("CreateDomain succeeded with a malformed zone")
+	}
+	if domain.LeaseID != 0 || domain.ExpiresAt != 0 {
+		t.Errorf("lease_id = %d, expires_at = %d after failure, want 0", domain.LeaseID, domain.ExpiresAt)
+	}
+
+	leases, err := raw.Leases(ctx)
+	if err != nil {
+		t.Fatalf("Leases: %v", err)
+	}
+	if len(leases.Leases) != 0 {
+		t.Errorf("got %d leases after failure, want 0", len(leases.Leases))
+	}
+	resp, err := raw.Get(ctx, storage.DomainKeyPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if resp.Count != 0 {
+		t.Errorf("got %d domain keys after failure, want 0", resp.Count)
+	}
+}
+
+// TestDomainWriteSingleTxn Domain 元数据与 CoreDNS 记录在同一事务中写入
+func TestDomainWriteSingleTxn(t *testing.T) {
+	logger.Log = logrus.New()
+	client, cfg := etcdtest.Start(t)
+	s := etcd.NewDomainStorage(client, cfg)
+	raw := client.GetClient()
+	ctx := context.Background()
+
+	if err := etcd.NewZoneStorage(client).CreateZone(ctx, &models.Zone{Zone: "example.com"}); err != nil {
+		t.Fatalf("CreateZone: %v", err)
+	}
+
+	// writeRevisions 返回 Domain 元数据与各条 CoreDNS 记录最近一次写入的 revision
+	writeRevisions := func() map[int64]int {
+		revisions := make(map[int64]int)
+		for _, prefix := range []string{storage.DomainPrefix(tenant.Default) + "example.com/www", "/skydns/com/example/www/"} {
+			resp, err := raw.Get(ctx, prefix, clientv3.WithPrefix())
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			for _, kv := range resp.Kvs {
+				revisions[kv.ModRevision]++
+			}
+		}
+		return revisions
+	}
+
+	domain := &models.Domain{Zone: "example.com", Domain: "www", IPs: []string{"192.0.2.1", "192.0.2.2"}, TTL: 60}
+	if err := s.CreateDomain(ctx, domain); err != nil {
+		t.Fatalf("CreateDomain: %v", err)
+	}
+	if revisions := writeRevisions(); len(revisions) != 1 {
+		t.Errorf("create wrote keys at revisions %v, want one revision", revisions)
+	}
+
+	domain = &models.Domain{Zone: "example.com", Domain: "www", IPs: []string{"192.0.2.3", "192.0.2.4"}}
+	if err := s.UpdateDomain(ctx, domain); err != nil {
+		t.Fatalf("UpdateDomain: %v", err)
+	}
+	if revisions := writeRevisions(); len(revisions) != 1 {
+		t.Errorf("update wrote keys at revisions %v, want one revision", revisions)
+	}
+}
@@ -0,0 +1,86 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"dancer/internal/logger"
+	"dancer/internal/storage"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+// electionSessionTTL 选举会话租约时长 (秒)，leader 异常退出后其他副本最多等待该时长接管
+const electionSessionTTL = 15
+
+// RunAsLeader 参与名为 name 的 leader 选举，当选后执行 fn
+// fn 的 ctx 在失去领导权（会话过期、etcd 重连）或外部 ctx 取消时被取消
+// fn 返回后重新参与选举，直到外部 ctx 取消
+func (c *Client) RunAsLeader(ctx context.Context, name string, fn func(ctx context.Context)) {
+	defer c.setElection(name, ElectionStopped, nil)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		c.setElection(name, ElectionCandidate, nil)
+		if err := c.campaign(ctx, name, fn); err != nil && ctx.Err() == nil {
+			c.setElection(name, ElectionCandidate, err)
+			logger.Log.WithError(err).WithField("election", name).Warn("Leader election failed, retrying")
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(c.getConnectInterval()):
+			}
+		}
+	}
+}
+
+// campaign 执行一轮选举
+func (c *Client) campaign(ctx context.Context, name string, fn func(ctx context.Context)) error {
+	if err := c.WaitForConnection(defaultWaitTimeout); err != nil {
+		return err
+	}
+
+	session, err := concurrency.NewSession(c.GetClient(), concurrency.WithTTL(electionSessionTTL))
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	election := concurrency.NewElection(session, storage.ElectionKeyPrefix+name)
+	if err := election.Campaign(ctx, candidateID()); err != nil {
+		return err
+	}
+	logger.Log.WithField("election", name).Info("Became leader")
+	c.setElection(name, ElectionLeader, nil)
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-session.Done():
+			cancel()
+		case <-leaderCtx.Done():
+		}
+	}()
+
+	fn(leaderCtx)
+
+	// 主动让出领导权，便于其他副本尽快接管
+	resignCtx, resignCancel := context.WithTimeout(context.Background(), c.getDialTimeout())
+	defer resignCancel()
+	_ = election.Resign(resignCtx)
+	logger.Log.WithField("election", name).Info("Leadership released")
+	return nil
+}
+
+// candidateID 当前副本的选举标识
+func candidateID() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
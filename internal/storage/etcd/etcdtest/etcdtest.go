@@ -0,0 +1,70 @@
+// Package etcdtest 为测试启动进程内的 etcd
+package etcdtest
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"dancer/internal/config"
+	"dancer/internal/storage/etcd"
+	"go.etcd.io/etcd/server/v3/embed"
+)
+
+// Start 在临时目录中启动单节点 etcd，返回连接到该 etcd 的客户端与配置，测试结束时关闭
+func Start(t testing.TB) (*etcd.Client, *config.Config) {
+	t.Helper()
+
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+	clientURL := freeURL(t)
+	peerURL := freeURL(t)
+	cfg.ListenClientUrls = []url.URL{clientURL}
+	cfg.AdvertiseClientUrls = []url.URL{clientURL}
+	cfg.ListenPeerUrls = []url.URL{peerURL}
+	cfg.AdvertisePeerUrls = []url.URL{peerURL}
+	cfg.InitialCluster = fmt.Sprintf("%s=%s", cfg.Name, peerURL.String())
+
+	server, err := embed.StartEtcd(cfg)
+	if err != nil {
+		t.Fatalf("failed to start etcd: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	select {
+	case <-server.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.Fatal("etcd did not become ready")
+	}
+
+	appCfg := &config.Config{}
+	appCfg.Etcd.Endpoints = []string{clientURL.String()}
+	appCfg.Etcd.CorednsPrefix = "/skydns"
+	appCfg.Etcd.MaxTxnOps = 128
+
+	client, err := etcd.NewClient(appCfg)
+	if err != nil {
+		t.Fatalf("failed to create etcd client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	if err := client.WaitForConnection(10 * time.Second); err != nil {
+		t.Fatalf("failed to connect to etcd: %v", err)
+	}
+	return client, appCfg
+}
+
+// freeURL 返回本机一个空闲端口的 URL
+func freeURL(t testing.TB) url.URL {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to reserve a port: %v", err)
+	}
+	defer l.Close()
+	return url.URL{Scheme: "http", Host: l.Addr().String()}
+}
@@ -0,0 +1,188 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// EventStorage Zone / Domain / 用户变更事件
+type EventStorage struct {
+	client *Client
+}
+
+func NewEventStorage(client *Client) *EventStorage {
+	return &EventStorage{client: client}
+}
+
+// errEventHandler 包装回调返回的错误，与 watch 自身的错误区分
+type errEventHandler struct {
+	err error
+}
+
+func (e *errEventHandler) Error() string { return e.err.Error() }
+
+// StartRevision 确定事件流的起始 revision，事件流从该 revision 之后开始
+// revision 为 0 时返回当前 revision；revision 之后的历史已被压缩时返回 ErrRevisionCompacted
+func (s *EventStorage) StartRevision(ctx context.Context, revision int64) (int64, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return 0, errors.ErrEtcdUnavailable
+	}
+
+	if revision == 0 {
+		resp, err := s.client.client.Get(ctx, storage.RootKeyPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+		if err != nil {
+			return 0, err
+		}
+		return resp.Header.Revision, nil
+	}
+
+	_, err := s.client.client.Get(ctx, storage.RootKeyPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly(), clientv3.WithRev(revision+1))
+	switch rpctypes.Error(err) {
+	case nil, rpctypes.ErrFutureRev:
+		return revision, nil
+	case rpctypes.ErrCompacted:
+		return 0, errors.ErrRevisionCompacted
+	default:
+		return 0, err
+	}
+}
+
+// WatchEvents 监听 revision 之后 Zone / Domain / 用户的变更，按 revision 顺序将事件交给 fn，阻塞直到 ctx 取消或 fn 返回错误
+// 三类数据使用同一个 watch，事件全局有序；watch 因连接问题中断时从最后送达的 revision 继续
+// 所需的历史已被压缩时返回 ErrRevisionCompacted，调用方需重新全量读取
+func (s *EventStorage) WatchEvents(ctx context.Context, revision int64, fn func(events []*models.ChangeEvent) error) error {
+	next := revision + 1
+	for {
+		err := s.watchEventsOnce(ctx, &next, fn)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if handlerErr, ok := err.(*errEventHandler); ok {
+			return handlerErr.err
+		}
+		if err == errors.ErrRevisionCompacted {
+			return err
+		}
+		logger.Log.WithError(err).WithField("revision", next).Warn("Event watch interrupted, resuming")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// watchEventsOnce 从 *next 开始 watch，每送达一批事件推进 *next，watch 中断时返回
+func (s *EventStorage) watchEventsOnce(ctx context.Context, next *int64, fn func(events []*models.ChangeEvent) error) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+	client := s.client.GetClient()
+	if client == nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	watchCtx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+	defer cancel()
+
+	wch := client.Watch(watchCtx, storage.RootKeyPrefix, clientv3.WithPrefix(), clientv3.WithRev(*next), clientv3.WithPrevKV())
+	for wresp := range wch {
+		if wresp.CompactRevision != 0 {
+			return errors.ErrRevisionCompacted
+		}
+		if err := wresp.Err(); err != nil {
+			return err
+		}
+		if len(wresp.Events) == 0 {
+			continue
+		}
+
+		events := make([]*models.ChangeEvent, 0, len(wresp.Events))
+		for _, ev := range wresp.Events {
+			if event := decodeChangeEvent(ev); event != nil {
+				events = append(events, event)
+			}
+		}
+		*next = wresp.Events[len(wresp.Events)-1].Kv.ModRevision + 1
+
+		if len(events) > 0 {
+			if err := fn(events); err != nil {
+				return &errEventHandler{err: err}
+			}
+		}
+	}
+	return ctx.Err()
+}
+
+// decodeChangeEvent 将 etcd 事件转换为变更事件，不关注的 key 返回 nil
+func decodeChangeEvent(ev *clientv3.Event) *models.ChangeEvent {
+	key := string(ev.Kv.Key)
+	event := &models.ChangeEvent{Revision: ev.Kv.ModRevision}
+
+	// create / update 取变更后的值，delete 取删除前的值
+	kv := ev.Kv
+	switch {
+	case ev.Type == clientv3.EventTypeDelete:
+		event.Op = models.EventOpDelete
+		kv = ev.PrevKv
+	case ev.IsCreate():
+		event.Op = models.EventOpCreate
+	default:
+		event.Op = models.EventOpUpdate
+	}
+
+	// key 均为 {prefix}{tenant}/... 形式，升级前不含租户的 key 在迁移时被删除，其事件不关注
+	if t, name, ok := storage.SplitTenantKey(key, storage.ZoneKeyPrefix); ok {
+		event.Tenant = t
+		event.Type = models.EventTypeZone
+		event.Name = name
+		event.ZoneName = event.Name
+		event.Zone = decodeEventValue[models.Zone](kv)
+		return event
+	}
+
+	if t, rest, ok := storage.SplitTenantKey(key, storage.DomainKeyPrefix); ok {
+		zone, domain, ok := strings.Cut(rest, "/")
+		if !ok {
+			return nil
+		}
+		event.Tenant = t
+		event.Type = models.EventTypeDomain
+		event.ZoneName, event.Name = zone, domain
+		event.Domain = decodeEventValue[models.Domain](kv)
+		return event
+	}
+
+	if t, id, ok := storage.SplitTenantKey(key, storage.UserKeyPrefix); ok {
+		event.Tenant = t
+		event.Type = models.EventTypeUser
+		event.Name = id
+		event.User = decodeEventValue[models.User](kv)
+		return event
+	}
+
+	return nil
+}
+
+// decodeEventValue 解码事件携带的值，值不存在或格式错误时返回 nil
+func decodeEventValue[T any](kv *mvccpb.KeyValue) *T {
+	if kv == nil {
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(kv.Value, &v); err != nil {
+		return nil
+	}
+	return &v
+}
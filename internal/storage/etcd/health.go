@@ -0,0 +1,213 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.etcd.io/etcd/client/v3"
+)
+
+// String 返回连接状态的名称
+func (s ConnectionState) String() string {
+	switch s {
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	}
+	return "disconnected"
+}
+
+// ClusterStatus etcd 集群状态
+type ClusterStatus struct {
+	State     string            `json:"state"`            // 客户端连接状态: connected / connecting / disconnected
+	Leader    string            `json:"leader,omitempty"` // leader 成员名称，成员没有名称时为成员 ID
+	Endpoints []*EndpointStatus `json:"endpoints"`
+}
+
+// EndpointStatus 单个 etcd 节点的状态
+type EndpointStatus struct {
+	Endpoint    string   `json:"endpoint"`
+	Status      string   `json:"status"`                   // up / down
+	LatencyMs   float64  `json:"latency_ms"`               // 状态请求耗时 (毫秒)
+	MemberID    string   `json:"member_id,omitempty"`      // 十六进制成员 ID
+	Name        string   `json:"name,omitempty"`           // 成员名称
+	IsLeader    bool     `json:"is_leader"`                // 是否为 leader
+	Version     string   `json:"version,omitempty"`        // etcd 版本
+	DBSize      int64    `json:"db_size,omitempty"`        // 数据库文件大小 (字节)
+	DBSizeInUse int64    `json:"db_size_in_use,omitempty"` // 数据库实际使用大小 (字节)
+	RaftTerm    uint64   `json:"raft_term,omitempty"`
+	RaftIndex   uint64   `json:"raft_index,omitempty"`
+	Alarms      []string `json:"alarms,omitempty"` // 节点报告的错误，如 NOSPACE
+	Error       string   `json:"error,omitempty"`  // 请求失败的原因
+}
+
+// ClusterStatus 逐个请求配置的 etcd 节点，返回各节点的状态、延迟与集群 leader
+// 未连接时不请求节点，所有节点为 down
+func (c *Client) ClusterStatus(ctx context.Context) *ClusterStatus {
+	status := &ClusterStatus{
+		State:     c.GetState().String(),
+		Endpoints: make([]*EndpointStatus, len(c.config.Etcd.Endpoints)),
+	}
+
+	client := c.GetClient()
+	if client == nil {
+		for i, endpoint := range c.config.Etcd.Endpoints {
+			status.Endpoints[i] = &EndpointStatus{Endpoint: endpoint, Status: "down", Error: "etcd not connected"}
+		}
+		return status
+	}
+
+	// 成员名称，获取失败时只显示 ID
+	names := make(map[uint64]string)
+	listCtx, cancel := context.WithTimeout(ctx, c.getDialTimeout())
+	members, err := client.MemberList(listCtx)
+	cancel()
+	if err == nil {
+		for _, m := range members.Members {
+			names[m.ID] = m.Name
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, endpoint := range c.config.Etcd.Endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statusCtx, cancel := context.WithTimeout(ctx, c.getDialTimeout())
+			defer cancel()
+			status.Endpoints[i] = endpointStatus(statusCtx, client, endpoint, names)
+		}()
+	}
+	wg.Wait()
+
+	for _, ep := range status.Endpoints {
+		if ep.IsLeader {
+			status.Leader = ep.Name
+			if status.Leader == "" {
+				status.Leader = ep.MemberID
+			}
+			break
+		}
+	}
+	return status
+}
+
+// endpointStatus 请求单个节点的状态
+func endpointStatus(ctx context.Context, client *clientv3.Client, endpoint string, names map[uint64]string) *EndpointStatus {
+	start := time.Now()
+	resp, err := client.Status(ctx, endpoint)
+	status := &EndpointStatus{
+		Endpoint:  endpoint,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		status.Status = "down"
+		status.Error = err.Error()
+		return status
+	}
+
+	status.Status = "up"
+	status.MemberID = fmt.Sprintf("%x", resp.Header.MemberId)
+	status.Name = names[resp.Header.MemberId]
+	status.IsLeader = resp.Leader == resp.Header.MemberId
+	status.Version = resp.Version
+	status.DBSize = resp.DbSize
+	status.DBSizeInUse = resp.DbSizeInUse
+	status.RaftTerm = resp.RaftTerm
+	status.RaftIndex = resp.RaftIndex
+	status.Alarms = resp.Errors
+	return status
+}
+
+// 选举中的角色
+const (
+	ElectionCandidate = "candidate" // 等待当选
+	ElectionLeader    = "leader"    // 已当选，正在执行任务
+	ElectionStopped   = "stopped"   // 已退出选举
+)
+
+// ElectionStatus 本副本在某个 leader 选举中的状态
+type ElectionStatus struct {
+	Name  string `json:"name"`
+	Role  string `json:"role"`            // candidate / leader / stopped
+	Since int64  `json:"since"`           // 进入当前角色的时间戳
+	Error string `json:"error,omitempty"` // 最近一次选举失败的原因，当选后清空
+}
+
+// setElection 更新选举状态；err 为 nil 时保留之前的错误，当选时清空
+func (c *Client) setElection(name, role string, err error) {
+	c.electionMu.Lock()
+	defer c.electionMu.Unlock()
+
+	if c.elections == nil {
+		c.elections = make(map[string]*ElectionStatus)
+	}
+	es, ok := c.elections[name]
+	if !ok {
+		es = &ElectionStatus{Name: name}
+		c.elections[name] = es
+	}
+	if es.Role != role {
+		es.Role = role
+		es.Since = time.Now().Unix()
+	}
+	switch {
+	case err != nil:
+		es.Error = err.Error()
+	case role == ElectionLeader:
+		es.Error = ""
+	}
+}
+
+// ElectionStatus 返回本副本参与的所有选举的状态，按名称排序
+func (c *Client) ElectionStatus() []ElectionStatus {
+	c.electionMu.Lock()
+	defer c.electionMu.Unlock()
+
+	statuses := make([]ElectionStatus, 0, len(c.elections))
+	for _, es := range c.elections {
+		statuses = append(statuses, *es)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// CoreDNSSyncStatus 本进程写入 CoreDNS 记录的情况
+type CoreDNSSyncStatus struct {
+	LastSuccessAt int64  `json:"last_success_at,omitempty"` // 最近一次成功写入的时间戳
+	LastFailureAt int64  `json:"last_failure_at,omitempty"` // 最近一次写入失败的时间戳
+	LastError     string `json:"last_error,omitempty"`      // 最近一次写入失败的原因
+}
+
+// coreDNSTracker 记录 CoreDNS 记录写入的结果
+type coreDNSTracker struct {
+	mu     sync.Mutex
+	status CoreDNSSyncStatus
+}
+
+// observe 记录一次写入的结果
+func (t *coreDNSTracker) observe(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now().Unix()
+	if err != nil {
+		t.status.LastFailureAt = now
+		t.status.LastError = err.Error()
+		return
+	}
+	t.status.LastSuccessAt = now
+}
+
+// CoreDNSSyncStatus 返回本进程写入 CoreDNS 记录的情况
+func (c *Client) CoreDNSSyncStatus() CoreDNSSyncStatus {
+	c.coreDNS.mu.Lock()
+	defer c.coreDNS.mu.Unlock()
+	return c.coreDNS.status
+}
@@ -0,0 +1,245 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"dancer/internal/config"
+	"dancer/internal/errors"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"dancer/internal/tenant"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// HistoryStorage Domain 历史版本存储操作
+// 历史版本有两个来源：etcd 中尚未压缩的 revision，以及历史记录器按变更事件保存的快照
+type HistoryStorage struct {
+	client *Client
+	config *config.Config
+}
+
+func NewHistoryStorage(client *Client, cfg *config.Config) *HistoryStorage {
+	return &HistoryStorage{client: client, config: cfg}
+}
+
+// ListDomainVersions 列出 Domain 的历史版本（含删除），按 revision 倒序，最多 max_versions 个
+// etcd 中的 revision 覆盖记录器尚未处理的最新变更，快照覆盖已被压缩的历史
+func (s *HistoryStorage) ListDomainVersions(ctx context.Context, zone, domain string) ([]*models.DomainVersion, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	snapshots, err := s.listSnapshots(ctx, s.domainHistoryPrefix(zone, domain))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.DomainPrefix(tenant.FromContext(ctx))+zone+"/"+domain)
+	if err != nil {
+		return nil, err
+	}
+	var live []*models.DomainVersion
+	if len(resp.Kvs) > 0 {
+		if live, err = s.liveVersions(ctx, resp.Kvs[0], 0); err != nil {
+			return nil, err
+		}
+	}
+
+	versions := mergeVersions(snapshots[domain], live)
+	if len(versions) > s.config.History.MaxVersions {
+		versions = versions[:s.config.History.MaxVersions]
+	}
+	return versions, nil
+}
+
+// ListZoneVersions 列出 Zone 下所有 Domain（含已删除的 Domain）的历史版本，按 Domain 短名分组，组内按 revision 倒序
+// etcd 中的 revision 只回溯到 since 时间点及之前的第一个版本为止
+func (s *HistoryStorage) ListZoneVersions(ctx context.Context, zone string, since int64) (map[string][]*models.DomainVersion, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	snapshots, err := s.listSnapshots(ctx, storage.DomainHistoryKeyPrefix+zone+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.DomainPrefix(tenant.FromContext(ctx))+zone+"/", clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]*models.DomainVersion, len(snapshots))
+	for domain, versions := range snapshots {
+		result[domain] = versions
+	}
+	for _, kv := range resp.Kvs {
+		domain := strings.TrimPrefix(string(kv.Key), storage.DomainPrefix(tenant.FromContext(ctx))+zone+"/")
+		live, err := s.liveVersions(ctx, kv, since)
+		if err != nil {
+			return nil, err
+		}
+		result[domain] = mergeVersions(result[domain], live)
+	}
+	return result, nil
+}
+
+// liveVersions 从 kv 开始沿 etcd revision 回溯同一生命周期内的版本，直到创建版本、已压缩的 revision 或 max_versions 个
+// since > 0 时回溯到时间戳不晚于 since 的第一个版本为止；临时 Domain 不记录历史
+func (s *HistoryStorage) liveVersions(ctx context.Context, kv *mvccpb.KeyValue, since int64) ([]*models.DomainVersion, error) {
+	var versions []*models.DomainVersion
+	for len(versions) < s.config.History.MaxVersions {
+		var domain models.Domain
+		if err := json.Unmarshal(kv.Value, &domain); err != nil || domain.IsEphemeral() {
+			break
+		}
+
+		v := &models.DomainVersion{Revision: kv.ModRevision, Op: models.EventOpUpdate, Timestamp: domain.UpdatedAt, Domain: &domain}
+		if kv.Version == 1 {
+			v.Op = models.EventOpCreate
+		}
+		versions = append(versions, v)
+		if v.Op == models.EventOpCreate || (since > 0 && v.Timestamp <= since) {
+			break
+		}
+
+		resp, err := s.client.client.Get(ctx, string(kv.Key), clientv3.WithRev(kv.ModRevision-1))
+		if rpctypes.Error(err) == rpctypes.ErrCompacted {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Kvs) == 0 {
+			break
+		}
+		kv = resp.Kvs[0]
+	}
+	return versions, nil
+}
+
+// listSnapshots 读取前缀下 ctx 所属租户的快照，按 Domain 短名分组，组内按 revision 倒序
+func (s *HistoryStorage) listSnapshots(ctx context.Context, prefix string) (map[string][]*models.DomainVersion, error) {
+	resp, err := s.client.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend))
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make(map[string][]*models.DomainVersion)
+	for _, kv := range resp.Kvs {
+		rest := strings.TrimPrefix(string(kv.Key), storage.DomainHistoryKeyPrefix)
+		parts := strings.Split(rest, "/")
+		if len(parts) != 3 {
+			continue
+		}
+		var v models.DomainVersion
+		if err := json.Unmarshal(kv.Value, &v); err != nil || tenant.Of(v.Tenant) != tenant.FromContext(ctx) {
+			continue
+		}
+		v.ZoneName, v.Name = parts[0], parts[1]
+		snapshots[v.Name] = append(snapshots[v.Name], &v)
+	}
+	return snapshots, nil
+}
+
+// SaveVersions 保存一批版本快照并将事件处理进度推进到 cursor
+// 快照按 etcd 单个事务的操作数上限分块写入，进度在最后一块中更新；之后丢弃各 Domain 超出 max_versions 的最旧快照
+func (s *HistoryStorage) SaveVersions(ctx context.Context, versions []*models.DomainVersion, cursor int64) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	ops := make([]clientv3.Op, 0, len(versions)+1)
+	touched := make(map[string]bool)
+	for _, v := range versions {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal domain version: %w", err)
+		}
+		prefix := s.domainHistoryPrefix(v.ZoneName, v.Name)
+		ops = append(ops, clientv3.OpPut(prefix+fmt.Sprintf("%020d", v.Revision), string(data)))
+		touched[prefix] = true
+	}
+	ops = append(ops, clientv3.OpPut(storage.HistoryCursorKey, strconv.FormatInt(cursor, 10)))
+	if err := s.commitChunked(ctx, ops); err != nil {
+		return err
+	}
+
+	for prefix := range touched {
+		if err := s.trimSnapshots(ctx, prefix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trimSnapshots 丢弃前缀下超出 max_versions 的最旧快照
+func (s *HistoryStorage) trimSnapshots(ctx context.Context, prefix string) error {
+	resp, err := s.client.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return err
+	}
+	excess := len(resp.Kvs) - s.config.History.MaxVersions
+	if excess <= 0 {
+		return nil
+	}
+
+	// key 以补零的 revision 结尾，按 key 顺序即按时间顺序
+	_, err = s.client.client.Delete(ctx, prefix, clientv3.WithRange(string(resp.Kvs[excess].Key)))
+	return err
+}
+
+// GetCursor 获取已处理到的事件 revision，尚未处理过事件时返回 0
+func (s *HistoryStorage) GetCursor(ctx context.Context) (int64, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return 0, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.HistoryCursorKey)
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.Kvs) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseInt(string(resp.Kvs[0].Value), 10, 64)
+}
+
+// commitChunked 按 etcd 单个事务的操作数上限分块提交
+func (s *HistoryStorage) commitChunked(ctx context.Context, ops []clientv3.Op) error {
+	maxOps := s.config.Etcd.MaxTxnOps
+	for start := 0; start < len(ops); start += maxOps {
+		end := min(start+maxOps, len(ops))
+		if _, err := s.client.client.Txn(ctx).Then(ops[start:end]...).Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// domainHistoryPrefix 生成 Domain 快照前缀
+func (s *HistoryStorage) domainHistoryPrefix(zone, domain string) string {
+	return storage.DomainHistoryKeyPrefix + zone + "/" + domain + "/"
+}
+
+// mergeVersions 合并两组版本，同一 revision 只保留一个，按 revision 倒序
+func mergeVersions(a, b []*models.DomainVersion) []*models.DomainVersion {
+	seen := make(map[int64]bool, len(a)+len(b))
+	merged := make([]*models.DomainVersion, 0, len(a)+len(b))
+	for _, v := range append(append([]*models.DomainVersion(nil), a...), b...) {
+		if seen[v.Revision] {
+			continue
+		}
+		seen[v.Revision] = true
+		merged = append(merged, v)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Revision > merged[j].Revision })
+	return merged
+}
@@ -0,0 +1,223 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"dancer/internal/errors"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// 动态实例与静态记录共用 Domain 的 CoreDNS 目录，通过 key 末段前缀区分：
+// 静态记录为 x{n}，动态实例为 i-{instance_id}
+const (
+	staticRecordKeyPrefix   = "x"
+	instanceRecordKeyPrefix = "i-"
+)
+
+// RegisterInstance 注册动态实例，实例元数据与 CoreDNS 记录绑定到实例自己的租约；Zone 未发布到 coredns 时不写入 CoreDNS 记录
+// 同一实例重复注册时，旧租约会被撤销；实例已由其他 Token 注册时返回 ErrForbidden
+func (s *DomainStorage) RegisterInstance(ctx context.Context, inst *models.Instance) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	domain, err := s.GetDomain(ctx, inst.Zone, inst.Domain)
+	if err != nil {
+		return err
+	}
+
+	if prev, err := s.GetInstance(ctx, inst.Zone, inst.Domain, inst.InstanceID); err == nil {
+		if prev.TokenID != inst.TokenID {
+			return errors.ErrForbidden
+		}
+		s.revokeLease(ctx, prev.LeaseID)
+	}
+
+	lease, err := s.client.client.Grant(ctx, inst.LeaseTTL)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	inst.LeaseID = int64(lease.ID)
+	inst.RegisteredAt = now
+	inst.LastHeartbeat = now
+
+	data, err := json.Marshal(inst)
+	if err != nil {
+		return err
+	}
+	record, err := json.Marshal(coreDNSRecord{Host: inst.IP, TTL: domain.TTL})
+	if err != nil {
+		return err
+	}
+
+	zone, err := s.publishingZone(ctx, inst.Zone, nil)
+	if err != nil {
+		return err
+	}
+
+	// 实例记录写入 Zone 发布到的每个 CoreDNS 视图
+	withLease := clientv3.WithLease(lease.ID)
+	ops := []clientv3.Op{clientv3.OpPut(s.instanceKey(inst.Zone, inst.Domain, inst.InstanceID), string(data), withLease)}
+	for _, v := range s.views {
+		if zone.PublishesTo(v.view) {
+			ops = append(ops, clientv3.OpPut(v.instanceKey(inst.Zone, inst.Domain, inst.InstanceID), string(record), withLease))
+		}
+	}
+	_, err = s.client.client.Txn(ctx).Then(ops...).Commit()
+	return err
+}
+
+// HeartbeatInstance 实例心跳，续约实例租约
+func (s *DomainStorage) HeartbeatInstance(ctx context.Context, zone, domain, instanceID string) (*models.Instance, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	inst, err := s.GetInstance(ctx, zone, domain, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.client.client.KeepAliveOnce(ctx, clientv3.LeaseID(inst.LeaseID)); err != nil {
+		if rpctypes.Error(err) == rpctypes.ErrLeaseNotFound {
+			return nil, errors.ErrInstanceNotFound
+		}
+		return nil, err
+	}
+
+	inst.LastHeartbeat = time.Now().Unix()
+	data, err := json.Marshal(inst)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.client.client.Put(ctx, s.instanceKey(zone, domain, instanceID), string(data),
+		clientv3.WithLease(clientv3.LeaseID(inst.LeaseID)))
+	if err != nil {
+		if rpctypes.Error(err) == rpctypes.ErrLeaseNotFound {
+			return nil, errors.ErrInstanceNotFound
+		}
+		return nil, err
+	}
+
+	return inst, nil
+}
+
+// DeregisterInstance 注销动态实例，不影响 Domain 的静态 IP
+func (s *DomainStorage) DeregisterInstance(ctx context.Context, zone, domain, instanceID string) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	inst, err := s.GetInstance(ctx, zone, domain, instanceID)
+	if err != nil {
+		return err
+	}
+
+	ops := []clientv3.Op{clientv3.OpDelete(s.instanceKey(zone, domain, instanceID))}
+	for _, v := range s.views {
+		ops = append(ops, clientv3.OpDelete(v.instanceKey(zone, domain, instanceID)))
+	}
+	_, err = s.client.client.Txn(ctx).Then(ops...).Commit()
+	if err != nil {
+		return err
+	}
+
+	s.revokeLease(ctx, inst.LeaseID)
+	return nil
+}
+
+// GetInstance 获取动态实例
+func (s *DomainStorage) GetInstance(ctx context.Context, zone, domain, instanceID string) (*models.Instance, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, s.instanceKey(zone, domain, instanceID))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, errors.ErrInstanceNotFound
+	}
+
+	var inst models.Instance
+	if err := json.Unmarshal(resp.Kvs[0].Value, &inst); err != nil {
+		return nil, err
+	}
+	return &inst, nil
+}
+
+// ListInstances 列出 Domain 下所有动态实例
+func (s *DomainStorage) ListInstances(ctx context.Context, zone, domain string) ([]*models.Instance, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	return s.listInstances(ctx, s.instancePrefix(zone, domain))
+}
+
+// listInstances 按前缀列出动态实例
+func (s *DomainStorage) listInstances(ctx context.Context, prefix string) ([]*models.Instance, error) {
+	kvs, err := s.client.listKVs(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]*models.Instance, 0, len(kvs))
+	for _, kv := range kvs {
+		var inst models.Instance
+		if err := json.Unmarshal(kv.Value, &inst); err != nil {
+			continue
+		}
+		instances = append(instances, &inst)
+	}
+	return instances, nil
+}
+
+// fillDynamicIPs 填充 Domain 的动态实例 IP
+func (s *DomainStorage) fillDynamicIPs(ctx context.Context, zone string, domains ...*models.Domain) error {
+	prefix := storage.InstanceKeyPrefix + zone + "/"
+	if len(domains) == 1 {
+		prefix = s.instancePrefix(zone, domains[0].Domain)
+	}
+
+	byDomain, err := s.dynamicIPsByDomain(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	for _, d := range domains {
+		d.DynamicIPs = byDomain[d.Domain]
+	}
+	return nil
+}
+
+// dynamicIPsByDomain 按 Domain 分组列出前缀下动态实例的 IP
+func (s *DomainStorage) dynamicIPsByDomain(ctx context.Context, prefix string) (map[string][]string, error) {
+	instances, err := s.listInstances(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	byDomain := make(map[string][]string)
+	for _, inst := range instances {
+		byDomain[inst.Domain] = append(byDomain[inst.Domain], inst.IP)
+	}
+	return byDomain, nil
+}
+
+// instanceKey 生成动态实例的 etcd key
+func (s *DomainStorage) instanceKey(zone, domain, instanceID string) string {
+	return s.instancePrefix(zone, domain) + instanceID
+}
+
+// instancePrefix 生成 Domain 下动态实例前缀
+func (s *DomainStorage) instancePrefix(zone, domain string) string {
+	return storage.InstanceKeyPrefix + zone + "/" + domain + "/"
+}
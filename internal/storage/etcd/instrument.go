@@ -0,0 +1,280 @@
+package etcd
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"time"
+
+	"dancer/internal/config"
+	"dancer/internal/metrics"
+	"dancer/internal/tracing"
+	"go.etcd.io/etcd/client/v3"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// storagePackage 存储层包路径，用于在调用栈中识别存储方法
+const storagePackage = "dancer/internal/storage/etcd"
+
+// maxCallerDepth 查找存储方法时最多检查的栈帧数
+const maxCallerDepth = 32
+
+// coreDNSPrefix 返回 CoreDNS 视图的记录在 etcd 中的前缀，以 / 结尾；视图未配置时返回 false
+func coreDNSPrefix(cfg *config.Config, view string) (string, bool) {
+	var prefix string
+	if view == config.DefaultView {
+		prefix = cfg.Etcd.CorednsPrefix
+		if prefix == "" {
+			prefix = "/skydns/"
+		}
+	} else {
+		for _, v := range cfg.Views {
+			if v.Name == view {
+				prefix = v.Prefix
+				break
+			}
+		}
+		if prefix == "" {
+			return "", false
+		}
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix, true
+}
+
+// viewNames 返回所有 CoreDNS 视图的名称，默认视图在最前
+func viewNames(cfg *config.Config) []string {
+	names := []string{config.DefaultView}
+	for _, v := range cfg.Views {
+		names = append(names, v.Name)
+	}
+	return names
+}
+
+// coreDNSPrefixes 返回所有 CoreDNS 视图的前缀
+func coreDNSPrefixes(cfg *config.Config) []string {
+	var prefixes []string
+	for _, name := range viewNames(cfg) {
+		prefix, _ := coreDNSPrefix(cfg, name)
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes
+}
+
+// instrumentedKV 包装 etcd KV，为每次操作创建 span，按存储方法记录耗时与错误，并统计 CoreDNS 记录的写入
+type instrumentedKV struct {
+	clientv3.KV
+	corednsPrefixes []string
+	coreDNS         *coreDNSTracker
+}
+
+func newInstrumentedKV(kv clientv3.KV, corednsPrefixes []string, coreDNS *coreDNSTracker) *instrumentedKV {
+	return &instrumentedKV{KV: kv, corednsPrefixes: corednsPrefixes, coreDNS: coreDNS}
+}
+
+func (kv *instrumentedKV) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	op := startOperation(ctx, storageMethod(), "get", attribute.String("etcd.key", key))
+	resp, err := kv.KV.Get(op.ctx, key, opts...)
+	op.end(err)
+	return resp, err
+}
+
+func (kv *instrumentedKV) Put(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	op := startOperation(ctx, storageMethod(), "put", attribute.String("etcd.key", key))
+	resp, err := kv.KV.Put(op.ctx, key, val, opts...)
+	op.end(err)
+	kv.observeCoreDNS([]clientv3.Op{clientv3.OpPut(key, val)}, err, true)
+	return resp, err
+}
+
+func (kv *instrumentedKV) Delete(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	op := startOperation(ctx, storageMethod(), "delete", attribute.String("etcd.key", key))
+	resp, err := kv.KV.Delete(op.ctx, key, opts...)
+	op.end(err)
+	kv.observeCoreDNS([]clientv3.Op{clientv3.OpDelete(key)}, err, true)
+	return resp, err
+}
+
+func (kv *instrumentedKV) Do(ctx context.Context, o clientv3.Op) (clientv3.OpResponse, error) {
+	op := startOperation(ctx, storageMethod(), opName(o), attribute.String("etcd.key", string(o.KeyBytes())))
+	resp, err := kv.KV.Do(op.ctx, o)
+	op.end(err)
+	kv.observeCoreDNS([]clientv3.Op{o}, err, true)
+	return resp, err
+}
+
+func (kv *instrumentedKV) Txn(ctx context.Context) clientv3.Txn {
+	// span 在 Commit 时创建，底层 Txn 也延迟到 Commit 时在 span 的 ctx 下创建
+	return &instrumentedTxn{ctx: ctx, kv: kv, method: storageMethod()}
+}
+
+// isCoreDNSKey key 是否在某个 CoreDNS 视图的前缀下
+func (kv *instrumentedKV) isCoreDNSKey(key string) bool {
+	for _, prefix := range kv.corednsPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// observeCoreDNS 统计 ops 中 CoreDNS 记录的写入；不包含 CoreDNS 记录时不计数
+func (kv *instrumentedKV) observeCoreDNS(ops []clientv3.Op, err error, succeeded bool) {
+	var puts, deletes int
+	for _, op := range ops {
+		if !kv.isCoreDNSKey(string(op.KeyBytes())) {
+			continue
+		}
+		switch {
+		case op.IsPut():
+			puts++
+		case op.IsDelete():
+			deletes++
+		}
+	}
+	if puts+deletes == 0 {
+		return
+	}
+
+	switch {
+	case err != nil:
+		metrics.CoreDNSSyncs.WithLabelValues("failure").Inc()
+		kv.coreDNS.observe(err)
+	case !succeeded:
+		metrics.CoreDNSSyncs.WithLabelValues("conflict").Inc()
+	default:
+		metrics.CoreDNSSyncs.WithLabelValues("success").Inc()
+		kv.coreDNS.observe(nil)
+		metrics.CoreDNSRecordWrites.WithLabelValues("put").Add(float64(puts))
+		metrics.CoreDNSRecordWrites.WithLabelValues("delete").Add(float64(deletes))
+	}
+}
+
+// instrumentedTxn 包装 etcd Txn，提交时创建 span、记录耗时与 Then 分支中 CoreDNS 记录的写入
+// 条件与操作先记录下来，提交时在 span 的 ctx 下创建底层 Txn
+type instrumentedTxn struct {
+	ctx     context.Context
+	kv      *instrumentedKV
+	method  string
+	cmps    []clientv3.Cmp
+	thenOps []clientv3.Op
+	elseOps []clientv3.Op
+}
+
+func (t *instrumentedTxn) If(cs ...clientv3.Cmp) clientv3.Txn {
+	t.cmps = append(t.cmps, cs...)
+	return t
+}
+
+func (t *instrumentedTxn) Then(ops ...clientv3.Op) clientv3.Txn {
+	t.thenOps = append(t.thenOps, ops...)
+	return t
+}
+
+func (t *instrumentedTxn) Else(ops ...clientv3.Op) clientv3.Txn {
+	t.elseOps = append(t.elseOps, ops...)
+	return t
+}
+
+func (t *instrumentedTxn) Commit() (*clientv3.TxnResponse, error) {
+	op := startOperation(t.ctx, t.method, "txn", attribute.Int("etcd.txn.ops", len(t.thenOps)+len(t.elseOps)))
+	resp, err := t.kv.KV.Txn(op.ctx).If(t.cmps...).Then(t.thenOps...).Else(t.elseOps...).Commit()
+	if err == nil {
+		op.span.SetAttributes(attribute.Bool("etcd.txn.succeeded", resp.Succeeded))
+	}
+	op.end(err)
+	t.kv.observeCoreDNS(t.thenOps, err, err == nil && resp.Succeeded)
+	return resp, err
+}
+
+// operation 一次进行中的 etcd 操作
+type operation struct {
+	ctx    context.Context
+	span   trace.Span
+	method string
+	name   string
+	start  time.Time
+}
+
+// startOperation 开始一次 etcd 操作，创建名为 "etcd <op>" 的 span
+func startOperation(ctx context.Context, method, name string, attrs ...attribute.KeyValue) *operation {
+	attrs = append(attrs,
+		semconv.DBSystemKey.String("etcd"),
+		semconv.DBOperationName(name),
+		attribute.String("dancer.storage.method", method),
+	)
+	ctx, span := tracing.Start(ctx, "etcd "+name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return &operation{ctx: ctx, span: span, method: method, name: name, start: time.Now()}
+}
+
+// end 结束操作，记录耗时与错误
+func (o *operation) end(err error) {
+	metrics.EtcdOperationDuration.WithLabelValues(o.method, o.name).Observe(time.Since(o.start).Seconds())
+	if err != nil {
+		metrics.EtcdOperationErrors.WithLabelValues(o.method, o.name).Inc()
+	}
+	tracing.RecordError(o.span, err)
+	o.span.End()
+}
+
+// opName 返回 Op 的类型
+func opName(op clientv3.Op) string {
+	switch {
+	case op.IsGet():
+		return "get"
+	case op.IsPut():
+		return "put"
+	case op.IsDelete():
+		return "delete"
+	case op.IsTxn():
+		return "txn"
+	}
+	return "other"
+}
+
+// storageMethod 返回调用栈中最外层的存储方法，如 DomainStorage.CreateDomain
+// 存储方法之间的调用（如 BackupStorage.Restore 调用 DomainStorage 的方法）计入外层方法；不经过存储方法的调用返回 other
+func storageMethod() string {
+	var pcs [maxCallerDepth]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	method := ""
+	for {
+		frame, more := frames.Next()
+		name, inPackage := strings.CutPrefix(frame.Function, storagePackage+".")
+		if !inPackage && method != "" {
+			break
+		}
+		if inPackage {
+			if m, ok := storageMethodName(name); ok {
+				method = m
+			}
+		}
+		if !more {
+			break
+		}
+	}
+	if method == "" {
+		return "other"
+	}
+	return method
+}
+
+// storageMethodName 将 (*DomainStorage).CreateDomain.func1 转换为 DomainStorage.CreateDomain，不是存储方法时返回 false
+func storageMethodName(name string) (string, bool) {
+	name, ok := strings.CutPrefix(name, "(*")
+	if !ok {
+		return "", false
+	}
+	typ, method, ok := strings.Cut(name, ").")
+	if !ok || !strings.HasSuffix(typ, "Storage") {
+		return "", false
+	}
+	method, _, _ = strings.Cut(method, ".")
+	return typ + "." + method, true
+}
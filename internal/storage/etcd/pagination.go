@@ -0,0 +1,182 @@
+package etcd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"dancer/internal/errors"
+	"dancer/internal/models"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// pageScanBatch 按 key 顺序分页时每次从 etcd 读取的最少条数
+const pageScanBatch = 100
+
+// pageCursor 分页游标内容，对调用方不透明
+// 按 key 排序时记录上一页最后一个 key（相对前缀），其他排序方式记录偏移量
+type pageCursor struct {
+	Key    string `json:"k,omitempty"`
+	Offset int    `json:"o,omitempty"`
+}
+
+func encodeCursor(c pageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (pageCursor, error) {
+	var c pageCursor
+	if s == "" {
+		return c, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, errors.ErrInvalidInput
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.Offset < 0 {
+		return c, errors.ErrInvalidInput
+	}
+	return c, nil
+}
+
+// listPage 分页读取 prefix 下的数据
+// 按 key 排序且指定了 limit 时，使用 WithFromKey / WithLimit 从游标位置开始分批读取，只读取填满一页所需的数据；
+// 其他排序方式需要读取全部数据后在内存中排序
+// decode 解码失败返回 false，对应的 key 被跳过
+func listPage[T any](ctx context.Context, c *Client, prefix string, q *models.ListQuery[T], decode func(kv *mvccpb.KeyValue) (T, bool)) (*models.Page[T], error) {
+	cursor, err := decodeCursor(q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.Less != nil || q.Limit == 0 {
+		return listPageInMemory(ctx, c, prefix, q, cursor, decode)
+	}
+
+	total, err := countMatches(ctx, c, prefix+q.KeyPrefix, q.Match, decode)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := q.Order == models.SortDesc
+	start, end := prefix+q.KeyPrefix, clientv3.GetPrefixRangeEnd(prefix+q.KeyPrefix)
+	if cursor.Key != "" {
+		if desc {
+			end = prefix + cursor.Key
+		} else {
+			start = prefix + cursor.Key + "\x00"
+		}
+	}
+
+	page := &models.Page[T]{Items: make([]T, 0, q.Limit), Total: total}
+	batch := int64(max(q.Limit+1, pageScanBatch))
+	for start < end {
+		opts := []clientv3.OpOption{clientv3.WithRange(end), clientv3.WithLimit(batch)}
+		if desc {
+			opts = append(opts, clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend))
+		}
+		resp, err := c.client.Get(ctx, start, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, kv := range resp.Kvs {
+			item, ok := decode(kv)
+			if !ok || (q.Match != nil && !q.Match(item)) {
+				continue
+			}
+			page.Items = append(page.Items, item)
+			if len(page.Items) == q.Limit {
+				// 本批还有未读取的数据或 etcd 中还有更多数据时返回下一页游标
+				if i < len(resp.Kvs)-1 || resp.More {
+					page.NextCursor = encodeCursor(pageCursor{Key: strings.TrimPrefix(string(kv.Key), prefix)})
+				}
+				return page, nil
+			}
+		}
+
+		if !resp.More || len(resp.Kvs) == 0 {
+			break
+		}
+		last := string(resp.Kvs[len(resp.Kvs)-1].Key)
+		if desc {
+			end = last
+		} else {
+			start = last + "\x00"
+		}
+	}
+
+	return page, nil
+}
+
+// listPageInMemory 读取全部数据，过滤、排序后按偏移量分页
+func listPageInMemory[T any](ctx context.Context, c *Client, prefix string, q *models.ListQuery[T], cursor pageCursor, decode func(kv *mvccpb.KeyValue) (T, bool)) (*models.Page[T], error) {
+	resp, err := c.client.Get(ctx, prefix+q.KeyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]T, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		item, ok := decode(kv)
+		if !ok || (q.Match != nil && !q.Match(item)) {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	// etcd 返回的数据已按 key 升序排列，相同排序值的数据保持 key 顺序
+	desc := q.Order == models.SortDesc
+	if q.Less != nil {
+		sort.SliceStable(items, func(i, j int) bool {
+			if desc {
+				return q.Less(items[j], items[i])
+			}
+			return q.Less(items[i], items[j])
+		})
+	} else if desc {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	page := &models.Page[T]{Items: items, Total: len(items)}
+	if q.Limit == 0 {
+		return page, nil
+	}
+
+	from := min(cursor.Offset, len(items))
+	to := min(from+q.Limit, len(items))
+	page.Items = items[from:to]
+	if to < len(items) {
+		page.NextCursor = encodeCursor(pageCursor{Offset: to})
+	}
+	return page, nil
+}
+
+// countMatches 统计 prefix 下满足过滤条件的数据条数，无过滤条件时只读取数量
+func countMatches[T any](ctx context.Context, c *Client, prefix string, match func(T) bool, decode func(kv *mvccpb.KeyValue) (T, bool)) (int, error) {
+	if match == nil {
+		resp, err := c.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+		if err != nil {
+			return 0, err
+		}
+		return int(resp.Count), nil
+	}
+
+	resp, err := c.client.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, kv := range resp.Kvs {
+		if item, ok := decode(kv); ok && match(item) {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,108 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"dancer/internal/errors"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// PublishedStorage 记录已发布到外部发布目标的域名
+// 发布目标上可能存在不由 Dancer 管理的记录，撤下记录时只处理这里记录过的域名
+type PublishedStorage struct {
+	client *Client
+}
+
+func NewPublishedStorage(client *Client) *PublishedStorage {
+	return &PublishedStorage{client: client}
+}
+
+// ListPublishedNames 列出 Zone 下已发布到 publisherName 的域名
+func (s *PublishedStorage) ListPublishedNames(ctx context.Context, publisherName, zone string) ([]string, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	prefix := s.zonePrefix(publisherName, zone)
+	resp, err := s.client.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		names = append(names, strings.TrimPrefix(string(kv.Key), prefix))
+	}
+	return names, nil
+}
+
+// ListPublishedZones 列出在 publisherName 上有已发布域名的 Zone
+func (s *PublishedStorage) ListPublishedZones(ctx context.Context, publisherName string) ([]string, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	prefix := storage.PublishedKeyPrefix + publisherName + "/"
+	resp, err := s.client.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+
+	var zones []string
+	for _, kv := range resp.Kvs {
+		zone, _, _ := strings.Cut(strings.TrimPrefix(string(kv.Key), prefix), "/")
+		if len(zones) == 0 || zones[len(zones)-1] != zone {
+			zones = append(zones, zone)
+		}
+	}
+	return zones, nil
+}
+
+// IsPublished 域名是否已发布到 publisherName
+func (s *PublishedStorage) IsPublished(ctx context.Context, publisherName, zone, name string) (bool, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return false, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, s.zonePrefix(publisherName, zone)+name, clientv3.WithCountOnly())
+	if err != nil {
+		return false, err
+	}
+	return resp.Count > 0, nil
+}
+
+// MarkPublished 记录域名已发布，保存最近一次发布的内容
+func (s *PublishedStorage) MarkPublished(ctx context.Context, publisherName, zone string, rs *models.PublishedRecordSet) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	data, err := json.Marshal(rs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record set: %w", err)
+	}
+
+	_, err = s.client.client.Put(ctx, s.zonePrefix(publisherName, zone)+rs.Name, string(data))
+	return err
+}
+
+// UnmarkPublished 删除域名的发布记录
+func (s *PublishedStorage) UnmarkPublished(ctx context.Context, publisherName, zone, name string) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	_, err := s.client.client.Delete(ctx, s.zonePrefix(publisherName, zone)+name)
+	return err
+}
+
+// zonePrefix 生成 Zone 在发布目标下的 key 前缀
+// 格式: /dancer/published/{publisher}/{zone}/
+func (s *PublishedStorage) zonePrefix(publisherName, zone string) string {
+	return storage.PublishedKeyPrefix + publisherName + "/" + zone + "/"
+}
@@ -0,0 +1,52 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.etcd.io/etcd/client/v3"
+)
+
+// maxCreateAttempts 创建记录时 ID 已被占用的最大重试次数
+const maxCreateAttempts = 16
+
+// createRecord 写入新记录，key 为 prefix + ID，仅当 key 不存在时写入，返回写入的 revision
+// ID 为毫秒时间戳，同一毫秒内的另一次创建（可能来自其他副本）已占用该 ID 时加 1 后重试，ID 仍按创建时间排序
+// encode 将记录的 ID 设置为 id 并返回序列化后的记录
+func (c *Client) createRecord(ctx context.Context, prefix, id string, encode func(id string) ([]byte, error)) (int64, error) {
+	return c.createRecordWith(ctx, prefix, id, encode, nil)
+}
+
+// createRecordWith 与 createRecord 相同，extra 不为空时返回与记录在同一事务中写入的其他操作（如索引 key）
+func (c *Client) createRecordWith(ctx context.Context, prefix, id string, encode func(id string) ([]byte, error), extra func(id string) []clientv3.Op) (int64, error) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid record id %q: %w", id, err)
+	}
+
+	for attempt := int64(0); attempt < maxCreateAttempts; attempt++ {
+		id := strconv.FormatInt(n+attempt, 10)
+		data, err := encode(id)
+		if err != nil {
+			return 0, err
+		}
+
+		key := prefix + id
+		ops := []clientv3.Op{clientv3.OpPut(key, string(data))}
+		if extra != nil {
+			ops = append(ops, extra(id)...)
+		}
+		resp, err := c.client.Txn(ctx).
+			If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+			Then(ops...).
+			Commit()
+		if err != nil {
+			return 0, err
+		}
+		if resp.Succeeded {
+			return resp.Header.Revision, nil
+		}
+	}
+	return 0, fmt.Errorf("no free id under %s after %d attempts", prefix, maxCreateAttempts)
+}
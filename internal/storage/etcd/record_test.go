@@ -0,0 +1,101 @@
+package etcd_test
+
+import (
+	"context"
+	"testing"
+
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/storage/etcd/etcdtest"
+	"github.com/sirupsen/logrus"
+)
+
+// TestCreateWithTakenID 同一毫秒内创建的记录得到相同的 ID 时，后创建的记录改用下一个 ID，不覆盖先创建的记录
+func TestCreateWithTakenID(t *testing.T) {
+	logger.Log = logrus.New()
+	client, cfg := etcdtest.Start(t)
+	ctx := context.Background()
+	const id = "1704067200000"
+
+	t.Run("schedule", func(t *testing.T) {
+		s := etcd.NewScheduleStorage(client)
+		first := &models.ScheduledChange{ID: id, Domain: "first"}
+		second := &models.ScheduledChange{ID: id, Domain: "second"}
+		for _, sc := range []*models.ScheduledChange{first, second} {
+			if err := s.CreateSchedule(ctx, sc); err != nil {
+				t.Fatalf("CreateSchedule: %v", err)
+			}
+		}
+		if second.ID != "1704067200001" {
+			t.Errorf("second id = %s, want 1704067200001", second.ID)
+		}
+		for _, want := range []*models.ScheduledChange{first, second} {
+			got, err := s.GetSchedule(ctx, want.ID)
+			if err != nil {
+				t.Fatalf("GetSchedule(%s): %v", want.ID, err)
+			}
+			if got.Domain != want.Domain || got.Revision != want.Revision {
+				t.Errorf("schedule %s = %s at revision %d, want %s at revision %d", want.ID, got.Domain, got.Revision, want.Domain, want.Revision)
+			}
+		}
+	})
+
+	t.Run("change request", func(t *testing.T) {
+		s := etcd.NewChangeRequestStorage(client)
+		first := &models.ChangeRequest{ID: id, Zone: "first.com"}
+		second := &models.ChangeRequest{ID: id, Zone: "second.com"}
+		for _, cr := range []*models.ChangeRequest{first, second} {
+			if err := s.CreateChangeRequest(ctx, cr); err != nil {
+				t.Fatalf("CreateChangeRequest: %v", err)
+			}
+		}
+		for _, want := range []*models.ChangeRequest{first, second} {
+			got, err := s.GetChangeRequest(ctx, want.ID)
+			if err != nil {
+				t.Fatalf("GetChangeRequest(%s): %v", want.ID, err)
+			}
+			if got.Zone != want.Zone {
+				t.Errorf("change request %s zone = %s, want %s", want.ID, got.Zone, want.Zone)
+			}
+		}
+	})
+
+	t.Run("api token", func(t *testing.T) {
+		s := etcd.NewAPITokenStorage(client)
+		first := &models.APIToken{ID: id, Name: "first", TokenHash: "hash1"}
+		second := &models.APIToken{ID: id, Name: "second", TokenHash: "hash2"}
+		for _, token := range []*models.APIToken{first, second} {
+			if err := s.CreateToken(ctx, token); err != nil {
+				t.Fatalf("CreateToken: %v", err)
+			}
+		}
+		tokens, err := s.ListTokens(ctx)
+		if err != nil {
+			t.Fatalf("ListTokens: %v", err)
+		}
+		if len(tokens) != 2 || tokens[0].Name != "first" || tokens[1].Name != "second" || tokens[1].ID != second.ID {
+			t.Errorf("tokens = %+v, want first and second", tokens)
+		}
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		s := etcd.NewWebhookStorage(client, cfg)
+		first := &models.Webhook{ID: id, Name: "first"}
+		second := &models.Webhook{ID: id, Name: "second"}
+		for _, wh := range []*models.Webhook{first, second} {
+			if err := s.CreateWebhook(ctx, wh); err != nil {
+				t.Fatalf("CreateWebhook: %v", err)
+			}
+		}
+		for _, want := range []*models.Webhook{first, second} {
+			got, err := s.GetWebhook(ctx, want.ID)
+			if err != nil {
+				t.Fatalf("GetWebhook(%s): %v", want.ID, err)
+			}
+			if got.Name != want.Name {
+				t.Errorf("webhook %s name = %s, want %s", want.ID, got.Name, want.Name)
+			}
+		}
+	})
+}
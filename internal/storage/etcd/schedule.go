@@ -0,0 +1,137 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"dancer/internal/errors"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"dancer/internal/tenant"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// ScheduleStorage 定时变更存储操作，记录所属租户，查询只返回 ctx 所属租户的定时变更
+type ScheduleStorage struct {
+	client *Client
+}
+
+func NewScheduleStorage(client *Client) *ScheduleStorage {
+	return &ScheduleStorage{client: client}
+}
+
+// ListSchedules 列出 ctx 所属租户的定时变更
+func (s *ScheduleStorage) ListSchedules(ctx context.Context) ([]*models.ScheduledChange, error) {
+	all, err := s.ListAllSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make([]*models.ScheduledChange, 0, len(all))
+	for _, sc := range all {
+		if tenant.Of(sc.Tenant) == tenant.FromContext(ctx) {
+			owned = append(owned, sc)
+		}
+	}
+	return owned, nil
+}
+
+// ListAllSchedules 列出所有租户的定时变更
+func (s *ScheduleStorage) ListAllSchedules(ctx context.Context) ([]*models.ScheduledChange, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.ScheduleKeyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*models.ScheduledChange, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var sc models.ScheduledChange
+		if err := json.Unmarshal(kv.Value, &sc); err != nil {
+			continue
+		}
+		sc.Revision = kv.ModRevision
+		schedules = append(schedules, &sc)
+	}
+
+	return schedules, nil
+}
+
+// GetSchedule 获取 ctx 所属租户的定时变更
+func (s *ScheduleStorage) GetSchedule(ctx context.Context, id string) (*models.ScheduledChange, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.ScheduleKeyPrefix+id)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, errors.ErrScheduleNotFound
+	}
+
+	var sc models.ScheduledChange
+	if err := json.Unmarshal(resp.Kvs[0].Value, &sc); err != nil {
+		return nil, err
+	}
+	if tenant.Of(sc.Tenant) != tenant.FromContext(ctx) {
+		return nil, errors.ErrScheduleNotFound
+	}
+	sc.Revision = resp.Kvs[0].ModRevision
+
+	return &sc, nil
+}
+
+// CreateSchedule 创建属于 ctx 所属租户的定时变更，ID 已被占用时改用下一个 ID
+func (s *ScheduleStorage) CreateSchedule(ctx context.Context, sc *models.ScheduledChange) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	sc.Tenant = tenant.FromContext(ctx)
+	revision, err := s.client.createRecord(ctx, storage.ScheduleKeyPrefix, sc.ID, func(id string) ([]byte, error) {
+		sc.ID = id
+		data, err := json.Marshal(sc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal schedule: %w", err)
+		}
+		return data, nil
+	})
+	if err != nil {
+		return err
+	}
+	sc.Revision = revision
+	return nil
+}
+
+// UpdateSchedule 更新定时变更
+// 仅当 etcd 中的记录自读取后未被修改时才写入，否则返回 ErrScheduleConflict
+func (s *ScheduleStorage) UpdateSchedule(ctx context.Context, sc *models.ScheduledChange) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	data, err := json.Marshal(sc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedule: %w", err)
+	}
+
+	key := storage.ScheduleKeyPrefix + sc.ID
+	resp, err := s.client.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", sc.Revision)).
+		Then(clientv3.OpPut(key, string(data))).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return errors.ErrScheduleConflict
+	}
+	sc.Revision = resp.Header.Revision
+	return nil
+}
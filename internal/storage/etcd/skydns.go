@@ -0,0 +1,272 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"dancer/internal/config"
+	"dancer/internal/errors"
+	"dancer/internal/models"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// SkyDNSPublisher 内置的 coredns 类型发布目标，对应一个 CoreDNS 视图
+// 将记录以 SkyDNS 格式写入视图的 etcd 前缀，供 CoreDNS etcd 插件与内置 DNS 服务器读取
+// DomainStorage 通过 planOps 将记录变更与 Domain 元数据放在同一事务中提交；Publisher 接口的方法各自提交事务
+type SkyDNSPublisher struct {
+	client *Client
+	config *config.Config
+	view   string
+	root   string // 视图的 etcd 前缀，以 / 结尾
+}
+
+func NewSkyDNSPublisher(client *Client, cfg *config.Config, view, prefix string) *SkyDNSPublisher {
+	return &SkyDNSPublisher{client: client, config: cfg, view: view, root: prefix}
+}
+
+// newViews 按配置为每个 CoreDNS 视图创建发布目标，默认视图在最前
+func newViews(client *Client, cfg *config.Config) []*SkyDNSPublisher {
+	var views []*SkyDNSPublisher
+	for _, name := range viewNames(cfg) {
+		prefix, _ := coreDNSPrefix(cfg, name)
+		views = append(views, NewSkyDNSPublisher(client, cfg, name, prefix))
+	}
+	return views
+}
+
+// coreDNSRecord CoreDNS (SkyDNS) 记录格式
+type coreDNSRecord struct {
+	Host string `json:"host"`
+	TTL  int    `json:"ttl"`
+}
+
+// Publish 将域名的静态记录替换为 rs 中的 IP，不影响动态实例记录
+func (p *SkyDNSPublisher) Publish(ctx context.Context, zone string, rs *models.PublishedRecordSet) error {
+	if err := p.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	ops, err := p.planOps(ctx, zone, subdomain(zone, rs.Name), rs.IPs, rs.TTL)
+	if err != nil {
+		return err
+	}
+	return p.commit(ctx, ops)
+}
+
+// Remove 删除域名下的所有记录，包括动态实例记录
+func (p *SkyDNSPublisher) Remove(ctx context.Context, zone, name string) error {
+	if err := p.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	_, err := p.client.client.Delete(ctx, p.domainPrefix(zone, subdomain(zone, name)), clientv3.WithPrefix())
+	return err
+}
+
+// List 列出 Zone 下的记录，包括动态实例记录与不由 Dancer 写入的记录；host 不是 IP 的记录被忽略
+func (p *SkyDNSPublisher) List(ctx context.Context, zone string) ([]*models.PublishedRecordSet, error) {
+	if err := p.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	zonePrefix := path.Join(p.prefix(), reverseZone(zone)) + "/"
+	resp, err := p.client.client.Get(ctx, zonePrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*models.PublishedRecordSet)
+	for _, kv := range resp.Kvs {
+		var record coreDNSRecord
+		if err := json.Unmarshal(kv.Value, &record); err != nil || net.ParseIP(record.Host) == nil {
+			continue
+		}
+
+		// {zonePrefix}{反转的子域名}/{记录 ID}
+		labels := strings.Split(strings.TrimPrefix(string(kv.Key), zonePrefix), "/")
+		labels = labels[:len(labels)-1]
+		for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+			labels[i], labels[j] = labels[j], labels[i]
+		}
+		name := strings.Join(append(labels, zone), ".")
+
+		rs, ok := byName[name]
+		if !ok {
+			rs = &models.PublishedRecordSet{Name: name, TTL: record.TTL}
+			byName[name] = rs
+		}
+		rs.IPs = append(rs.IPs, record.Host)
+	}
+
+	sets := make([]*models.PublishedRecordSet, 0, len(byName))
+	for _, rs := range byName {
+		sets = append(sets, rs)
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i].Name < sets[j].Name })
+	return sets, nil
+}
+
+// planOps 对比现有静态记录与期望的 IP 列表，生成同步所需的 etcd 操作，写入的记录带上 opts（如租约）
+// 1. 删除不再需要（或重复）的记录
+// 2. 保留的记录如果 TTL 发生变化则重写
+// 3. 新增的 IP 优先复用被删除记录的 key，其次使用未被占用的最小索引写入
+// 同一事务中不能对同一个 key 既删除又写入，因此被复用的 key 只生成写入操作
+func (p *SkyDNSPublisher) planOps(ctx context.Context, zone, domain string, ips []string, ttl int, opts ...clientv3.OpOption) ([]clientv3.Op, error) {
+	// 获取现有的 CoreDNS 记录（含动态实例记录，用于判断 key 是否被占用）
+	resp, err := p.client.client.Get(ctx, p.domainPrefix(zone, domain), clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	occupied := make(map[string]bool, len(resp.Kvs))
+	existing := make(map[string]coreDNSRecord)
+	keys := make([]string, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		key := string(kv.Key)
+		occupied[key] = true
+		if !isStaticRecordKey(key) {
+			continue
+		}
+		var record coreDNSRecord
+		if err := json.Unmarshal(kv.Value, &record); err != nil || record.Host == "" {
+			continue
+		}
+		existing[key] = record
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// 计算需要添加和删除的记录
+	desiredIPs := make(map[string]bool)
+	for _, ip := range ips {
+		desiredIPs[ip] = true
+	}
+
+	var ops []clientv3.Op
+	var freed []string
+	kept := make(map[string]bool)
+	for _, key := range keys {
+		record := existing[key]
+		if !desiredIPs[record.Host] || kept[record.Host] {
+			// 不再需要的记录，稍后复用或删除
+			freed = append(freed, key)
+			continue
+		}
+		kept[record.Host] = true
+
+		// 保留的记录如果 TTL 发生变化，需要重写
+		if record.TTL != ttl {
+			data, err := json.Marshal(coreDNSRecord{Host: record.Host, TTL: ttl})
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, clientv3.OpPut(key, string(data), opts...))
+		}
+	}
+
+	// 添加新记录
+	index := 1
+	for _, ip := range ips {
+		if kept[ip] {
+			continue
+		}
+		kept[ip] = true
+
+		var key string
+		if len(freed) > 0 {
+			key, freed = freed[0], freed[1:]
+		} else {
+			key = p.recordKey(zone, domain, strconv.Itoa(index))
+			for occupied[key] {
+				index++
+				key = p.recordKey(zone, domain, strconv.Itoa(index))
+			}
+			occupied[key] = true
+		}
+
+		data, err := json.Marshal(coreDNSRecord{Host: ip, TTL: ttl})
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, clientv3.OpPut(key, string(data), opts...))
+	}
+
+	// 删除未被复用的记录
+	for _, key := range freed {
+		ops = append(ops, clientv3.OpDelete(key))
+	}
+
+	return ops, nil
+}
+
+// commit 提交记录变更，同一 Domain 的变更在一个事务中提交，超过单个事务操作数上限时分块提交
+func (p *SkyDNSPublisher) commit(ctx context.Context, ops []clientv3.Op) error {
+	maxOps := p.config.Etcd.MaxTxnOps
+	for start := 0; start < len(ops); start += maxOps {
+		end := min(start+maxOps, len(ops))
+		if _, err := p.client.client.Txn(ctx).Then(ops[start:end]...).Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// View 返回视图名称
+func (p *SkyDNSPublisher) View() string {
+	return p.view
+}
+
+// prefix 获取视图的 CoreDNS etcd 前缀
+func (p *SkyDNSPublisher) prefix() string {
+	return p.root
+}
+
+// domainPrefix 生成 Domain 的 CoreDNS key 前缀
+func (p *SkyDNSPublisher) domainPrefix(zone, domain string) string {
+	return path.Join(p.prefix(), reverseZone(zone), domain) + "/"
+}
+
+// recordKey 生成静态记录的 CoreDNS key
+// 格式: {prefix}/{反转zone}/{domain}/x{index}
+// 示例: /skydns/com/example/www/x1
+func (p *SkyDNSPublisher) recordKey(zone, domain, index string) string {
+	key := path.Join(p.prefix(), reverseZone(zone), domain, staticRecordKeyPrefix+index)
+	if !strings.HasPrefix(key, "/") {
+		key = "/" + key
+	}
+	return key
+}
+
+// instanceKey 生成动态实例的 CoreDNS key
+// 格式: {prefix}/{反转zone}/{domain}/i-{instance_id}
+func (p *SkyDNSPublisher) instanceKey(zone, domain, instanceID string) string {
+	key := path.Join(p.prefix(), reverseZone(zone), domain, instanceRecordKeyPrefix+instanceID)
+	if !strings.HasPrefix(key, "/") {
+		key = "/" + key
+	}
+	return key
+}
+
+// reverseZone 反转域名层级
+func reverseZone(zone string) string {
+	parts := strings.Split(zone, ".")
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return path.Join(parts...)
+}
+
+// subdomain 由完整域名得到 Zone 下的子域名部分，如 www.example.com → www
+func subdomain(zone, name string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(name, "."), "."+zone)
+}
+
+// isStaticRecordKey 判断 CoreDNS key 是否为静态记录
+func isStaticRecordKey(key string) bool {
+	return strings.HasPrefix(path.Base(key), staticRecordKeyPrefix)
+}
@@ -0,0 +1,277 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"dancer/internal/config"
+	"dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"dancer/internal/tenant"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// TenantStorage 租户存储操作
+type TenantStorage struct {
+	client *Client
+	config *config.Config
+}
+
+func NewTenantStorage(client *Client, cfg *config.Config) *TenantStorage {
+	return &TenantStorage{client: client, config: cfg}
+}
+
+// ListTenants 列出所有租户
+func (s *TenantStorage) ListTenants(ctx context.Context) ([]*models.Tenant, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.TenantKeyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	tenants := make([]*models.Tenant, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var t models.Tenant
+		if err := json.Unmarshal(kv.Value, &t); err != nil {
+			continue
+		}
+		tenants = append(tenants, &t)
+	}
+
+	return tenants, nil
+}
+
+// GetTenant 获取租户
+func (s *TenantStorage) GetTenant(ctx context.Context, id string) (*models.Tenant, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.TenantKeyPrefix+id)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, errors.ErrTenantNotFound
+	}
+
+	var t models.Tenant
+	if err := json.Unmarshal(resp.Kvs[0].Value, &t); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// CreateTenant 创建租户，租户已存在时返回 ErrTenantExists
+func (s *TenantStorage) CreateTenant(ctx context.Context, t *models.Tenant) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	created, err := s.putIfAbsent(ctx, t)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return errors.ErrTenantExists
+	}
+	return nil
+}
+
+// DeleteTenant 删除租户及其用户，以及属于该租户的 API Token、Webhook、定时变更、变更申请与回收站条目
+// 调用方需确认租户下已没有 Zone；按 etcd 单个事务的操作数上限分块提交，中途失败时可以重新删除
+func (s *TenantStorage) DeleteTenant(ctx context.Context, id string) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	if _, err := s.GetTenant(ctx, id); err != nil {
+		return err
+	}
+
+	ops := []clientv3.Op{
+		clientv3.OpDelete(storage.UserPrefix(id), clientv3.WithPrefix()),
+		clientv3.OpDelete(storage.ZonePrefix(id), clientv3.WithPrefix()),
+		clientv3.OpDelete(storage.DomainPrefix(id), clientv3.WithPrefix()),
+	}
+
+	// 按记录中的 tenant 字段找出属于该租户的记录，children 为记录下属数据的前缀
+	owned := []struct {
+		prefix   string
+		children []string
+	}{
+		{storage.APITokenKeyPrefix, nil},
+		{storage.WebhookKeyPrefix, []string{storage.WebhookDeliveryKeyPrefix, storage.WebhookPendingKeyPrefix}},
+		{storage.ScheduleKeyPrefix, nil},
+		{storage.ChangeKeyPrefix, nil},
+		{storage.TrashKeyPrefix, []string{storage.TrashRecordKeyPrefix}},
+	}
+	for _, o := range owned {
+		resp, err := s.client.client.Get(ctx, o.prefix, clientv3.WithPrefix())
+		if err != nil {
+			return err
+		}
+		for _, kv := range resp.Kvs {
+			var record struct {
+				Tenant    string `json:"tenant"`
+				TokenHash string `json:"token_hash"`
+			}
+			if err := json.Unmarshal(kv.Value, &record); err != nil || record.Tenant != id {
+				continue
+			}
+			ops = append(ops, clientv3.OpDelete(string(kv.Key)))
+			if record.TokenHash != "" {
+				ops = append(ops, clientv3.OpDelete(storage.APITokenHashKeyPrefix+record.TokenHash))
+			}
+			recordID := strings.TrimPrefix(string(kv.Key), o.prefix)
+			for _, children := range o.children {
+				ops = append(ops, clientv3.OpDelete(children+recordID+"/", clientv3.WithPrefix()))
+			}
+		}
+	}
+
+	// 租户记录最后删除，中途失败时租户仍然存在
+	ops = append(ops, clientv3.OpDelete(storage.TenantKeyPrefix+id))
+
+	maxOps := s.config.Etcd.MaxTxnOps
+	for start := 0; start < len(ops); start += maxOps {
+		end := min(start+maxOps, len(ops))
+		if _, err := s.client.client.Txn(ctx).Then(ops[start:end]...).Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MigrateLayout 创建默认租户，并将升级前不含租户的用户、Zone 与 Domain key 迁移到默认租户下
+// 每个 key 的迁移在一个事务中完成，key 自读取后被修改时跳过，下次启动时再迁移；Domain 保留原有租约
+// 升级前的默认管理员迁移后成为超级管理员，其他管理员成为默认租户的管理员，需要时由超级管理员单独授予超级管理员
+func (s *TenantStorage) MigrateLayout(ctx context.Context) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	now := time.Now().Unix()
+	if _, err := s.putIfAbsent(ctx, &models.Tenant{ID: tenant.Default, Name: tenant.Default, CreatedAt: now, UpdatedAt: now}); err != nil {
+		return err
+	}
+
+	layouts := []struct {
+		prefix   string
+		newKey   func(rest string) string
+		isLegacy func(rest string) bool
+	}{
+		// {prefix}{zone} → {prefix}{tenant}/{zone}
+		{storage.ZoneKeyPrefix, func(rest string) string { return storage.ZonePrefix(tenant.Default) + rest },
+			func(rest string) bool { return !strings.Contains(rest, "/") }},
+		// {prefix}{zone}/{domain} → {prefix}{tenant}/{zone}/{domain}
+		{storage.DomainKeyPrefix, func(rest string) string { return storage.DomainPrefix(tenant.Default) + rest },
+			func(rest string) bool { return strings.Count(rest, "/") == 1 }},
+		// {prefix}{id} → {prefix}{tenant}/{id}
+		{storage.UserKeyPrefix, func(rest string) string { return storage.UserPrefix(tenant.Default) + rest },
+			func(rest string) bool { return !strings.Contains(rest, "/") }},
+	}
+
+	for _, layout := range layouts {
+		resp, err := s.client.client.Get(ctx, layout.prefix, clientv3.WithPrefix())
+		if err != nil {
+			return err
+		}
+
+		migrated := 0
+		for _, kv := range resp.Kvs {
+			rest := strings.TrimPrefix(string(kv.Key), layout.prefix)
+			if !layout.isLegacy(rest) {
+				continue
+			}
+
+			value := string(kv.Value)
+			if layout.prefix == storage.UserKeyPrefix {
+				if value, err = promoteAdmin(kv.Value); err != nil {
+					logger.Log.WithError(err).WithField("key", string(kv.Key)).Warn("Skipping malformed user during tenant migration")
+					continue
+				}
+			}
+
+			var opts []clientv3.OpOption
+			if kv.Lease != 0 {
+				opts = append(opts, clientv3.WithLease(clientv3.LeaseID(kv.Lease)))
+			}
+			// 先删除旧 key 再写入新 key，按名称而非完整 key 索引的 watcher 依次处理后记录仍然存在
+			newKey := layout.newKey(rest)
+			ops := []clientv3.Op{
+				clientv3.OpDelete(string(kv.Key)),
+				clientv3.OpPut(newKey, value, opts...),
+			}
+			if layout.prefix == storage.ZoneKeyPrefix {
+				ops = append(ops, clientv3.OpPut(storage.ZoneOwnerKeyPrefix+rest, tenant.Default))
+			}
+
+			// 新 key 已存在（如升级后已重新写入）时以新 key 为准，只删除旧 key
+			txn, err := s.client.client.Txn(ctx).
+				If(clientv3.Compare(clientv3.ModRevision(string(kv.Key)), "=", kv.ModRevision)).
+				Then(clientv3.OpTxn(
+					[]clientv3.Cmp{clientv3.Compare(clientv3.CreateRevision(newKey), "=", 0)},
+					ops,
+					[]clientv3.Op{clientv3.OpDelete(string(kv.Key))},
+				)).
+				Commit()
+			if err != nil {
+				return fmt.Errorf("failed to migrate %s: %w", string(kv.Key), err)
+			}
+			if txn.Succeeded {
+				migrated++
+			}
+		}
+
+		if migrated > 0 {
+			logger.Log.WithField("prefix", layout.prefix).WithField("count", migrated).Info("Migrated keys to the default tenant")
+		}
+	}
+	return nil
+}
+
+// putIfAbsent 写入租户记录，已存在时不做修改，返回是否写入
+func (s *TenantStorage) putIfAbsent(ctx context.Context, t *models.Tenant) (bool, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal tenant: %w", err)
+	}
+
+	key := storage.TenantKeyPrefix + t.ID
+	resp, err := s.client.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(data))).
+		Commit()
+	if err != nil {
+		return false, err
+	}
+	return resp.Succeeded, nil
+}
+
+// promoteAdmin 将升级前的默认管理员提升为超级管理员，返回迁移后的用户数据
+func promoteAdmin(data []byte) (string, error) {
+	var user models.User
+	if err := json.Unmarshal(data, &user); err != nil {
+		return "", err
+	}
+	if user.ID != models.DefaultAdminID || user.UserType != models.UserTypeAdmin {
+		return string(data), nil
+	}
+
+	user.UserType = models.UserTypeSuperAdmin
+	promoted, err := json.Marshal(&user)
+	if err != nil {
+		return "", err
+	}
+	return string(promoted), nil
+}
@@ -0,0 +1,52 @@
+package etcd_test
+
+import (
+	"context"
+	"testing"
+
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/storage/etcd/etcdtest"
+	"github.com/sirupsen/logrus"
+)
+
+// TestMigrateLayoutAdmins 升级前的默认管理员迁移后成为超级管理员，其他管理员仍是默认租户的管理员
+func TestMigrateLayoutAdmins(t *testing.T) {
+	logger.Log = logrus.New()
+	client, cfg := etcdtest.Start(t)
+	raw := client.GetClient()
+	ctx := context.Background()
+
+	legacy := map[string]string{
+		models.DefaultAdminID: `{"id":"10000","username":"admin","user_type":"admin"}`,
+		"10001":               `{"id":"10001","username":"ops","user_type":"admin"}`,
+		"10002":               `{"id":"10002","username":"dev","user_type":"normal"}`,
+	}
+	for id, user := range legacy {
+		if _, err := raw.Put(ctx, storage.UserKeyPrefix+id, user); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	if err := etcd.NewTenantStorage(client, cfg).MigrateLayout(ctx); err != nil {
+		t.Fatalf("MigrateLayout: %v", err)
+	}
+
+	users := etcd.NewUserStorage(client)
+	want := map[string]models.UserType{
+		models.DefaultAdminID: models.UserTypeSuperAdmin,
+		"10001":               models.UserTypeAdmin,
+		"10002":               models.UserTypeNormal,
+	}
+	for id, userType := range want {
+		user, err := users.GetUser(ctx, id)
+		if err != nil {
+			t.Fatalf("GetUser(%s): %v", id, err)
+		}
+		if user.UserType != userType {
+			t.Errorf("user %s type = %s, want %s", id, user.UserType, userType)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"dancer/internal/errors"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"dancer/internal/tenant"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// APITokenStorage API Token 存储操作，Token 记录所属租户，查询只返回 ctx 所属租户的 Token
+type APITokenStorage struct {
+	client *Client
+}
+
+func NewAPITokenStorage(client *Client) *APITokenStorage {
+	return &APITokenStorage{client: client}
+}
+
+// ListTokens 列出 ctx 所属租户的 API Token
+func (s *APITokenStorage) ListTokens(ctx context.Context) ([]*models.APIToken, error) {
+	tokens, err := s.listAllTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make([]*models.APIToken, 0, len(tokens))
+	for _, token := range tokens {
+		if tenant.Of(token.Tenant) == tenant.FromContext(ctx) {
+			owned = append(owned, token)
+		}
+	}
+	return owned, nil
+}
+
+// listAllTokens 列出所有租户的 API Token
+func (s *APITokenStorage) listAllTokens(ctx context.Context) ([]*models.APIToken, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.APITokenKeyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*models.APIToken, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var token models.APIToken
+		if err := json.Unmarshal(kv.Value, &token); err != nil {
+			continue
+		}
+		tokens = append(tokens, &token)
+	}
+
+	return tokens, nil
+}
+
+// GetTokenByHash 通过摘要索引获取 API Token，在所有租户中查找，用于认证
+func (s *APITokenStorage) GetTokenByHash(ctx context.Context, hash string) (*models.APIToken, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	idKV, err := s.client.getKV(ctx, storage.APITokenHashKeyPrefix+hash)
+	if err != nil {
+		return nil, err
+	}
+	if idKV == nil {
+		return nil, errors.ErrTokenNotFound
+	}
+
+	kv, err := s.client.getKV(ctx, storage.APITokenKeyPrefix+string(idKV.Value))
+	if err != nil {
+		return nil, err
+	}
+	if kv == nil {
+		return nil, errors.ErrTokenNotFound
+	}
+
+	var token models.APIToken
+	if err := json.Unmarshal(kv.Value, &token); err != nil {
+		return nil, err
+	}
+	if token.TokenHash != hash {
+		return nil, errors.ErrTokenNotFound
+	}
+	return &token, nil
+}
+
+// IndexTokenHashes 为还没有摘要索引的 Token（升级前创建）补写索引，启动时调用
+func (s *APITokenStorage) IndexTokenHashes(ctx context.Context) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.APITokenKeyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+
+	for _, kv := range resp.Kvs {
+		var token models.APIToken
+		if err := json.Unmarshal(kv.Value, &token); err != nil || token.TokenHash == "" {
+			continue
+		}
+
+		// Token 自读取后被删除时不写入
+		hashKey := storage.APITokenHashKeyPrefix + token.TokenHash
+		if _, err := s.client.client.Txn(ctx).
+			If(
+				clientv3.Compare(clientv3.CreateRevision(hashKey), "=", 0),
+				clientv3.Compare(clientv3.ModRevision(string(kv.Key)), "=", kv.ModRevision),
+			).
+			Then(clientv3.OpPut(hashKey, token.ID)).
+			Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateToken 创建属于 ctx 所属租户的 API Token，ID 已被占用时改用下一个 ID
+func (s *APITokenStorage) CreateToken(ctx context.Context, token *models.APIToken) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	token.Tenant = tenant.FromContext(ctx)
+
+	// 摘要索引与 Token 在同一事务中写入
+	_, err := s.client.createRecordWith(ctx, storage.APITokenKeyPrefix, token.ID, func(id string) ([]byte, error) {
+		token.ID = id
+		data, err := json.Marshal(token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal token: %w", err)
+		}
+		return data, nil
+	}, func(id string) []clientv3.Op {
+		return []clientv3.Op{clientv3.OpPut(storage.APITokenHashKeyPrefix+token.TokenHash, id)}
+	})
+	return err
+}
+
+// DeleteToken 删除 ctx 所属租户的 API Token
+func (s *APITokenStorage) DeleteToken(ctx context.Context, id string) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	key := storage.APITokenKeyPrefix + id
+	resp, err := s.client.client.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if len(resp.Kvs) == 0 {
+		return errors.ErrTokenNotFound
+	}
+	var token models.APIToken
+	if err := json.Unmarshal(resp.Kvs[0].Value, &token); err != nil {
+		return err
+	}
+	if tenant.Of(token.Tenant) != tenant.FromContext(ctx) {
+		return errors.ErrTokenNotFound
+	}
+
+	// 仅当读取后未被修改时删除，摘要索引一并删除
+	txn, err := s.client.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", resp.Kvs[0].ModRevision)).
+		Then(clientv3.OpDelete(key), clientv3.OpDelete(storage.APITokenHashKeyPrefix+token.TokenHash)).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !txn.Succeeded {
+		return errors.ErrTokenNotFound
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package etcd_test
+
+import (
+	"context"
+	"testing"
+
+	"dancer/internal/errors"
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/storage/etcd/etcdtest"
+	"github.com/sirupsen/logrus"
+)
+
+// TestTokenHashIndex 按摘要索引查找 Token，删除后不再能查到；升级前没有索引的 Token 补写索引后可以查到
+func TestTokenHashIndex(t *testing.T) {
+	logger.Log = logrus.New()
+	client, _ := etcdtest.Start(t)
+	s := etcd.NewAPITokenStorage(client)
+	ctx := context.Background()
+
+	token := &models.APIToken{ID: "1000", Name: "web", TokenHash: "h1", Zone: "example.com"}
+	if err := s.CreateToken(ctx, token); err != nil {
+		t.Fatalf("CreateToken: %v", err)
+	}
+	got, err := s.GetTokenByHash(ctx, "h1")
+	if err != nil || got.ID != "1000" {
+		t.Fatalf("GetTokenByHash = %+v, %v; want token 1000", got, err)
+	}
+	if _, err := s.GetTokenByHash(ctx, "h2"); err != errors.ErrTokenNotFound {
+		t.Errorf("GetTokenByHash unknown hash: err = %v, want ErrTokenNotFound", err)
+	}
+
+	if err := s.DeleteToken(ctx, "1000"); err != nil {
+		t.Fatalf("DeleteToken: %v", err)
+	}
+	if _, err := s.GetTokenByHash(ctx, "h1"); err != errors.ErrTokenNotFound {
+		t.Errorf("GetTokenByHash after delete: err = %v, want ErrTokenNotFound", err)
+	}
+
+	// 升级前的 Token 只有记录本身
+	raw := client.GetClient()
+	if _, err := raw.Put(ctx, storage.APITokenKeyPrefix+"2000", `{"id":"2000","token_hash":"h3","zone":"example.com"}`); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := s.GetTokenByHash(ctx, "h3"); err != errors.ErrTokenNotFound {
+		t.Errorf("GetTokenByHash before indexing: err = %v, want ErrTokenNotFound", err)
+	}
+	if err := s.IndexTokenHashes(ctx); err != nil {
+		t.Fatalf("IndexTokenHashes: %v", err)
+	}
+	if got, err := s.GetTokenByHash(ctx, "h3"); err != nil || got.ID != "2000" {
+		t.Errorf("GetTokenByHash after indexing = %+v, %v; want token 2000", got, err)
+	}
+}
@@ -0,0 +1,320 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"dancer/internal/config"
+	"dancer/internal/errors"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"dancer/internal/tenant"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// TrashStorage 回收站存储操作
+// 删除的 Zone / Domain 连同完整记录移入回收站，CoreDNS 记录随删除撤下，恢复时重新写入
+// 条目记录所属租户，查询只返回 ctx 所属租户的条目
+type TrashStorage struct {
+	client  *Client
+	config  *config.Config
+	domains *DomainStorage
+}
+
+func NewTrashStorage(client *Client, cfg *config.Config) *TrashStorage {
+	return &TrashStorage{
+		client:  client,
+		config:  cfg,
+		domains: NewDomainStorage(client, cfg),
+	}
+}
+
+// ListEntries 列出 ctx 所属租户的回收站条目，按删除时间倒序
+func (s *TrashStorage) ListEntries(ctx context.Context) ([]*models.TrashEntry, error) {
+	entries, err := s.ListAllEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make([]*models.TrashEntry, 0, len(entries))
+	for _, entry := range entries {
+		if tenant.Of(entry.Tenant) == tenant.FromContext(ctx) {
+			owned = append(owned, entry)
+		}
+	}
+	return owned, nil
+}
+
+// ListAllEntries 列出所有租户的回收站条目，按删除时间倒序，供到期清理使用
+func (s *TrashStorage) ListAllEntries(ctx context.Context) ([]*models.TrashEntry, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.TrashKeyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*models.TrashEntry, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var entry models.TrashEntry
+		if err := json.Unmarshal(kv.Value, &entry); err != nil {
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].DeletedAt > entries[j].DeletedAt })
+
+	return entries, nil
+}
+
+// GetEntry 获取 ctx 所属租户的回收站条目
+func (s *TrashStorage) GetEntry(ctx context.Context, id string) (*models.TrashEntry, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.TrashKeyPrefix+id)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, errors.ErrTrashEntryNotFound
+	}
+
+	var entry models.TrashEntry
+	if err := json.Unmarshal(resp.Kvs[0].Value, &entry); err != nil {
+		return nil, err
+	}
+	if tenant.Of(entry.Tenant) != tenant.FromContext(ctx) {
+		return nil, errors.ErrTrashEntryNotFound
+	}
+
+	return &entry, nil
+}
+
+// TrashZone 删除 Zone 并将其及其下所有 Domain 移入回收站
+// 依次写入 Domain 记录与条目、撤下 CoreDNS 记录、删除 Domain 与动态实例，最后删除 Zone 本身，按 etcd 单个事务的操作数上限分块提交；
+// 中途失败时 Zone 仍然存在，可以重新删除。临时 Domain 与动态实例不进入回收站，其租约被撤销
+func (s *TrashStorage) TrashZone(ctx context.Context, zone string) (*models.TrashEntry, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	zoneKey := zoneKey(ctx, zone)
+	resp, err := s.client.client.Get(ctx, zoneKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, errors.ErrZoneNotFound
+	}
+	var z models.Zone
+	if err := json.Unmarshal(resp.Kvs[0].Value, &z); err != nil {
+		return nil, err
+	}
+
+	resp, err = s.client.client.Get(ctx, s.domains.domainPrefix(ctx, zone), clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	instances, err := s.domains.listInstances(ctx, storage.InstanceKeyPrefix+zone+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	entry := newTrashEntry(ctx, models.TrashTypeZone, zone, s.config.Trash.Retention)
+	entry.ZoneRecord = &z
+
+	var puts, deletes []clientv3.Op
+	var leases []int64
+	for _, kv := range resp.Kvs {
+		var d models.Domain
+		if err := json.Unmarshal(kv.Value, &d); err != nil {
+			continue
+		}
+		deletes = append(deletes, s.domains.deleteCoreDNSOps(zone, d.Domain)...)
+		if d.IsEphemeral() {
+			leases = append(leases, d.LeaseID)
+			continue
+		}
+		puts = append(puts, clientv3.OpPut(s.recordKey(entry.ID, d.Domain), string(kv.Value)))
+		entry.DomainCount++
+	}
+	for _, inst := range instances {
+		leases = append(leases, inst.LeaseID)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal trash entry: %w", err)
+	}
+
+	ops := append(puts, clientv3.OpPut(storage.TrashKeyPrefix+entry.ID, string(data)))
+	ops = append(ops, deletes...)
+	ops = append(ops,
+		clientv3.OpDelete(s.domains.domainPrefix(ctx, zone), clientv3.WithPrefix()),
+		clientv3.OpDelete(storage.InstanceKeyPrefix+zone+"/", clientv3.WithPrefix()),
+		clientv3.OpDelete(zoneKey),
+		clientv3.OpDelete(storage.ZoneOwnerKeyPrefix+zone),
+	)
+	if err := s.commitChunked(ctx, ops); err != nil {
+		return nil, err
+	}
+
+	for _, leaseID := range leases {
+		s.domains.revokeLease(ctx, leaseID)
+	}
+	return entry, nil
+}
+
+// RestoreDomain 从回收站恢复 Domain 并重新写入 CoreDNS 记录，Domain 已存在时返回 ErrDomainExists
+func (s *TrashStorage) RestoreDomain(ctx context.Context, entry *models.TrashEntry) (*models.Domain, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	domain := *entry.Record
+	domain.UpdatedAt = time.Now().Unix()
+
+	ops, err := s.domains.domainPutOps(ctx, &domain, nil)
+	if err != nil {
+		return nil, err
+	}
+	ops = append(ops, clientv3.OpDelete(storage.TrashKeyPrefix+entry.ID))
+
+	key := s.domains.domainKey(ctx, domain.Zone, domain.Domain)
+	resp, err := s.client.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(ops...).
+		Commit()
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Succeeded {
+		return nil, errors.ErrDomainExists
+	}
+	return &domain, nil
+}
+
+// RestoreZone 从回收站恢复 Zone 及其下所有 Domain 并重新写入 CoreDNS 记录
+// 先登记 Zone 所属租户，Zone 名称已被其他租户使用时返回 ErrZoneExists；
+// 再写入 Domain，Zone 本身与条目的删除在最后一块中提交；调用方需确认 Zone 不存在
+func (s *TrashStorage) RestoreZone(ctx context.Context, entry *models.TrashEntry) (*models.Zone, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	if err := claimZone(ctx, s.client, entry.Zone); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.client.Get(ctx, s.recordPrefix(entry.ID), clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	var ops []clientv3.Op
+	for _, kv := range resp.Kvs {
+		var d models.Domain
+		if err := json.Unmarshal(kv.Value, &d); err != nil {
+			continue
+		}
+		d.UpdatedAt = now
+		domainOps, err := s.domains.domainPutOps(ctx, &d, entry.ZoneRecord)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, domainOps...)
+	}
+
+	zone := *entry.ZoneRecord
+	zone.RecordCount = len(resp.Kvs)
+	zone.UpdatedAt = now
+	data, err := json.Marshal(&zone)
+	if err != nil {
+		return nil, err
+	}
+	ops = append(ops,
+		clientv3.OpPut(zoneKey(ctx, zone.Zone), string(data)),
+		clientv3.OpDelete(storage.TrashKeyPrefix+entry.ID),
+		clientv3.OpDelete(s.recordPrefix(entry.ID), clientv3.WithPrefix()),
+	)
+	if err := s.commitChunked(ctx, ops); err != nil {
+		return nil, err
+	}
+	return &zone, nil
+}
+
+// DeleteEntry 永久删除回收站条目及其 Domain 记录，不检查条目所属租户
+func (s *TrashStorage) DeleteEntry(ctx context.Context, id string) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Txn(ctx).Then(
+		clientv3.OpDelete(storage.TrashKeyPrefix+id),
+		clientv3.OpDelete(s.recordPrefix(id), clientv3.WithPrefix()),
+	).Commit()
+	if err != nil {
+		return err
+	}
+	if resp.Responses[0].GetResponseDeleteRange().Deleted == 0 {
+		return errors.ErrTrashEntryNotFound
+	}
+	return nil
+}
+
+// commitChunked 按 etcd 单个事务的操作数上限分块提交
+func (s *TrashStorage) commitChunked(ctx context.Context, ops []clientv3.Op) error {
+	maxOps := s.config.Etcd.MaxTxnOps
+	for start := 0; start < len(ops); start += maxOps {
+		end := min(start+maxOps, len(ops))
+		if _, err := s.client.client.Txn(ctx).Then(ops[start:end]...).Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordKey 生成 Zone 条目下 Domain 记录的 etcd key
+func (s *TrashStorage) recordKey(id, domain string) string {
+	return s.recordPrefix(id) + domain
+}
+
+// recordPrefix 生成 Zone 条目下 Domain 记录前缀
+func (s *TrashStorage) recordPrefix(id string) string {
+	return storage.TrashRecordKeyPrefix + id + "/"
+}
+
+// newTrashEntry 生成属于 ctx 所属租户的回收站条目，ID 取纳秒时间戳，同一批删除中的多个条目也不会重复
+func newTrashEntry(ctx context.Context, typ models.TrashType, zone string, retention int64) *models.TrashEntry {
+	now := time.Now()
+	return &models.TrashEntry{
+		ID:        strconv.FormatInt(now.UnixNano(), 10),
+		Tenant:    tenant.FromContext(ctx),
+		Type:      typ,
+		Zone:      zone,
+		DeletedAt: now.Unix(),
+		ExpiresAt: now.Unix() + retention,
+	}
+}
+
+// trashDomainOp 生成将 Domain 移入回收站的写入操作
+func trashDomainOp(ctx context.Context, domain *models.Domain, retention int64) (clientv3.Op, error) {
+	entry := newTrashEntry(ctx, models.TrashTypeDomain, domain.Zone, retention)
+	entry.Domain = domain.Domain
+	entry.Record = domain
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return clientv3.Op{}, fmt.Errorf("failed to marshal trash entry: %w", err)
+	}
+	return clientv3.OpPut(storage.TrashKeyPrefix+entry.ID, string(data)), nil
+}
@@ -9,10 +9,12 @@ import (
 	"dancer/internal/errors"
 	"dancer/internal/models"
 	"dancer/internal/storage"
+	"dancer/internal/tenant"
+	"go.etcd.io/etcd/api/v3/mvccpb"
 	"go.etcd.io/etcd/client/v3"
 )
 
-// UserStorage 用户存储操作
+// UserStorage 用户存储操作，读写 ctx 所属租户的用户
 type UserStorage struct {
 	client *Client
 }
@@ -38,7 +40,7 @@ func (s *UserStorage) GetUser(ctx context.Context, id string) (*models.User, err
 		return nil, err
 	}
 
-	key := storage.UserKeyPrefix + id
+	key := storage.UserPrefix(tenant.FromContext(ctx)) + id
 	resp, err := s.client.client.Get(ctx, key)
 	if err != nil {
 		return nil, err
@@ -63,7 +65,7 @@ func (s *UserStorage) GetUserByUsername(ctx context.Context, username string) (*
 	}
 
 	// 获取所有用户并筛选
-	resp, err := s.client.client.Get(ctx, storage.UserKeyPrefix, clientv3.WithPrefix())
+	resp, err := s.client.client.Get(ctx, storage.UserPrefix(tenant.FromContext(ctx)), clientv3.WithPrefix())
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +89,7 @@ func (s *UserStorage) ListUsers(ctx context.Context) ([]*models.User, error) {
 		return nil, err
 	}
 
-	resp, err := s.client.client.Get(ctx, storage.UserKeyPrefix, clientv3.WithPrefix())
+	resp, err := s.client.client.Get(ctx, storage.UserPrefix(tenant.FromContext(ctx)), clientv3.WithPrefix())
 	if err != nil {
 		return nil, err
 	}
@@ -104,13 +106,28 @@ func (s *UserStorage) ListUsers(ctx context.Context) ([]*models.User, error) {
 	return users, nil
 }
 
+// ListUsersPage 分页列出用户
+func (s *UserStorage) ListUsersPage(ctx context.Context, q *models.ListQuery[*models.User]) (*models.Page[*models.User], error) {
+	if err := s.checkConnection(); err != nil {
+		return nil, err
+	}
+
+	return listPage(ctx, s.client, storage.UserPrefix(tenant.FromContext(ctx)), q, func(kv *mvccpb.KeyValue) (*models.User, bool) {
+		var user models.User
+		if err := json.Unmarshal(kv.Value, &user); err != nil {
+			return nil, false
+		}
+		return &user, true
+	})
+}
+
 // CreateUser 创建用户
 func (s *UserStorage) CreateUser(ctx context.Context, user *models.User) error {
 	if err := s.checkConnection(); err != nil {
 		return err
 	}
 
-	key := storage.UserKeyPrefix + user.ID
+	key := storage.UserPrefix(tenant.FromContext(ctx)) + user.ID
 
 	// 检查用户是否已存在（通过用户名）
 	_, err := s.GetUserByUsername(ctx, user.Username)
@@ -133,7 +150,7 @@ func (s *UserStorage) UpdateUser(ctx context.Context, user *models.User) error {
 		return err
 	}
 
-	key := storage.UserKeyPrefix + user.ID
+	key := storage.UserPrefix(tenant.FromContext(ctx)) + user.ID
 
 	// 检查用户是否存在
 	_, err := s.GetUser(ctx, user.ID)
@@ -156,7 +173,7 @@ func (s *UserStorage) DeleteUser(ctx context.Context, id string) error {
 		return err
 	}
 
-	key := storage.UserKeyPrefix + id
+	key := storage.UserPrefix(tenant.FromContext(ctx)) + id
 	_, err := s.client.client.Delete(ctx, key)
 	return err
 }
@@ -167,7 +184,7 @@ func (s *UserStorage) CountUsers(ctx context.Context) (int64, error) {
 		return 0, err
 	}
 
-	resp, err := s.client.client.Get(ctx, storage.UserKeyPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+	resp, err := s.client.client.Get(ctx, storage.UserPrefix(tenant.FromContext(ctx)), clientv3.WithPrefix(), clientv3.WithCountOnly())
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,131 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"dancer/internal/config"
+	"dancer/internal/errors"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// VerificationStorage DNS 校验结果存储操作，每个 Domain 只保留最近一次结果
+type VerificationStorage struct {
+	client *Client
+	config *config.Config
+}
+
+func NewVerificationStorage(client *Client, cfg *config.Config) *VerificationStorage {
+	return &VerificationStorage{client: client, config: cfg}
+}
+
+// SaveVerification 保存 Domain 的校验结果，覆盖之前的结果
+func (s *VerificationStorage) SaveVerification(ctx context.Context, v *models.DomainVerification) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal verification: %w", err)
+	}
+
+	_, err = s.client.client.Put(ctx, s.verificationKey(v.Zone, v.Domain), string(data))
+	return err
+}
+
+// GetVerification 获取 Domain 最近一次校验结果
+func (s *VerificationStorage) GetVerification(ctx context.Context, zone, domain string) (*models.DomainVerification, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, s.verificationKey(zone, domain))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, errors.ErrVerificationNotFound
+	}
+
+	var v models.DomainVerification
+	if err := json.Unmarshal(resp.Kvs[0].Value, &v); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal verification: %w", err)
+	}
+	return &v, nil
+}
+
+// ListZoneVerifications 列出 Zone 下所有 Domain 最近一次校验结果，按 Domain 短名排序
+func (s *VerificationStorage) ListZoneVerifications(ctx context.Context, zone string) ([]*models.DomainVerification, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, s.zonePrefix(zone), clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*models.DomainVerification, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var v models.DomainVerification
+		if err := json.Unmarshal(kv.Value, &v); err != nil {
+			continue
+		}
+		results = append(results, &v)
+	}
+	return results, nil
+}
+
+// ReplaceZoneVerifications 用一次 Zone 校验的结果替换 Zone 下所有旧结果，已删除 Domain 的结果随之清除
+// 结果较多时分批写入，每批不超过 max_txn_ops 个操作
+func (s *VerificationStorage) ReplaceZoneVerifications(ctx context.Context, zone string, results []*models.DomainVerification) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	var ops []clientv3.Op
+	keep := make(map[string]bool, len(results))
+	for _, v := range results {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal verification: %w", err)
+		}
+		key := s.verificationKey(zone, v.Domain)
+		keep[key] = true
+		ops = append(ops, clientv3.OpPut(key, string(data)))
+	}
+
+	// etcd 不允许同一事务中删除与写入重叠的 key，只删除本次没有结果的旧 key
+	resp, err := s.client.client.Get(ctx, s.zonePrefix(zone), clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return err
+	}
+	for _, kv := range resp.Kvs {
+		if !keep[string(kv.Key)] {
+			ops = append(ops, clientv3.OpDelete(string(kv.Key)))
+		}
+	}
+
+	maxOps := s.config.Etcd.MaxTxnOps
+	for start := 0; start < len(ops); start += maxOps {
+		end := min(start+maxOps, len(ops))
+		if _, err := s.client.client.Txn(ctx).Then(ops[start:end]...).Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// zonePrefix Zone 下校验结果的 key 前缀
+func (s *VerificationStorage) zonePrefix(zone string) string {
+	return storage.VerificationKeyPrefix + zone + "/"
+}
+
+// verificationKey 生成 Domain 校验结果的 etcd key
+func (s *VerificationStorage) verificationKey(zone, domain string) string {
+	return s.zonePrefix(zone) + domain
+}
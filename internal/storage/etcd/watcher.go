@@ -0,0 +1,73 @@
+package etcd
+
+import (
+	"context"
+	"time"
+
+	"dancer/internal/logger"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// WatchHandler 前缀监听回调
+// 回调在同一个 goroutine 中按 revision 顺序调用
+type WatchHandler interface {
+	// Reset 用前缀下的全量数据重建状态
+	// 在首次启动以及 watch 中断（revision 被压缩、etcd 重连）后调用
+	Reset(kvs []*mvccpb.KeyValue, revision int64)
+	// Apply 应用一批增量事件，revision 为该批事件对应的 etcd revision
+	// 收到进度通知时 events 为空，仅推进 revision
+	Apply(events []*clientv3.Event, revision int64)
+}
+
+// WatchPrefix 持续监听 prefix 下的变化，阻塞直到 ctx 取消
+// 先做一次全量读取，再从读取时的 revision 开始 watch；watch 中断时重新全量读取，保证不丢失事件
+func (c *Client) WatchPrefix(ctx context.Context, prefix string, h WatchHandler) {
+	for {
+		if err := c.watchOnce(ctx, prefix, h); err != nil && ctx.Err() == nil {
+			logger.Log.WithError(err).WithField("prefix", prefix).Warn("Watch interrupted, resyncing")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// watchOnce 执行一轮全量读取 + watch，watch 中断时返回
+func (c *Client) watchOnce(ctx context.Context, prefix string, h WatchHandler) error {
+	if err := c.WaitForConnection(defaultWaitTimeout); err != nil {
+		return err
+	}
+	client := c.GetClient()
+	if client == nil {
+		return nil
+	}
+
+	resp, err := client.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+	h.Reset(resp.Kvs, resp.Header.Revision)
+
+	watchCtx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+	defer cancel()
+
+	wch := client.Watch(watchCtx, prefix, clientv3.WithPrefix(), clientv3.WithRev(resp.Header.Revision+1), clientv3.WithPrevKV())
+	for wresp := range wch {
+		if wresp.CompactRevision != 0 {
+			logger.Log.WithField("prefix", prefix).WithField("compact_revision", wresp.CompactRevision).Warn("Watch revision compacted")
+			return nil
+		}
+		if err := wresp.Err(); err != nil {
+			return err
+		}
+		// 进度通知不含事件，但表明 revision 之前的事件都已送达
+		if len(wresp.Events) > 0 || wresp.IsProgressNotify() {
+			h.Apply(wresp.Events, wresp.Header.Revision)
+		}
+	}
+	return ctx.Err()
+}
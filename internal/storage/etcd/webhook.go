@@ -0,0 +1,362 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"dancer/internal/config"
+	"dancer/internal/errors"
+	"dancer/internal/models"
+	"dancer/internal/storage"
+	"dancer/internal/tenant"
+	"go.etcd.io/etcd/client/v3"
+)
+
+// WebhookStorage Webhook 订阅及投递记录存储操作，订阅记录所属租户，查询只返回 ctx 所属租户的订阅
+// 等待投递的记录另有一个索引 key，与投递记录在同一事务中写入和删除，投递器只读取索引中的记录
+type WebhookStorage struct {
+	client *Client
+	config *config.Config
+}
+
+func NewWebhookStorage(client *Client, cfg *config.Config) *WebhookStorage {
+	return &WebhookStorage{client: client, config: cfg}
+}
+
+// ListWebhooks 列出 ctx 所属租户的 Webhook 订阅
+func (s *WebhookStorage) ListWebhooks(ctx context.Context) ([]*models.Webhook, error) {
+	webhooks, err := s.ListAllWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make([]*models.Webhook, 0, len(webhooks))
+	for _, wh := range webhooks {
+		if tenant.Of(wh.Tenant) == tenant.FromContext(ctx) {
+			owned = append(owned, wh)
+		}
+	}
+	return owned, nil
+}
+
+// ListAllWebhooks 列出所有租户的 Webhook 订阅，供投递器使用
+func (s *WebhookStorage) ListAllWebhooks(ctx context.Context) ([]*models.Webhook, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.WebhookKeyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]*models.Webhook, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var wh models.Webhook
+		if err := json.Unmarshal(kv.Value, &wh); err != nil {
+			continue
+		}
+		webhooks = append(webhooks, &wh)
+	}
+
+	return webhooks, nil
+}
+
+// GetWebhook 获取 ctx 所属租户的 Webhook 订阅
+func (s *WebhookStorage) GetWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.WebhookKeyPrefix+id)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, errors.ErrWebhookNotFound
+	}
+
+	var wh models.Webhook
+	if err := json.Unmarshal(resp.Kvs[0].Value, &wh); err != nil {
+		return nil, err
+	}
+	if tenant.Of(wh.Tenant) != tenant.FromContext(ctx) {
+		return nil, errors.ErrWebhookNotFound
+	}
+
+	return &wh, nil
+}
+
+// CreateWebhook 创建属于 ctx 所属租户的 Webhook 订阅，ID 已被占用时改用下一个 ID
+func (s *WebhookStorage) CreateWebhook(ctx context.Context, wh *models.Webhook) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	wh.Tenant = tenant.FromContext(ctx)
+	_, err := s.client.createRecord(ctx, storage.WebhookKeyPrefix, wh.ID, func(id string) ([]byte, error) {
+		wh.ID = id
+		data, err := json.Marshal(wh)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal webhook: %w", err)
+		}
+		return data, nil
+	})
+	return err
+}
+
+// SaveWebhook 更新 ctx 所属租户的 Webhook 订阅
+func (s *WebhookStorage) SaveWebhook(ctx context.Context, wh *models.Webhook) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	wh.Tenant = tenant.FromContext(ctx)
+	data, err := json.Marshal(wh)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook: %w", err)
+	}
+
+	_, err = s.client.client.Put(ctx, storage.WebhookKeyPrefix+wh.ID, string(data))
+	return err
+}
+
+// DeleteWebhook 删除 ctx 所属租户的 Webhook 订阅及其所有投递记录
+func (s *WebhookStorage) DeleteWebhook(ctx context.Context, id string) error {
+	if _, err := s.GetWebhook(ctx, id); err != nil {
+		return err
+	}
+
+	resp, err := s.client.client.Txn(ctx).Then(
+		clientv3.OpDelete(storage.WebhookKeyPrefix+id),
+		clientv3.OpDelete(s.deliveryPrefix(id), clientv3.WithPrefix()),
+		clientv3.OpDelete(storage.WebhookPendingKeyPrefix+id+"/", clientv3.WithPrefix()),
+	).Commit()
+	if err != nil {
+		return err
+	}
+	if resp.Responses[0].GetResponseDeleteRange().Deleted == 0 {
+		return errors.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// ListDeliveries 按时间顺序列出投递记录，webhookID 为空时列出所有订阅的记录
+func (s *WebhookStorage) ListDeliveries(ctx context.Context, webhookID string) ([]*models.WebhookDelivery, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	prefix := storage.WebhookDeliveryKeyPrefix
+	if webhookID != "" {
+		prefix = s.deliveryPrefix(webhookID)
+	}
+	resp, err := s.client.client.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*models.WebhookDelivery, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var d models.WebhookDelivery
+		if err := json.Unmarshal(kv.Value, &d); err != nil {
+			continue
+		}
+		d.ModRevision = kv.ModRevision
+		deliveries = append(deliveries, &d)
+	}
+
+	return deliveries, nil
+}
+
+// ListPendingDeliveries 按时间顺序列出所有订阅等待投递的记录，只读取索引中的记录
+func (s *WebhookStorage) ListPendingDeliveries(ctx context.Context) ([]*models.WebhookDelivery, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.WebhookPendingKeyPrefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+
+	ops := make([]clientv3.Op, len(resp.Kvs))
+	for i, kv := range resp.Kvs {
+		ops[i] = clientv3.OpGet(storage.WebhookDeliveryKeyPrefix + strings.TrimPrefix(string(kv.Key), storage.WebhookPendingKeyPrefix))
+	}
+
+	deliveries := make([]*models.WebhookDelivery, 0, len(ops))
+	maxOps := s.config.Etcd.MaxTxnOps
+	for start := 0; start < len(ops); start += maxOps {
+		end := min(start+maxOps, len(ops))
+		txn, err := s.client.client.Txn(ctx).Then(ops[start:end]...).Commit()
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range txn.Responses {
+			for _, kv := range r.GetResponseRange().Kvs {
+				var d models.WebhookDelivery
+				if err := json.Unmarshal(kv.Value, &d); err != nil || d.Status != models.DeliveryStatusPending {
+					continue
+				}
+				d.ModRevision = kv.ModRevision
+				deliveries = append(deliveries, &d)
+			}
+		}
+	}
+
+	return deliveries, nil
+}
+
+// IndexPendingDeliveries 为还没有索引的等待投递记录（升级前写入）补写索引，投递器启动时调用
+func (s *WebhookStorage) IndexPendingDeliveries(ctx context.Context) error {
+	deliveries, err := s.ListDeliveries(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	var ops []clientv3.Op
+	for _, d := range deliveries {
+		if d.Status == models.DeliveryStatusPending {
+			ops = append(ops, clientv3.OpPut(s.pendingKey(d.WebhookID, d.ID), ""))
+		}
+	}
+	return s.commitChunked(ctx, ops)
+}
+
+// GetDelivery 获取投递记录
+func (s *WebhookStorage) GetDelivery(ctx context.Context, webhookID, id string) (*models.WebhookDelivery, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, s.deliveryKey(webhookID, id))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, errors.ErrDeliveryNotFound
+	}
+
+	var d models.WebhookDelivery
+	if err := json.Unmarshal(resp.Kvs[0].Value, &d); err != nil {
+		return nil, err
+	}
+	d.ModRevision = resp.Kvs[0].ModRevision
+
+	return &d, nil
+}
+
+// UpdateDelivery 更新投递记录
+// 仅当 etcd 中的记录自读取后未被修改（且未被删除）时才写入，否则返回 ErrDeliveryConflict
+func (s *WebhookStorage) UpdateDelivery(ctx context.Context, d *models.WebhookDelivery) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook delivery: %w", err)
+	}
+
+	key := s.deliveryKey(d.WebhookID, d.ID)
+	resp, err := s.client.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", d.ModRevision)).
+		Then(clientv3.OpPut(key, string(data)), s.pendingOp(d)).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return errors.ErrDeliveryConflict
+	}
+	d.ModRevision = resp.Header.Revision
+	return nil
+}
+
+// DeleteDeliveries 删除投递记录
+func (s *WebhookStorage) DeleteDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	ops := make([]clientv3.Op, 0, 2*len(deliveries))
+	for _, d := range deliveries {
+		ops = append(ops, clientv3.OpDelete(s.deliveryKey(d.WebhookID, d.ID)), clientv3.OpDelete(s.pendingKey(d.WebhookID, d.ID)))
+	}
+	return s.commitChunked(ctx, ops)
+}
+
+// GetCursor 获取已处理到的事件 revision，尚未处理过事件时返回 0
+func (s *WebhookStorage) GetCursor(ctx context.Context) (int64, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return 0, errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.WebhookCursorKey)
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.Kvs) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseInt(string(resp.Kvs[0].Value), 10, 64)
+}
+
+// EnqueueDeliveries 写入新的投递记录并将事件处理进度推进到 cursor
+// 记录按 etcd 单个事务的操作数上限分块写入，进度在最后一块中更新；
+// 中途失败时进度不变，重新处理同一批事件会生成相同 ID 的记录并覆盖
+func (s *WebhookStorage) EnqueueDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery, cursor int64) error {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	ops := make([]clientv3.Op, 0, 2*len(deliveries)+1)
+	for _, d := range deliveries {
+		data, err := json.Marshal(d)
+		if err != nil {
+			return fmt.Errorf("failed to marshal webhook delivery: %w", err)
+		}
+		ops = append(ops, clientv3.OpPut(s.deliveryKey(d.WebhookID, d.ID), string(data)), s.pendingOp(d))
+	}
+	ops = append(ops, clientv3.OpPut(storage.WebhookCursorKey, strconv.FormatInt(cursor, 10)))
+	return s.commitChunked(ctx, ops)
+}
+
+// commitChunked 按 etcd 单个事务的操作数上限分块提交
+func (s *WebhookStorage) commitChunked(ctx context.Context, ops []clientv3.Op) error {
+	maxOps := s.config.Etcd.MaxTxnOps
+	for start := 0; start < len(ops); start += maxOps {
+		end := min(start+maxOps, len(ops))
+		if _, err := s.client.client.Txn(ctx).Then(ops[start:end]...).Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliveryKey 生成投递记录的 etcd key
+func (s *WebhookStorage) deliveryKey(webhookID, id string) string {
+	return s.deliveryPrefix(webhookID) + id
+}
+
+// pendingKey 生成等待投递索引的 etcd key
+func (s *WebhookStorage) pendingKey(webhookID, id string) string {
+	return storage.WebhookPendingKeyPrefix + webhookID + "/" + id
+}
+
+// pendingOp 生成按投递状态写入或删除等待投递索引的 etcd 操作
+func (s *WebhookStorage) pendingOp(d *models.WebhookDelivery) clientv3.Op {
+	if d.Status == models.DeliveryStatusPending {
+		return clientv3.OpPut(s.pendingKey(d.WebhookID, d.ID), "")
+	}
+	return clientv3.OpDelete(s.pendingKey(d.WebhookID, d.ID))
+}
+
+// deliveryPrefix 生成订阅下投递记录前缀
+func (s *WebhookStorage) deliveryPrefix(webhookID string) string {
+	return storage.WebhookDeliveryKeyPrefix + webhookID + "/"
+}
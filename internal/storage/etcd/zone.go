@@ -3,14 +3,28 @@ package etcd
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
+	"strings"
 
 	"dancer/internal/errors"
 	"dancer/internal/models"
 	"dancer/internal/storage"
+	"dancer/internal/tenant"
+	"go.etcd.io/etcd/api/v3/mvccpb"
 	"go.etcd.io/etcd/client/v3"
 )
 
-// ZoneStorage Zone 存储操作
+// maxReconcileAttempts 校正单个 Zone 的记录数量时，Zone 被并发修改的最大重试次数
+const maxReconcileAttempts = 3
+
+// maxIncrementAttempts 增减 Zone 的记录数量时，Zone 被并发修改的最大重试次数
+const maxIncrementAttempts = 16
+
+// errZoneModified Zone 自读取后已被修改
+var errZoneModified = stderrors.New("zone was modified concurrently")
+
+// ZoneStorage Zone 存储操作，读写 ctx 所属租户的 Zone
+// Zone 名称在所有租户中唯一，创建时在同一事务中登记 Zone 所属租户
 type ZoneStorage struct {
 	client *Client
 }
@@ -19,19 +33,28 @@ func NewZoneStorage(client *Client) *ZoneStorage {
 	return &ZoneStorage{client: client}
 }
 
-// ListZones 列出所有 Zone
+// ListZones 列出 ctx 所属租户的所有 Zone
 func (s *ZoneStorage) ListZones(ctx context.Context) ([]*models.Zone, error) {
+	return s.listZones(ctx, storage.ZonePrefix(tenant.FromContext(ctx)))
+}
+
+// ListAllZones 列出所有租户的 Zone，供统计使用
+func (s *ZoneStorage) ListAllZones(ctx context.Context) ([]*models.Zone, error) {
+	return s.listZones(ctx, storage.ZoneKeyPrefix)
+}
+
+func (s *ZoneStorage) listZones(ctx context.Context, prefix string) ([]*models.Zone, error) {
 	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
 		return nil, errors.ErrEtcdUnavailable
 	}
 
-	resp, err := s.client.client.Get(ctx, storage.ZoneKeyPrefix, clientv3.WithPrefix())
+	kvs, err := s.client.listKVs(ctx, prefix)
 	if err != nil {
 		return nil, err
 	}
 
-	zones := make([]*models.Zone, 0, len(resp.Kvs))
-	for _, kv := range resp.Kvs {
+	zones := make([]*models.Zone, 0, len(kvs))
+	for _, kv := range kvs {
 		var zone models.Zone
 		if err := json.Unmarshal(kv.Value, &zone); err != nil {
 			continue
@@ -42,44 +65,70 @@ func (s *ZoneStorage) ListZones(ctx context.Context) ([]*models.Zone, error) {
 	return zones, nil
 }
 
+// ListZonesPage 分页列出 Zone
+func (s *ZoneStorage) ListZonesPage(ctx context.Context, q *models.ListQuery[*models.Zone]) (*models.Page[*models.Zone], error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return nil, errors.ErrEtcdUnavailable
+	}
+
+	return listPage(ctx, s.client, storage.ZonePrefix(tenant.FromContext(ctx)), q, func(kv *mvccpb.KeyValue) (*models.Zone, bool) {
+		var zone models.Zone
+		if err := json.Unmarshal(kv.Value, &zone); err != nil {
+			return nil, false
+		}
+		return &zone, true
+	})
+}
+
 // GetZone 获取 Zone 详情
 func (s *ZoneStorage) GetZone(ctx context.Context, zone string) (*models.Zone, error) {
 	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
 		return nil, errors.ErrEtcdUnavailable
 	}
 
-	key := s.zoneKey(zone)
-	resp, err := s.client.client.Get(ctx, key)
+	kv, err := s.client.getKV(ctx, zoneKey(ctx, zone))
 	if err != nil {
 		return nil, err
 	}
 
-	if len(resp.Kvs) == 0 {
+	if kv == nil {
 		return nil, errors.ErrZoneNotFound
 	}
 
 	var z models.Zone
-	if err := json.Unmarshal(resp.Kvs[0].Value, &z); err != nil {
+	if err := json.Unmarshal(kv.Value, &z); err != nil {
 		return nil, err
 	}
 
 	return &z, nil
 }
 
-// CreateZone 创建 Zone
+// CreateZone 创建 Zone，Zone 名称已被任一租户使用时返回 ErrZoneExists
 func (s *ZoneStorage) CreateZone(ctx context.Context, zone *models.Zone) error {
 	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
 		return errors.ErrEtcdUnavailable
 	}
 
-	key := s.zoneKey(zone.Zone)
 	data, err := json.Marshal(zone)
 	if err != nil {
 		return err
 	}
 
-	_, err = s.client.client.Put(ctx, key, string(data))
-	return err
+	ownerKey := storage.ZoneOwnerKeyPrefix + zone.Zone
+	resp, err := s.client.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(ownerKey), "=", 0)).
+		Then(
+			clientv3.OpPut(ownerKey, tenant.FromContext(ctx)),
+			clientv3.OpPut(zoneKey(ctx, zone.Zone), string(data)),
+		).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return errors.ErrZoneExists
+	}
+	return nil
 }
 
 // UpdateZone 更新 Zone
@@ -88,7 +137,7 @@ func (s *ZoneStorage) UpdateZone(ctx context.Context, zone *models.Zone) error {
 		return errors.ErrEtcdUnavailable
 	}
 
-	key := s.zoneKey(zone.Zone)
+	key := zoneKey(ctx, zone.Zone)
 	data, err := json.Marshal(zone)
 	if err != nil {
 		return err
@@ -98,32 +147,46 @@ func (s *ZoneStorage) UpdateZone(ctx context.Context, zone *models.Zone) error {
 	return err
 }
 
-// DeleteZone 删除 Zone（级联删除该 Zone 下所有 Domain）
-func (s *ZoneStorage) DeleteZone(ctx context.Context, zone string) error {
-	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
-		return errors.ErrEtcdUnavailable
-	}
-
-	// 删除 Zone 本身
-	key := s.zoneKey(zone)
-	_, err := s.client.client.Delete(ctx, key)
+// claimZone 将 Zone 登记为 ctx 所属租户所有，已属于该租户时不做修改，属于其他租户时返回 ErrZoneExists
+func claimZone(ctx context.Context, client *Client, zone string) error {
+	ownerKey := storage.ZoneOwnerKeyPrefix + zone
+	resp, err := client.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(ownerKey), "=", 0)).
+		Then(clientv3.OpPut(ownerKey, tenant.FromContext(ctx))).
+		Else(clientv3.OpGet(ownerKey)).
+		Commit()
 	if err != nil {
 		return err
 	}
-
-	// 级联删除该 Zone 下的所有 Domain
-	domainPrefix := storage.DomainKeyPrefix + zone + "/"
-	_, err = s.client.client.Delete(ctx, domainPrefix, clientv3.WithPrefix())
-	if err != nil {
-		return err
+	if resp.Succeeded {
+		return nil
+	}
+	kvs := resp.Responses[0].GetResponseRange().Kvs
+	if len(kvs) > 0 && string(kvs[0].Value) != tenant.FromContext(ctx) {
+		return errors.ErrZoneExists
 	}
-
 	return nil
 }
 
-// zoneKey 生成 Zone 的 etcd key
-func (s *ZoneStorage) zoneKey(zone string) string {
-	return storage.ZoneKeyPrefix + zone
+// zoneKey 生成 ctx 所属租户下 Zone 的 etcd key
+func zoneKey(ctx context.Context, zone string) string {
+	return storage.ZonePrefix(tenant.FromContext(ctx)) + zone
+}
+
+// GetZoneOwner 获取 Zone 所属的租户，Zone 不存在时返回空字符串
+func (s *ZoneStorage) GetZoneOwner(ctx context.Context, zone string) (string, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return "", errors.ErrEtcdUnavailable
+	}
+
+	resp, err := s.client.client.Get(ctx, storage.ZoneOwnerKeyPrefix+zone)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Kvs) == 0 {
+		return "", nil
+	}
+	return string(resp.Kvs[0].Value), nil
 }
 
 // ZoneExists 检查 Zone 是否存在
@@ -149,11 +212,132 @@ func (s *ZoneStorage) UpdateZoneRecordCount(ctx context.Context, zone string, co
 }
 
 // IncrementZoneRecordCount 增加 Zone 的记录数量
+// 仅当 Zone 自读取后未被修改时写入，被并发修改时重新读取后重试，避免并发的增减互相覆盖
 func (s *ZoneStorage) IncrementZoneRecordCount(ctx context.Context, zone string, delta int) error {
-	z, err := s.GetZone(ctx, zone)
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return errors.ErrEtcdUnavailable
+	}
+
+	key := zoneKey(ctx, zone)
+	for attempt := 0; attempt < maxIncrementAttempts; attempt++ {
+		resp, err := s.client.client.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if len(resp.Kvs) == 0 {
+			return errors.ErrZoneNotFound
+		}
+
+		var z models.Zone
+		if err := json.Unmarshal(resp.Kvs[0].Value, &z); err != nil {
+			return err
+		}
+		if _, err := s.setRecordCount(ctx, resp.Kvs[0], z.RecordCount+delta); err != errZoneModified {
+			return err
+		}
+	}
+	return errZoneModified
+}
+
+// ReconcileRecordCounts 按 Domain key 重新统计所有租户 Zone 的记录数量，与保存的值不同时更新，返回更新的 Zone 数
+// 临时 Domain 的租约到期时 key 由 etcd 直接删除，不经过服务层，记录数量需要按 key 校正；直接读取 etcd，不经过读缓存
+func (s *ZoneStorage) ReconcileRecordCounts(ctx context.Context) (int, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return 0, errors.ErrEtcdUnavailable
+	}
+
+	// 在同一个 revision 下读取 Zone 与 Domain key
+	resp, err := s.client.client.Txn(ctx).Then(
+		clientv3.OpGet(storage.ZoneKeyPrefix, clientv3.WithPrefix()),
+		clientv3.OpGet(storage.DomainKeyPrefix, clientv3.WithPrefix(), clientv3.WithKeysOnly()),
+	).Commit()
 	if err != nil {
-		return err
+		return 0, err
 	}
-	z.RecordCount += delta
-	return s.UpdateZone(ctx, z)
+
+	counts := make(map[string]int) // {tenant}/{zone} -> Domain 数量
+	for _, kv := range resp.Responses[1].GetResponseRange().Kvs {
+		t, name, ok := storage.SplitDomainKey(string(kv.Key))
+		if !ok {
+			continue
+		}
+		zone, _, _ := strings.Cut(name, "/")
+		counts[tenant.Of(t)+"/"+zone]++
+	}
+
+	updated := 0
+	for _, kv := range resp.Responses[0].GetResponseRange().Kvs {
+		t, zone, ok := storage.SplitZoneKey(string(kv.Key))
+		if !ok {
+			continue
+		}
+		// Zone 在读取后被修改时跳过，由下一次校正处理
+		changed, err := s.setRecordCount(ctx, kv, counts[tenant.Of(t)+"/"+zone])
+		if err != nil && err != errZoneModified {
+			return updated, err
+		}
+		if changed {
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+// ReconcileRecordCount 按 Domain key 重新统计 ctx 所属租户的 Zone 的记录数量，与保存的值不同时更新
+// 返回是否更新；Zone 不存在时不做任何事，Zone 在统计期间被修改时重新统计
+func (s *ZoneStorage) ReconcileRecordCount(ctx context.Context, zone string) (bool, error) {
+	if err := s.client.WaitForConnection(defaultWaitTimeout); err != nil {
+		return false, errors.ErrEtcdUnavailable
+	}
+
+	for attempt := 0; attempt < maxReconcileAttempts; attempt++ {
+		resp, err := s.client.client.Txn(ctx).Then(
+			clientv3.OpGet(zoneKey(ctx, zone)),
+			clientv3.OpGet(storage.DomainPrefix(tenant.FromContext(ctx))+zone+"/", clientv3.WithPrefix(), clientv3.WithCountOnly()),
+		).Commit()
+		if err != nil {
+			return false, err
+		}
+
+		kvs := resp.Responses[0].GetResponseRange().Kvs
+		if len(kvs) == 0 {
+			return false, nil
+		}
+		changed, err := s.setRecordCount(ctx, kvs[0], int(resp.Responses[1].GetResponseRange().Count))
+		if err != errZoneModified {
+			return changed, err
+		}
+	}
+	return false, errZoneModified
+}
+
+// setRecordCount 将 Zone 的记录数量设置为 count，返回是否更新
+// 仅当 Zone 自读取后未被修改时写入，否则返回 errZoneModified
+func (s *ZoneStorage) setRecordCount(ctx context.Context, kv *mvccpb.KeyValue, count int) (bool, error) {
+	var zone models.Zone
+	if err := json.Unmarshal(kv.Value, &zone); err != nil {
+		return false, nil
+	}
+	if zone.RecordCount == count {
+		return false, nil
+	}
+
+	zone.RecordCount = count
+	data, err := json.Marshal(&zone)
+	if err != nil {
+		return false, err
+	}
+
+	key := string(kv.Key)
+	resp, err := s.client.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", kv.ModRevision)).
+		Then(clientv3.OpPut(key, string(data))).
+		Commit()
+	if err != nil {
+		return false, err
+	}
+	if !resp.Succeeded {
+		return false, errZoneModified
+	}
+	return true, nil
 }
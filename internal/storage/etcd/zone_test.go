@@ -0,0 +1,51 @@
+package etcd_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"dancer/internal/logger"
+	"dancer/internal/models"
+	"dancer/internal/storage/etcd"
+	"dancer/internal/storage/etcd/etcdtest"
+	"github.com/sirupsen/logrus"
+)
+
+// TestIncrementZoneRecordCountConcurrent 并发增减 Zone 的记录数量时不丢失任何一次更新
+func TestIncrementZoneRecordCountConcurrent(t *testing.T) {
+	logger.Log = logrus.New()
+	client, _ := etcdtest.Start(t)
+	s := etcd.NewZoneStorage(client)
+	ctx := context.Background()
+
+	if err := s.CreateZone(ctx, &models.Zone{Zone: "example.com"}); err != nil {
+		t.Fatalf("CreateZone: %v", err)
+	}
+
+	const workers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- s.IncrementZoneRecordCount(ctx, "example.com", 2)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("IncrementZoneRecordCount: %v", err)
+		}
+	}
+
+	zone, err := s.GetZone(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("GetZone: %v", err)
+	}
+	if zone.RecordCount != 2*workers {
+		t.Errorf("record_count = %d, want %d", zone.RecordCount, 2*workers)
+	}
+}
@@ -1,7 +1,89 @@
 package storage
 
+import "strings"
+
 const (
+	RootKeyPrefix = "/dancer/" // 所有 Dancer 数据的公共前缀
+
+	TenantKeyPrefix    = "/dancer/tenants/"     // 租户前缀
+	ZoneOwnerKeyPrefix = "/dancer/zone_owners/" // Zone 所属租户，Zone 名称在所有租户中唯一
+
+	// 用户、Zone 与 Domain 按租户分组: {prefix}{tenant}/...
 	UserKeyPrefix   = "/dancer/users/"   // 用户数据前缀
 	ZoneKeyPrefix   = "/dancer/zones/"   // Zone (二级域名) 前缀
 	DomainKeyPrefix = "/dancer/domains/" // Domain (完整域名) 前缀
+
+	APITokenKeyPrefix     = "/dancer/tokens/"       // API Token 前缀
+	APITokenHashKeyPrefix = "/dancer/token_hashes/" // API Token 摘要索引: {prefix}{token_hash} → Token ID
+	InstanceKeyPrefix     = "/dancer/instances/"    // 动态注册实例前缀
+
+	ScheduleKeyPrefix = "/dancer/schedules/" // 定时变更前缀
+	ChangeKeyPrefix   = "/dancer/changes/"   // 变更申请前缀
+	ElectionKeyPrefix = "/dancer/election/"  // leader 选举前缀
+
+	WebhookKeyPrefix         = "/dancer/webhooks/"           // Webhook 订阅前缀
+	WebhookDeliveryKeyPrefix = "/dancer/webhook_deliveries/" // Webhook 投递记录前缀，按订阅分组
+	WebhookPendingKeyPrefix  = "/dancer/webhook_pending/"    // 等待投递的投递记录索引，与投递记录 key 结构相同
+	WebhookCursorKey         = "/dancer/webhook_cursor"      // Webhook 已处理到的事件 revision
+
+	DomainHistoryKeyPrefix = "/dancer/history/domains/" // Domain 历史版本快照前缀
+	HistoryCursorKey       = "/dancer/history_cursor"   // 历史版本记录器已处理到的事件 revision
+
+	TrashKeyPrefix       = "/dancer/trash/"         // 回收站条目前缀
+	TrashRecordKeyPrefix = "/dancer/trash_records/" // 回收站中 Zone 条目的 Domain 记录前缀，按条目分组
+
+	VerificationKeyPrefix = "/dancer/verifications/" // DNS 校验结果前缀，每个 Domain 保留最近一次结果
+
+	PublishedKeyPrefix = "/dancer/published/" // 已发布到外部发布目标的域名，按发布目标与 Zone 分组
 )
+
+// UserPrefix 租户的用户前缀
+func UserPrefix(tenant string) string {
+	return UserKeyPrefix + tenant + "/"
+}
+
+// ZonePrefix 租户的 Zone 前缀
+func ZonePrefix(tenant string) string {
+	return ZoneKeyPrefix + tenant + "/"
+}
+
+// DomainPrefix 租户的 Domain 前缀
+func DomainPrefix(tenant string) string {
+	return DomainKeyPrefix + tenant + "/"
+}
+
+// SplitTenantKey 将 {prefix}{tenant}/{rest} 形式的 key 拆分为租户与其余部分
+func SplitTenantKey(key, prefix string) (tenant, rest string, ok bool) {
+	if !strings.HasPrefix(key, prefix) {
+		return "", "", false
+	}
+	return strings.Cut(strings.TrimPrefix(key, prefix), "/")
+}
+
+// SplitZoneKey 将 Zone key 拆分为租户与 Zone 名称；迁移前不含租户的 key 返回空租户
+func SplitZoneKey(key string) (tenant, zone string, ok bool) {
+	if !strings.HasPrefix(key, ZoneKeyPrefix) {
+		return "", "", false
+	}
+	rest := strings.TrimPrefix(key, ZoneKeyPrefix)
+	if t, zone, found := strings.Cut(rest, "/"); found {
+		return t, zone, true
+	}
+	return "", rest, true
+}
+
+// SplitDomainKey 将 Domain key 拆分为租户与 {zone}/{domain}；迁移前不含租户的 key 返回空租户
+func SplitDomainKey(key string) (tenant, name string, ok bool) {
+	if !strings.HasPrefix(key, DomainKeyPrefix) {
+		return "", "", false
+	}
+	rest := strings.TrimPrefix(key, DomainKeyPrefix)
+	switch strings.Count(rest, "/") {
+	case 1:
+		return "", rest, true
+	case 2:
+		t, name, _ := strings.Cut(rest, "/")
+		return t, name, true
+	}
+	return "", "", false
+}
@@ -0,0 +1,29 @@
+package tenant
+
+import "context"
+
+// Default 默认租户，升级前的用户、Zone 与 Domain 都迁移到该租户，超级管理员也属于该租户
+const Default = "default"
+
+type contextKey struct{}
+
+// WithTenant 返回属于租户 id 的 ctx，存储层按 ctx 中的租户读写数据
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, Of(id))
+}
+
+// FromContext 返回 ctx 所属的租户，未设置时为默认租户
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok {
+		return id
+	}
+	return Default
+}
+
+// Of 返回记录所属的租户；升级前创建的记录及未带租户的 JWT 没有租户字段，属于默认租户
+func Of(id string) string {
+	if id == "" {
+		return Default
+	}
+	return id
+}
@@ -0,0 +1,100 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"dancer/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName 本项目 span 的 instrumentation 名称
+const tracerName = "dancer"
+
+// 导出方式
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Init 按配置初始化全局 TracerProvider，并启用 W3C trace-context 传播
+// 返回的函数在退出时调用，导出尚未发送的 span；exporter 为 none 时不创建 TracerProvider，span 不会被记录
+func Init(cfg *config.Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	exporter, err := newExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	tp := NewProvider(cfg, exporter)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// NewProvider 创建导出到 exporter 的 TracerProvider
+// 测试时可传入 tracetest.NewInMemoryExporter()，通过 otel.SetTracerProvider 安装，ForceFlush 后读取生成的 span
+func NewProvider(cfg *config.Config, exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(newSampler(cfg)),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(cfg.Tracing.ServiceName))),
+	)
+}
+
+// newSampler 按配置的采样率采样本服务发起的链路，未配置时全部采样；请求携带上游 span 时沿用上游的采样决定
+func newSampler(cfg *config.Config) sdktrace.Sampler {
+	ratio := 1.0
+	if cfg.Tracing.SampleRatio != nil {
+		ratio = *cfg.Tracing.SampleRatio
+	}
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))
+}
+
+// newExporter 按配置创建 exporter，none 返回 nil
+func newExporter(cfg *config.Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Tracing.Exporter {
+	case ExporterNone:
+		return nil, nil
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.Tracing.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Tracing.Endpoint))
+		}
+		if cfg.Tracing.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		// 只创建客户端，不连接接收端；接收端不可用时 span 在导出时丢弃
+		return otlptracehttp.New(context.Background(), opts...)
+	}
+	return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Tracing.Exporter)
+}
+
+// Start 在 ctx 下开始一个 span，调用方负责调用 span.End()
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// RecordError 记录错误并将 span 标记为失败，err 为 nil 时不做任何事
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"dancer/internal/config"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestSampleRatio 采样率为 0 时不记录本服务发起的链路，但沿用上游的采样决定；未配置时全部采样
+func TestSampleRatio(t *testing.T) {
+	zero, one := 0.0, 1.0
+	sampledParent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+
+	tests := []struct {
+		name   string
+		ratio  *float64
+		parent trace.SpanContext
+		want   bool
+	}{
+		{"unset", nil, trace.SpanContext{}, true},
+		{"one", &one, trace.SpanContext{}, true},
+		{"zero", &zero, trace.SpanContext{}, false},
+		{"zero with sampled parent", &zero, sampledParent, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Tracing.SampleRatio = tt.ratio
+
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(newSampler(cfg)), sdktrace.WithSpanProcessor(recorder))
+
+			ctx := trace.ContextWithRemoteSpanContext(context.Background(), tt.parent)
+			_, span := tp.Tracer(tracerName).Start(ctx, "test")
+			span.End()
+
+			if got := len(recorder.Ended()) == 1; got != tt.want {
+				t.Errorf("sampled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNewProvider 通过 NewProvider 创建的 TracerProvider 将 span 导出到传入的 exporter
+func TestNewProvider(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Tracing.ServiceName = "dancer-test"
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := NewProvider(cfg, exporter)
+
+	_, span := tp.Tracer(tracerName).Start(context.Background(), "test")
+	span.End()
+	if err := tp.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "test" {
+		t.Fatalf("exported %d spans, want the test span", len(spans))
+	}
+	if name, _ := spans[0].Resource.Set().Value("service.name"); name.AsString() != "dancer-test" {
+		t.Errorf("service.name = %q, want dancer-test", name.AsString())
+	}
+}
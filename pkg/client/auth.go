@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// Health 健康检查，服务不健康（503）时同样返回检查结果
+func (c *Client) Health(ctx context.Context) (*HealthStatus, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/health", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var status HealthStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Login 登录，成功后客户端使用返回的 Token
+func (c *Client) Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
+	var resp LoginResponse
+	if err := c.post(ctx, "/api/auth/login", req, &resp); err != nil {
+		return nil, err
+	}
+	c.Token = resp.Token
+	return &resp, nil
+}
+
+// RefreshToken 刷新 Token，成功后客户端使用新的 Token
+func (c *Client) RefreshToken(ctx context.Context) (*LoginResponse, error) {
+	var resp LoginResponse
+	if err := c.postData(ctx, "/api/auth/refresh", nil, &resp); err != nil {
+		return nil, err
+	}
+	c.Token = resp.Token
+	return &resp, nil
+}
+
+// Me 获取当前用户信息
+func (c *Client) Me(ctx context.Context) (*UserDTO, error) {
+	var user UserDTO
+	if err := c.post(ctx, "/api/me", nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ChangePassword 修改当前用户密码
+func (c *Client) ChangePassword(ctx context.Context, req *ChangePasswordRequest) error {
+	return c.post(ctx, "/api/me/change-password", req, nil)
+}
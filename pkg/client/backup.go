@@ -0,0 +1,84 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+)
+
+// ExportBackup 导出全量备份，返回备份文件内容（Admin）
+func (c *Client) ExportBackup(ctx context.Context, req *ExportBackupRequest) ([]byte, error) {
+	resp, err := c.send(ctx, http.MethodPost, "/api/backup/export", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// RestoreBackup 从备份文件恢复（Admin）
+func (c *Client) RestoreBackup(ctx context.Context, archive []byte, req *RestoreBackupRequest) (*RestoreResult, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	fields := map[string]string{
+		"mode":       string(req.Mode),
+		"dry_run":    strconv.FormatBool(req.DryRun),
+		"passphrase": req.Passphrase,
+	}
+	for name, value := range fields {
+		if err := w.WriteField(name, value); err != nil {
+			return nil, err
+		}
+	}
+	part, err := w.CreateFormFile("archive", "backup")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(archive); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	httpReq, err := c.newRequest(ctx, http.MethodPost, "/api/backup/restore", &body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", w.FormDataContentType())
+
+	resp, err := c.do(c.HTTPClient, httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result RestoreResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SyncPlan 生成声明式同步计划（Admin）
+func (c *Client) SyncPlan(ctx context.Context, req *SyncRequest) (*SyncPlan, error) {
+	return c.syncPlan(ctx, "/api/sync/plan", req)
+}
+
+// SyncApply 生成并应用声明式同步计划（Admin）
+func (c *Client) SyncApply(ctx context.Context, req *SyncRequest) (*SyncPlan, error) {
+	return c.syncPlan(ctx, "/api/sync/apply", req)
+}
+
+// syncPlan 调用返回同步计划的接口
+func (c *Client) syncPlan(ctx context.Context, path string, req *SyncRequest) (*SyncPlan, error) {
+	var plan SyncPlan
+	if err := c.post(ctx, path, req, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
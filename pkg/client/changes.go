@@ -0,0 +1,74 @@
+package client
+
+import "context"
+
+// ListSchedules 列出定时变更
+func (c *Client) ListSchedules(ctx context.Context, req *ListSchedulesRequest) (*ScheduleListDTO, error) {
+	var list ScheduleListDTO
+	if err := c.post(ctx, "/api/dns/schedules/list", req, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetSchedule 获取定时变更详情
+func (c *Client) GetSchedule(ctx context.Context, req *GetScheduleRequest) (*ScheduleDTO, error) {
+	return c.schedule(ctx, "/api/dns/schedules/get", req)
+}
+
+// CreateSchedule 创建定时变更
+func (c *Client) CreateSchedule(ctx context.Context, req *CreateScheduleRequest) (*ScheduleDTO, error) {
+	return c.schedule(ctx, "/api/dns/schedules/create", req)
+}
+
+// CancelSchedule 取消定时变更
+func (c *Client) CancelSchedule(ctx context.Context, req *CancelScheduleRequest) (*ScheduleDTO, error) {
+	return c.schedule(ctx, "/api/dns/schedules/cancel", req)
+}
+
+// ListChangeRequests 列出变更申请
+func (c *Client) ListChangeRequests(ctx context.Context, req *ListChangeRequestsRequest) (*ChangeRequestListDTO, error) {
+	var list ChangeRequestListDTO
+	if err := c.post(ctx, "/api/dns/changes/list", req, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetChangeRequest 获取变更申请详情
+func (c *Client) GetChangeRequest(ctx context.Context, req *GetChangeRequestRequest) (*ChangeRequestDTO, error) {
+	return c.changeRequest(ctx, "/api/dns/changes/get", req)
+}
+
+// ProposeChangeRequest 提交变更申请
+func (c *Client) ProposeChangeRequest(ctx context.Context, req *ProposeChangeRequest) (*ChangeRequestDTO, error) {
+	return c.changeRequest(ctx, "/api/dns/changes/propose", req)
+}
+
+// ApproveChangeRequest 批准并应用变更申请（Admin）
+func (c *Client) ApproveChangeRequest(ctx context.Context, req *ReviewChangeRequest) (*ChangeRequestDTO, error) {
+	return c.changeRequest(ctx, "/api/dns/changes/approve", req)
+}
+
+// RejectChangeRequest 拒绝变更申请（Admin）
+func (c *Client) RejectChangeRequest(ctx context.Context, req *ReviewChangeRequest) (*ChangeRequestDTO, error) {
+	return c.changeRequest(ctx, "/api/dns/changes/reject", req)
+}
+
+// schedule 调用返回定时变更的接口
+func (c *Client) schedule(ctx context.Context, path string, req interface{}) (*ScheduleDTO, error) {
+	var sc ScheduleDTO
+	if err := c.post(ctx, path, req, &sc); err != nil {
+		return nil, err
+	}
+	return &sc, nil
+}
+
+// changeRequest 调用返回变更申请的接口
+func (c *Client) changeRequest(ctx context.Context, path string, req interface{}) (*ChangeRequestDTO, error) {
+	var cr ChangeRequestDTO
+	if err := c.post(ctx, path, req, &cr); err != nil {
+		return nil, err
+	}
+	return &cr, nil
+}
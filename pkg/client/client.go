@@ -0,0 +1,150 @@
+// Package client Dancer API 的 Go 客户端
+// 每个方法对应一个 HTTP 接口，请求与响应类型见 types.go
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultTimeout 默认请求超时，不适用于事件流
+const defaultTimeout = 30 * time.Second
+
+// Client Dancer API 客户端
+// Token 为登录获得的 JWT，服务注册接口使用 API Token；Login 成功后自动设置
+type Client struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// New 创建客户端，baseURL 为服务地址，如 http://localhost:8080
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// APIError 服务端返回的错误响应
+type APIError struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %s (HTTP %d)", e.Code, e.Message, e.StatusCode)
+}
+
+// IsNotFound 是否为资源不存在错误
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsUnauthorized 是否为未认证或 Token 无效错误
+func IsUnauthorized(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized
+}
+
+// post 以 JSON 调用接口，响应解析到 out；out 为 nil 时丢弃响应
+func (c *Client) post(ctx context.Context, path string, in, out interface{}) error {
+	return c.call(ctx, http.MethodPost, path, in, out)
+}
+
+// call 以指定方法调用接口，in 为 nil 且不是 POST 时不发送请求体
+func (c *Client) call(ctx context.Context, method, path string, in, out interface{}) error {
+	resp, err := c.send(ctx, method, path, in)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// send 以 JSON 调用接口，返回未读取的成功响应
+func (c *Client) send(ctx context.Context, method, path string, in interface{}) (*http.Response, error) {
+	if in == nil && method == http.MethodPost {
+		in = struct{}{}
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.do(c.HTTPClient, req)
+}
+
+// postData 调用返回统一响应结构的接口，data 字段解析到 out
+func (c *Client) postData(ctx context.Context, path string, in, out interface{}) error {
+	return c.post(ctx, path, in, &Response{Data: out})
+}
+
+// newRequest 创建带认证头的请求
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	return req, nil
+}
+
+// do 发送请求，非 2xx 响应转换为 *APIError
+func (c *Client) do(httpClient *http.Client, req *http.Request) (*http.Response, error) {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	// v1 错误为 {"code", "message"}，v2 错误包装在 {"error": {...}} 中
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var wrapped struct {
+		Error *APIError `json:"error"`
+	}
+	if json.Unmarshal(data, &wrapped) == nil && wrapped.Error != nil {
+		apiErr.Code, apiErr.Message = wrapped.Error.Code, wrapped.Error.Message
+	} else {
+		_ = json.Unmarshal(data, apiErr)
+	}
+	if apiErr.Code == "" {
+		apiErr.Code = "http_error"
+		apiErr.Message = strings.TrimSpace(string(data))
+		if apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+	}
+	return nil, apiErr
+}
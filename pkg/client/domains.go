@@ -0,0 +1,137 @@
+package client
+
+import "context"
+
+// ListDomains 列出 Zone 下的 Domain
+func (c *Client) ListDomains(ctx context.Context, req *ListDomainsRequest) (*DomainListDTO, error) {
+	var list DomainListDTO
+	if err := c.post(ctx, "/api/dns/domains/list", req, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetDomain 获取 Domain 详情
+func (c *Client) GetDomain(ctx context.Context, req *GetDomainRequest) (*DomainDTO, error) {
+	return c.domain(ctx, "/api/dns/domains/get", req)
+}
+
+// CreateDomain 创建 Domain
+func (c *Client) CreateDomain(ctx context.Context, req *CreateDomainRequest) (*DomainDTO, error) {
+	return c.domain(ctx, "/api/dns/domains/create", req)
+}
+
+// UpdateDomain 更新 Domain
+func (c *Client) UpdateDomain(ctx context.Context, req *UpdateDomainRequest) (*DomainDTO, error) {
+	return c.domain(ctx, "/api/dns/domains/update", req)
+}
+
+// DeleteDomain 删除 Domain
+func (c *Client) DeleteDomain(ctx context.Context, req *DeleteDomainRequest) error {
+	return c.post(ctx, "/api/dns/domains/delete", req, nil)
+}
+
+// RenewDomain 续约临时 Domain
+func (c *Client) RenewDomain(ctx context.Context, req *RenewDomainRequest) (*DomainDTO, error) {
+	return c.domain(ctx, "/api/dns/domains/renew", req)
+}
+
+// BatchDomains 批量变更 Zone 下的 Domain
+func (c *Client) BatchDomains(ctx context.Context, req *BatchDomainsRequest) (*BatchDomainsResultDTO, error) {
+	var result BatchDomainsResultDTO
+	if err := c.post(ctx, "/api/dns/domains/batch", req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListInstances 列出 Domain 的动态实例
+func (c *Client) ListInstances(ctx context.Context, req *ListInstancesRequest) (*InstanceListDTO, error) {
+	var list InstanceListDTO
+	if err := c.post(ctx, "/api/dns/domains/instances", req, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// DomainHistory 获取 Domain 的版本历史
+func (c *Client) DomainHistory(ctx context.Context, req *DomainHistoryRequest) (*DomainHistoryDTO, error) {
+	var history DomainHistoryDTO
+	if err := c.post(ctx, "/api/dns/domains/history", req, &history); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// DiffDomainVersions 对比 Domain 的两个版本
+func (c *Client) DiffDomainVersions(ctx context.Context, req *DiffDomainVersionsRequest) (*DomainChangeDiff, error) {
+	var diff DomainChangeDiff
+	if err := c.post(ctx, "/api/dns/domains/diff", req, &diff); err != nil {
+		return nil, err
+	}
+	return &diff, nil
+}
+
+// RollbackDomain 将 Domain 恢复到历史版本，目标为删除版本时 Domain 被删除，返回 nil
+func (c *Client) RollbackDomain(ctx context.Context, req *RollbackDomainRequest) (*DomainDTO, error) {
+	var domain DomainDTO
+	if err := c.post(ctx, "/api/dns/domains/rollback", req, &domain); err != nil {
+		return nil, err
+	}
+	if domain.Domain == "" {
+		return nil, nil
+	}
+	return &domain, nil
+}
+
+// ListDomainTrash 列出回收站中的 Domain
+func (c *Client) ListDomainTrash(ctx context.Context, req *ListTrashRequest) (*TrashListDTO, error) {
+	return c.trash(ctx, "/api/dns/domains/trash", req)
+}
+
+// RestoreDomain 从回收站恢复 Domain
+func (c *Client) RestoreDomain(ctx context.Context, req *TrashEntryRequest) (*DomainDTO, error) {
+	return c.domain(ctx, "/api/dns/domains/restore", req)
+}
+
+// PurgeDomain 永久删除回收站中的 Domain（Admin）
+func (c *Client) PurgeDomain(ctx context.Context, req *TrashEntryRequest) error {
+	return c.post(ctx, "/api/dns/domains/purge", req, nil)
+}
+
+// VerifyDomain 向 DNS 服务器查询 Domain 并与记录对比
+func (c *Client) VerifyDomain(ctx context.Context, req *VerifyDomainRequest) (*DomainVerification, error) {
+	return c.domainVerification(ctx, "/api/dns/domains/verify", req)
+}
+
+// GetDomainVerification 获取 Domain 最近一次校验结果
+func (c *Client) GetDomainVerification(ctx context.Context, req *VerifyDomainRequest) (*DomainVerification, error) {
+	return c.domainVerification(ctx, "/api/dns/domains/verification", req)
+}
+
+// SearchDomains 跨 Zone 搜索 Domain
+func (c *Client) SearchDomains(ctx context.Context, req *SearchDomainsRequest) (*SearchDomainsDTO, error) {
+	var result SearchDomainsDTO
+	if err := c.post(ctx, "/api/dns/search", req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// domain 调用返回 Domain 的接口
+func (c *Client) domain(ctx context.Context, path string, req interface{}) (*DomainDTO, error) {
+	var domain DomainDTO
+	if err := c.post(ctx, path, req, &domain); err != nil {
+		return nil, err
+	}
+	return &domain, nil
+}
+
+// domainVerification 调用返回 Domain 校验结果的接口
+func (c *Client) domainVerification(ctx context.Context, path string, req *VerifyDomainRequest) (*DomainVerification, error) {
+	var result DomainVerification
+	if err := c.post(ctx, path, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// StreamEvents 订阅 Zone / Domain / 用户变更事件，每个事件调用一次 fn，直到 ctx 取消、fn 返回错误或连接断开
+// 连接正常断开时返回 nil，调用方可以用最后收到的 revision 重新订阅以续传；
+// 续传所需的历史已被压缩时返回的错误满足 IsRevisionCompacted，需重新全量读取后不带 revision 重新订阅
+func (c *Client) StreamEvents(ctx context.Context, req *StreamEventsRequest, fn func(*ChangeEventDTO) error) error {
+	query := url.Values{}
+	if req.Zone != "" {
+		query.Set("zone", req.Zone)
+	}
+	for _, typ := range req.Types {
+		query.Add("types", string(typ))
+	}
+	if req.Revision > 0 {
+		query.Set("revision", strconv.FormatInt(req.Revision, 10))
+	}
+
+	path := "/api/dns/events"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	httpReq, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	// 事件流是长连接，不使用请求超时
+	resp, err := c.do(&http.Client{Transport: c.HTTPClient.Transport}, httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var name, data string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// 空行结束一条事件
+			if data != "" {
+				if err := dispatchEvent(name, data, fn); err != nil {
+					return err
+				}
+			}
+			name, data = "", ""
+		case strings.HasPrefix(line, "event:"):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " ")
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return scanner.Err()
+}
+
+// IsRevisionCompacted 是否为续传所需的历史已被压缩错误
+func IsRevisionCompacted(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == "revision_compacted"
+}
+
+// dispatchEvent 处理一条 SSE 事件：ready 忽略，reset 转换为与订阅时相同的 *APIError，其余为变更事件
+func dispatchEvent(name, data string, fn func(*ChangeEventDTO) error) error {
+	switch name {
+	case "ready":
+		return nil
+	case "reset":
+		apiErr := &APIError{StatusCode: http.StatusGone}
+		if err := json.Unmarshal([]byte(data), apiErr); err != nil {
+			return err
+		}
+		return apiErr
+	}
+
+	var event ChangeEventDTO
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return err
+	}
+	return fn(&event)
+}
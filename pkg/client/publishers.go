@@ -0,0 +1,21 @@
+package client
+
+import "context"
+
+// ListPublishers 列出已配置的发布目标（Admin）
+func (c *Client) ListPublishers(ctx context.Context) (*PublisherListDTO, error) {
+	var list PublisherListDTO
+	if err := c.post(ctx, "/api/dns/publishers/list", nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// ListPublished 读取发布目标上 Zone 当前的地址记录（Admin）
+func (c *Client) ListPublished(ctx context.Context, req *ListPublishedRequest) (*PublishedRecords, error) {
+	var records PublishedRecords
+	if err := c.post(ctx, "/api/dns/publishers/records", req, &records); err != nil {
+		return nil, err
+	}
+	return &records, nil
+}
@@ -0,0 +1,52 @@
+package client
+
+import "context"
+
+// ListTokens 列出 API Token（Admin）
+func (c *Client) ListTokens(ctx context.Context) (*APITokenListDTO, error) {
+	var list APITokenListDTO
+	if err := c.post(ctx, "/api/tokens/list", nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// CreateToken 创建 API Token，明文 Token 仅在此返回（Admin）
+func (c *Client) CreateToken(ctx context.Context, req *CreateAPITokenRequest) (*APITokenCreatedDTO, error) {
+	var token APITokenCreatedDTO
+	if err := c.post(ctx, "/api/tokens/create", req, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// DeleteToken 删除 API Token（Admin）
+func (c *Client) DeleteToken(ctx context.Context, req *DeleteAPITokenRequest) error {
+	return c.post(ctx, "/api/tokens/delete", req, nil)
+}
+
+// 以下服务注册接口使用 API Token 认证，调用前将 Token 设置为 API Token
+
+// Register 注册服务实例
+func (c *Client) Register(ctx context.Context, req *RegisterInstanceRequest) (*InstanceDTO, error) {
+	return c.instance(ctx, "/api/registry/register", req)
+}
+
+// Heartbeat 续约服务实例
+func (c *Client) Heartbeat(ctx context.Context, req *HeartbeatInstanceRequest) (*InstanceDTO, error) {
+	return c.instance(ctx, "/api/registry/heartbeat", req)
+}
+
+// Deregister 注销服务实例
+func (c *Client) Deregister(ctx context.Context, req *DeregisterInstanceRequest) error {
+	return c.post(ctx, "/api/registry/deregister", req, nil)
+}
+
+// instance 调用返回服务实例的接口
+func (c *Client) instance(ctx context.Context, path string, req interface{}) (*InstanceDTO, error) {
+	var inst InstanceDTO
+	if err := c.post(ctx, path, req, &inst); err != nil {
+		return nil, err
+	}
+	return &inst, nil
+}
@@ -0,0 +1,35 @@
+package client
+
+import "context"
+
+// ListTenants 列出租户（Super Admin）
+func (c *Client) ListTenants(ctx context.Context) (*TenantListDTO, error) {
+	var list TenantListDTO
+	if err := c.post(ctx, "/api/tenants/list", nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetTenant 获取租户（Super Admin）
+func (c *Client) GetTenant(ctx context.Context, req *TenantRequest) (*Tenant, error) {
+	var t Tenant
+	if err := c.post(ctx, "/api/tenants/get", req, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// CreateTenant 创建租户及其第一个管理员（Super Admin）
+func (c *Client) CreateTenant(ctx context.Context, req *CreateTenantRequest) (*Tenant, error) {
+	var t Tenant
+	if err := c.post(ctx, "/api/tenants/create", req, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// DeleteTenant 删除没有 Zone 的租户及其用户（Super Admin）
+func (c *Client) DeleteTenant(ctx context.Context, req *TenantRequest) error {
+	return c.post(ctx, "/api/tenants/delete", req, nil)
+}
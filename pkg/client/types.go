@@ -0,0 +1,214 @@
+package client
+
+import "dancer/internal/models"
+
+// 请求与响应类型与服务端共用，以别名导出，模块外的程序无需引用 internal 包
+
+// 通用
+type (
+	Response    = models.Response
+	PageRequest = models.PageRequest
+	SortOrder   = models.SortOrder
+)
+
+// 认证与用户
+type (
+	LoginRequest          = models.LoginRequest
+	LoginResponse         = models.LoginResponse
+	ChangePasswordRequest = models.ChangePasswordRequest
+	UserType              = models.UserType
+	UserDTO               = models.UserDTO
+	UserListDTO           = models.UserListDTO
+	ListUsersRequest      = models.ListUsersRequest
+	CreateUserRequest     = models.CreateUserRequest
+	UpdateUserRequest     = models.UpdateUserRequest
+	DeleteUserRequest     = models.DeleteUserRequest
+)
+
+// Zone
+type (
+	ZoneDTO             = models.ZoneDTO
+	ZoneListDTO         = models.ZoneListDTO
+	ListZonesRequest    = models.ListZonesRequest
+	GetZoneRequest      = models.GetZoneRequest
+	CreateZoneRequest   = models.CreateZoneRequest
+	UpdateZoneRequest   = models.UpdateZoneRequest
+	DeleteZoneRequest   = models.DeleteZoneRequest
+	RollbackZoneRequest = models.RollbackZoneRequest
+	ZoneRollbackDTO     = models.ZoneRollbackDTO
+)
+
+// Domain
+type (
+	DomainDTO                 = models.DomainDTO
+	DomainListDTO             = models.DomainListDTO
+	ListDomainsRequest        = models.ListDomainsRequest
+	GetDomainRequest          = models.GetDomainRequest
+	CreateDomainRequest       = models.CreateDomainRequest
+	UpdateDomainRequest       = models.UpdateDomainRequest
+	DeleteDomainRequest       = models.DeleteDomainRequest
+	RenewDomainRequest        = models.RenewDomainRequest
+	BatchMode                 = models.BatchMode
+	BatchDomainsRequest       = models.BatchDomainsRequest
+	BatchDomainsResultDTO     = models.BatchDomainsResultDTO
+	BatchItemResultDTO        = models.BatchItemResultDTO
+	ChangeOp                  = models.ChangeOp
+	DomainChange              = models.DomainChange
+	DomainChangeDiff          = models.DomainChangeDiff
+	RecordSet                 = models.RecordSet
+	SearchDomainsRequest      = models.SearchDomainsRequest
+	SearchDomainsDTO          = models.SearchDomainsDTO
+	DomainHistoryRequest      = models.DomainHistoryRequest
+	DomainHistoryDTO          = models.DomainHistoryDTO
+	DomainVersionDTO          = models.DomainVersionDTO
+	DiffDomainVersionsRequest = models.DiffDomainVersionsRequest
+	RollbackDomainRequest     = models.RollbackDomainRequest
+)
+
+// 回收站
+type (
+	ListTrashRequest  = models.ListTrashRequest
+	TrashEntryRequest = models.TrashEntryRequest
+	TrashEntryDTO     = models.TrashEntryDTO
+	TrashListDTO      = models.TrashListDTO
+)
+
+// DNS 校验
+type (
+	VerifyDomainRequest = models.VerifyDomainRequest
+	VerifyZoneRequest   = models.VerifyZoneRequest
+	VerificationStatus  = models.VerificationStatus
+	VerificationAnswer  = models.VerificationAnswer
+	DomainVerification  = models.DomainVerification
+	ZoneVerification    = models.ZoneVerification
+)
+
+// 发布目标
+type (
+	PublisherInfo        = models.PublisherInfo
+	PublisherListDTO     = models.PublisherListDTO
+	ListPublishedRequest = models.ListPublishedRequest
+	PublishedRecordSet   = models.PublishedRecordSet
+	PublishedRecords     = models.PublishedRecords
+)
+
+// 变更事件
+type (
+	StreamEventsRequest = models.StreamEventsRequest
+	EventType           = models.EventType
+	EventOp             = models.EventOp
+	ChangeEventDTO      = models.ChangeEventDTO
+)
+
+// 定时变更与变更申请
+type (
+	ScheduleDTO               = models.ScheduleDTO
+	ScheduleListDTO           = models.ScheduleListDTO
+	ListSchedulesRequest      = models.ListSchedulesRequest
+	GetScheduleRequest        = models.GetScheduleRequest
+	CreateScheduleRequest     = models.CreateScheduleRequest
+	CancelScheduleRequest     = models.CancelScheduleRequest
+	ChangeRequestDTO          = models.ChangeRequestDTO
+	ChangeRequestListDTO      = models.ChangeRequestListDTO
+	ListChangeRequestsRequest = models.ListChangeRequestsRequest
+	GetChangeRequestRequest   = models.GetChangeRequestRequest
+	ProposeChangeRequest      = models.ProposeChangeRequest
+	ReviewChangeRequest       = models.ReviewChangeRequest
+)
+
+// API Token 与服务注册
+type (
+	APITokenDTO               = models.APITokenDTO
+	APITokenCreatedDTO        = models.APITokenCreatedDTO
+	APITokenListDTO           = models.APITokenListDTO
+	CreateAPITokenRequest     = models.CreateAPITokenRequest
+	DeleteAPITokenRequest     = models.DeleteAPITokenRequest
+	InstanceDTO               = models.InstanceDTO
+	InstanceListDTO           = models.InstanceListDTO
+	ListInstancesRequest      = models.ListInstancesRequest
+	RegisterInstanceRequest   = models.RegisterInstanceRequest
+	HeartbeatInstanceRequest  = models.HeartbeatInstanceRequest
+	DeregisterInstanceRequest = models.DeregisterInstanceRequest
+)
+
+// Webhook
+type (
+	WebhookDTO                   = models.WebhookDTO
+	WebhookSecretDTO             = models.WebhookSecretDTO
+	WebhookListDTO               = models.WebhookListDTO
+	WebhookDeliveryDTO           = models.WebhookDeliveryDTO
+	WebhookDeliveryListDTO       = models.WebhookDeliveryListDTO
+	GetWebhookRequest            = models.GetWebhookRequest
+	CreateWebhookRequest         = models.CreateWebhookRequest
+	UpdateWebhookRequest         = models.UpdateWebhookRequest
+	ListWebhookDeliveriesRequest = models.ListWebhookDeliveriesRequest
+	RedeliverWebhookRequest      = models.RedeliverWebhookRequest
+)
+
+// 租户
+type (
+	Tenant              = models.Tenant
+	TenantListDTO       = models.TenantListDTO
+	TenantRequest       = models.TenantRequest
+	CreateTenantRequest = models.CreateTenantRequest
+)
+
+// 备份恢复与声明式同步
+type (
+	ExportBackupRequest  = models.ExportBackupRequest
+	RestoreBackupRequest = models.RestoreBackupRequest
+	RestoreMode          = models.RestoreMode
+	RestoreResult        = models.RestoreResult
+	SyncRequest          = models.SyncRequest
+	SyncPlan             = models.SyncPlan
+	SyncStatus           = models.SyncStatus
+	ZoneSyncPlan         = models.ZoneSyncPlan
+	DesiredZone          = models.DesiredZone
+	DesiredDomain        = models.DesiredDomain
+)
+
+// v2 资源接口
+type (
+	ListMeta             = models.ListMeta
+	ZoneResource         = models.ZoneResource
+	DomainResource       = models.DomainResource
+	UserResource         = models.UserResource
+	CreateZoneResource   = models.CreateZoneResource
+	PutZoneResource      = models.PutZoneResource
+	PatchZoneResource    = models.PatchZoneResource
+	CreateDomainResource = models.CreateDomainResource
+	PutDomainResource    = models.PutDomainResource
+	PatchDomainResource  = models.PatchDomainResource
+	CreateUserResource   = models.CreateUserResource
+	PatchUserResource    = models.PatchUserResource
+)
+
+// 常用枚举值
+const (
+	SortAsc  = models.SortAsc
+	SortDesc = models.SortDesc
+
+	UserTypeSuperAdmin = models.UserTypeSuperAdmin
+	UserTypeAdmin      = models.UserTypeAdmin
+	UserTypeNormal     = models.UserTypeNormal
+
+	BatchModeAtomic     = models.BatchModeAtomic
+	BatchModeBestEffort = models.BatchModeBestEffort
+
+	ChangeOpCreate = models.ChangeOpCreate
+	ChangeOpUpdate = models.ChangeOpUpdate
+	ChangeOpDelete = models.ChangeOpDelete
+
+	RestoreModeReplace = models.RestoreModeReplace
+	RestoreModeMerge   = models.RestoreModeMerge
+
+	SyncStatusApplied  = models.SyncStatusApplied
+	SyncStatusProposed = models.SyncStatusProposed
+	SyncStatusFailed   = models.SyncStatusFailed
+)
+
+// HealthStatus 健康检查结果
+type HealthStatus struct {
+	Status     string            `json:"status"`     // up / down
+	Components map[string]string `json:"components"` // 各组件状态
+}
@@ -0,0 +1,31 @@
+package client
+
+import "context"
+
+// ListUsers 列出用户（Admin）
+func (c *Client) ListUsers(ctx context.Context, req *ListUsersRequest) (*UserListDTO, error) {
+	var list UserListDTO
+	if err := c.post(ctx, "/api/user/list", req, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// CreateUser 创建用户（Admin）
+func (c *Client) CreateUser(ctx context.Context, req *CreateUserRequest) (*UserDTO, error) {
+	var user UserDTO
+	if err := c.post(ctx, "/api/user/create", req, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateUser 更新用户（Admin）
+func (c *Client) UpdateUser(ctx context.Context, req *UpdateUserRequest) error {
+	return c.post(ctx, "/api/user/update", req, nil)
+}
+
+// DeleteUser 删除用户（Admin）
+func (c *Client) DeleteUser(ctx context.Context, req *DeleteUserRequest) error {
+	return c.post(ctx, "/api/user/delete", req, nil)
+}
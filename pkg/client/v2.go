@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// V2Client /api/v2 资源接口客户端，与 Client 共用地址与 Token
+// 列表查询参数同 v1 列表请求，以 url.Values 传递，如 name_prefix、limit、cursor
+type V2Client struct {
+	c *Client
+}
+
+// V2 返回 v2 资源接口客户端
+func (c *Client) V2() *V2Client {
+	return &V2Client{c: c}
+}
+
+// envelope v2 成功响应
+type envelope struct {
+	Data interface{} `json:"data"`
+	Meta *ListMeta   `json:"meta"`
+}
+
+// ListZones GET /zones（Admin）
+func (v *V2Client) ListZones(ctx context.Context, query url.Values) ([]*ZoneResource, *ListMeta, error) {
+	var zones []*ZoneResource
+	meta, err := v.list(ctx, "/zones", query, &zones)
+	return zones, meta, err
+}
+
+// CreateZone POST /zones（Admin）
+func (v *V2Client) CreateZone(ctx context.Context, req *CreateZoneResource) (*ZoneResource, error) {
+	return v2Call[ZoneResource](ctx, v, http.MethodPost, "/zones", req)
+}
+
+// GetZone GET /zones/{zone}（Admin）
+func (v *V2Client) GetZone(ctx context.Context, zone string) (*ZoneResource, error) {
+	return v2Call[ZoneResource](ctx, v, http.MethodGet, zonePath(zone), nil)
+}
+
+// PutZone PUT /zones/{zone}，不存在时创建（Admin）
+func (v *V2Client) PutZone(ctx context.Context, zone string, req *PutZoneResource) (*ZoneResource, error) {
+	return v2Call[ZoneResource](ctx, v, http.MethodPut, zonePath(zone), req)
+}
+
+// PatchZone PATCH /zones/{zone}（Admin）
+func (v *V2Client) PatchZone(ctx context.Context, zone string, req *PatchZoneResource) (*ZoneResource, error) {
+	return v2Call[ZoneResource](ctx, v, http.MethodPatch, zonePath(zone), req)
+}
+
+// DeleteZone DELETE /zones/{zone}，confirm 为 true 时连同 Domain 一起删除（Admin）
+func (v *V2Client) DeleteZone(ctx context.Context, zone string, confirm bool) error {
+	path := zonePath(zone)
+	if confirm {
+		path += "?confirm=" + url.QueryEscape(zone)
+	}
+	return v.call(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// ListDomains GET /zones/{zone}/domains
+func (v *V2Client) ListDomains(ctx context.Context, zone string, query url.Values) ([]*DomainResource, *ListMeta, error) {
+	var domains []*DomainResource
+	meta, err := v.list(ctx, zonePath(zone)+"/domains", query, &domains)
+	return domains, meta, err
+}
+
+// CreateDomain POST /zones/{zone}/domains
+func (v *V2Client) CreateDomain(ctx context.Context, zone string, req *CreateDomainResource) (*DomainResource, error) {
+	return v2Call[DomainResource](ctx, v, http.MethodPost, zonePath(zone)+"/domains", req)
+}
+
+// GetDomain GET /zones/{zone}/domains/{name}
+func (v *V2Client) GetDomain(ctx context.Context, zone, name string) (*DomainResource, error) {
+	return v2Call[DomainResource](ctx, v, http.MethodGet, domainPath(zone, name), nil)
+}
+
+// PutDomain PUT /zones/{zone}/domains/{name}，不存在时创建
+func (v *V2Client) PutDomain(ctx context.Context, zone, name string, req *PutDomainResource) (*DomainResource, error) {
+	return v2Call[DomainResource](ctx, v, http.MethodPut, domainPath(zone, name), req)
+}
+
+// PatchDomain PATCH /zones/{zone}/domains/{name}
+func (v *V2Client) PatchDomain(ctx context.Context, zone, name string, req *PatchDomainResource) (*DomainResource, error) {
+	return v2Call[DomainResource](ctx, v, http.MethodPatch, domainPath(zone, name), req)
+}
+
+// DeleteDomain DELETE /zones/{zone}/domains/{name}
+func (v *V2Client) DeleteDomain(ctx context.Context, zone, name string) error {
+	return v.call(ctx, http.MethodDelete, domainPath(zone, name), nil, nil)
+}
+
+// ListUsers GET /users（Admin）
+func (v *V2Client) ListUsers(ctx context.Context, query url.Values) ([]*UserResource, *ListMeta, error) {
+	var users []*UserResource
+	meta, err := v.list(ctx, "/users", query, &users)
+	return users, meta, err
+}
+
+// CreateUser POST /users（Admin）
+func (v *V2Client) CreateUser(ctx context.Context, req *CreateUserResource) (*UserResource, error) {
+	return v2Call[UserResource](ctx, v, http.MethodPost, "/users", req)
+}
+
+// GetUser GET /users/{id}（Admin）
+func (v *V2Client) GetUser(ctx context.Context, id string) (*UserResource, error) {
+	return v2Call[UserResource](ctx, v, http.MethodGet, "/users/"+url.PathEscape(id), nil)
+}
+
+// PatchUser PATCH /users/{id}（Admin）
+func (v *V2Client) PatchUser(ctx context.Context, id string, req *PatchUserResource) (*UserResource, error) {
+	return v2Call[UserResource](ctx, v, http.MethodPatch, "/users/"+url.PathEscape(id), req)
+}
+
+// DeleteUser DELETE /users/{id}（Admin）
+func (v *V2Client) DeleteUser(ctx context.Context, id string) error {
+	return v.call(ctx, http.MethodDelete, "/users/"+url.PathEscape(id), nil, nil)
+}
+
+// call 调用 v2 接口，data 解析到 out
+func (v *V2Client) call(ctx context.Context, method, path string, in, out interface{}) error {
+	var resp interface{}
+	if out != nil {
+		resp = &envelope{Data: out}
+	}
+	return v.c.call(ctx, method, "/api/v2"+path, in, resp)
+}
+
+// v2Call 调用返回单个资源的 v2 接口
+func v2Call[T any](ctx context.Context, v *V2Client, method, path string, in interface{}) (*T, error) {
+	var out T
+	if err := v.call(ctx, method, path, in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// list 调用 v2 列表接口
+func (v *V2Client) list(ctx context.Context, path string, query url.Values, out interface{}) (*ListMeta, error) {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	resp := &envelope{Data: out}
+	if err := v.c.call(ctx, http.MethodGet, "/api/v2"+path, nil, resp); err != nil {
+		return nil, err
+	}
+	return resp.Meta, nil
+}
+
+func zonePath(zone string) string {
+	return "/zones/" + url.PathEscape(zone)
+}
+
+func domainPath(zone, name string) string {
+	return zonePath(zone) + "/domains/" + url.PathEscape(name)
+}
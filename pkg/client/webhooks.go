@@ -0,0 +1,63 @@
+package client
+
+import "context"
+
+// ListWebhooks 列出 Webhook 订阅（Admin）
+func (c *Client) ListWebhooks(ctx context.Context) (*WebhookListDTO, error) {
+	var list WebhookListDTO
+	if err := c.post(ctx, "/api/webhooks/list", nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetWebhook 获取 Webhook 订阅详情（Admin）
+func (c *Client) GetWebhook(ctx context.Context, req *GetWebhookRequest) (*WebhookDTO, error) {
+	var wh WebhookDTO
+	if err := c.post(ctx, "/api/webhooks/get", req, &wh); err != nil {
+		return nil, err
+	}
+	return &wh, nil
+}
+
+// CreateWebhook 创建 Webhook 订阅，签名密钥仅在创建和重置时返回（Admin）
+func (c *Client) CreateWebhook(ctx context.Context, req *CreateWebhookRequest) (*WebhookSecretDTO, error) {
+	return c.webhookSecret(ctx, "/api/webhooks/create", req)
+}
+
+// UpdateWebhook 更新 Webhook 订阅（Admin）
+func (c *Client) UpdateWebhook(ctx context.Context, req *UpdateWebhookRequest) (*WebhookSecretDTO, error) {
+	return c.webhookSecret(ctx, "/api/webhooks/update", req)
+}
+
+// DeleteWebhook 删除 Webhook 订阅（Admin）
+func (c *Client) DeleteWebhook(ctx context.Context, req *GetWebhookRequest) error {
+	return c.post(ctx, "/api/webhooks/delete", req, nil)
+}
+
+// ListWebhookDeliveries 列出 Webhook 投递记录（Admin）
+func (c *Client) ListWebhookDeliveries(ctx context.Context, req *ListWebhookDeliveriesRequest) (*WebhookDeliveryListDTO, error) {
+	var list WebhookDeliveryListDTO
+	if err := c.post(ctx, "/api/webhooks/deliveries", req, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// RedeliverWebhook 重新投递（Admin）
+func (c *Client) RedeliverWebhook(ctx context.Context, req *RedeliverWebhookRequest) (*WebhookDeliveryDTO, error) {
+	var d WebhookDeliveryDTO
+	if err := c.post(ctx, "/api/webhooks/redeliver", req, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// webhookSecret 调用返回 Webhook 及签名密钥的接口
+func (c *Client) webhookSecret(ctx context.Context, path string, req interface{}) (*WebhookSecretDTO, error) {
+	var wh WebhookSecretDTO
+	if err := c.post(ctx, path, req, &wh); err != nil {
+		return nil, err
+	}
+	return &wh, nil
+}
@@ -0,0 +1,97 @@
+package client
+
+import "context"
+
+// ListZones 列出 Zone（Admin）
+func (c *Client) ListZones(ctx context.Context, req *ListZonesRequest) (*ZoneListDTO, error) {
+	var list ZoneListDTO
+	if err := c.post(ctx, "/api/dns/zones/list", req, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetZone 获取 Zone 详情（Admin）
+func (c *Client) GetZone(ctx context.Context, req *GetZoneRequest) (*ZoneDTO, error) {
+	return c.zone(ctx, "/api/dns/zones/get", req)
+}
+
+// CreateZone 创建 Zone（Admin）
+func (c *Client) CreateZone(ctx context.Context, req *CreateZoneRequest) (*ZoneDTO, error) {
+	return c.zone(ctx, "/api/dns/zones/create", req)
+}
+
+// UpdateZone 更新 Zone（Admin）
+func (c *Client) UpdateZone(ctx context.Context, req *UpdateZoneRequest) (*ZoneDTO, error) {
+	return c.zone(ctx, "/api/dns/zones/update", req)
+}
+
+// DeleteZone 删除 Zone，返回回收站条目（Admin）
+func (c *Client) DeleteZone(ctx context.Context, req *DeleteZoneRequest) (*TrashEntryDTO, error) {
+	var entry TrashEntryDTO
+	if err := c.postData(ctx, "/api/dns/zones/delete", req, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// RollbackZone 将 Zone 下所有 Domain 恢复到指定时间点（Admin）
+func (c *Client) RollbackZone(ctx context.Context, req *RollbackZoneRequest) (*ZoneRollbackDTO, error) {
+	var result ZoneRollbackDTO
+	if err := c.post(ctx, "/api/dns/zones/rollback", req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListZoneTrash 列出回收站中的 Zone（Admin）
+func (c *Client) ListZoneTrash(ctx context.Context, req *ListTrashRequest) (*TrashListDTO, error) {
+	return c.trash(ctx, "/api/dns/zones/trash", req)
+}
+
+// RestoreZone 从回收站恢复 Zone（Admin）
+func (c *Client) RestoreZone(ctx context.Context, req *TrashEntryRequest) (*ZoneDTO, error) {
+	return c.zone(ctx, "/api/dns/zones/restore", req)
+}
+
+// PurgeZone 永久删除回收站中的 Zone（Admin）
+func (c *Client) PurgeZone(ctx context.Context, req *TrashEntryRequest) error {
+	return c.post(ctx, "/api/dns/zones/purge", req, nil)
+}
+
+// VerifyZone 向 DNS 服务器查询 Zone 下所有 Domain 并与记录对比（Admin）
+func (c *Client) VerifyZone(ctx context.Context, req *VerifyZoneRequest) (*ZoneVerification, error) {
+	return c.zoneVerification(ctx, "/api/dns/zones/verify", req)
+}
+
+// ListZoneVerifications 获取 Zone 下各 Domain 最近一次校验结果（Admin）
+func (c *Client) ListZoneVerifications(ctx context.Context, req *VerifyZoneRequest) (*ZoneVerification, error) {
+	return c.zoneVerification(ctx, "/api/dns/zones/verifications", req)
+}
+
+// zone 调用返回 Zone 的接口
+func (c *Client) zone(ctx context.Context, path string, req interface{}) (*ZoneDTO, error) {
+	var zone ZoneDTO
+	if err := c.post(ctx, path, req, &zone); err != nil {
+		return nil, err
+	}
+	return &zone, nil
+}
+
+// trash 调用返回回收站列表的接口
+func (c *Client) trash(ctx context.Context, path string, req *ListTrashRequest) (*TrashListDTO, error) {
+	var list TrashListDTO
+	if err := c.post(ctx, path, req, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// zoneVerification 调用返回 Zone 校验结果的接口
+func (c *Client) zoneVerification(ctx context.Context, path string, req *VerifyZoneRequest) (*ZoneVerification, error) {
+	var result ZoneVerification
+	if err := c.post(ctx, path, req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}